@@ -0,0 +1,108 @@
+package api
+
+import (
+    "fmt"
+    "net/http"
+
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/pkg/middleware"
+
+    "github.com/gorilla/mux"
+)
+
+// routePolicy describe quién puede acceder a una ruta: cualquiera (público), un cliente con
+// sesión de mesa (invitado), cualquier empleado autenticado (rutas sobre sus propios datos) o el
+// personal cuyo rol tenga el permiso indicado
+type routePolicy struct {
+    public     bool
+    guest      bool
+    anyStaff   bool
+    permission models.Permission
+}
+
+var (
+    publicAccess = routePolicy{public: true}
+    guestAccess  = routePolicy{guest: true}
+    staffAccess  = routePolicy{anyStaff: true}
+)
+
+// requires restringe la ruta a empleados autenticados por JWT cuyo rol tenga el permiso
+func requires(permission models.Permission) routePolicy {
+    return routePolicy{permission: permission}
+}
+
+// wrap aplica al handler el middleware correspondiente a la política
+func (p routePolicy) wrap(handler http.Handler, validateSession middleware.SessionValidator, hasPermission middleware.PermissionResolver) http.Handler {
+    switch {
+    case p.public:
+        return handler
+    case p.guest:
+        return middleware.GuestMiddleware()(handler)
+    case p.anyStaff:
+        return middleware.AuthMiddleware(nil, validateSession)(handler)
+    default:
+        return middleware.PermissionMiddleware(p.permission, validateSession, hasPermission)(handler)
+    }
+}
+
+// policyRouter registra cada ruta junto con su política de acceso, de modo que ninguna
+// ruta pueda quedar expuesta sin haber declarado explícitamente quién puede usarla. Las políticas
+// las aplica enforce como middleware del router, así que también una ruta registrada sin pasar por
+// handle queda bloqueada. Las rutas del personal validan además la sesión del token con
+// validateSession y los permisos del rol con hasPermission
+type policyRouter struct {
+    router          *mux.Router
+    policies        map[*mux.Route]routePolicy
+    validateSession middleware.SessionValidator
+    hasPermission   middleware.PermissionResolver
+}
+
+func newPolicyRouter(router *mux.Router, validateSession middleware.SessionValidator, hasPermission middleware.PermissionResolver) *policyRouter {
+    pr := &policyRouter{router: router, policies: make(map[*mux.Route]routePolicy), validateSession: validateSession, hasPermission: hasPermission}
+    router.Use(pr.enforce)
+    return pr
+}
+
+func (pr *policyRouter) handle(method, path string, policy routePolicy, handler http.Handler) {
+    route := pr.router.Handle(path, handler).Methods(method)
+    pr.policies[route] = policy
+}
+
+func (pr *policyRouter) handlePrefix(method, prefix string, policy routePolicy, handler http.Handler) {
+    route := pr.router.PathPrefix(prefix).Handler(handler).Methods(method)
+    pr.policies[route] = policy
+}
+
+// enforce aplica a cada solicitud la política de la ruta que la atiende; una ruta sin política se
+// rechaza en lugar de quedar abierta
+func (pr *policyRouter) enforce(next http.Handler) http.Handler {
+    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        policy, ok := pr.policies[mux.CurrentRoute(r)]
+        if !ok {
+            http.Error(w, "Forbidden", http.StatusForbidden)
+            return
+        }
+        policy.wrap(next, pr.validateSession, pr.hasPermission).ServeHTTP(w, r)
+    })
+}
+
+// verify recorre todas las rutas del router y falla si alguna fue registrada sin política
+// o con una política de personal sin permiso (que rechazaría a todos sin dejar rastro)
+func (pr *policyRouter) verify() error {
+    return pr.router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+        path, err := route.GetPathTemplate()
+        if err != nil {
+            return err
+        }
+        methods, _ := route.GetMethods()
+
+        policy, ok := pr.policies[route]
+        if !ok {
+            return fmt.Errorf("route %v %s has no access policy", methods, path)
+        }
+        if !policy.public && !policy.guest && !policy.anyStaff && policy.permission == "" {
+            return fmt.Errorf("route %v %s has a staff policy without permission", methods, path)
+        }
+        return nil
+    })
+}
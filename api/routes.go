@@ -1,6 +1,9 @@
 package api
 
 import (
+	"log"
+	"time"
+
 	"gastrobar-backend/cmd/app"
 	"gastrobar-backend/internal/models"
 	"gastrobar-backend/pkg/middleware"
@@ -10,63 +13,233 @@ import (
 
 func SetupRoutes(app *app.App) *mux.Router {
     router := mux.NewRouter()
+    routes := newPolicyRouter(router, app.AuthSvc.ValidateSession, app.PermissionSvc.HasPermission)
 
     // Rutas públicas (sin middleware)
 
     // POST /login: Inicia sesión usando username y password
-    router.HandleFunc("/login", app.AuthHandler.LoginHandler()).Methods("POST")
+    routes.handle("POST", "/login", publicAccess, app.AuthHandler.LoginHandler())
 
     // GET /menu-items: Lista todos los ítems (público)
-    router.HandleFunc("/menu-items", app.MenuItemHandler.ListMenuItemsHandler()).Methods("GET")
-
-
-    // Order Details (público)
-    router.HandleFunc("/order-details", app.OrderDetailHandler.CreateOrderDetailHandler()).Methods("POST")
-    router.HandleFunc("/order-details/{id}", app.OrderDetailHandler.GetOrderDetailByIDHandler()).Methods("GET")
-    router.HandleFunc("/orders/{order_id}/details", app.OrderDetailHandler.GetOrderDetailsByOrderIDHandler()).Methods("GET")
-    router.HandleFunc("/order-details/{id}", app.OrderDetailHandler.UpdateOrderDetailHandler()).Methods("PUT")
-    router.HandleFunc("/order-details/{id}", app.OrderDetailHandler.DeleteOrderDetailHandler()).Methods("DELETE")
-    router.HandleFunc("/orders/{order_id}/complete", app.CustomerOrderHandler.CompleteOrderHandler()).Methods("POST")
+    routes.handle("GET", "/menu-items", publicAccess, app.MenuItemHandler.ListMenuItemsHandler())
+
+    // GET /public/menu: Carta digital con fotos, etiquetas y traducciones según Accept-Language (público)
+    routes.handle("GET", "/public/menu", publicAccess, app.PublicMenuHandler.GetPublicMenuHandler())
+
+    // Archivos subidos (fotos del menú y miniaturas)
+    routes.handlePrefix("GET", app.FileStorage.BaseURL()+"/", publicAccess, app.FileStorage.Handler())
+
+    // Autopedido por QR: el cliente canjea el token de la mesa por una sesión limitada a esa mesa
+    guestSessionLimit := middleware.RateLimitMiddleware(10, time.Minute)
+    guestOrderLimit := middleware.RateLimitMiddleware(30, time.Minute)
+    routes.handle("POST", "/guest/session", publicAccess, guestSessionLimit(app.GuestOrderingHandler.StartGuestSessionHandler()))
+    routes.handle("GET", "/guest/menu", guestAccess, app.PublicMenuHandler.GetPublicMenuHandler())
+    routes.handle("GET", "/guest/order", guestAccess, app.GuestOrderingHandler.GetGuestOrderHandler())
+    routes.handle("POST", "/guest/order-lines", guestAccess, guestOrderLimit(app.GuestOrderingHandler.AddGuestOrderLineHandler()))
+    routes.handle("POST", "/guest/request-bill", guestAccess, guestOrderLimit(app.GuestOrderingHandler.RequestGuestBillHandler()))
     //------------------------------------------------------------------------------->>>
-    //Rutas Protegidas (con middleware)
-
-    // Rutas del módulo de business (restringidas a administradores y dueños)
-    router.Handle("/business", middleware.AuthMiddleware([]models.EmployeeRole{models.EmployeeRoleAdmin, models.EmployeeRoleOwner})(app.BusinessHandler.GetBusinessHandler())).Methods("GET")
-    router.Handle("/business", middleware.AuthMiddleware([]models.EmployeeRole{models.EmployeeRoleAdmin, models.EmployeeRoleOwner})(app.BusinessHandler.UpdateBusinessHandler())).Methods("PUT")
-
-    // Rutas del módulo de employees (restringidas a administradores y dueños)
-    router.Handle("/employees", middleware.AuthMiddleware([]models.EmployeeRole{models.EmployeeRoleAdmin, models.EmployeeRoleOwner})(app.EmployeeHandler.CreateEmployeeHandler())).Methods("POST")
-    router.Handle("/employees/{id}", middleware.AuthMiddleware([]models.EmployeeRole{models.EmployeeRoleAdmin, models.EmployeeRoleOwner})(app.EmployeeHandler.GetEmployeeHandler())).Methods("GET")
-    router.Handle("/employees", middleware.AuthMiddleware([]models.EmployeeRole{models.EmployeeRoleAdmin, models.EmployeeRoleOwner})(app.EmployeeHandler.ListEmployeesHandler())).Methods("GET")
-    router.Handle("/employees/role/employee", middleware.AuthMiddleware([]models.EmployeeRole{models.EmployeeRoleAdmin, models.EmployeeRoleOwner})(app.EmployeeHandler.ListEmployeesByRoleHandler(models.EmployeeRoleEmployee))).Methods("GET")
-    router.Handle("/employees/{id}", middleware.AuthMiddleware([]models.EmployeeRole{models.EmployeeRoleAdmin, models.EmployeeRoleOwner})(app.EmployeeHandler.UpdateEmployeeHandler())).Methods("PUT")
-    router.Handle("/employees/{id}/password", middleware.AuthMiddleware([]models.EmployeeRole{models.EmployeeRoleAdmin, models.EmployeeRoleOwner})(app.EmployeeHandler.UpdateEmployeePasswordHandler())).Methods("PUT")
+    //Rutas Protegidas (con middleware): cada ruta declara el permiso que exige; los permisos de
+    //cada rol están en role_permissions y los edita el dueño
+
+    // Rutas del módulo de business
+    routes.handle("GET", "/business", requires(models.PermissionBusinessView), app.BusinessHandler.GetBusinessHandler())
+    routes.handle("PUT", "/business", requires(models.PermissionBusinessEdit), app.BusinessHandler.UpdateBusinessHandler())
+
+    // Rutas del módulo de employees (solo se gestionan empleados de rango inferior; el dueño se designa por traspaso)
+    routes.handle("POST", "/employees", requires(models.PermissionEmployeesManage), app.EmployeeHandler.CreateEmployeeHandler())
+    routes.handle("GET", "/employees/{id}", requires(models.PermissionEmployeesView), app.EmployeeHandler.GetEmployeeHandler())
+    routes.handle("GET", "/employees", requires(models.PermissionEmployeesView), app.EmployeeHandler.ListEmployeesHandler())
+    routes.handle("GET", "/employees/role/employee", requires(models.PermissionEmployeesView), app.EmployeeHandler.ListEmployeesByRoleHandler(models.EmployeeRoleEmployee))
+    routes.handle("PUT", "/employees/{id}", requires(models.PermissionEmployeesManage), app.EmployeeHandler.UpdateEmployeeHandler())
+    routes.handle("PUT", "/employees/{id}/password", requires(models.PermissionEmployeesManage), app.EmployeeHandler.UpdateEmployeePasswordHandler())
+    routes.handle("POST", "/employees/{id}/deactivate", requires(models.PermissionEmployeesManage), app.EmployeeHandler.DeactivateEmployeeHandler())
+    routes.handle("POST", "/employees/{id}/reactivate", requires(models.PermissionEmployeesManage), app.EmployeeHandler.ReactivateEmployeeHandler())
+    routes.handle("POST", "/employees/{id}/transfer-ownership", requires(models.PermissionEmployeesTransferOwnership), app.EmployeeHandler.TransferOwnershipHandler())
+    routes.handle("GET", "/employees/{id}/audit-logs", requires(models.PermissionEmployeesAudit), app.EmployeeHandler.ListAuditLogsHandler())
+
+    // Perfil propio del empleado autenticado (datos de contacto y cambio de contraseña)
+    routes.handle("GET", "/me", staffAccess, app.EmployeeHandler.GetMeHandler())
+    routes.handle("PUT", "/me", staffAccess, app.EmployeeHandler.UpdateMeHandler())
+    routes.handle("POST", "/me/password", staffAccess, app.AuthHandler.ChangePasswordHandler())
+    routes.handle("GET", "/me/permissions", staffAccess, app.PermissionHandler.GetMyPermissionsHandler())
+
+    // Rutas del módulo de permisos (catálogo y permisos de cada rol)
+    routes.handle("GET", "/permissions", requires(models.PermissionPermissionsManage), app.PermissionHandler.ListPermissionsHandler())
+    routes.handle("GET", "/role-permissions", requires(models.PermissionPermissionsManage), app.PermissionHandler.ListRolePermissionsHandler())
+    routes.handle("GET", "/role-permissions/{role}", requires(models.PermissionPermissionsManage), app.PermissionHandler.GetRolePermissionsHandler())
+    routes.handle("PUT", "/role-permissions/{role}", requires(models.PermissionPermissionsManage), app.PermissionHandler.SetRolePermissionsHandler())
 
     // Rutas del módulo de employee_tasks
-    router.Handle("/tasks", middleware.AuthMiddleware([]models.EmployeeRole{models.EmployeeRoleAdmin, models.EmployeeRoleOwner})(app.EmployeeTaskHandler.CreateTaskHandler())).Methods("POST")
-    router.Handle("/tasks/{id}", middleware.AuthMiddleware([]models.EmployeeRole{models.EmployeeRoleAdmin, models.EmployeeRoleOwner, models.EmployeeRoleEmployee})(app.EmployeeTaskHandler.GetTaskHandler())).Methods("GET")
-    router.Handle("/tasks", middleware.AuthMiddleware([]models.EmployeeRole{models.EmployeeRoleAdmin, models.EmployeeRoleOwner})(app.EmployeeTaskHandler.ListTasksHandler())).Methods("GET")
-    router.Handle("/my-tasks", middleware.AuthMiddleware([]models.EmployeeRole{models.EmployeeRoleAdmin, models.EmployeeRoleOwner, models.EmployeeRoleEmployee})(app.EmployeeTaskHandler.ListTasksByEmployeeHandler())).Methods("GET")
-    router.Handle("/tasks/{id}", middleware.AuthMiddleware([]models.EmployeeRole{models.EmployeeRoleAdmin, models.EmployeeRoleOwner})(app.EmployeeTaskHandler.UpdateTaskHandler())).Methods("PUT")
-    router.Handle("/tasks/{id}/status", middleware.AuthMiddleware([]models.EmployeeRole{models.EmployeeRoleAdmin, models.EmployeeRoleOwner})(app.EmployeeTaskHandler.UpdateTaskStatusHandler())).Methods("PUT")
-    router.Handle("/tasks/{id}", middleware.AuthMiddleware([]models.EmployeeRole{models.EmployeeRoleAdmin, models.EmployeeRoleOwner})(app.EmployeeTaskHandler.DeleteTaskHandler())).Methods("DELETE")
+    routes.handle("POST", "/tasks", requires(models.PermissionTasksManage), app.EmployeeTaskHandler.CreateTaskHandler())
+    routes.handle("GET", "/tasks/{id}", staffAccess, app.EmployeeTaskHandler.GetTaskHandler())
+    routes.handle("GET", "/tasks", requires(models.PermissionTasksView), app.EmployeeTaskHandler.ListTasksHandler())
+    routes.handle("GET", "/my-tasks", staffAccess, app.EmployeeTaskHandler.ListTasksByEmployeeHandler())
+    routes.handle("PUT", "/tasks/{id}", requires(models.PermissionTasksManage), app.EmployeeTaskHandler.UpdateTaskHandler())
+    routes.handle("PUT", "/tasks/{id}/status", staffAccess, app.EmployeeTaskHandler.UpdateTaskStatusHandler())
+    routes.handle("GET", "/tasks/{id}/history", staffAccess, app.EmployeeTaskHandler.GetTaskHistoryHandler())
+    routes.handle("DELETE", "/tasks/{id}", requires(models.PermissionTasksManage), app.EmployeeTaskHandler.DeleteTaskHandler())
 
     // Rutas del módulo de tables
-    router.Handle("/tables", middleware.AuthMiddleware([]models.EmployeeRole{models.EmployeeRoleAdmin, models.EmployeeRoleOwner})(app.TableHandler.CreateTableHandler())).Methods("POST")
-    router.Handle("/tables/{id}", middleware.AuthMiddleware([]models.EmployeeRole{models.EmployeeRoleAdmin, models.EmployeeRoleOwner, models.EmployeeRoleEmployee})(app.TableHandler.GetTableHandler())).Methods("GET")
-    router.Handle("/tables", middleware.AuthMiddleware([]models.EmployeeRole{models.EmployeeRoleAdmin, models.EmployeeRoleOwner, models.EmployeeRoleEmployee})(app.TableHandler.ListTablesHandler())).Methods("GET")
-    router.Handle("/tables/{id}", middleware.AuthMiddleware([]models.EmployeeRole{models.EmployeeRoleAdmin, models.EmployeeRoleOwner, models.EmployeeRoleEmployee})(app.TableHandler.UpdateTableHandler())).Methods("PUT")
+    routes.handle("POST", "/tables", requires(models.PermissionTablesManage), app.TableHandler.CreateTableHandler())
+    routes.handle("GET", "/tables/{id}", requires(models.PermissionTablesView), app.TableHandler.GetTableHandler())
+    routes.handle("GET", "/tables", requires(models.PermissionTablesView), app.TableHandler.ListTablesHandler())
+    routes.handle("PUT", "/tables/{id}", requires(models.PermissionTablesUpdate), app.TableHandler.UpdateTableHandler())
+    routes.handle("DELETE", "/tables/{id}", requires(models.PermissionTablesManage), app.TableHandler.DeleteTableHandler())
+    routes.handle("POST", "/tables/{id}/activate", requires(models.PermissionTablesManage), app.TableHandler.ActivateTableHandler())
+    routes.handle("POST", "/tables/{id}/deactivate", requires(models.PermissionTablesManage), app.TableHandler.DeactivateTableHandler())
+    routes.handle("GET", "/tables/{id}/qr-token", requires(models.PermissionTablesManage), app.GuestOrderingHandler.GetTableQRTokenHandler())
+    routes.handle("POST", "/tables/{id}/qr-token/rotate", requires(models.PermissionTablesManage), app.GuestOrderingHandler.RotateTableQRTokenHandler())
+
+    // Rutas del módulo de reservations (reservas tomadas por el personal)
+    routes.handle("POST", "/reservations", requires(models.PermissionReservationsManage), app.ReservationHandler.CreateReservationHandler())
+    routes.handle("GET", "/reservations", requires(models.PermissionReservationsManage), app.ReservationHandler.ListReservationsHandler())
+    routes.handle("GET", "/reservations/availability", requires(models.PermissionReservationsManage), app.ReservationHandler.SearchAvailabilityHandler())
+    routes.handle("GET", "/reservations/{id}", requires(models.PermissionReservationsManage), app.ReservationHandler.GetReservationHandler())
+    routes.handle("PUT", "/reservations/{id}", requires(models.PermissionReservationsManage), app.ReservationHandler.UpdateReservationHandler())
+    routes.handle("POST", "/reservations/{id}/seat", requires(models.PermissionReservationsManage), app.ReservationHandler.SeatReservationHandler())
+    routes.handle("POST", "/reservations/{id}/no-show", requires(models.PermissionReservationsManage), app.ReservationHandler.MarkNoShowHandler())
+    routes.handle("POST", "/reservations/{id}/cancel", requires(models.PermissionReservationsManage), app.ReservationHandler.CancelReservationHandler())
+
+    // Rutas del módulo de waitlist (lista de espera de clientes sin reserva)
+    routes.handle("POST", "/waitlist", requires(models.PermissionWaitlistManage), app.WaitlistHandler.AddPartyHandler())
+    routes.handle("GET", "/waitlist", requires(models.PermissionWaitlistManage), app.WaitlistHandler.ListWaitlistHandler())
+    routes.handle("GET", "/waitlist/estimate", requires(models.PermissionWaitlistManage), app.WaitlistHandler.EstimateWaitHandler())
+    routes.handle("POST", "/waitlist/seat-next", requires(models.PermissionWaitlistManage), app.WaitlistHandler.SeatNextPartyHandler())
+    routes.handle("GET", "/waitlist/{id}", requires(models.PermissionWaitlistManage), app.WaitlistHandler.GetWaitlistEntryHandler())
+    routes.handle("POST", "/waitlist/{id}/leave", requires(models.PermissionWaitlistManage), app.WaitlistHandler.MarkPartyLeftHandler())
+
+    // Rutas del módulo de waiter_assignments (secciones de mesas de cada mesero por turno)
+    routes.handle("POST", "/waiter-assignments", requires(models.PermissionWaiterAssignmentsManage), app.WaiterAssignmentHandler.CreateAssignmentHandler())
+    routes.handle("GET", "/waiter-assignments", requires(models.PermissionWaiterAssignmentsManage), app.WaiterAssignmentHandler.ListAssignmentsHandler())
+    routes.handle("DELETE", "/waiter-assignments/{id}", requires(models.PermissionWaiterAssignmentsManage), app.WaiterAssignmentHandler.DeleteAssignmentHandler())
+    routes.handle("GET", "/my-tables", staffAccess, app.WaiterAssignmentHandler.GetMyTablesHandler())
+
+    // Rutas del módulo de horarios (plantillas de turno, horarios semanales y turnos de los empleados)
+    routes.handle("POST", "/shift-templates", requires(models.PermissionSchedulesManage), app.ScheduleHandler.CreateTemplateHandler())
+    routes.handle("GET", "/shift-templates", requires(models.PermissionSchedulesManage), app.ScheduleHandler.ListTemplatesHandler())
+    routes.handle("PUT", "/shift-templates/{id}", requires(models.PermissionSchedulesManage), app.ScheduleHandler.UpdateTemplateHandler())
+    routes.handle("DELETE", "/shift-templates/{id}", requires(models.PermissionSchedulesManage), app.ScheduleHandler.DeleteTemplateHandler())
+    routes.handle("POST", "/schedules", requires(models.PermissionSchedulesManage), app.ScheduleHandler.CreateScheduleHandler())
+    routes.handle("GET", "/schedules", requires(models.PermissionSchedulesManage), app.ScheduleHandler.ListSchedulesHandler())
+    routes.handle("GET", "/schedules/{id}", requires(models.PermissionSchedulesManage), app.ScheduleHandler.GetScheduleHandler())
+    routes.handle("DELETE", "/schedules/{id}", requires(models.PermissionSchedulesManage), app.ScheduleHandler.DeleteScheduleHandler())
+    routes.handle("POST", "/schedules/{id}/shifts", requires(models.PermissionSchedulesManage), app.ScheduleHandler.AddShiftHandler())
+    routes.handle("GET", "/schedules/{id}/conflicts", requires(models.PermissionSchedulesManage), app.ScheduleHandler.GetConflictsHandler())
+    routes.handle("POST", "/schedules/{id}/publish", requires(models.PermissionSchedulesManage), app.ScheduleHandler.PublishScheduleHandler())
+    routes.handle("POST", "/schedules/{id}/unpublish", requires(models.PermissionSchedulesManage), app.ScheduleHandler.UnpublishScheduleHandler())
+    routes.handle("POST", "/schedules/{id}/copy", requires(models.PermissionSchedulesManage), app.ScheduleHandler.CopyScheduleHandler())
+    routes.handle("PUT", "/shifts/{id}", requires(models.PermissionSchedulesManage), app.ScheduleHandler.UpdateShiftHandler())
+    routes.handle("DELETE", "/shifts/{id}", requires(models.PermissionSchedulesManage), app.ScheduleHandler.DeleteShiftHandler())
+    routes.handle("GET", "/my-shifts", staffAccess, app.ScheduleHandler.GetMyShiftsHandler())
+
+    // Rutas del módulo de asistencia (marcaciones propias del empleado autenticado y hojas de asistencia)
+    routes.handle("GET", "/time-clock/status", staffAccess, app.TimeClockHandler.GetStatusHandler())
+    routes.handle("POST", "/time-clock/clock-in", staffAccess, app.TimeClockHandler.ClockInHandler())
+    routes.handle("POST", "/time-clock/clock-out", staffAccess, app.TimeClockHandler.ClockOutHandler())
+    routes.handle("POST", "/time-clock/break-start", staffAccess, app.TimeClockHandler.StartBreakHandler())
+    routes.handle("POST", "/time-clock/break-end", staffAccess, app.TimeClockHandler.EndBreakHandler())
+    routes.handle("GET", "/time-clock/missed-punches", requires(models.PermissionTimeClockManage), app.TimeClockHandler.ListMissedPunchesHandler())
+    routes.handle("POST", "/time-entries", requires(models.PermissionTimeClockManage), app.TimeClockHandler.CreateEntryHandler())
+    routes.handle("PUT", "/time-entries/{id}", requires(models.PermissionTimeClockManage), app.TimeClockHandler.AdjustEntryHandler())
+    routes.handle("GET", "/timesheets/{employee_id}", requires(models.PermissionTimeClockManage), app.TimeClockHandler.GetTimesheetHandler())
+
+    // Rutas del módulo de nómina (tarifas y recargos con payroll.configure; la liquidación es por periodo)
+    routes.handle("GET", "/payroll", requires(models.PermissionPayrollView), app.PayrollHandler.GetPayrollHandler())
+    routes.handle("GET", "/payroll/export", requires(models.PermissionPayrollView), app.PayrollHandler.ExportPayrollHandler())
+    routes.handle("GET", "/payroll/rates", requires(models.PermissionPayrollView), app.PayrollHandler.ListRatesHandler())
+    routes.handle("PUT", "/payroll/rates/{employee_id}", requires(models.PermissionPayrollConfigure), app.PayrollHandler.SetRateHandler())
+    routes.handle("GET", "/payroll/settings", requires(models.PermissionPayrollView), app.PayrollHandler.GetSettingsHandler())
+    routes.handle("PUT", "/payroll/settings", requires(models.PermissionPayrollConfigure), app.PayrollHandler.UpdateSettingsHandler())
+    routes.handle("GET", "/payroll/holidays", requires(models.PermissionPayrollView), app.PayrollHandler.ListHolidaysHandler())
+    routes.handle("POST", "/payroll/holidays", requires(models.PermissionPayrollManage), app.PayrollHandler.AddHolidayHandler())
+    routes.handle("DELETE", "/payroll/holidays/{date}", requires(models.PermissionPayrollManage), app.PayrollHandler.DeleteHolidayHandler())
+    routes.handle("GET", "/payroll/tips", requires(models.PermissionPayrollView), app.PayrollHandler.ListTipsHandler())
+    routes.handle("POST", "/payroll/tips", requires(models.PermissionPayrollManage), app.PayrollHandler.AddTipHandler())
+    routes.handle("DELETE", "/payroll/tips/{id}", requires(models.PermissionPayrollManage), app.PayrollHandler.DeleteTipHandler())
 
     // Rutas del módulo de menu_items
-    router.Handle("/menu-items", middleware.AuthMiddleware([]models.EmployeeRole{models.EmployeeRoleAdmin})(app.MenuItemHandler.CreateMenuItemHandler())).Methods("POST")
-    router.Handle("/menu-items/{id}", middleware.AuthMiddleware([]models.EmployeeRole{models.EmployeeRoleAdmin})(app.MenuItemHandler.GetMenuItemHandler())).Methods("GET")
-    router.Handle("/menu-items/{id}", middleware.AuthMiddleware([]models.EmployeeRole{models.EmployeeRoleAdmin})(app.MenuItemHandler.UpdateMenuItemHandler())).Methods("PUT")
-    router.Handle("/menu-items/{id}", middleware.AuthMiddleware([]models.EmployeeRole{models.EmployeeRoleAdmin})(app.MenuItemHandler.DeleteMenuItemHandler())).Methods("DELETE")
-
-
-    // Rutas del módulo de ordenes
-    router.Handle("/orders/{order_id}", middleware.AuthMiddleware([]models.EmployeeRole{models.EmployeeRoleAdmin, models.EmployeeRoleEmployee})(app.CustomerOrderHandler.GetOrderWithDetailsHandler())).Methods("GET")
-    router.Handle("/orders/{order_id}/complete-by-employee", middleware.AuthMiddleware([]models.EmployeeRole{models.EmployeeRoleAdmin, models.EmployeeRoleEmployee})(app.CustomerOrderHandler.CompleteOrderByEmployeeHandler())).Methods("POST")
+    routes.handle("POST", "/menu-items", requires(models.PermissionMenuEdit), app.MenuItemHandler.CreateMenuItemHandler())
+    routes.handle("POST", "/menu-items/import", requires(models.PermissionMenuEdit), app.MenuItemHandler.ImportMenuItemsHandler())
+    routes.handle("GET", "/menu-items/export", requires(models.PermissionMenuExport), app.MenuItemHandler.ExportMenuItemsHandler())
+    routes.handle("GET", "/menu-items/low-stock", requires(models.PermissionStockViewAlerts), app.MenuItemHandler.ListLowStockItemsHandler())
+    routes.handle("GET", "/menu-items/{id}", requires(models.PermissionMenuView), app.MenuItemHandler.GetMenuItemHandler())
+    routes.handle("PUT", "/menu-items/{id}", requires(models.PermissionMenuEdit), app.MenuItemHandler.UpdateMenuItemHandler())
+    routes.handle("DELETE", "/menu-items/{id}", requires(models.PermissionMenuEdit), app.MenuItemHandler.DeleteMenuItemHandler())
+    routes.handle("GET", "/menu-items/{id}/price-history", requires(models.PermissionMenuView), app.MenuItemHandler.GetPriceHistoryHandler())
+    routes.handle("POST", "/menu-items/{id}/scheduled-prices", requires(models.PermissionMenuEdit), app.MenuItemHandler.SchedulePriceChangeHandler())
+    routes.handle("GET", "/menu-items/{id}/scheduled-prices", requires(models.PermissionMenuView), app.MenuItemHandler.ListScheduledPriceChangesHandler())
+    routes.handle("DELETE", "/menu-items/{id}/scheduled-prices/{change_id}", requires(models.PermissionMenuEdit), app.MenuItemHandler.CancelScheduledPriceChangeHandler())
+    routes.handle("POST", "/menu-items/{id}/image", requires(models.PermissionMenuEdit), app.PublicMenuHandler.UploadMenuItemImageHandler())
+    routes.handle("DELETE", "/menu-items/{id}/image", requires(models.PermissionMenuEdit), app.PublicMenuHandler.DeleteMenuItemImageHandler())
+    routes.handle("PUT", "/menu-items/{id}/tags", requires(models.PermissionMenuEdit), app.PublicMenuHandler.SetMenuItemTagsHandler())
+    routes.handle("GET", "/menu-items/{id}/translations", requires(models.PermissionMenuView), app.PublicMenuHandler.ListMenuItemTranslationsHandler())
+    routes.handle("PUT", "/menu-items/{id}/translations/{locale}", requires(models.PermissionMenuEdit), app.PublicMenuHandler.SaveMenuItemTranslationHandler())
+    routes.handle("DELETE", "/menu-items/{id}/translations/{locale}", requires(models.PermissionMenuEdit), app.PublicMenuHandler.DeleteMenuItemTranslationHandler())
+    routes.handle("GET", "/menu-items/{id}/recipe", requires(models.PermissionMenuView), app.IngredientHandler.GetRecipeHandler())
+    routes.handle("PUT", "/menu-items/{id}/recipe", requires(models.PermissionMenuEdit), app.IngredientHandler.SetRecipeHandler())
+    routes.handle("GET", "/menu-items/{id}/stock-movements", requires(models.PermissionInventoryView), app.StockMovementHandler.ListMenuItemStockMovementsHandler())
+
+    // Rutas del módulo de ingredients (inventario por insumo)
+    routes.handle("POST", "/ingredients", requires(models.PermissionInventoryEdit), app.IngredientHandler.CreateIngredientHandler())
+    routes.handle("GET", "/ingredients", requires(models.PermissionInventoryView), app.IngredientHandler.ListIngredientsHandler())
+    routes.handle("GET", "/ingredients/{id}", requires(models.PermissionInventoryView), app.IngredientHandler.GetIngredientHandler())
+    routes.handle("PUT", "/ingredients/{id}", requires(models.PermissionInventoryEdit), app.IngredientHandler.UpdateIngredientHandler())
+    routes.handle("DELETE", "/ingredients/{id}", requires(models.PermissionInventoryEdit), app.IngredientHandler.DeleteIngredientHandler())
+    routes.handle("GET", "/ingredients/{id}/stock-movements", requires(models.PermissionInventoryView), app.StockMovementHandler.ListIngredientStockMovementsHandler())
+
+    // Rutas del libro de movimientos de inventario (mermas, ajustes y conciliación)
+    routes.handle("POST", "/stock-movements", requires(models.PermissionInventoryAdjust), app.StockMovementHandler.RecordStockMovementHandler())
+    routes.handle("GET", "/stock-movements/reconciliation", requires(models.PermissionInventoryView), app.StockMovementHandler.GetStockReconciliationHandler())
+
+    // Rutas del módulo de suppliers (proveedores)
+    routes.handle("POST", "/suppliers", requires(models.PermissionSuppliersManage), app.SupplierHandler.CreateSupplierHandler())
+    routes.handle("GET", "/suppliers", requires(models.PermissionSuppliersManage), app.SupplierHandler.ListSuppliersHandler())
+    routes.handle("GET", "/suppliers/{id}", requires(models.PermissionSuppliersManage), app.SupplierHandler.GetSupplierHandler())
+    routes.handle("PUT", "/suppliers/{id}", requires(models.PermissionSuppliersManage), app.SupplierHandler.UpdateSupplierHandler())
+    routes.handle("DELETE", "/suppliers/{id}", requires(models.PermissionSuppliersManage), app.SupplierHandler.DeleteSupplierHandler())
+
+    // Rutas del módulo de purchase_orders (órdenes de compra y recepción de mercancía)
+    routes.handle("POST", "/purchase-orders", requires(models.PermissionPurchasingManage), app.PurchaseOrderHandler.CreatePurchaseOrderHandler())
+    routes.handle("GET", "/purchase-orders", requires(models.PermissionPurchasingManage), app.PurchaseOrderHandler.ListPurchaseOrdersHandler())
+    routes.handle("GET", "/purchase-orders/{id}", requires(models.PermissionPurchasingManage), app.PurchaseOrderHandler.GetPurchaseOrderHandler())
+    routes.handle("PUT", "/purchase-orders/{id}", requires(models.PermissionPurchasingManage), app.PurchaseOrderHandler.UpdatePurchaseOrderHandler())
+    routes.handle("DELETE", "/purchase-orders/{id}", requires(models.PermissionPurchasingManage), app.PurchaseOrderHandler.DeletePurchaseOrderHandler())
+    routes.handle("POST", "/purchase-orders/{id}/send", requires(models.PermissionPurchasingManage), app.PurchaseOrderHandler.SendPurchaseOrderHandler())
+    routes.handle("POST", "/purchase-orders/{id}/receive", requires(models.PermissionPurchasingManage), app.PurchaseOrderHandler.ReceivePurchaseOrderHandler())
+
+    // Rutas de los conteos físicos de inventario (cualquier empleado registra lo que contó; la gerencia revisa y aprueba)
+    routes.handle("POST", "/inventory-counts", requires(models.PermissionInventoryCountsManage), app.InventoryCountHandler.OpenInventoryCountHandler())
+    routes.handle("GET", "/inventory-counts", requires(models.PermissionInventoryCountsManage), app.InventoryCountHandler.ListInventoryCountsHandler())
+    routes.handle("GET", "/inventory-counts/{id}", requires(models.PermissionInventoryCountsRecord), app.InventoryCountHandler.GetInventoryCountHandler())
+    routes.handle("PUT", "/inventory-counts/{id}/entries", requires(models.PermissionInventoryCountsRecord), app.InventoryCountHandler.RecordInventoryCountEntryHandler())
+    routes.handle("GET", "/inventory-counts/{id}/variances", requires(models.PermissionInventoryCountsManage), app.InventoryCountHandler.GetInventoryCountVariancesHandler())
+    routes.handle("POST", "/inventory-counts/{id}/approve", requires(models.PermissionInventoryCountsManage), app.InventoryCountHandler.ApproveInventoryCountHandler())
+    routes.handle("POST", "/inventory-counts/{id}/cancel", requires(models.PermissionInventoryCountsManage), app.InventoryCountHandler.CancelInventoryCountHandler())
+    routes.handle("GET", "/inventory-counts/{id}/shrinkage", requires(models.PermissionInventoryCountsManage), app.InventoryCountHandler.GetInventoryShrinkageReportHandler())
+
+    // Rutas de los reportes de costo y margen
+    routes.handle("GET", "/reports/menu-item-costs", requires(models.PermissionReportsView), app.CostReportHandler.GetMenuItemCostsHandler())
+    routes.handle("GET", "/reports/margins", requires(models.PermissionReportsView), app.CostReportHandler.GetMarginReportHandler())
+
+    // Rutas del módulo de ordenes (el cierre de cuenta es exclusivo del personal)
+    routes.handle("GET", "/orders/{order_id}", requires(models.PermissionOrdersView), app.CustomerOrderHandler.GetOrderWithDetailsHandler())
+    routes.handle("POST", "/orders/{order_id}/complete", requires(models.PermissionOrdersClose), app.CustomerOrderHandler.CompleteOrderHandler())
+    routes.handle("POST", "/orders/{order_id}/complete-by-employee", requires(models.PermissionOrdersClose), app.CustomerOrderHandler.CompleteOrderByEmployeeHandler())
+    routes.handle("POST", "/orders/{order_id}/request-bill", requires(models.PermissionOrdersClose), app.CustomerOrderHandler.RequestBillHandler())
+
+    // Rutas del módulo de order_details (personal del negocio)
+    routes.handle("POST", "/order-details", requires(models.PermissionOrdersEdit), app.OrderDetailHandler.CreateOrderDetailHandler())
+    routes.handle("GET", "/order-details/{id}", requires(models.PermissionOrdersView), app.OrderDetailHandler.GetOrderDetailByIDHandler())
+    routes.handle("GET", "/orders/{order_id}/details", requires(models.PermissionOrdersView), app.OrderDetailHandler.GetOrderDetailsByOrderIDHandler())
+    routes.handle("PUT", "/order-details/{id}", requires(models.PermissionOrdersEdit), app.OrderDetailHandler.UpdateOrderDetailHandler())
+    routes.handle("DELETE", "/order-details/{id}", requires(models.PermissionOrdersVoid), app.OrderDetailHandler.DeleteOrderDetailHandler())
+    routes.handle("POST", "/order-details/{id}/confirm", requires(models.PermissionOrdersEdit), app.OrderDetailHandler.ConfirmOrderDetailHandler())
+    routes.handle("GET", "/guest-order-lines/pending", requires(models.PermissionOrdersView), app.OrderDetailHandler.ListPendingConfirmationHandler())
+
+    // Verificar que ninguna ruta haya quedado registrada sin política de acceso
+    if err := routes.verify(); err != nil {
+        log.Fatalf("Invalid route access policy: %v", err)
+    }
 
     return router
-}
\ No newline at end of file
+}
@@ -0,0 +1,503 @@
+package api
+
+import (
+    "database/sql"
+    "net/http"
+    "net/http/httptest"
+    "regexp"
+    "strings"
+    "sync"
+    "testing"
+    "time"
+
+    "gastrobar-backend/cmd/app"
+    "gastrobar-backend/config"
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/internal/services"
+
+    "github.com/golang-jwt/jwt/v5"
+    "github.com/gorilla/mux"
+    _ "github.com/lib/pq"
+    "github.com/pkg/errors"
+)
+
+const testJWTSecret = "test-jwt-secret"
+
+// routeAccess es la política esperada de cada ruta. La prueba falla si el router tiene una ruta que
+// no está aquí o si aquí hay una que el router no tiene, así que toda ruta nueva debe declararse
+var routeAccess = []struct {
+    method string
+    path   string
+    policy routePolicy
+}{
+    {"POST",   "/login",                                        publicAccess},
+    {"GET",    "/menu-items",                                   publicAccess},
+    {"GET",    "/public/menu",                                  publicAccess},
+    {"GET",    "/uploads/",                                     publicAccess},
+    {"POST",   "/guest/session",                                publicAccess},
+    {"GET",    "/guest/menu",                                   guestAccess},
+    {"GET",    "/guest/order",                                  guestAccess},
+    {"POST",   "/guest/order-lines",                            guestAccess},
+    {"POST",   "/guest/request-bill",                           guestAccess},
+    {"GET",    "/business",                                     requires(models.PermissionBusinessView)},
+    {"PUT",    "/business",                                     requires(models.PermissionBusinessEdit)},
+    {"POST",   "/employees",                                    requires(models.PermissionEmployeesManage)},
+    {"GET",    "/employees/{id}",                               requires(models.PermissionEmployeesView)},
+    {"GET",    "/employees",                                    requires(models.PermissionEmployeesView)},
+    {"GET",    "/employees/role/employee",                      requires(models.PermissionEmployeesView)},
+    {"PUT",    "/employees/{id}",                               requires(models.PermissionEmployeesManage)},
+    {"PUT",    "/employees/{id}/password",                      requires(models.PermissionEmployeesManage)},
+    {"POST",   "/employees/{id}/deactivate",                    requires(models.PermissionEmployeesManage)},
+    {"POST",   "/employees/{id}/reactivate",                    requires(models.PermissionEmployeesManage)},
+    {"POST",   "/employees/{id}/transfer-ownership",            requires(models.PermissionEmployeesTransferOwnership)},
+    {"GET",    "/employees/{id}/audit-logs",                    requires(models.PermissionEmployeesAudit)},
+    {"GET",    "/me",                                           staffAccess},
+    {"PUT",    "/me",                                           staffAccess},
+    {"POST",   "/me/password",                                  staffAccess},
+    {"GET",    "/me/permissions",                               staffAccess},
+    {"GET",    "/permissions",                                  requires(models.PermissionPermissionsManage)},
+    {"GET",    "/role-permissions",                             requires(models.PermissionPermissionsManage)},
+    {"GET",    "/role-permissions/{role}",                      requires(models.PermissionPermissionsManage)},
+    {"PUT",    "/role-permissions/{role}",                      requires(models.PermissionPermissionsManage)},
+    {"POST",   "/tasks",                                        requires(models.PermissionTasksManage)},
+    {"GET",    "/tasks/{id}",                                   staffAccess},
+    {"GET",    "/tasks",                                        requires(models.PermissionTasksView)},
+    {"GET",    "/my-tasks",                                     staffAccess},
+    {"PUT",    "/tasks/{id}",                                   requires(models.PermissionTasksManage)},
+    {"PUT",    "/tasks/{id}/status",                            staffAccess},
+    {"GET",    "/tasks/{id}/history",                           staffAccess},
+    {"DELETE", "/tasks/{id}",                                   requires(models.PermissionTasksManage)},
+    {"POST",   "/tables",                                       requires(models.PermissionTablesManage)},
+    {"GET",    "/tables/{id}",                                  requires(models.PermissionTablesView)},
+    {"GET",    "/tables",                                       requires(models.PermissionTablesView)},
+    {"PUT",    "/tables/{id}",                                  requires(models.PermissionTablesUpdate)},
+    {"DELETE", "/tables/{id}",                                  requires(models.PermissionTablesManage)},
+    {"POST",   "/tables/{id}/activate",                         requires(models.PermissionTablesManage)},
+    {"POST",   "/tables/{id}/deactivate",                       requires(models.PermissionTablesManage)},
+    {"GET",    "/tables/{id}/qr-token",                         requires(models.PermissionTablesManage)},
+    {"POST",   "/tables/{id}/qr-token/rotate",                  requires(models.PermissionTablesManage)},
+    {"POST",   "/reservations",                                 requires(models.PermissionReservationsManage)},
+    {"GET",    "/reservations",                                 requires(models.PermissionReservationsManage)},
+    {"GET",    "/reservations/availability",                    requires(models.PermissionReservationsManage)},
+    {"GET",    "/reservations/{id}",                            requires(models.PermissionReservationsManage)},
+    {"PUT",    "/reservations/{id}",                            requires(models.PermissionReservationsManage)},
+    {"POST",   "/reservations/{id}/seat",                       requires(models.PermissionReservationsManage)},
+    {"POST",   "/reservations/{id}/no-show",                    requires(models.PermissionReservationsManage)},
+    {"POST",   "/reservations/{id}/cancel",                     requires(models.PermissionReservationsManage)},
+    {"POST",   "/waitlist",                                     requires(models.PermissionWaitlistManage)},
+    {"GET",    "/waitlist",                                     requires(models.PermissionWaitlistManage)},
+    {"GET",    "/waitlist/estimate",                            requires(models.PermissionWaitlistManage)},
+    {"POST",   "/waitlist/seat-next",                           requires(models.PermissionWaitlistManage)},
+    {"GET",    "/waitlist/{id}",                                requires(models.PermissionWaitlistManage)},
+    {"POST",   "/waitlist/{id}/leave",                          requires(models.PermissionWaitlistManage)},
+    {"POST",   "/waiter-assignments",                           requires(models.PermissionWaiterAssignmentsManage)},
+    {"GET",    "/waiter-assignments",                           requires(models.PermissionWaiterAssignmentsManage)},
+    {"DELETE", "/waiter-assignments/{id}",                      requires(models.PermissionWaiterAssignmentsManage)},
+    {"GET",    "/my-tables",                                    staffAccess},
+    {"POST",   "/shift-templates",                              requires(models.PermissionSchedulesManage)},
+    {"GET",    "/shift-templates",                              requires(models.PermissionSchedulesManage)},
+    {"PUT",    "/shift-templates/{id}",                         requires(models.PermissionSchedulesManage)},
+    {"DELETE", "/shift-templates/{id}",                         requires(models.PermissionSchedulesManage)},
+    {"POST",   "/schedules",                                    requires(models.PermissionSchedulesManage)},
+    {"GET",    "/schedules",                                    requires(models.PermissionSchedulesManage)},
+    {"GET",    "/schedules/{id}",                               requires(models.PermissionSchedulesManage)},
+    {"DELETE", "/schedules/{id}",                               requires(models.PermissionSchedulesManage)},
+    {"POST",   "/schedules/{id}/shifts",                        requires(models.PermissionSchedulesManage)},
+    {"GET",    "/schedules/{id}/conflicts",                     requires(models.PermissionSchedulesManage)},
+    {"POST",   "/schedules/{id}/publish",                       requires(models.PermissionSchedulesManage)},
+    {"POST",   "/schedules/{id}/unpublish",                     requires(models.PermissionSchedulesManage)},
+    {"POST",   "/schedules/{id}/copy",                          requires(models.PermissionSchedulesManage)},
+    {"PUT",    "/shifts/{id}",                                  requires(models.PermissionSchedulesManage)},
+    {"DELETE", "/shifts/{id}",                                  requires(models.PermissionSchedulesManage)},
+    {"GET",    "/my-shifts",                                    staffAccess},
+    {"GET",    "/time-clock/status",                            staffAccess},
+    {"POST",   "/time-clock/clock-in",                          staffAccess},
+    {"POST",   "/time-clock/clock-out",                         staffAccess},
+    {"POST",   "/time-clock/break-start",                       staffAccess},
+    {"POST",   "/time-clock/break-end",                         staffAccess},
+    {"GET",    "/time-clock/missed-punches",                    requires(models.PermissionTimeClockManage)},
+    {"POST",   "/time-entries",                                 requires(models.PermissionTimeClockManage)},
+    {"PUT",    "/time-entries/{id}",                            requires(models.PermissionTimeClockManage)},
+    {"GET",    "/timesheets/{employee_id}",                     requires(models.PermissionTimeClockManage)},
+    {"GET",    "/payroll",                                      requires(models.PermissionPayrollView)},
+    {"GET",    "/payroll/export",                               requires(models.PermissionPayrollView)},
+    {"GET",    "/payroll/rates",                                requires(models.PermissionPayrollView)},
+    {"PUT",    "/payroll/rates/{employee_id}",                  requires(models.PermissionPayrollConfigure)},
+    {"GET",    "/payroll/settings",                             requires(models.PermissionPayrollView)},
+    {"PUT",    "/payroll/settings",                             requires(models.PermissionPayrollConfigure)},
+    {"GET",    "/payroll/holidays",                             requires(models.PermissionPayrollView)},
+    {"POST",   "/payroll/holidays",                             requires(models.PermissionPayrollManage)},
+    {"DELETE", "/payroll/holidays/{date}",                      requires(models.PermissionPayrollManage)},
+    {"GET",    "/payroll/tips",                                 requires(models.PermissionPayrollView)},
+    {"POST",   "/payroll/tips",                                 requires(models.PermissionPayrollManage)},
+    {"DELETE", "/payroll/tips/{id}",                            requires(models.PermissionPayrollManage)},
+    {"POST",   "/menu-items",                                   requires(models.PermissionMenuEdit)},
+    {"POST",   "/menu-items/import",                            requires(models.PermissionMenuEdit)},
+    {"GET",    "/menu-items/export",                            requires(models.PermissionMenuExport)},
+    {"GET",    "/menu-items/low-stock",                         requires(models.PermissionStockViewAlerts)},
+    {"GET",    "/menu-items/{id}",                              requires(models.PermissionMenuView)},
+    {"PUT",    "/menu-items/{id}",                              requires(models.PermissionMenuEdit)},
+    {"DELETE", "/menu-items/{id}",                              requires(models.PermissionMenuEdit)},
+    {"GET",    "/menu-items/{id}/price-history",                requires(models.PermissionMenuView)},
+    {"POST",   "/menu-items/{id}/scheduled-prices",             requires(models.PermissionMenuEdit)},
+    {"GET",    "/menu-items/{id}/scheduled-prices",             requires(models.PermissionMenuView)},
+    {"DELETE", "/menu-items/{id}/scheduled-prices/{change_id}", requires(models.PermissionMenuEdit)},
+    {"POST",   "/menu-items/{id}/image",                        requires(models.PermissionMenuEdit)},
+    {"DELETE", "/menu-items/{id}/image",                        requires(models.PermissionMenuEdit)},
+    {"PUT",    "/menu-items/{id}/tags",                         requires(models.PermissionMenuEdit)},
+    {"GET",    "/menu-items/{id}/translations",                 requires(models.PermissionMenuView)},
+    {"PUT",    "/menu-items/{id}/translations/{locale}",        requires(models.PermissionMenuEdit)},
+    {"DELETE", "/menu-items/{id}/translations/{locale}",        requires(models.PermissionMenuEdit)},
+    {"GET",    "/menu-items/{id}/recipe",                       requires(models.PermissionMenuView)},
+    {"PUT",    "/menu-items/{id}/recipe",                       requires(models.PermissionMenuEdit)},
+    {"GET",    "/menu-items/{id}/stock-movements",              requires(models.PermissionInventoryView)},
+    {"POST",   "/ingredients",                                  requires(models.PermissionInventoryEdit)},
+    {"GET",    "/ingredients",                                  requires(models.PermissionInventoryView)},
+    {"GET",    "/ingredients/{id}",                             requires(models.PermissionInventoryView)},
+    {"PUT",    "/ingredients/{id}",                             requires(models.PermissionInventoryEdit)},
+    {"DELETE", "/ingredients/{id}",                             requires(models.PermissionInventoryEdit)},
+    {"GET",    "/ingredients/{id}/stock-movements",             requires(models.PermissionInventoryView)},
+    {"POST",   "/stock-movements",                              requires(models.PermissionInventoryAdjust)},
+    {"GET",    "/stock-movements/reconciliation",               requires(models.PermissionInventoryView)},
+    {"POST",   "/suppliers",                                    requires(models.PermissionSuppliersManage)},
+    {"GET",    "/suppliers",                                    requires(models.PermissionSuppliersManage)},
+    {"GET",    "/suppliers/{id}",                               requires(models.PermissionSuppliersManage)},
+    {"PUT",    "/suppliers/{id}",                               requires(models.PermissionSuppliersManage)},
+    {"DELETE", "/suppliers/{id}",                               requires(models.PermissionSuppliersManage)},
+    {"POST",   "/purchase-orders",                              requires(models.PermissionPurchasingManage)},
+    {"GET",    "/purchase-orders",                              requires(models.PermissionPurchasingManage)},
+    {"GET",    "/purchase-orders/{id}",                         requires(models.PermissionPurchasingManage)},
+    {"PUT",    "/purchase-orders/{id}",                         requires(models.PermissionPurchasingManage)},
+    {"DELETE", "/purchase-orders/{id}",                         requires(models.PermissionPurchasingManage)},
+    {"POST",   "/purchase-orders/{id}/send",                    requires(models.PermissionPurchasingManage)},
+    {"POST",   "/purchase-orders/{id}/receive",                 requires(models.PermissionPurchasingManage)},
+    {"POST",   "/inventory-counts",                             requires(models.PermissionInventoryCountsManage)},
+    {"GET",    "/inventory-counts",                             requires(models.PermissionInventoryCountsManage)},
+    {"GET",    "/inventory-counts/{id}",                        requires(models.PermissionInventoryCountsRecord)},
+    {"PUT",    "/inventory-counts/{id}/entries",                requires(models.PermissionInventoryCountsRecord)},
+    {"GET",    "/inventory-counts/{id}/variances",              requires(models.PermissionInventoryCountsManage)},
+    {"POST",   "/inventory-counts/{id}/approve",                requires(models.PermissionInventoryCountsManage)},
+    {"POST",   "/inventory-counts/{id}/cancel",                 requires(models.PermissionInventoryCountsManage)},
+    {"GET",    "/inventory-counts/{id}/shrinkage",              requires(models.PermissionInventoryCountsManage)},
+    {"GET",    "/reports/menu-item-costs",                      requires(models.PermissionReportsView)},
+    {"GET",    "/reports/margins",                              requires(models.PermissionReportsView)},
+    {"GET",    "/orders/{order_id}",                            requires(models.PermissionOrdersView)},
+    {"POST",   "/orders/{order_id}/complete",                   requires(models.PermissionOrdersClose)},
+    {"POST",   "/orders/{order_id}/complete-by-employee",       requires(models.PermissionOrdersClose)},
+    {"POST",   "/orders/{order_id}/request-bill",               requires(models.PermissionOrdersClose)},
+    {"POST",   "/order-details",                                requires(models.PermissionOrdersEdit)},
+    {"GET",    "/order-details/{id}",                           requires(models.PermissionOrdersView)},
+    {"GET",    "/orders/{order_id}/details",                    requires(models.PermissionOrdersView)},
+    {"PUT",    "/order-details/{id}",                           requires(models.PermissionOrdersEdit)},
+    {"DELETE", "/order-details/{id}",                           requires(models.PermissionOrdersVoid)},
+    {"POST",   "/order-details/{id}/confirm",                   requires(models.PermissionOrdersEdit)},
+    {"GET",    "/guest-order-lines/pending",                    requires(models.PermissionOrdersView)},
+}
+
+// stubPermissionRepository guarda en memoria los permisos de cada rol
+type stubPermissionRepository struct {
+    mu      sync.Mutex
+    granted map[models.EmployeeRole][]models.Permission
+}
+
+func (r *stubPermissionRepository) FindAll() ([]models.PermissionInfo, error) {
+    permissions := []models.PermissionInfo{}
+    for _, permission := range models.AllPermissions {
+        permissions = append(permissions, models.PermissionInfo{Code: permission})
+    }
+    return permissions, nil
+}
+
+func (r *stubPermissionRepository) FindRolePermissions() ([]models.RolePermissions, error) {
+    rolePermissions := []models.RolePermissions{}
+    for _, role := range []models.EmployeeRole{models.EmployeeRoleOwner, models.EmployeeRoleAdmin, models.EmployeeRoleEmployee} {
+        found, _ := r.FindByRole(role)
+        rolePermissions = append(rolePermissions, found)
+    }
+    return rolePermissions, nil
+}
+
+func (r *stubPermissionRepository) FindByRole(role models.EmployeeRole) (models.RolePermissions, error) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    return models.RolePermissions{Role: role, Permissions: append([]models.Permission{}, r.granted[role]...)}, nil
+}
+
+func (r *stubPermissionRepository) SetRolePermissions(role models.EmployeeRole, permissions []models.Permission, grantedBy int) (models.RolePermissions, error) {
+    r.mu.Lock()
+    r.granted[role] = append([]models.Permission{}, permissions...)
+    r.mu.Unlock()
+    return r.FindByRole(role)
+}
+
+func (r *stubPermissionRepository) SeedRolePermissions(defaults map[models.EmployeeRole][]models.Permission) (bool, error) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    if len(r.granted) > 0 {
+        return false, nil
+    }
+    for role, permissions := range defaults {
+        r.granted[role] = append([]models.Permission{}, permissions...)
+    }
+    return true, nil
+}
+
+// stubAuthService valida las sesiones sin base de datos; las de los empleados en revoked están revocadas
+type stubAuthService struct {
+    services.AuthService
+    mu      sync.Mutex
+    revoked map[int]bool
+}
+
+func (s *stubAuthService) ValidateSession(employeeID, tokenVersion int) error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    if s.revoked[employeeID] {
+        return errors.New("session has been revoked")
+    }
+    return nil
+}
+
+// routeTestEnv es el router de la aplicación con handlers de prueba: cada handler responde 200 con
+// el header X-Route de la ruta, de modo que el estado que recibe el cliente lo deciden solo las
+// políticas de acceso
+type routeTestEnv struct {
+    router        *mux.Router
+    auth          *stubAuthService
+    permissionSvc services.PermissionService
+}
+
+func newRouteTestEnv(t *testing.T) *routeTestEnv {
+    t.Helper()
+    t.Setenv("JWT_SECRET", testJWTSecret)
+    config.LoadConfig()
+
+    // La base no se usa: los handlers se reemplazan y la sesión y los permisos se resuelven en memoria
+    db, err := sql.Open("postgres", "host=127.0.0.1 port=1 sslmode=disable")
+    if err != nil {
+        t.Fatalf("failed to open database handle: %v", err)
+    }
+    t.Cleanup(func() { db.Close() })
+
+    a := app.NewApp(db)
+    auth := &stubAuthService{AuthService: a.AuthSvc, revoked: make(map[int]bool)}
+    a.AuthSvc = auth
+    a.PermissionSvc = services.NewPermissionService(&stubPermissionRepository{granted: make(map[models.EmployeeRole][]models.Permission)})
+    if seeded, err := a.PermissionSvc.SeedDefaultRolePermissions(); err != nil || !seeded {
+        t.Fatalf("failed to seed default role permissions: seeded %v, err %v", seeded, err)
+    }
+
+    router := SetupRoutes(a)
+    err = router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+        key, err := routeKey(route)
+        if err != nil {
+            return err
+        }
+        route.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+            w.Header().Set("X-Route", key)
+            w.WriteHeader(http.StatusOK)
+        }))
+        return nil
+    })
+    if err != nil {
+        t.Fatalf("failed to walk routes: %v", err)
+    }
+    return &routeTestEnv{router: router, auth: auth, permissionSvc: a.PermissionSvc}
+}
+
+func routeKey(route *mux.Route) (string, error) {
+    path, err := route.GetPathTemplate()
+    if err != nil {
+        return "", err
+    }
+    methods, err := route.GetMethods()
+    if err != nil {
+        return "", err
+    }
+    return strings.Join(methods, ",") + " " + path, nil
+}
+
+var pathVariable = regexp.MustCompile(`\{[^}]+\}`)
+
+// requestPath convierte la plantilla de la ruta en una ruta concreta
+func requestPath(template string) string {
+    path := pathVariable.ReplaceAllString(template, "1")
+    if strings.HasSuffix(path, "/") {
+        path += "menu-items/1/foto.jpg"
+    }
+    return path
+}
+
+func (env *routeTestEnv) do(method, path, token string) *httptest.ResponseRecorder {
+    req := httptest.NewRequest(method, path, nil)
+    if token != "" {
+        req.Header.Set("Authorization", "Bearer "+token)
+    }
+    rec := httptest.NewRecorder()
+    env.router.ServeHTTP(rec, req)
+    return rec
+}
+
+func signToken(t *testing.T, secret string, claims jwt.MapClaims) string {
+    t.Helper()
+    claims["exp"] = time.Now().Add(time.Hour).Unix()
+    token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+    if err != nil {
+        t.Fatalf("failed to sign token: %v", err)
+    }
+    return token
+}
+
+func staffToken(t *testing.T, employeeID int, role models.EmployeeRole) string {
+    return signToken(t, testJWTSecret, jwt.MapClaims{"employee_id": employeeID, "role": string(role), "tv": 0})
+}
+
+// routeCaller es un cliente de la prueba; role es vacío para los que no son empleados
+type routeCaller struct {
+    name  string
+    token string
+    guest bool
+    role  models.EmployeeRole
+}
+
+func routeCallers(t *testing.T) []routeCaller {
+    return []routeCaller{
+        {name: "anonymous"},
+        {name: "forged", token: signToken(t, "other-secret", jwt.MapClaims{"employee_id": 1, "role": string(models.EmployeeRoleOwner), "tv": 0})},
+        {name: "guest", token: signToken(t, testJWTSecret, jwt.MapClaims{"scope": "guest", "table_id": 1, "qr_version": 1}), guest: true},
+        {name: "employee", token: staffToken(t, 3, models.EmployeeRoleEmployee), role: models.EmployeeRoleEmployee},
+        {name: "admin", token: staffToken(t, 2, models.EmployeeRoleAdmin), role: models.EmployeeRoleAdmin},
+        {name: "owner", token: staffToken(t, 1, models.EmployeeRoleOwner), role: models.EmployeeRoleOwner},
+    }
+}
+
+// expectedStatus es el estado que debe recibir el cliente según la política de la ruta
+func expectedStatus(policy routePolicy, caller routeCaller, granted map[models.EmployeeRole][]models.Permission) int {
+    switch {
+    case policy.public:
+        return http.StatusOK
+    case caller.token == "" || caller.name == "forged":
+        return http.StatusUnauthorized
+    case policy.guest:
+        if caller.guest {
+            return http.StatusOK
+        }
+        return http.StatusForbidden // Token de empleado en una ruta de cliente
+    case caller.guest:
+        return http.StatusUnauthorized // El token de mesa no lleva rol
+    case policy.anyStaff:
+        return http.StatusOK
+    }
+    for _, permission := range granted[caller.role] {
+        if permission == policy.permission {
+            return http.StatusOK
+        }
+    }
+    return http.StatusForbidden
+}
+
+// TestRouteAccess recorre todas las rutas del router y comprueba el estado que recibe cada tipo de
+// cliente con los permisos por defecto de cada rol
+func TestRouteAccess(t *testing.T) {
+    env := newRouteTestEnv(t)
+
+    // Comparar las rutas registradas con las declaradas en routeAccess
+    registered := make(map[string]bool)
+    err := env.router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+        key, err := routeKey(route)
+        if err != nil {
+            return err
+        }
+        registered[key] = true
+        return nil
+    })
+    if err != nil {
+        t.Fatalf("failed to walk routes: %v", err)
+    }
+    for _, expected := range routeAccess {
+        key := expected.method + " " + expected.path
+        if !registered[key] {
+            t.Errorf("route %s is declared in routeAccess but not registered", key)
+        }
+        delete(registered, key)
+    }
+    for key := range registered {
+        t.Errorf("route %s has no expected access in routeAccess", key)
+    }
+
+    granted := models.DefaultRolePermissions()
+    callers := routeCallers(t)
+    for _, expected := range routeAccess {
+        key := expected.method + " " + expected.path
+        for _, caller := range callers {
+            rec := env.do(expected.method, requestPath(expected.path), caller.token)
+            want := expectedStatus(expected.policy, caller, granted)
+            if rec.Code != want {
+                t.Errorf("%s as %s: got status %d, want %d", key, caller.name, rec.Code, want)
+                continue
+            }
+            if want == http.StatusOK && rec.Header().Get("X-Route") != key {
+                t.Errorf("%s as %s: served by route %q", key, caller.name, rec.Header().Get("X-Route"))
+            }
+        }
+    }
+}
+
+// TestRouteAccessFollowsRolePermissions comprueba que los cambios de permisos de un rol se aplican
+// en la siguiente solicitud
+func TestRouteAccessFollowsRolePermissions(t *testing.T) {
+    env := newRouteTestEnv(t)
+    employee := staffToken(t, 3, models.EmployeeRoleEmployee)
+
+    if rec := env.do("PUT", "/menu-items/1", employee); rec.Code != http.StatusForbidden {
+        t.Fatalf("PUT /menu-items/1 before grant: got status %d, want %d", rec.Code, http.StatusForbidden)
+    }
+    if rec := env.do("DELETE", "/order-details/1", employee); rec.Code != http.StatusOK {
+        t.Fatalf("DELETE /order-details/1 before revoke: got status %d, want %d", rec.Code, http.StatusOK)
+    }
+
+    // Conceder menu.edit y quitar orders.void al rol empleado
+    var permissions []models.Permission
+    for _, permission := range models.DefaultRolePermissions()[models.EmployeeRoleEmployee] {
+        if permission != models.PermissionOrdersVoid {
+            permissions = append(permissions, permission)
+        }
+    }
+    permissions = append(permissions, models.PermissionMenuEdit)
+    request := models.RolePermissionsRequest{Permissions: permissions}
+    if _, err := env.permissionSvc.SetRolePermissions(models.EmployeeRoleEmployee, request, 1); err != nil {
+        t.Fatalf("failed to set role permissions: %v", err)
+    }
+
+    if rec := env.do("PUT", "/menu-items/1", employee); rec.Code != http.StatusOK {
+        t.Errorf("PUT /menu-items/1 after grant: got status %d, want %d", rec.Code, http.StatusOK)
+    }
+    if rec := env.do("DELETE", "/order-details/1", employee); rec.Code != http.StatusForbidden {
+        t.Errorf("DELETE /order-details/1 after revoke: got status %d, want %d", rec.Code, http.StatusForbidden)
+    }
+}
+
+// TestRevokedSessionIsRejected comprueba que un token cuya sesión fue revocada (cambio de contraseña
+// o de rol, baja) ya no sirve en las rutas del personal
+func TestRevokedSessionIsRejected(t *testing.T) {
+    env := newRouteTestEnv(t)
+    admin := staffToken(t, 2, models.EmployeeRoleAdmin)
+
+    env.auth.mu.Lock()
+    env.auth.revoked[2] = true
+    env.auth.mu.Unlock()
+
+    for _, request := range []struct {
+        method string
+        path   string
+        want   int
+    }{
+        {"GET", "/me", http.StatusUnauthorized},
+        {"GET", "/employees", http.StatusUnauthorized},
+        {"GET", "/menu-items", http.StatusOK},
+    } {
+        if rec := env.do(request.method, request.path, admin); rec.Code != request.want {
+            t.Errorf("%s %s with revoked session: got status %d, want %d", request.method, request.path, rec.Code, request.want)
+        }
+    }
+}
@@ -3,9 +3,12 @@ package app
 import (
 	"database/sql"
 
+	"gastrobar-backend/config"
 	"gastrobar-backend/internal/handlers"
 	"gastrobar-backend/internal/repositories"
 	"gastrobar-backend/internal/services"
+	"gastrobar-backend/pkg/notifier"
+	"gastrobar-backend/pkg/storage"
 )
 
 // App contiene todas las dependencias de la aplicación
@@ -15,7 +18,26 @@ type App struct {
 	EmployeeTaskRepo        repositories.EmployeeTaskRepository
 	TableRepo               repositories.TableRepository
 	MenuItemRepo            repositories.MenuItemRepository
+	MenuItemPriceRepo       repositories.MenuItemPriceRepository
+	MenuItemImageRepo       repositories.MenuItemImageRepository
+	MenuItemTagRepo         repositories.MenuItemTagRepository
+	MenuItemTranslationRepo repositories.MenuItemTranslationRepository
 	OrderDetailRepo         repositories.OrderDetailRepository
+	IngredientRepo          repositories.IngredientRepository
+	RecipeRepo              repositories.RecipeRepository
+	StockMovementRepo       repositories.StockMovementRepository
+	SupplierRepo            repositories.SupplierRepository
+	PurchaseOrderRepo       repositories.PurchaseOrderRepository
+	InventoryCountRepo      repositories.InventoryCountRepository
+	CostReportRepo          repositories.CostReportRepository
+	ReservationRepo         repositories.ReservationRepository
+	WaitlistRepo            repositories.WaitlistRepository
+	WaiterAssignmentRepo    repositories.WaiterAssignmentRepository
+	ShiftTemplateRepo       repositories.ShiftTemplateRepository
+	ScheduleRepo            repositories.ScheduleRepository
+	TimeClockRepo           repositories.TimeClockRepository
+	PayrollRepo             repositories.PayrollRepository
+	PermissionRepo          repositories.PermissionRepository
 	CustomerOrderRepo       repositories.CustomerOrderRepository
 	AuthSvc                 services.AuthService
 	BusinessSvc             services.BusinessService
@@ -25,6 +47,21 @@ type App struct {
 	MenuItemSvc             services.MenuItemService
 	CustomerOrderSvc        services.CustomerOrderService
 	OrderDetailSvc          services.OrderDetailService
+	PublicMenuSvc           services.PublicMenuService
+	GuestOrderingSvc        services.GuestOrderingService
+	IngredientSvc           services.IngredientService
+	StockMovementSvc        services.StockMovementService
+	SupplierSvc             services.SupplierService
+	PurchaseOrderSvc        services.PurchaseOrderService
+	InventoryCountSvc       services.InventoryCountService
+	CostReportSvc           services.CostReportService
+	ReservationSvc          services.ReservationService
+	WaitlistSvc             services.WaitlistService
+	WaiterAssignmentSvc     services.WaiterAssignmentService
+	ScheduleSvc             services.ScheduleService
+	TimeClockSvc            services.TimeClockService
+	PayrollSvc              services.PayrollService
+	PermissionSvc           services.PermissionService
 	AuthHandler             *handlers.AuthHandler
 	BusinessHandler         *handlers.BusinessHandler
 	EmployeeHandler         *handlers.EmployeeHandler
@@ -33,6 +70,22 @@ type App struct {
 	MenuItemHandler         *handlers.MenuItemHandler
 	CustomerOrderHandler    *handlers.CustomerOrderHandler
 	OrderDetailHandler      *handlers.OrderDetailHandler
+	PublicMenuHandler       *handlers.PublicMenuHandler
+	GuestOrderingHandler    *handlers.GuestOrderingHandler
+	IngredientHandler       *handlers.IngredientHandler
+	StockMovementHandler    *handlers.StockMovementHandler
+	SupplierHandler         *handlers.SupplierHandler
+	PurchaseOrderHandler    *handlers.PurchaseOrderHandler
+	InventoryCountHandler   *handlers.InventoryCountHandler
+	CostReportHandler       *handlers.CostReportHandler
+	ReservationHandler      *handlers.ReservationHandler
+	WaitlistHandler         *handlers.WaitlistHandler
+	WaiterAssignmentHandler *handlers.WaiterAssignmentHandler
+	ScheduleHandler         *handlers.ScheduleHandler
+	TimeClockHandler        *handlers.TimeClockHandler
+	PayrollHandler          *handlers.PayrollHandler
+	PermissionHandler       *handlers.PermissionHandler
+	FileStorage             *storage.LocalStorage
 }
 
 // NewApp inicializa todas las dependencias de la aplicación
@@ -43,18 +96,58 @@ func NewApp(db *sql.DB) *App {
 	employeeTaskRepo := repositories.NewEmployeeTaskRepository(db)
 	tableRepo := repositories.NewTableRepository(db)
 	menuItemRepo := repositories.NewMenuItemRepository(db)
+	menuItemPriceRepo := repositories.NewMenuItemPriceRepository(db)
+	menuItemImageRepo := repositories.NewMenuItemImageRepository(db)
+	menuItemTagRepo := repositories.NewMenuItemTagRepository(db)
+	menuItemTranslationRepo := repositories.NewMenuItemTranslationRepository(db)
 	customerOrderRepo := repositories.NewCustomerOrderRepository(db)
 	orderDetailRepo := repositories.NewOrderDetailRepository(db)
+	ingredientRepo := repositories.NewIngredientRepository(db)
+	recipeRepo := repositories.NewRecipeRepository(db)
+	stockMovementRepo := repositories.NewStockMovementRepository(db)
+	supplierRepo := repositories.NewSupplierRepository(db)
+	purchaseOrderRepo := repositories.NewPurchaseOrderRepository(db)
+	inventoryCountRepo := repositories.NewInventoryCountRepository(db)
+	costReportRepo := repositories.NewCostReportRepository(db)
+	reservationRepo := repositories.NewReservationRepository(db)
+	waitlistRepo := repositories.NewWaitlistRepository(db)
+	waiterAssignmentRepo := repositories.NewWaiterAssignmentRepository(db)
+	shiftTemplateRepo := repositories.NewShiftTemplateRepository(db)
+	scheduleRepo := repositories.NewScheduleRepository(db)
+	timeClockRepo := repositories.NewTimeClockRepository(db)
+	payrollRepo := repositories.NewPayrollRepository(db)
+	permissionRepo := repositories.NewPermissionRepository(db)
+
+	// Inicializar el almacenamiento de archivos subidos
+	fileStorage := storage.NewLocalStorage(config.GetUploadDir(), config.GetUploadURL())
+
+	// Inicializar el notificador de alertas (webhook si está configurado, log en caso contrario)
+	alertNotifier := notifier.New(config.GetAlertWebhookURL())
 
 	// Inicializar servicios
+	permissionSvc := services.NewPermissionService(permissionRepo)
 	authSvc := services.NewAuthService(employeeRepo)
-	businessSvc := services.NewBusinessService(businessRepo)
+	businessSvc := services.NewBusinessService(businessRepo, tableRepo)
 	employeeSvc := services.NewEmployeeService(employeeRepo)
-	employeeTaskSvc := services.NewEmployeeTaskService(employeeTaskRepo, employeeRepo)
-	tableSvc := services.NewTableService(tableRepo)
-	menuItemSvc := services.NewMenuItemService(menuItemRepo)
+	employeeTaskSvc := services.NewEmployeeTaskService(employeeTaskRepo, employeeRepo, permissionSvc)
+	tableSvc := services.NewTableService(tableRepo, businessRepo, customerOrderRepo)
+	menuItemSvc := services.NewMenuItemService(menuItemRepo, menuItemPriceRepo)
 	customerOrderSvc := services.NewCustomerOrderService(customerOrderRepo, menuItemRepo, orderDetailRepo)
-	orderDetailSvc := services.NewOrderDetailService(orderDetailRepo, customerOrderRepo, menuItemRepo, tableRepo)
+	orderDetailSvc := services.NewOrderDetailService(orderDetailRepo, customerOrderRepo, menuItemRepo, tableRepo, alertNotifier)
+	publicMenuSvc := services.NewPublicMenuService(menuItemRepo, menuItemImageRepo, menuItemTagRepo, menuItemTranslationRepo, fileStorage)
+	guestOrderingSvc := services.NewGuestOrderingService(tableRepo, customerOrderRepo, orderDetailRepo, businessRepo, orderDetailSvc)
+	ingredientSvc := services.NewIngredientService(ingredientRepo, recipeRepo, menuItemRepo)
+	stockMovementSvc := services.NewStockMovementService(stockMovementRepo, menuItemRepo, ingredientRepo)
+	supplierSvc := services.NewSupplierService(supplierRepo)
+	purchaseOrderSvc := services.NewPurchaseOrderService(purchaseOrderRepo, supplierRepo, menuItemRepo, ingredientRepo)
+	inventoryCountSvc := services.NewInventoryCountService(inventoryCountRepo, menuItemRepo)
+	costReportSvc := services.NewCostReportService(costReportRepo)
+	reservationSvc := services.NewReservationService(reservationRepo, tableRepo)
+	waitlistSvc := services.NewWaitlistService(waitlistRepo)
+	waiterAssignmentSvc := services.NewWaiterAssignmentService(waiterAssignmentRepo, employeeRepo)
+	scheduleSvc := services.NewScheduleService(scheduleRepo, shiftTemplateRepo)
+	timeClockSvc := services.NewTimeClockService(timeClockRepo, scheduleRepo, employeeRepo)
+	payrollSvc := services.NewPayrollService(payrollRepo, timeClockRepo)
 
 	// Inicializar manejadores
 	authHandler := handlers.NewAuthHandler(authSvc)
@@ -65,6 +158,21 @@ func NewApp(db *sql.DB) *App {
 	menuItemHandler := handlers.NewMenuItemHandler(menuItemSvc)
 	customerOrderHandler := handlers.NewCustomerOrderHandler(customerOrderSvc)
 	orderDetailHandler := handlers.NewOrderDetailHandler(orderDetailSvc)
+	publicMenuHandler := handlers.NewPublicMenuHandler(publicMenuSvc)
+	guestOrderingHandler := handlers.NewGuestOrderingHandler(guestOrderingSvc)
+	ingredientHandler := handlers.NewIngredientHandler(ingredientSvc)
+	stockMovementHandler := handlers.NewStockMovementHandler(stockMovementSvc)
+	supplierHandler := handlers.NewSupplierHandler(supplierSvc)
+	purchaseOrderHandler := handlers.NewPurchaseOrderHandler(purchaseOrderSvc)
+	inventoryCountHandler := handlers.NewInventoryCountHandler(inventoryCountSvc)
+	costReportHandler := handlers.NewCostReportHandler(costReportSvc)
+	reservationHandler := handlers.NewReservationHandler(reservationSvc)
+	waitlistHandler := handlers.NewWaitlistHandler(waitlistSvc)
+	waiterAssignmentHandler := handlers.NewWaiterAssignmentHandler(waiterAssignmentSvc)
+	scheduleHandler := handlers.NewScheduleHandler(scheduleSvc)
+	timeClockHandler := handlers.NewTimeClockHandler(timeClockSvc)
+	payrollHandler := handlers.NewPayrollHandler(payrollSvc)
+	permissionHandler := handlers.NewPermissionHandler(permissionSvc)
 
 	return &App{
 		EmployeeRepo:            employeeRepo,
@@ -72,8 +180,27 @@ func NewApp(db *sql.DB) *App {
 		EmployeeTaskRepo:        employeeTaskRepo,
 		TableRepo:               tableRepo,
 		MenuItemRepo:            menuItemRepo,
+		MenuItemPriceRepo:       menuItemPriceRepo,
+		MenuItemImageRepo:       menuItemImageRepo,
+		MenuItemTagRepo:         menuItemTagRepo,
+		MenuItemTranslationRepo: menuItemTranslationRepo,
 		CustomerOrderRepo:       customerOrderRepo,
 		OrderDetailRepo:         orderDetailRepo,
+		IngredientRepo:          ingredientRepo,
+		RecipeRepo:              recipeRepo,
+		StockMovementRepo:       stockMovementRepo,
+		SupplierRepo:            supplierRepo,
+		PurchaseOrderRepo:       purchaseOrderRepo,
+		InventoryCountRepo:      inventoryCountRepo,
+		CostReportRepo:          costReportRepo,
+		ReservationRepo:         reservationRepo,
+		WaitlistRepo:            waitlistRepo,
+		WaiterAssignmentRepo:    waiterAssignmentRepo,
+		ShiftTemplateRepo:       shiftTemplateRepo,
+		ScheduleRepo:            scheduleRepo,
+		TimeClockRepo:           timeClockRepo,
+		PayrollRepo:             payrollRepo,
+		PermissionRepo:          permissionRepo,
 		AuthSvc:                 authSvc,
 		BusinessSvc:             businessSvc,
 		EmployeeSvc:             employeeSvc,
@@ -82,6 +209,21 @@ func NewApp(db *sql.DB) *App {
 		MenuItemSvc:             menuItemSvc,
 		CustomerOrderSvc:        customerOrderSvc,
 		OrderDetailSvc:          orderDetailSvc,
+		PublicMenuSvc:           publicMenuSvc,
+		GuestOrderingSvc:        guestOrderingSvc,
+		IngredientSvc:           ingredientSvc,
+		StockMovementSvc:        stockMovementSvc,
+		SupplierSvc:             supplierSvc,
+		PurchaseOrderSvc:        purchaseOrderSvc,
+		InventoryCountSvc:       inventoryCountSvc,
+		CostReportSvc:           costReportSvc,
+		ReservationSvc:          reservationSvc,
+		WaitlistSvc:             waitlistSvc,
+		WaiterAssignmentSvc:     waiterAssignmentSvc,
+		ScheduleSvc:             scheduleSvc,
+		TimeClockSvc:            timeClockSvc,
+		PayrollSvc:              payrollSvc,
+		PermissionSvc:           permissionSvc,
 		AuthHandler:             authHandler,
 		BusinessHandler:         businessHandler,
 		EmployeeHandler:         employeeHandler,
@@ -90,5 +232,21 @@ func NewApp(db *sql.DB) *App {
 		MenuItemHandler:         menuItemHandler,
 		CustomerOrderHandler:    customerOrderHandler,
 		OrderDetailHandler:      orderDetailHandler,
+		PublicMenuHandler:       publicMenuHandler,
+		GuestOrderingHandler:    guestOrderingHandler,
+		IngredientHandler:       ingredientHandler,
+		StockMovementHandler:    stockMovementHandler,
+		SupplierHandler:         supplierHandler,
+		PurchaseOrderHandler:    purchaseOrderHandler,
+		InventoryCountHandler:   inventoryCountHandler,
+		CostReportHandler:       costReportHandler,
+		ReservationHandler:      reservationHandler,
+		WaitlistHandler:         waitlistHandler,
+		WaiterAssignmentHandler: waiterAssignmentHandler,
+		ScheduleHandler:         scheduleHandler,
+		TimeClockHandler:        timeClockHandler,
+		PayrollHandler:          payrollHandler,
+		PermissionHandler:       permissionHandler,
+		FileStorage:             fileStorage,
 	}
 }
@@ -3,6 +3,7 @@ package main
 import (
 	"log"
 	"net/http"
+	"time"
 
 	"gastrobar-backend/api"
 	"gastrobar-backend/cmd/app"
@@ -20,6 +21,30 @@ func main() {
 	//Configurar la aplicación
 	app := app.NewApp(database.DB)
 
+	// Sembrar los permisos por defecto de cada rol si todavía no hay ninguno
+	seeded, err := app.PermissionSvc.SeedDefaultRolePermissions()
+	if err != nil {
+		log.Fatalf("Error seeding role permissions: %v", err)
+	}
+	if seeded {
+		log.Println("Seeded default role permissions")
+	}
+
+	// Aplicar periódicamente los cambios de precio programados
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for range ticker.C {
+			applied, err := app.MenuItemSvc.ApplyDueScheduledPriceChanges()
+			if err != nil {
+				log.Printf("Error applying scheduled price changes: %v", err)
+			}
+			if applied > 0 {
+				log.Printf("Applied %d scheduled price changes", applied)
+			}
+		}
+	}()
+
 	// Configurar las rutas
 	router := api.SetupRoutes(app)
 
@@ -9,14 +9,17 @@ import (
 
 // Config define la estructura para las variables de configuración
 type Config struct {
-    DBHost     string
-    DBPort     string
-    DBUser     string
-    DBPassword string
-    DBName     string
-    Port       string
-    JWTSecret  string
-    JWTExpire  string
+    DBHost          string
+    DBPort          string
+    DBUser          string
+    DBPassword      string
+    DBName          string
+    Port            string
+    JWTSecret       string
+    JWTExpire       string
+    UploadDir       string
+    UploadURL       string
+    AlertWebhookURL string
 }
 
 var cfg Config // Variable global para almacenar la configuración
@@ -28,14 +31,17 @@ func LoadConfig() Config {
     }
 
     cfg = Config{
-        DBHost:     os.Getenv("DB_HOST"),
-        DBPort:     os.Getenv("DB_PORT"),
-        DBUser:     os.Getenv("DB_USER"),
-        DBPassword: os.Getenv("DB_PASSWORD"),
-        DBName:     os.Getenv("DB_NAME"),
-        Port:       os.Getenv("PORT"),
-        JWTSecret:  os.Getenv("JWT_SECRET"),
-        JWTExpire:  os.Getenv("JWT_EXPIRATION"),
+        DBHost:          os.Getenv("DB_HOST"),
+        DBPort:          os.Getenv("DB_PORT"),
+        DBUser:          os.Getenv("DB_USER"),
+        DBPassword:      os.Getenv("DB_PASSWORD"),
+        DBName:          os.Getenv("DB_NAME"),
+        Port:            os.Getenv("PORT"),
+        JWTSecret:       os.Getenv("JWT_SECRET"),
+        JWTExpire:       os.Getenv("JWT_EXPIRATION"),
+        UploadDir:       os.Getenv("UPLOAD_DIR"),
+        UploadURL:       os.Getenv("UPLOAD_URL"),
+        AlertWebhookURL: os.Getenv("ALERT_WEBHOOK_URL"),
     }
 
     return cfg
@@ -55,4 +61,25 @@ func GetJWTExpire() string {
         log.Fatal("JWT_EXPIRATION not set in configuration")
     }
     return cfg.JWTExpire
-}
\ No newline at end of file
+}
+
+// GetUploadDir devuelve el directorio donde se guardan los archivos subidos (por defecto "uploads")
+func GetUploadDir() string {
+    if cfg.UploadDir == "" {
+        return "uploads"
+    }
+    return cfg.UploadDir
+}
+
+// GetUploadURL devuelve el prefijo público de los archivos subidos (por defecto "/uploads")
+func GetUploadURL() string {
+    if cfg.UploadURL == "" {
+        return "/uploads"
+    }
+    return cfg.UploadURL
+}
+
+// GetAlertWebhookURL devuelve la URL a la que se envían las alertas (vacía = solo se registran en el log)
+func GetAlertWebhookURL() string {
+    return cfg.AlertWebhookURL
+}
@@ -2,10 +2,11 @@ package handlers
 
 import (
     "encoding/json"
+    "fmt"
     "log"
     "net/http"
+    "strings"
 
-    "github.com/pkg/errors"
     "gastrobar-backend/internal/models"
     "gastrobar-backend/internal/services"
 )
@@ -33,15 +34,84 @@ func (h *AuthHandler) LoginHandler() http.HandlerFunc {
 
         loginResp, err := h.authSvc.Login(loginReq)
         if err != nil {
-            if errors.Is(err, errors.New("invalid email or password")) {
+            if strings.Contains(err.Error(), "invalid email or password") || strings.Contains(err.Error(), "employee not found") {
                 http.Error(w, "Invalid email or password", http.StatusUnauthorized)
                 return
             }
+            if strings.Contains(err.Error(), "employee is inactive") {
+                http.Error(w, "Employee account is deactivated", http.StatusForbidden)
+                return
+            }
             log.Printf("Error during login: %v", err)
             http.Error(w, "Internal server error", http.StatusInternalServerError)
             return
         }
 
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(loginResp)
+    }
+}
+
+// ChangePasswordHandler cambia la contraseña del empleado autenticado; las demás sesiones quedan
+// invalidadas y la respuesta trae un token nuevo para seguir en la sesión actual
+func (h *AuthHandler) ChangePasswordHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        var request models.PasswordChangeRequest
+        if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+
+        // Obtener el employee_id del token JWT: solo se cambia la propia contraseña
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        loginResp, err := h.authSvc.ChangePassword(employeeID, request)
+        if err != nil {
+            if strings.Contains(err.Error(), "current password is incorrect") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusUnauthorized)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Current password is incorrect"})
+                return
+            }
+            if strings.Contains(err.Error(), "password must be at least") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("Password must be at least %d characters long", services.MinPasswordLength)})
+                return
+            }
+            if strings.Contains(err.Error(), "password must contain uppercase and lowercase letters and digits") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Password must contain uppercase and lowercase letters and digits"})
+                return
+            }
+            if strings.Contains(err.Error(), "password must not contain the username") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Password must not contain the username"})
+                return
+            }
+            if strings.Contains(err.Error(), "new password must differ from the current one") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "New password must differ from the current one"})
+                return
+            }
+            if strings.Contains(err.Error(), "employee not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee not found"})
+                return
+            }
+            log.Printf("Error changing password: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
         w.Header().Set("Content-Type", "application/json")
         json.NewEncoder(w).Encode(loginResp)
     }
@@ -4,6 +4,7 @@ import (
     "encoding/json"
     "log"
     "net/http"
+    "strings"
 
     "gastrobar-backend/internal/models"
     "gastrobar-backend/internal/services"
@@ -53,6 +54,18 @@ func (h *BusinessHandler) UpdateBusinessHandler() http.HandlerFunc {
 
         updatedBusiness, err := h.businessSvc.UpdateBusiness(business)
         if err != nil {
+            if strings.Contains(err.Error(), "max tables must be greater than 0") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Max tables must be greater than 0"})
+                return
+            }
+            if strings.Contains(err.Error(), "max tables is lower than the number of active tables") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Max tables is lower than the number of active tables"})
+                return
+            }
             if errors.Is(err, errors.Wrap(err, "business not found")) {
                 http.Error(w, "Business not found", http.StatusNotFound)
                 return
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+    "encoding/json"
+    "log"
+    "net/http"
+    "strings"
+    "time"
+
+    "gastrobar-backend/internal/services"
+)
+
+// CostReportHandler maneja los reportes de costo y margen (exclusivos del dueño)
+type CostReportHandler struct {
+    costReportSvc services.CostReportService
+}
+
+func NewCostReportHandler(costReportSvc services.CostReportService) *CostReportHandler {
+    return &CostReportHandler{
+        costReportSvc: costReportSvc,
+    }
+}
+
+// parseReportRange lee los parámetros opcionales from y to (YYYY-MM-DD o RFC3339). Si to es una
+// fecha sin hora se incluye el día completo
+func parseReportRange(r *http.Request) (*time.Time, *time.Time, bool) {
+    parse := func(value string, endOfDay bool) (*time.Time, bool) {
+        if value == "" {
+            return nil, true
+        }
+        if t, err := time.Parse(time.RFC3339, value); err == nil {
+            return &t, true
+        }
+        t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+        if err != nil {
+            return nil, false
+        }
+        if endOfDay {
+            t = t.AddDate(0, 0, 1)
+        }
+        return &t, true
+    }
+
+    from, ok := parse(r.URL.Query().Get("from"), false)
+    if !ok {
+        return nil, nil, false
+    }
+    to, ok := parse(r.URL.Query().Get("to"), true)
+    if !ok {
+        return nil, nil, false
+    }
+    return from, to, true
+}
+
+// GetMenuItemCostsHandler devuelve el costo unitario y el margen bruto de cada ítem
+func (h *CostReportHandler) GetMenuItemCostsHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        costs, err := h.costReportSvc.GetMenuItemCosts()
+        if err != nil {
+            log.Printf("Error getting menu item costs: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(costs)
+    }
+}
+
+// GetMarginReportHandler devuelve el costo teórico y la contribución por ítem de las órdenes completadas (?from=&to=)
+func (h *CostReportHandler) GetMarginReportHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        from, to, ok := parseReportRange(r)
+        if !ok {
+            http.Error(w, "Invalid date range, use YYYY-MM-DD or RFC3339", http.StatusBadRequest)
+            return
+        }
+
+        report, err := h.costReportSvc.GetMarginReport(from, to)
+        if err != nil {
+            if strings.Contains(err.Error(), "from must be before to") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "From must be before to"})
+                return
+            }
+            log.Printf("Error getting margin report: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(report)
+    }
+}
@@ -65,7 +65,14 @@ func (h *CustomerOrderHandler) CompleteOrderHandler() http.HandlerFunc {
             return
         }
 
-        updatedOrder, err := h.customerOrderSvc.CompleteOrder(orderID)
+        // Obtener el employee_id del token JWT
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        updatedOrder, err := h.customerOrderSvc.CompleteOrder(orderID, employeeID)
         if err != nil {
             if strings.Contains(err.Error(), "failed to find customer order") {
                 w.Header().Set("Content-Type", "application/json")
@@ -102,7 +109,14 @@ func (h *CustomerOrderHandler) CompleteOrderByEmployeeHandler() http.HandlerFunc
             return
         }
 
-        updatedOrder, err := h.customerOrderSvc.CompleteOrderByEmployee(orderID)
+        // Obtener el employee_id del token JWT
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        updatedOrder, err := h.customerOrderSvc.CompleteOrderByEmployee(orderID, employeeID)
         if err != nil {
             if strings.Contains(err.Error(), "failed to find customer order") {
                 w.Header().Set("Content-Type", "application/json")
@@ -125,4 +139,42 @@ func (h *CustomerOrderHandler) CompleteOrderByEmployeeHandler() http.HandlerFunc
         w.WriteHeader(http.StatusOK)
         json.NewEncoder(w).Encode(updatedOrder)
     }
-}
\ No newline at end of file
+}
+
+// RequestBillHandler marca que la mesa pidió la cuenta (la mesa queda esperando el pago)
+func (h *CustomerOrderHandler) RequestBillHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        orderIDStr := vars["order_id"]
+        orderID, err := strconv.Atoi(orderIDStr)
+        if err != nil {
+            w.Header().Set("Content-Type", "application/json")
+            w.WriteHeader(http.StatusBadRequest)
+            json.NewEncoder(w).Encode(map[string]string{"error": "invalid order ID"})
+            return
+        }
+
+        updatedOrder, err := h.customerOrderSvc.RequestBill(orderID)
+        if err != nil {
+            if strings.Contains(err.Error(), "failed to find customer order") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "customer order not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "order is not in 'pending' state") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "order is not in 'pending' state"})
+                return
+            }
+            log.Printf("Error requesting bill: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusOK)
+        json.NewEncoder(w).Encode(updatedOrder)
+    }
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"io"
 	"log"
 	"net/http"
 	"strconv"
@@ -11,6 +12,7 @@ import (
 	"gastrobar-backend/internal/services"
 
 	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
 )
 
 // EmployeeHandler maneja las solicitudes relacionadas con los empleados
@@ -34,8 +36,30 @@ func (h *EmployeeHandler) CreateEmployeeHandler() http.HandlerFunc {
             return
         }
 
-        response, err := h.employeeSvc.CreateEmployee(employee)
+        // Obtener el employee_id del token JWT: solo se gestionan empleados de rango inferior
+        actorID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        response, err := h.employeeSvc.CreateEmployee(employee, actorID)
         if err != nil {
+            if writeValidationError(w, err) {
+                return
+            }
+            if strings.Contains(err.Error(), "owner role can only be assigned through ownership transfer") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusForbidden)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Owner role can only be assigned through ownership transfer"})
+                return
+            }
+            if strings.Contains(err.Error(), "insufficient rank to manage this employee") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusForbidden)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Insufficient rank to manage this employee"})
+                return
+            }
             log.Printf("Error creating employee: %v", err)
             http.Error(w, "Internal server error", http.StatusInternalServerError)
             return
@@ -47,6 +71,19 @@ func (h *EmployeeHandler) CreateEmployeeHandler() http.HandlerFunc {
     }
 }
 
+// writeValidationError responde 422 con los errores por campo si err es un *models.ValidationError;
+// devuelve false si no lo es para que el manejador siga evaluando el error
+func writeValidationError(w http.ResponseWriter, err error) bool {
+    var validation *models.ValidationError
+    if !errors.As(err, &validation) {
+        return false
+    }
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusUnprocessableEntity)
+    json.NewEncoder(w).Encode(map[string]interface{}{"error": "Validation failed", "fields": validation.Fields})
+    return true
+}
+
 // GetEmployeeHandler maneja la solicitud para obtener un empleado por ID
 func (h *EmployeeHandler) GetEmployeeHandler() http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
@@ -124,8 +161,30 @@ func (h *EmployeeHandler) UpdateEmployeeHandler() http.HandlerFunc {
         }
         employee.ID = employeeID
 
-        updatedEmployee, err := h.employeeSvc.UpdateEmployee(employee)
+        // Obtener el employee_id del token JWT: solo se gestionan empleados de rango inferior
+        actorID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        updatedEmployee, err := h.employeeSvc.UpdateEmployee(employee, actorID)
         if err != nil {
+            if writeValidationError(w, err) {
+                return
+            }
+            if strings.Contains(err.Error(), "owner role can only be assigned through ownership transfer") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusForbidden)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Owner role can only be assigned through ownership transfer"})
+                return
+            }
+            if strings.Contains(err.Error(), "insufficient rank to manage this employee") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusForbidden)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Insufficient rank to manage this employee"})
+                return
+            }
             if strings.Contains(err.Error(), "employee not found") {
                 w.Header().Set("Content-Type", "application/json")
                 w.WriteHeader(http.StatusNotFound)
@@ -154,9 +213,22 @@ func (h *EmployeeHandler) UpdateEmployeePasswordHandler() http.HandlerFunc {
             return
         }
 
+        // Obtener el employee_id del token JWT: solo se gestionan empleados de rango inferior
+        actorID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
         // Generar una nueva contraseña aleatoria y actualizarla
-        newPassword, err := h.employeeSvc.UpdateEmployeePassword(employeeID)
+        newPassword, err := h.employeeSvc.UpdateEmployeePassword(employeeID, actorID)
         if err != nil {
+            if strings.Contains(err.Error(), "insufficient rank to manage this employee") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusForbidden)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Insufficient rank to manage this employee"})
+                return
+            }
             if strings.Contains(err.Error(), "employee not found") {
                 w.Header().Set("Content-Type", "application/json")
                 w.WriteHeader(http.StatusNotFound)
@@ -177,4 +249,288 @@ func (h *EmployeeHandler) UpdateEmployeePasswordHandler() http.HandlerFunc {
         w.WriteHeader(http.StatusOK)
         json.NewEncoder(w).Encode(response)
     }
-}
\ No newline at end of file
+}
+
+// GetMeHandler devuelve el perfil del empleado autenticado
+func (h *EmployeeHandler) GetMeHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        // Obtener el employee_id del token JWT
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        employee, err := h.employeeSvc.GetEmployee(employeeID)
+        if err != nil {
+            if strings.Contains(err.Error(), "employee not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee not found"})
+                return
+            }
+            log.Printf("Error getting profile: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(employee)
+    }
+}
+
+// UpdateMeHandler actualiza el email y el teléfono del empleado autenticado
+func (h *EmployeeHandler) UpdateMeHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        var profile models.ProfileUpdateRequest
+        if err := json.NewDecoder(r.Body).Decode(&profile); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+
+        // Obtener el employee_id del token JWT: solo se edita el propio perfil
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        updatedEmployee, err := h.employeeSvc.UpdateProfile(employeeID, profile)
+        if err != nil {
+            if writeValidationError(w, err) {
+                return
+            }
+            if strings.Contains(err.Error(), "employee not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee not found"})
+                return
+            }
+            log.Printf("Error updating profile: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(updatedEmployee)
+    }
+}
+
+// DeactivateEmployeeHandler da de baja a un empleado; opcionalmente indica a quién pasar sus tareas abiertas
+func (h *EmployeeHandler) DeactivateEmployeeHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        employeeID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid employee ID", http.StatusBadRequest)
+            return
+        }
+
+        // El cuerpo es opcional: sin reassign_tasks_to las tareas abiertas se cancelan
+        var request models.EmployeeDeactivationRequest
+        if err := json.NewDecoder(r.Body).Decode(&request); err != nil && err != io.EOF {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+
+        // Obtener el employee_id del token JWT
+        deactivatedBy, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        result, err := h.employeeSvc.DeactivateEmployee(employeeID, deactivatedBy, request)
+        if err != nil {
+            if strings.Contains(err.Error(), "cannot deactivate yourself") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Cannot deactivate yourself"})
+                return
+            }
+            if strings.Contains(err.Error(), "cannot reassign tasks to the same employee") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Cannot reassign tasks to the same employee"})
+                return
+            }
+            if strings.Contains(err.Error(), "reassignment target not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Reassignment target not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "reassignment target is inactive") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Reassignment target is inactive"})
+                return
+            }
+            if strings.Contains(err.Error(), "tasks can only be assigned to employees with role 'empleado'") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Tasks can only be assigned to employees with role 'empleado'"})
+                return
+            }
+            if strings.Contains(err.Error(), "employee is already inactive") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee is already inactive"})
+                return
+            }
+            if strings.Contains(err.Error(), "insufficient rank to manage this employee") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusForbidden)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Insufficient rank to manage this employee"})
+                return
+            }
+            if strings.Contains(err.Error(), "employee not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee not found"})
+                return
+            }
+            log.Printf("Error deactivating employee: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(result)
+    }
+}
+
+// ReactivateEmployeeHandler vuelve a habilitar a un empleado dado de baja
+func (h *EmployeeHandler) ReactivateEmployeeHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        employeeID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid employee ID", http.StatusBadRequest)
+            return
+        }
+
+        // Obtener el employee_id del token JWT: solo se gestionan empleados de rango inferior
+        actorID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        employee, err := h.employeeSvc.ReactivateEmployee(employeeID, actorID)
+        if err != nil {
+            if strings.Contains(err.Error(), "insufficient rank to manage this employee") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusForbidden)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Insufficient rank to manage this employee"})
+                return
+            }
+            if strings.Contains(err.Error(), "employee is already active") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee is already active"})
+                return
+            }
+            if strings.Contains(err.Error(), "employee not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee not found"})
+                return
+            }
+            log.Printf("Error reactivating employee: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(employee)
+    }
+}
+
+// TransferOwnershipHandler traspasa la propiedad del negocio al empleado indicado; el dueño actual
+// pasa a administrador y ambos deben iniciar sesión de nuevo
+func (h *EmployeeHandler) TransferOwnershipHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        newOwnerID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid employee ID", http.StatusBadRequest)
+            return
+        }
+
+        // Obtener el employee_id del token JWT: solo el dueño actual puede traspasar la propiedad
+        ownerID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        transfer, err := h.employeeSvc.TransferOwnership(ownerID, newOwnerID)
+        if err != nil {
+            if strings.Contains(err.Error(), "only the owner can transfer ownership") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusForbidden)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Only the owner can transfer ownership"})
+                return
+            }
+            if strings.Contains(err.Error(), "cannot transfer ownership to yourself") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Cannot transfer ownership to yourself"})
+                return
+            }
+            if strings.Contains(err.Error(), "employee is already an owner") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee is already an owner"})
+                return
+            }
+            if strings.Contains(err.Error(), "employee is inactive") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee is inactive"})
+                return
+            }
+            if strings.Contains(err.Error(), "employee not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee not found"})
+                return
+            }
+            log.Printf("Error transferring ownership: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(transfer)
+    }
+}
+
+// ListAuditLogsHandler devuelve el historial de acciones sobre un empleado
+func (h *EmployeeHandler) ListAuditLogsHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        employeeID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid employee ID", http.StatusBadRequest)
+            return
+        }
+
+        logs, err := h.employeeSvc.ListAuditLogs(employeeID)
+        if err != nil {
+            if strings.Contains(err.Error(), "employee not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee not found"})
+                return
+            }
+            log.Printf("Error listing employee audit logs: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(logs)
+    }
+}
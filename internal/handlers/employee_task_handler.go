@@ -50,6 +50,12 @@ func (h *EmployeeTaskHandler) CreateTaskHandler() http.HandlerFunc {
                 json.NewEncoder(w).Encode(map[string]string{"error": "Employee does not exist"})
                 return
             }
+            if strings.Contains(err.Error(), "employee is inactive") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee is inactive"})
+                return
+            }
             // Para otros errores inesperados, mantener el código 500
             log.Printf("Error creating task: %v", err)
             http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -162,6 +168,12 @@ func (h *EmployeeTaskHandler) UpdateTaskHandler() http.HandlerFunc {
                 json.NewEncoder(w).Encode(map[string]string{"error": "Employee does not exist"})
                 return
             }
+            if strings.Contains(err.Error(), "employee is inactive") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee is inactive"})
+                return
+            }
             // Verificar si el error es porque la tarea no existe
             if strings.Contains(err.Error(), "task not found") {
                 w.Header().Set("Content-Type", "application/json")
@@ -181,7 +193,8 @@ func (h *EmployeeTaskHandler) UpdateTaskHandler() http.HandlerFunc {
     }
 }
 
-// UpdateTaskStatusHandler maneja la solicitud para actualizar el estado de una tarea (admin y dueño)
+// UpdateTaskStatusHandler maneja la solicitud para cambiar el estado de una tarea (quien gestiona las
+// tareas o el empleado asignado)
 func (h *EmployeeTaskHandler) UpdateTaskStatusHandler() http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
         vars := mux.Vars(r)
@@ -200,20 +213,37 @@ func (h *EmployeeTaskHandler) UpdateTaskStatusHandler() http.HandlerFunc {
             return
         }
 
-        // Validar el estado
-        if request.Status != models.TaskStatusPending && request.Status != models.TaskStatusInProgress && request.Status != models.TaskStatusCompleted {
-            http.Error(w, "Invalid task status", http.StatusBadRequest)
+        // Obtener el employee_id del token JWT
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
             return
         }
 
-        err = h.employeeTaskSvc.UpdateTaskStatus(taskID, request.Status)
+        err = h.employeeTaskSvc.UpdateTaskStatus(taskID, request.Status, employeeID)
         if err != nil {
+            if strings.Contains(err.Error(), "invalid task status") {
+                http.Error(w, "Invalid task status", http.StatusBadRequest)
+                return
+            }
             if strings.Contains(err.Error(), "task not found") {
                 w.Header().Set("Content-Type", "application/json")
                 w.WriteHeader(http.StatusNotFound)
                 json.NewEncoder(w).Encode(map[string]string{"error": "Task not found"})
                 return
             }
+            if strings.Contains(err.Error(), "task is not assigned to you") || strings.Contains(err.Error(), "requires tasks.manage") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusForbidden)
+                json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+                return
+            }
+            if strings.Contains(err.Error(), "invalid status transition") || strings.Contains(err.Error(), "task status has changed") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+                return
+            }
             log.Printf("Error updating task status: %v", err)
             http.Error(w, "Internal server error", http.StatusInternalServerError)
             return
@@ -224,6 +254,47 @@ func (h *EmployeeTaskHandler) UpdateTaskStatusHandler() http.HandlerFunc {
     }
 }
 
+// GetTaskHistoryHandler devuelve los cambios de estado de una tarea
+func (h *EmployeeTaskHandler) GetTaskHistoryHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        taskID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid task ID", http.StatusBadRequest)
+            return
+        }
+
+        // Obtener el employee_id del token JWT
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        history, err := h.employeeTaskSvc.GetTaskHistory(taskID, employeeID)
+        if err != nil {
+            if strings.Contains(err.Error(), "task not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Task not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "task is not assigned to you") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusForbidden)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Task is not assigned to you"})
+                return
+            }
+            log.Printf("Error getting task history: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(history)
+    }
+}
+
 // DeleteTaskHandler maneja la solicitud para eliminar una tarea (admin y dueño)
 func (h *EmployeeTaskHandler) DeleteTaskHandler() http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
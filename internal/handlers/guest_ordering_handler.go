@@ -0,0 +1,240 @@
+package handlers
+
+import (
+    "encoding/json"
+    "log"
+    "net/http"
+    "strconv"
+    "strings"
+
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/internal/services"
+
+    "github.com/gorilla/mux"
+)
+
+// GuestOrderingHandler maneja las solicitudes del autopedido por QR en la mesa
+type GuestOrderingHandler struct {
+    guestOrderingSvc services.GuestOrderingService
+}
+
+// NewGuestOrderingHandler crea una nueva instancia del manejador de autopedido
+func NewGuestOrderingHandler(guestOrderingSvc services.GuestOrderingService) *GuestOrderingHandler {
+    return &GuestOrderingHandler{
+        guestOrderingSvc: guestOrderingSvc,
+    }
+}
+
+// GetTableQRTokenHandler devuelve el token firmado para el QR de una mesa
+func (h *GuestOrderingHandler) GetTableQRTokenHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        tableID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid table ID", http.StatusBadRequest)
+            return
+        }
+
+        qrToken, err := h.guestOrderingSvc.GetTableQRToken(tableID)
+        if err != nil {
+            if strings.Contains(err.Error(), "table not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Table not found"})
+                return
+            }
+            log.Printf("Error getting table QR token: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(qrToken)
+    }
+}
+
+// RotateTableQRTokenHandler invalida el QR actual de una mesa y genera uno nuevo
+func (h *GuestOrderingHandler) RotateTableQRTokenHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        tableID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid table ID", http.StatusBadRequest)
+            return
+        }
+
+        qrToken, err := h.guestOrderingSvc.RotateTableQRToken(tableID)
+        if err != nil {
+            if strings.Contains(err.Error(), "table not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Table not found"})
+                return
+            }
+            log.Printf("Error rotating table QR token: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(qrToken)
+    }
+}
+
+// StartGuestSessionHandler canjea el token del QR por una sesión de cliente
+func (h *GuestOrderingHandler) StartGuestSessionHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        var request models.GuestSessionRequest
+        if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+
+        session, err := h.guestOrderingSvc.StartSession(request.Token)
+        if err != nil {
+            if strings.Contains(err.Error(), "invalid table token") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusUnauthorized)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Invalid table token"})
+                return
+            }
+            if strings.Contains(err.Error(), "table is inactive") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusForbidden)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Table is not accepting orders"})
+                return
+            }
+            log.Printf("Error starting guest session: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusCreated)
+        json.NewEncoder(w).Encode(session)
+    }
+}
+
+// GetGuestOrderHandler devuelve el pedido pendiente de la mesa del cliente
+func (h *GuestOrderingHandler) GetGuestOrderHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        // Obtener la mesa de la sesión de cliente (del contexto, seteado por el middleware)
+        tableID, ok := r.Context().Value("table_id").(int)
+        if !ok {
+            http.Error(w, "Invalid table in token", http.StatusUnauthorized)
+            return
+        }
+        qrVersion, _ := r.Context().Value("qr_version").(int)
+
+        order, err := h.guestOrderingSvc.GetTableOrder(tableID, qrVersion)
+        if err != nil {
+            if strings.Contains(err.Error(), "guest session has been revoked") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusUnauthorized)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Guest session has been revoked"})
+                return
+            }
+            if strings.Contains(err.Error(), "no pending customer order found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "No open order for this table"})
+                return
+            }
+            log.Printf("Error getting guest order: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(order)
+    }
+}
+
+// AddGuestOrderLineHandler agrega una línea al pedido de la mesa del cliente
+func (h *GuestOrderingHandler) AddGuestOrderLineHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        // Obtener la mesa de la sesión de cliente (del contexto, seteado por el middleware)
+        tableID, ok := r.Context().Value("table_id").(int)
+        if !ok {
+            http.Error(w, "Invalid table in token", http.StatusUnauthorized)
+            return
+        }
+        qrVersion, _ := r.Context().Value("qr_version").(int)
+
+        var request models.GuestOrderLineRequest
+        if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+
+        createdDetail, err := h.guestOrderingSvc.AddOrderLine(tableID, qrVersion, request)
+        if err != nil {
+            if strings.Contains(err.Error(), "guest session has been revoked") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusUnauthorized)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Guest session has been revoked"})
+                return
+            }
+            if strings.Contains(err.Error(), "menu_item_id is required") || strings.Contains(err.Error(), "quantity must be greater than 0") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+                return
+            }
+            if strings.Contains(err.Error(), "failed to find menu item") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "menu item not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "insufficient stock for item") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+                return
+            }
+            log.Printf("Error adding guest order line: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusCreated)
+        json.NewEncoder(w).Encode(createdDetail)
+    }
+}
+
+// RequestGuestBillHandler pide la cuenta del pedido de la mesa del cliente
+func (h *GuestOrderingHandler) RequestGuestBillHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        // Obtener la mesa de la sesión de cliente (del contexto, seteado por el middleware)
+        tableID, ok := r.Context().Value("table_id").(int)
+        if !ok {
+            http.Error(w, "Invalid table in token", http.StatusUnauthorized)
+            return
+        }
+        qrVersion, _ := r.Context().Value("qr_version").(int)
+
+        order, err := h.guestOrderingSvc.RequestBill(tableID, qrVersion)
+        if err != nil {
+            if strings.Contains(err.Error(), "guest session has been revoked") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusUnauthorized)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Guest session has been revoked"})
+                return
+            }
+            if strings.Contains(err.Error(), "no pending customer order found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "No open order for this table"})
+                return
+            }
+            log.Printf("Error requesting guest bill: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(order)
+    }
+}
@@ -0,0 +1,303 @@
+package handlers
+
+import (
+    "encoding/json"
+    "log"
+    "net/http"
+    "strconv"
+    "strings"
+
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/internal/services"
+
+    "github.com/gorilla/mux"
+)
+
+// IngredientHandler maneja las solicitudes de ingredientes y recetas
+type IngredientHandler struct {
+    ingredientSvc services.IngredientService
+}
+
+func NewIngredientHandler(ingredientSvc services.IngredientService) *IngredientHandler {
+    return &IngredientHandler{
+        ingredientSvc: ingredientSvc,
+    }
+}
+
+func (h *IngredientHandler) CreateIngredientHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        var ingredient models.Ingredient
+        if err := json.NewDecoder(r.Body).Decode(&ingredient); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+
+        // Obtener el employee_id del token JWT para registrar el movimiento de stock
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        createdIngredient, err := h.ingredientSvc.CreateIngredient(ingredient, employeeID)
+        if err != nil {
+            if strings.Contains(err.Error(), "ingredient name cannot be empty") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Ingredient name cannot be empty"})
+                return
+            }
+            if strings.Contains(err.Error(), "ingredient name already exists") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Ingredient name already exists"})
+                return
+            }
+            if strings.Contains(err.Error(), "invalid ingredient unit") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Unit must be g, ml or unit"})
+                return
+            }
+            if strings.Contains(err.Error(), "stock cannot be negative") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Stock cannot be negative"})
+                return
+            }
+            log.Printf("Error creating ingredient: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusCreated)
+        json.NewEncoder(w).Encode(createdIngredient)
+    }
+}
+
+func (h *IngredientHandler) GetIngredientHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        ingredientID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid ingredient ID", http.StatusBadRequest)
+            return
+        }
+
+        ingredient, err := h.ingredientSvc.GetIngredient(ingredientID)
+        if err != nil {
+            if strings.Contains(err.Error(), "ingredient not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Ingredient not found"})
+                return
+            }
+            log.Printf("Error getting ingredient: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(ingredient)
+    }
+}
+
+func (h *IngredientHandler) ListIngredientsHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        ingredients, err := h.ingredientSvc.ListIngredients()
+        if err != nil {
+            log.Printf("Error listing ingredients: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(ingredients)
+    }
+}
+
+func (h *IngredientHandler) UpdateIngredientHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        ingredientID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid ingredient ID", http.StatusBadRequest)
+            return
+        }
+
+        var ingredient models.Ingredient
+        if err := json.NewDecoder(r.Body).Decode(&ingredient); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+        ingredient.ID = ingredientID
+
+        // Obtener el employee_id del token JWT para registrar el movimiento de stock
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        updatedIngredient, err := h.ingredientSvc.UpdateIngredient(ingredient, employeeID)
+        if err != nil {
+            if strings.Contains(err.Error(), "ingredient name cannot be empty") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Ingredient name cannot be empty"})
+                return
+            }
+            if strings.Contains(err.Error(), "ingredient name already exists") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Ingredient name already exists"})
+                return
+            }
+            if strings.Contains(err.Error(), "invalid ingredient unit") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Unit must be g, ml or unit"})
+                return
+            }
+            if strings.Contains(err.Error(), "stock cannot be negative") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Stock cannot be negative"})
+                return
+            }
+            if strings.Contains(err.Error(), "ingredient not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Ingredient not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "resulting stock cannot be negative") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Resulting stock cannot be negative"})
+                return
+            }
+            log.Printf("Error updating ingredient: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusOK)
+        json.NewEncoder(w).Encode(updatedIngredient)
+    }
+}
+
+func (h *IngredientHandler) DeleteIngredientHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        ingredientID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid ingredient ID", http.StatusBadRequest)
+            return
+        }
+
+        err = h.ingredientSvc.DeleteIngredient(ingredientID)
+        if err != nil {
+            if strings.Contains(err.Error(), "ingredient not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Ingredient not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "ingredient is used in a recipe") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Ingredient is used in a recipe"})
+                return
+            }
+            log.Printf("Error deleting ingredient: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.WriteHeader(http.StatusOK)
+        w.Write([]byte("Ingredient deleted successfully"))
+    }
+}
+
+func (h *IngredientHandler) GetRecipeHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        itemID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid item ID", http.StatusBadRequest)
+            return
+        }
+
+        recipe, err := h.ingredientSvc.GetRecipe(itemID)
+        if err != nil {
+            if strings.Contains(err.Error(), "item not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Item not found"})
+                return
+            }
+            log.Printf("Error getting recipe: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(recipe)
+    }
+}
+
+// SetRecipeHandler reemplaza la receta del ítem con la lista de ingredientes y cantidades enviada
+func (h *IngredientHandler) SetRecipeHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        itemID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid item ID", http.StatusBadRequest)
+            return
+        }
+
+        var items []models.RecipeItem
+        if err := json.NewDecoder(r.Body).Decode(&items); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+
+        recipe, err := h.ingredientSvc.SetRecipe(itemID, items)
+        if err != nil {
+            if strings.Contains(err.Error(), "item not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Item not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "ingredient not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Ingredient not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "recipe quantity must be greater than 0") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Recipe quantity must be greater than 0"})
+                return
+            }
+            if strings.Contains(err.Error(), "ingredient repeated in recipe") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Ingredient repeated in recipe"})
+                return
+            }
+            log.Printf("Error saving recipe: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusOK)
+        json.NewEncoder(w).Encode(recipe)
+    }
+}
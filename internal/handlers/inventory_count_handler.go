@@ -0,0 +1,315 @@
+package handlers
+
+import (
+    "encoding/json"
+    "log"
+    "net/http"
+    "strconv"
+    "strings"
+
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/internal/services"
+
+    "github.com/gorilla/mux"
+)
+
+// InventoryCountHandler maneja las solicitudes de los conteos físicos de inventario
+type InventoryCountHandler struct {
+    inventoryCountSvc services.InventoryCountService
+}
+
+func NewInventoryCountHandler(inventoryCountSvc services.InventoryCountService) *InventoryCountHandler {
+    return &InventoryCountHandler{
+        inventoryCountSvc: inventoryCountSvc,
+    }
+}
+
+// OpenInventoryCountHandler abre una sesión de conteo físico (solo puede haber una abierta)
+func (h *InventoryCountHandler) OpenInventoryCountHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        var request struct {
+            Notes string `json:"notes"`
+        }
+        if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+
+        // Obtener el employee_id del token JWT
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        count, err := h.inventoryCountSvc.OpenCount(request.Notes, employeeID)
+        if err != nil {
+            if strings.Contains(err.Error(), "an inventory count is already open") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "An inventory count is already open"})
+                return
+            }
+            log.Printf("Error opening inventory count: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusCreated)
+        json.NewEncoder(w).Encode(count)
+    }
+}
+
+func (h *InventoryCountHandler) ListInventoryCountsHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        counts, err := h.inventoryCountSvc.ListCounts()
+        if err != nil {
+            log.Printf("Error listing inventory counts: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(counts)
+    }
+}
+
+func (h *InventoryCountHandler) GetInventoryCountHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        inventoryCountID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid inventory count ID", http.StatusBadRequest)
+            return
+        }
+
+        count, err := h.inventoryCountSvc.GetCount(inventoryCountID)
+        if err != nil {
+            if strings.Contains(err.Error(), "inventory count not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Inventory count not found"})
+                return
+            }
+            log.Printf("Error getting inventory count: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(count)
+    }
+}
+
+// RecordInventoryCountEntryHandler registra (o reemplaza) la cantidad que el empleado contó de un ítem
+func (h *InventoryCountHandler) RecordInventoryCountEntryHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        inventoryCountID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid inventory count ID", http.StatusBadRequest)
+            return
+        }
+
+        var entry models.InventoryCountEntry
+        if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+        entry.InventoryCountID = inventoryCountID
+
+        // Cada empleado registra su propio conteo; el employee_id se toma del token JWT
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+        entry.EmployeeID = employeeID
+
+        savedEntry, err := h.inventoryCountSvc.RecordEntry(entry)
+        if err != nil {
+            if strings.Contains(err.Error(), "inventory count not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Inventory count not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "inventory count is not open") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Inventory count is not open"})
+                return
+            }
+            if strings.Contains(err.Error(), "counted quantity cannot be negative") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Counted quantity cannot be negative"})
+                return
+            }
+            if strings.Contains(err.Error(), "item not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Item not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "item stock is derived from its recipe ingredients") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Item stock is derived from its recipe ingredients"})
+                return
+            }
+            log.Printf("Error recording inventory count entry: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusOK)
+        json.NewEncoder(w).Encode(savedEntry)
+    }
+}
+
+// GetInventoryCountVariancesHandler compara lo contado con el stock del sistema para revisar las diferencias
+func (h *InventoryCountHandler) GetInventoryCountVariancesHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        inventoryCountID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid inventory count ID", http.StatusBadRequest)
+            return
+        }
+
+        variances, err := h.inventoryCountSvc.GetVariances(inventoryCountID)
+        if err != nil {
+            if strings.Contains(err.Error(), "inventory count not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Inventory count not found"})
+                return
+            }
+            log.Printf("Error getting inventory count variances: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(variances)
+    }
+}
+
+// ApproveInventoryCountHandler aprueba el conteo y corrige el stock de los ítems con diferencias
+func (h *InventoryCountHandler) ApproveInventoryCountHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        inventoryCountID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid inventory count ID", http.StatusBadRequest)
+            return
+        }
+
+        // Obtener el employee_id del token JWT
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        count, err := h.inventoryCountSvc.ApproveCount(inventoryCountID, employeeID)
+        if err != nil {
+            if strings.Contains(err.Error(), "inventory count not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Inventory count not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "inventory count is not open") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Inventory count is not open"})
+                return
+            }
+            if strings.Contains(err.Error(), "inventory count has no entries") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Inventory count has no entries"})
+                return
+            }
+            log.Printf("Error approving inventory count: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusOK)
+        json.NewEncoder(w).Encode(count)
+    }
+}
+
+func (h *InventoryCountHandler) CancelInventoryCountHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        inventoryCountID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid inventory count ID", http.StatusBadRequest)
+            return
+        }
+
+        count, err := h.inventoryCountSvc.CancelCount(inventoryCountID)
+        if err != nil {
+            if strings.Contains(err.Error(), "inventory count not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Inventory count not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "inventory count is not open") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Inventory count is not open"})
+                return
+            }
+            log.Printf("Error cancelling inventory count: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusOK)
+        json.NewEncoder(w).Encode(count)
+    }
+}
+
+// GetInventoryShrinkageReportHandler devuelve el reporte de faltantes de un conteo aprobado
+func (h *InventoryCountHandler) GetInventoryShrinkageReportHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        inventoryCountID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid inventory count ID", http.StatusBadRequest)
+            return
+        }
+
+        report, err := h.inventoryCountSvc.GetShrinkageReport(inventoryCountID)
+        if err != nil {
+            if strings.Contains(err.Error(), "inventory count not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Inventory count not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "inventory count is not approved") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Inventory count is not approved"})
+                return
+            }
+            log.Printf("Error getting shrinkage report: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(report)
+    }
+}
@@ -31,7 +31,14 @@ func (h *MenuItemHandler) CreateMenuItemHandler() http.HandlerFunc {
             return
         }
 
-        createdItem, err := h.menuItemSvc.CreateMenuItem(item)
+        // Obtener el employee_id del token JWT para registrar el movimiento de stock inicial
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        createdItem, err := h.menuItemSvc.CreateMenuItem(item, employeeID)
         if err != nil {
             if strings.Contains(err.Error(), "item name cannot be empty") {
                 w.Header().Set("Content-Type", "application/json")
@@ -63,6 +70,18 @@ func (h *MenuItemHandler) CreateMenuItemHandler() http.HandlerFunc {
                 json.NewEncoder(w).Encode(map[string]string{"error": "Stock cannot be negative"})
                 return
             }
+            if strings.Contains(err.Error(), "invalid stock mode") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Stock mode must be item or recipe"})
+                return
+            }
+            if strings.Contains(err.Error(), "reorder threshold cannot be negative") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Reorder threshold cannot be negative"})
+                return
+            }
             log.Printf("Error creating item: %v", err)
             http.Error(w, "Internal server error", http.StatusInternalServerError)
             return
@@ -116,6 +135,21 @@ func (h *MenuItemHandler) ListMenuItemsHandler() http.HandlerFunc {
     }
 }
 
+// ListLowStockItemsHandler lista los ítems por debajo de su umbral de reposición
+func (h *MenuItemHandler) ListLowStockItemsHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        items, err := h.menuItemSvc.ListLowStockItems()
+        if err != nil {
+            log.Printf("Error listing low stock items: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(items)
+    }
+}
+
 func (h *MenuItemHandler) UpdateMenuItemHandler() http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
         vars := mux.Vars(r)
@@ -133,7 +167,14 @@ func (h *MenuItemHandler) UpdateMenuItemHandler() http.HandlerFunc {
         }
         item.ID = itemID
 
-        updatedItem, err := h.menuItemSvc.UpdateMenuItem(item)
+        // Obtener el employee_id del token JWT para registrar quién cambió el precio
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        updatedItem, err := h.menuItemSvc.UpdateMenuItem(item, employeeID)
         if err != nil {
             if strings.Contains(err.Error(), "item name cannot be empty") {
                 w.Header().Set("Content-Type", "application/json")
@@ -165,6 +206,18 @@ func (h *MenuItemHandler) UpdateMenuItemHandler() http.HandlerFunc {
                 json.NewEncoder(w).Encode(map[string]string{"error": "Stock cannot be negative"})
                 return
             }
+            if strings.Contains(err.Error(), "invalid stock mode") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Stock mode must be item or recipe"})
+                return
+            }
+            if strings.Contains(err.Error(), "reorder threshold cannot be negative") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Reorder threshold cannot be negative"})
+                return
+            }
             if strings.Contains(err.Error(), "item not found") {
                 w.Header().Set("Content-Type", "application/json")
                 w.WriteHeader(http.StatusNotFound)
@@ -200,6 +253,12 @@ func (h *MenuItemHandler) DeleteMenuItemHandler() http.HandlerFunc {
                 json.NewEncoder(w).Encode(map[string]string{"error": "Item not found"})
                 return
             }
+            if strings.Contains(err.Error(), "item has stock history") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Item has stock history and cannot be deleted"})
+                return
+            }
             log.Printf("Error deleting item: %v", err)
             http.Error(w, "Internal server error", http.StatusInternalServerError)
             return
@@ -208,4 +267,249 @@ func (h *MenuItemHandler) DeleteMenuItemHandler() http.HandlerFunc {
         w.WriteHeader(http.StatusOK)
         w.Write([]byte("Item deleted successfully"))
     }
-}
\ No newline at end of file
+}
+
+func (h *MenuItemHandler) GetPriceHistoryHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        itemIDStr := vars["id"]
+        itemID, err := strconv.Atoi(itemIDStr)
+        if err != nil {
+            http.Error(w, "Invalid item ID", http.StatusBadRequest)
+            return
+        }
+
+        history, err := h.menuItemSvc.GetPriceHistory(itemID)
+        if err != nil {
+            if strings.Contains(err.Error(), "item not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Item not found"})
+                return
+            }
+            log.Printf("Error getting price history: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(history)
+    }
+}
+
+func (h *MenuItemHandler) SchedulePriceChangeHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        itemIDStr := vars["id"]
+        itemID, err := strconv.Atoi(itemIDStr)
+        if err != nil {
+            http.Error(w, "Invalid item ID", http.StatusBadRequest)
+            return
+        }
+
+        var change models.ScheduledPriceChange
+        if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+        change.MenuItemID = itemID
+
+        // Obtener el employee_id del token JWT (del contexto, seteado por el middleware)
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+        change.CreatedBy = employeeID
+
+        createdChange, err := h.menuItemSvc.SchedulePriceChange(change)
+        if err != nil {
+            if strings.Contains(err.Error(), "item not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Item not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "price must be greater than 0") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Price must be greater than 0"})
+                return
+            }
+            if strings.Contains(err.Error(), "effective date must be in the future") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Effective date must be in the future"})
+                return
+            }
+            log.Printf("Error scheduling price change: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusCreated)
+        json.NewEncoder(w).Encode(createdChange)
+    }
+}
+
+func (h *MenuItemHandler) ListScheduledPriceChangesHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        itemIDStr := vars["id"]
+        itemID, err := strconv.Atoi(itemIDStr)
+        if err != nil {
+            http.Error(w, "Invalid item ID", http.StatusBadRequest)
+            return
+        }
+
+        changes, err := h.menuItemSvc.ListScheduledPriceChanges(itemID)
+        if err != nil {
+            if strings.Contains(err.Error(), "item not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Item not found"})
+                return
+            }
+            log.Printf("Error listing scheduled price changes: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(changes)
+    }
+}
+
+func (h *MenuItemHandler) CancelScheduledPriceChangeHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        itemID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid item ID", http.StatusBadRequest)
+            return
+        }
+        changeID, err := strconv.Atoi(vars["change_id"])
+        if err != nil {
+            http.Error(w, "Invalid price change ID", http.StatusBadRequest)
+            return
+        }
+
+        err = h.menuItemSvc.CancelScheduledPriceChange(itemID, changeID)
+        if err != nil {
+            if strings.Contains(err.Error(), "scheduled price change not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Scheduled price change not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "only pending price changes can be cancelled") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Only pending price changes can be cancelled"})
+                return
+            }
+            log.Printf("Error cancelling scheduled price change: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.WriteHeader(http.StatusOK)
+        w.Write([]byte("Scheduled price change cancelled successfully"))
+    }
+}
+
+// MaxMenuImportSize limita el tamaño del archivo de importación del menú (2 MB)
+const MaxMenuImportSize = 2 << 20
+
+// menuFormat determina el formato a partir del parámetro ?format= o, en su defecto, del Content-Type
+func menuFormat(r *http.Request) string {
+    if format := strings.ToLower(r.URL.Query().Get("format")); format != "" {
+        return format
+    }
+    if strings.Contains(r.Header.Get("Content-Type"), "csv") {
+        return services.MenuFormatCSV
+    }
+    return services.MenuFormatJSON
+}
+
+func (h *MenuItemHandler) ImportMenuItemsHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        // Obtener el employee_id del token JWT para registrar los cambios de precio
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        dryRun := false
+        if value := r.URL.Query().Get("dry_run"); value != "" {
+            parsed, err := strconv.ParseBool(value)
+            if err != nil {
+                http.Error(w, "Invalid dry_run value", http.StatusBadRequest)
+                return
+            }
+            dryRun = parsed
+        }
+
+        body := http.MaxBytesReader(w, r.Body, MaxMenuImportSize)
+        result, err := h.menuItemSvc.ImportMenuItems(menuFormat(r), body, dryRun, employeeID)
+        if err != nil {
+            if strings.Contains(err.Error(), "import has invalid rows") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusUnprocessableEntity)
+                json.NewEncoder(w).Encode(result)
+                return
+            }
+            if strings.Contains(err.Error(), "unsupported import format") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Unsupported import format, use csv or json"})
+                return
+            }
+            if strings.Contains(err.Error(), "import file has no rows") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Import file has no rows"})
+                return
+            }
+            if strings.Contains(err.Error(), "invalid csv file") || strings.Contains(err.Error(), "invalid json file") || strings.Contains(err.Error(), "csv header must include") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Invalid import file: " + err.Error()})
+                return
+            }
+            log.Printf("Error importing items: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusOK)
+        json.NewEncoder(w).Encode(result)
+    }
+}
+
+func (h *MenuItemHandler) ExportMenuItemsHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        format := menuFormat(r)
+        switch format {
+        case services.MenuFormatCSV:
+            w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+            w.Header().Set("Content-Disposition", `attachment; filename="menu.csv"`)
+        case services.MenuFormatJSON:
+            w.Header().Set("Content-Type", "application/json")
+        default:
+            w.Header().Set("Content-Type", "application/json")
+            w.WriteHeader(http.StatusBadRequest)
+            json.NewEncoder(w).Encode(map[string]string{"error": "Unsupported export format, use csv or json"})
+            return
+        }
+
+        if err := h.menuItemSvc.ExportMenuItems(format, w); err != nil {
+            log.Printf("Error exporting items: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+    }
+}
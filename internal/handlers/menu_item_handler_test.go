@@ -0,0 +1,57 @@
+package handlers
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "gastrobar-backend/internal/repositories"
+    "gastrobar-backend/internal/services"
+
+    "github.com/gorilla/mux"
+    "github.com/pkg/errors"
+)
+
+// stubMenuItemRepository simula la eliminación de ítems: el 1 tiene movimientos de stock y el 2 no existe
+type stubMenuItemRepository struct {
+    repositories.MenuItemRepository
+    deleted []int
+}
+
+func (r *stubMenuItemRepository) Delete(itemID int) error {
+    switch itemID {
+    case 1:
+        return errors.New("item has stock history")
+    case 2:
+        return errors.New("item not found")
+    }
+    r.deleted = append(r.deleted, itemID)
+    return nil
+}
+
+// TestDeleteMenuItemHandler comprueba que un ítem con historial de stock responde 409 en lugar de
+// fallar con la violación de la FK de stock_movements
+func TestDeleteMenuItemHandler(t *testing.T) {
+    repo := &stubMenuItemRepository{}
+    handler := NewMenuItemHandler(services.NewMenuItemService(repo, nil)).DeleteMenuItemHandler()
+
+    for _, tc := range []struct {
+        id   string
+        want int
+    }{
+        {"1", http.StatusConflict},
+        {"2", http.StatusNotFound},
+        {"3", http.StatusOK},
+        {"x", http.StatusBadRequest},
+    } {
+        req := mux.SetURLVars(httptest.NewRequest("DELETE", "/menu-items/"+tc.id, nil), map[string]string{"id": tc.id})
+        rec := httptest.NewRecorder()
+        handler.ServeHTTP(rec, req)
+        if rec.Code != tc.want {
+            t.Errorf("DELETE /menu-items/%s: got status %d, want %d", tc.id, rec.Code, tc.want)
+        }
+    }
+    if len(repo.deleted) != 1 || repo.deleted[0] != 3 {
+        t.Errorf("deleted items: got %v, want [3]", repo.deleted)
+    }
+}
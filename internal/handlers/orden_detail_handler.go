@@ -58,11 +58,19 @@ func (h *OrderDetailHandler) CreateOrderDetailHandler() http.HandlerFunc {
             return
         }
 
+        // Obtener el employee_id del token JWT
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
         // Crear el order_detail
         orderDetail := models.OrderDetail{
             OrderID:    request.OrderID,
             MenuItemID: request.MenuItemID,
             Quantity:   request.Quantity,
+            CreatedBy:  &employeeID,
         }
         createdDetail, err := h.orderDetailSvc.CreateOrderDetail(orderDetail, request.TableID)
         if err != nil {
@@ -72,6 +80,12 @@ func (h *OrderDetailHandler) CreateOrderDetailHandler() http.HandlerFunc {
                 json.NewEncoder(w).Encode(map[string]string{"error": "table not found"})
                 return
             }
+            if strings.Contains(err.Error(), "table is inactive") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "table is inactive"})
+                return
+            }
             if strings.Contains(err.Error(), "failed to find menu item") {
                 w.Header().Set("Content-Type", "application/json")
                 w.WriteHeader(http.StatusNotFound)
@@ -299,4 +313,62 @@ func (h *OrderDetailHandler) DeleteOrderDetailHandler() http.HandlerFunc {
 
         w.WriteHeader(http.StatusNoContent)
     }
-}
\ No newline at end of file
+}
+
+func (h *OrderDetailHandler) ListPendingConfirmationHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        orderDetails, err := h.orderDetailSvc.ListPendingConfirmation()
+        if err != nil {
+            log.Printf("Error listing order details pending confirmation: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusOK)
+        json.NewEncoder(w).Encode(orderDetails)
+    }
+}
+
+func (h *OrderDetailHandler) ConfirmOrderDetailHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        idStr := vars["id"]
+        id, err := strconv.Atoi(idStr)
+        if err != nil {
+            w.Header().Set("Content-Type", "application/json")
+            w.WriteHeader(http.StatusBadRequest)
+            json.NewEncoder(w).Encode(map[string]string{"error": "invalid order detail ID"})
+            return
+        }
+
+        confirmedDetail, err := h.orderDetailSvc.ConfirmOrderDetail(id)
+        if err != nil {
+            if strings.Contains(err.Error(), "order detail not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "order detail not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "order detail is not pending confirmation") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+                return
+            }
+            if strings.Contains(err.Error(), "customer order is already completed") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+                return
+            }
+            log.Printf("Error confirming order detail: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusOK)
+        json.NewEncoder(w).Encode(confirmedDetail)
+    }
+}
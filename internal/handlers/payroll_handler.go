@@ -0,0 +1,425 @@
+package handlers
+
+import (
+    "encoding/json"
+    "fmt"
+    "log"
+    "net/http"
+    "strconv"
+    "strings"
+
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/internal/services"
+
+    "github.com/gorilla/mux"
+)
+
+// PayrollHandler maneja las tarifas, parámetros, festivos, propinas y la liquidación de la nómina
+type PayrollHandler struct {
+    payrollSvc services.PayrollService
+}
+
+func NewPayrollHandler(payrollSvc services.PayrollService) *PayrollHandler {
+    return &PayrollHandler{
+        payrollSvc: payrollSvc,
+    }
+}
+
+// ListRatesHandler devuelve a todos los empleados con su tarifa por hora (NULL si no tienen)
+func (h *PayrollHandler) ListRatesHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        rates, err := h.payrollSvc.ListRates()
+        if err != nil {
+            log.Printf("Error listing pay rates: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(rates)
+    }
+}
+
+func (h *PayrollHandler) SetRateHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        employeeID, err := strconv.Atoi(vars["employee_id"])
+        if err != nil {
+            http.Error(w, "Invalid employee ID", http.StatusBadRequest)
+            return
+        }
+
+        var request models.PayRateRequest
+        if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+
+        // Obtener el employee_id del token JWT para registrar quién fijó la tarifa
+        updatedBy, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        rate, err := h.payrollSvc.SetRate(employeeID, request.HourlyRate, updatedBy)
+        if err != nil {
+            if strings.Contains(err.Error(), "hourly rate cannot be negative") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Hourly rate cannot be negative"})
+                return
+            }
+            if strings.Contains(err.Error(), "employee not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee not found"})
+                return
+            }
+            log.Printf("Error setting pay rate: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(rate)
+    }
+}
+
+func (h *PayrollHandler) GetSettingsHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        settings, err := h.payrollSvc.GetSettings()
+        if err != nil {
+            if strings.Contains(err.Error(), "payroll settings not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Payroll settings not found"})
+                return
+            }
+            log.Printf("Error getting payroll settings: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(settings)
+    }
+}
+
+// UpdateSettingsHandler reemplaza el umbral de horas extra, la franja nocturna y los recargos
+func (h *PayrollHandler) UpdateSettingsHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        var settings models.PayrollSettings
+        if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+
+        // Obtener el employee_id del token JWT
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        updatedSettings, err := h.payrollSvc.UpdateSettings(settings, employeeID)
+        if err != nil {
+            if strings.Contains(err.Error(), "daily overtime hours must be between 0 and 24") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Daily overtime hours must be between 0 and 24"})
+                return
+            }
+            if strings.Contains(err.Error(), "surcharges cannot be negative") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Surcharges cannot be negative"})
+                return
+            }
+            if strings.Contains(err.Error(), "invalid night start") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Invalid night start, use HH:MM"})
+                return
+            }
+            if strings.Contains(err.Error(), "invalid night end") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Invalid night end, use HH:MM"})
+                return
+            }
+            if strings.Contains(err.Error(), "night start and end must differ") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Night start and end must differ"})
+                return
+            }
+            log.Printf("Error updating payroll settings: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(updatedSettings)
+    }
+}
+
+// ListHolidaysHandler devuelve los festivos; acepta from y to opcionales
+func (h *PayrollHandler) ListHolidaysHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        from, to, ok := parseReportRange(r)
+        if !ok {
+            http.Error(w, "Invalid date range, use YYYY-MM-DD or RFC3339", http.StatusBadRequest)
+            return
+        }
+
+        holidays, err := h.payrollSvc.ListHolidays(from, to)
+        if err != nil {
+            if strings.Contains(err.Error(), "from must be before to") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "From must be before to"})
+                return
+            }
+            log.Printf("Error listing holidays: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(holidays)
+    }
+}
+
+func (h *PayrollHandler) AddHolidayHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        var holiday models.Holiday
+        if err := json.NewDecoder(r.Body).Decode(&holiday); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+
+        createdHoliday, err := h.payrollSvc.AddHoliday(holiday)
+        if err != nil {
+            if strings.Contains(err.Error(), "invalid holiday date") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Invalid holiday date, use YYYY-MM-DD"})
+                return
+            }
+            if strings.Contains(err.Error(), "holiday description cannot be empty") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Holiday description cannot be empty"})
+                return
+            }
+            if strings.Contains(err.Error(), "holiday already exists") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Holiday already exists"})
+                return
+            }
+            log.Printf("Error adding holiday: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusCreated)
+        json.NewEncoder(w).Encode(createdHoliday)
+    }
+}
+
+func (h *PayrollHandler) DeleteHolidayHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        err := h.payrollSvc.DeleteHoliday(vars["date"])
+        if err != nil {
+            if strings.Contains(err.Error(), "invalid holiday date") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Invalid holiday date, use YYYY-MM-DD"})
+                return
+            }
+            if strings.Contains(err.Error(), "holiday not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Holiday not found"})
+                return
+            }
+            log.Printf("Error deleting holiday: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.WriteHeader(http.StatusOK)
+        w.Write([]byte("Holiday deleted successfully"))
+    }
+}
+
+// ListTipsHandler devuelve las propinas recaudadas; acepta from y to opcionales
+func (h *PayrollHandler) ListTipsHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        from, to, ok := parseReportRange(r)
+        if !ok {
+            http.Error(w, "Invalid date range, use YYYY-MM-DD or RFC3339", http.StatusBadRequest)
+            return
+        }
+
+        tips, err := h.payrollSvc.ListTips(from, to)
+        if err != nil {
+            if strings.Contains(err.Error(), "from must be before to") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "From must be before to"})
+                return
+            }
+            log.Printf("Error listing tips: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(tips)
+    }
+}
+
+// AddTipHandler registra propinas recaudadas para el fondo que se reparte en la nómina
+func (h *PayrollHandler) AddTipHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        var entry models.TipPoolEntry
+        if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+
+        // Obtener el employee_id del token JWT
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        createdEntry, err := h.payrollSvc.AddTip(entry, employeeID)
+        if err != nil {
+            if strings.Contains(err.Error(), "invalid collection date") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Invalid collection date, use YYYY-MM-DD"})
+                return
+            }
+            if strings.Contains(err.Error(), "amount must be greater than 0") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Amount must be greater than 0"})
+                return
+            }
+            log.Printf("Error adding tip: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusCreated)
+        json.NewEncoder(w).Encode(createdEntry)
+    }
+}
+
+func (h *PayrollHandler) DeleteTipHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        entryID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid tip pool entry ID", http.StatusBadRequest)
+            return
+        }
+
+        err = h.payrollSvc.DeleteTip(entryID)
+        if err != nil {
+            if strings.Contains(err.Error(), "tip pool entry not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Tip pool entry not found"})
+                return
+            }
+            log.Printf("Error deleting tip: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.WriteHeader(http.StatusOK)
+        w.Write([]byte("Tip pool entry deleted successfully"))
+    }
+}
+
+// GetPayrollHandler liquida el periodo indicado (?from=&to=, por defecto la quincena actual)
+func (h *PayrollHandler) GetPayrollHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        from, to, ok := parseReportRange(r)
+        if !ok {
+            http.Error(w, "Invalid date range, use YYYY-MM-DD or RFC3339", http.StatusBadRequest)
+            return
+        }
+
+        report, err := h.payrollSvc.CalculatePayroll(from, to)
+        if err != nil {
+            if strings.Contains(err.Error(), "from must be before to") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "From must be before to"})
+                return
+            }
+            log.Printf("Error calculating payroll: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(report)
+    }
+}
+
+// ExportPayrollHandler descarga la liquidación del periodo para el contador (?format=csv o json)
+func (h *PayrollHandler) ExportPayrollHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        from, to, ok := parseReportRange(r)
+        if !ok {
+            http.Error(w, "Invalid date range, use YYYY-MM-DD or RFC3339", http.StatusBadRequest)
+            return
+        }
+
+        report, err := h.payrollSvc.CalculatePayroll(from, to)
+        if err != nil {
+            if strings.Contains(err.Error(), "from must be before to") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "From must be before to"})
+                return
+            }
+            log.Printf("Error calculating payroll: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        format := menuFormat(r)
+        switch format {
+        case services.MenuFormatCSV:
+            filename := fmt.Sprintf("payroll_%s_%s.csv", report.From.Format("2006-01-02"), report.To.AddDate(0, 0, -1).Format("2006-01-02"))
+            w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+            w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+        case services.MenuFormatJSON:
+            w.Header().Set("Content-Type", "application/json")
+        default:
+            w.Header().Set("Content-Type", "application/json")
+            w.WriteHeader(http.StatusBadRequest)
+            json.NewEncoder(w).Encode(map[string]string{"error": "Unsupported export format, use csv or json"})
+            return
+        }
+
+        if err := h.payrollSvc.ExportPayroll(report, format, w); err != nil {
+            log.Printf("Error exporting payroll: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+    }
+}
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+    "encoding/json"
+    "log"
+    "net/http"
+    "strings"
+
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/internal/services"
+
+    "github.com/gorilla/mux"
+)
+
+// PermissionHandler maneja el catálogo de permisos y los permisos concedidos a cada rol
+type PermissionHandler struct {
+    permissionSvc services.PermissionService
+}
+
+func NewPermissionHandler(permissionSvc services.PermissionService) *PermissionHandler {
+    return &PermissionHandler{
+        permissionSvc: permissionSvc,
+    }
+}
+
+func (h *PermissionHandler) ListPermissionsHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        permissions, err := h.permissionSvc.ListPermissions()
+        if err != nil {
+            log.Printf("Error listing permissions: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(permissions)
+    }
+}
+
+func (h *PermissionHandler) ListRolePermissionsHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        rolePermissions, err := h.permissionSvc.ListRolePermissions()
+        if err != nil {
+            log.Printf("Error listing role permissions: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(rolePermissions)
+    }
+}
+
+func (h *PermissionHandler) GetRolePermissionsHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        role := models.EmployeeRole(mux.Vars(r)["role"])
+
+        rolePermissions, err := h.permissionSvc.GetRolePermissions(role)
+        if err != nil {
+            if strings.Contains(err.Error(), "invalid role") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Role not found"})
+                return
+            }
+            log.Printf("Error getting role permissions: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(rolePermissions)
+    }
+}
+
+// SetRolePermissionsHandler reemplaza todos los permisos de un rol por los del cuerpo
+func (h *PermissionHandler) SetRolePermissionsHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        role := models.EmployeeRole(mux.Vars(r)["role"])
+
+        var request models.RolePermissionsRequest
+        if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+
+        // Obtener el employee_id del token JWT para registrar quién concedió los permisos
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        rolePermissions, err := h.permissionSvc.SetRolePermissions(role, request, employeeID)
+        if err != nil {
+            if strings.Contains(err.Error(), "invalid role") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Role not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "unknown permission") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+                return
+            }
+            if strings.Contains(err.Error(), "owner role must keep permissions.manage") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Owner role must keep permissions.manage"})
+                return
+            }
+            log.Printf("Error setting role permissions: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(rolePermissions)
+    }
+}
+
+// GetMyPermissionsHandler devuelve los permisos del rol del empleado autenticado (para que el cliente
+// muestre solo las acciones disponibles)
+func (h *PermissionHandler) GetMyPermissionsHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        role, ok := r.Context().Value("role").(string)
+        if !ok {
+            http.Error(w, "Role not found in token", http.StatusUnauthorized)
+            return
+        }
+
+        rolePermissions, err := h.permissionSvc.GetRolePermissions(models.EmployeeRole(role))
+        if err != nil {
+            log.Printf("Error getting own permissions: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(rolePermissions)
+    }
+}
@@ -0,0 +1,294 @@
+package handlers
+
+import (
+    "encoding/json"
+    "io"
+    "log"
+    "net/http"
+    "strconv"
+    "strings"
+
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/internal/services"
+
+    "github.com/gorilla/mux"
+)
+
+// PublicMenuHandler maneja las solicitudes de la carta digital y de su contenido (fotos, etiquetas e idiomas)
+type PublicMenuHandler struct {
+    publicMenuSvc services.PublicMenuService
+}
+
+// NewPublicMenuHandler crea una nueva instancia del manejador de la carta digital
+func NewPublicMenuHandler(publicMenuSvc services.PublicMenuService) *PublicMenuHandler {
+    return &PublicMenuHandler{
+        publicMenuSvc: publicMenuSvc,
+    }
+}
+
+// GetPublicMenuHandler devuelve la carta digital en el idioma indicado por Accept-Language
+func (h *PublicMenuHandler) GetPublicMenuHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        menu, err := h.publicMenuSvc.GetPublicMenu(r.Header.Get("Accept-Language"))
+        if err != nil {
+            log.Printf("Error getting public menu: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.Header().Set("Content-Language", menu.Locale)
+        w.Header().Set("Vary", "Accept-Language")
+        json.NewEncoder(w).Encode(menu)
+    }
+}
+
+// UploadMenuItemImageHandler recibe la foto de un ítem (multipart, campo "image")
+func (h *PublicMenuHandler) UploadMenuItemImageHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        itemID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid item ID", http.StatusBadRequest)
+            return
+        }
+
+        r.Body = http.MaxBytesReader(w, r.Body, services.MaxMenuImageSize+(1<<20))
+        file, _, err := r.FormFile("image")
+        if err != nil {
+            w.Header().Set("Content-Type", "application/json")
+            w.WriteHeader(http.StatusBadRequest)
+            json.NewEncoder(w).Encode(map[string]string{"error": "Image file is required"})
+            return
+        }
+        defer file.Close()
+
+        data, err := io.ReadAll(io.LimitReader(file, services.MaxMenuImageSize+1))
+        if err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+
+        image, err := h.publicMenuSvc.UploadMenuItemImage(itemID, data)
+        if err != nil {
+            if strings.Contains(err.Error(), "item not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Item not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "image file is required") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Image file is required"})
+                return
+            }
+            if strings.Contains(err.Error(), "image exceeds maximum size") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusRequestEntityTooLarge)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Image exceeds maximum size of 5MB"})
+                return
+            }
+            if strings.Contains(err.Error(), "image dimensions exceed maximum") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusRequestEntityTooLarge)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Image dimensions exceed maximum of 40 megapixels"})
+                return
+            }
+            if strings.Contains(err.Error(), "unsupported image format") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Unsupported image format, use JPEG or PNG"})
+                return
+            }
+            log.Printf("Error uploading menu item image: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusCreated)
+        json.NewEncoder(w).Encode(image)
+    }
+}
+
+// DeleteMenuItemImageHandler elimina la foto de un ítem
+func (h *PublicMenuHandler) DeleteMenuItemImageHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        itemID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid item ID", http.StatusBadRequest)
+            return
+        }
+
+        err = h.publicMenuSvc.DeleteMenuItemImage(itemID)
+        if err != nil {
+            if strings.Contains(err.Error(), "image not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Image not found"})
+                return
+            }
+            log.Printf("Error deleting menu item image: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.WriteHeader(http.StatusOK)
+        w.Write([]byte("Image deleted successfully"))
+    }
+}
+
+// SetMenuItemTagsHandler reemplaza las etiquetas de alérgenos y dietas de un ítem
+func (h *PublicMenuHandler) SetMenuItemTagsHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        itemID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid item ID", http.StatusBadRequest)
+            return
+        }
+
+        var request struct {
+            Tags []models.MenuItemTag `json:"tags"`
+        }
+        if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+
+        tags, err := h.publicMenuSvc.SetMenuItemTags(itemID, request.Tags)
+        if err != nil {
+            if strings.Contains(err.Error(), "item not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Item not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "invalid tag") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+                return
+            }
+            log.Printf("Error setting menu item tags: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(map[string][]models.MenuItemTag{"tags": tags})
+    }
+}
+
+// ListMenuItemTranslationsHandler lista las traducciones de un ítem
+func (h *PublicMenuHandler) ListMenuItemTranslationsHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        itemID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid item ID", http.StatusBadRequest)
+            return
+        }
+
+        translations, err := h.publicMenuSvc.ListMenuItemTranslations(itemID)
+        if err != nil {
+            if strings.Contains(err.Error(), "item not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Item not found"})
+                return
+            }
+            log.Printf("Error listing menu item translations: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(translations)
+    }
+}
+
+// SaveMenuItemTranslationHandler crea o actualiza la traducción de un ítem para un idioma
+func (h *PublicMenuHandler) SaveMenuItemTranslationHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        itemID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid item ID", http.StatusBadRequest)
+            return
+        }
+
+        var translation models.MenuItemTranslation
+        if err := json.NewDecoder(r.Body).Decode(&translation); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+        translation.MenuItemID = itemID
+        translation.Locale = vars["locale"]
+
+        savedTranslation, err := h.publicMenuSvc.SaveMenuItemTranslation(translation)
+        if err != nil {
+            if strings.Contains(err.Error(), "item not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Item not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "invalid locale") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Invalid locale"})
+                return
+            }
+            if strings.Contains(err.Error(), "default locale is edited on the menu item itself") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Default locale is edited on the menu item itself"})
+                return
+            }
+            if strings.Contains(err.Error(), "item name cannot be empty") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Item name cannot be empty"})
+                return
+            }
+            log.Printf("Error saving menu item translation: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusOK)
+        json.NewEncoder(w).Encode(savedTranslation)
+    }
+}
+
+// DeleteMenuItemTranslationHandler elimina la traducción de un ítem para un idioma
+func (h *PublicMenuHandler) DeleteMenuItemTranslationHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        itemID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid item ID", http.StatusBadRequest)
+            return
+        }
+
+        err = h.publicMenuSvc.DeleteMenuItemTranslation(itemID, vars["locale"])
+        if err != nil {
+            if strings.Contains(err.Error(), "translation not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Translation not found"})
+                return
+            }
+            log.Printf("Error deleting menu item translation: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.WriteHeader(http.StatusOK)
+        w.Write([]byte("Translation deleted successfully"))
+    }
+}
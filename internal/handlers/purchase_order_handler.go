@@ -0,0 +1,407 @@
+package handlers
+
+import (
+    "encoding/json"
+    "log"
+    "net/http"
+    "strconv"
+    "strings"
+
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/internal/services"
+
+    "github.com/gorilla/mux"
+)
+
+// PurchaseOrderHandler maneja las solicitudes de órdenes de compra
+type PurchaseOrderHandler struct {
+    purchaseOrderSvc services.PurchaseOrderService
+}
+
+func NewPurchaseOrderHandler(purchaseOrderSvc services.PurchaseOrderService) *PurchaseOrderHandler {
+    return &PurchaseOrderHandler{
+        purchaseOrderSvc: purchaseOrderSvc,
+    }
+}
+
+func (h *PurchaseOrderHandler) CreatePurchaseOrderHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        var order models.PurchaseOrder
+        if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+
+        // Obtener el employee_id del token JWT
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        createdOrder, err := h.purchaseOrderSvc.CreatePurchaseOrder(order, employeeID)
+        if err != nil {
+            if strings.Contains(err.Error(), "supplier not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Supplier not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "purchase order must have at least one line") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Purchase order must have at least one line"})
+                return
+            }
+            if strings.Contains(err.Error(), "quantity must be greater than 0") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Quantity must be greater than 0"})
+                return
+            }
+            if strings.Contains(err.Error(), "unit cost cannot be negative") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Unit cost cannot be negative"})
+                return
+            }
+            if strings.Contains(err.Error(), "line must reference either a menu item or an ingredient") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Line must reference either a menu item or an ingredient"})
+                return
+            }
+            if strings.Contains(err.Error(), "item not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Item not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "ingredient not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Ingredient not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "item stock is derived from its recipe ingredients") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Item stock is derived from its recipe ingredients"})
+                return
+            }
+            if strings.Contains(err.Error(), "item quantity must be a whole number") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Item quantity must be a whole number"})
+                return
+            }
+            log.Printf("Error creating purchase order: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusCreated)
+        json.NewEncoder(w).Encode(createdOrder)
+    }
+}
+
+func (h *PurchaseOrderHandler) GetPurchaseOrderHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        purchaseOrderID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid purchase order ID", http.StatusBadRequest)
+            return
+        }
+
+        order, err := h.purchaseOrderSvc.GetPurchaseOrder(purchaseOrderID)
+        if err != nil {
+            if strings.Contains(err.Error(), "purchase order not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Purchase order not found"})
+                return
+            }
+            log.Printf("Error getting purchase order: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(order)
+    }
+}
+
+// ListPurchaseOrdersHandler lista las órdenes de compra; acepta ?status= para filtrar por estado
+func (h *PurchaseOrderHandler) ListPurchaseOrdersHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        orders, err := h.purchaseOrderSvc.ListPurchaseOrders(r.URL.Query().Get("status"))
+        if err != nil {
+            if strings.Contains(err.Error(), "invalid purchase order status") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Invalid purchase order status"})
+                return
+            }
+            log.Printf("Error listing purchase orders: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(orders)
+    }
+}
+
+func (h *PurchaseOrderHandler) UpdatePurchaseOrderHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        purchaseOrderID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid purchase order ID", http.StatusBadRequest)
+            return
+        }
+
+        var order models.PurchaseOrder
+        if err := json.NewDecoder(r.Body).Decode(&order); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+        order.ID = purchaseOrderID
+
+        updatedOrder, err := h.purchaseOrderSvc.UpdatePurchaseOrder(order)
+        if err != nil {
+            if strings.Contains(err.Error(), "purchase order not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Purchase order not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "purchase order status does not allow this operation") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Purchase order status does not allow this operation"})
+                return
+            }
+            if strings.Contains(err.Error(), "supplier not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Supplier not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "purchase order must have at least one line") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Purchase order must have at least one line"})
+                return
+            }
+            if strings.Contains(err.Error(), "quantity must be greater than 0") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Quantity must be greater than 0"})
+                return
+            }
+            if strings.Contains(err.Error(), "unit cost cannot be negative") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Unit cost cannot be negative"})
+                return
+            }
+            if strings.Contains(err.Error(), "line must reference either a menu item or an ingredient") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Line must reference either a menu item or an ingredient"})
+                return
+            }
+            if strings.Contains(err.Error(), "item not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Item not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "ingredient not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Ingredient not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "item stock is derived from its recipe ingredients") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Item stock is derived from its recipe ingredients"})
+                return
+            }
+            if strings.Contains(err.Error(), "item quantity must be a whole number") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Item quantity must be a whole number"})
+                return
+            }
+            log.Printf("Error updating purchase order: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusOK)
+        json.NewEncoder(w).Encode(updatedOrder)
+    }
+}
+
+func (h *PurchaseOrderHandler) SendPurchaseOrderHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        purchaseOrderID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid purchase order ID", http.StatusBadRequest)
+            return
+        }
+
+        sentOrder, err := h.purchaseOrderSvc.SendPurchaseOrder(purchaseOrderID)
+        if err != nil {
+            if strings.Contains(err.Error(), "purchase order not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Purchase order not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "purchase order status does not allow this operation") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Purchase order status does not allow this operation"})
+                return
+            }
+            log.Printf("Error sending purchase order: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusOK)
+        json.NewEncoder(w).Encode(sentOrder)
+    }
+}
+
+func (h *PurchaseOrderHandler) DeletePurchaseOrderHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        purchaseOrderID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid purchase order ID", http.StatusBadRequest)
+            return
+        }
+
+        err = h.purchaseOrderSvc.DeletePurchaseOrder(purchaseOrderID)
+        if err != nil {
+            if strings.Contains(err.Error(), "purchase order not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Purchase order not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "purchase order status does not allow this operation") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Purchase order status does not allow this operation"})
+                return
+            }
+            log.Printf("Error deleting purchase order: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.WriteHeader(http.StatusOK)
+        w.Write([]byte("Purchase order deleted successfully"))
+    }
+}
+
+// ReceivePurchaseOrderHandler registra la recepción (total o parcial) de la mercancía de una orden enviada
+func (h *PurchaseOrderHandler) ReceivePurchaseOrderHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        purchaseOrderID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid purchase order ID", http.StatusBadRequest)
+            return
+        }
+
+        var receipt models.PurchaseOrderReceipt
+        if err := json.NewDecoder(r.Body).Decode(&receipt); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+
+        // Obtener el employee_id del token JWT
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        receivedOrder, err := h.purchaseOrderSvc.ReceivePurchaseOrder(purchaseOrderID, receipt, employeeID)
+        if err != nil {
+            if strings.Contains(err.Error(), "purchase order not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Purchase order not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "purchase order status does not allow this operation") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Purchase order status does not allow this operation"})
+                return
+            }
+            if strings.Contains(err.Error(), "receipt must have at least one line") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Receipt must have at least one line"})
+                return
+            }
+            if strings.Contains(err.Error(), "purchase order line not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Purchase order line not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "purchase order line duplicated in receipt") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Purchase order line duplicated in receipt"})
+                return
+            }
+            if strings.Contains(err.Error(), "quantity must be greater than 0") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Quantity must be greater than 0"})
+                return
+            }
+            if strings.Contains(err.Error(), "unit cost cannot be negative") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Unit cost cannot be negative"})
+                return
+            }
+            if strings.Contains(err.Error(), "item quantity must be a whole number") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Item quantity must be a whole number"})
+                return
+            }
+            if strings.Contains(err.Error(), "received quantity exceeds ordered quantity") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Received quantity exceeds ordered quantity"})
+                return
+            }
+            log.Printf("Error receiving purchase order: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusOK)
+        json.NewEncoder(w).Encode(receivedOrder)
+    }
+}
@@ -0,0 +1,482 @@
+package handlers
+
+import (
+    "encoding/json"
+    "log"
+    "net/http"
+    "strconv"
+    "strings"
+    "time"
+
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/internal/services"
+
+    "github.com/gorilla/mux"
+)
+
+// ReservationHandler maneja las solicitudes de reservas
+type ReservationHandler struct {
+    reservationSvc services.ReservationService
+}
+
+func NewReservationHandler(reservationSvc services.ReservationService) *ReservationHandler {
+    return &ReservationHandler{
+        reservationSvc: reservationSvc,
+    }
+}
+
+func (h *ReservationHandler) CreateReservationHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        var reservation models.Reservation
+        if err := json.NewDecoder(r.Body).Decode(&reservation); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+
+        // Obtener el employee_id del token JWT
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        createdReservation, err := h.reservationSvc.CreateReservation(reservation, employeeID)
+        if err != nil {
+            if strings.Contains(err.Error(), "guest name cannot be empty") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Guest name cannot be empty"})
+                return
+            }
+            if strings.Contains(err.Error(), "guest phone cannot be empty") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Guest phone cannot be empty"})
+                return
+            }
+            if strings.Contains(err.Error(), "party size must be greater than 0") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Party size must be greater than 0"})
+                return
+            }
+            if strings.Contains(err.Error(), "reservation time is required") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Reservation time is required"})
+                return
+            }
+            if strings.Contains(err.Error(), "reservation time must be in the future") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Reservation time must be in the future"})
+                return
+            }
+            if strings.Contains(err.Error(), "duration must be greater than 0") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Duration must be greater than 0"})
+                return
+            }
+            if strings.Contains(err.Error(), "reservation must have at least one table") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Reservation must have at least one table"})
+                return
+            }
+            if strings.Contains(err.Error(), "table duplicated in reservation") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Table duplicated in reservation"})
+                return
+            }
+            if strings.Contains(err.Error(), "table not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Table not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "table is inactive") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Table is inactive"})
+                return
+            }
+            if strings.Contains(err.Error(), "tables capacity is lower than party size") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Tables capacity is lower than party size"})
+                return
+            }
+            if strings.Contains(err.Error(), "table already booked for that time") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Table already booked for that time"})
+                return
+            }
+            log.Printf("Error creating reservation: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusCreated)
+        json.NewEncoder(w).Encode(createdReservation)
+    }
+}
+
+func (h *ReservationHandler) GetReservationHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        reservationID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid reservation ID", http.StatusBadRequest)
+            return
+        }
+
+        reservation, err := h.reservationSvc.GetReservation(reservationID)
+        if err != nil {
+            if strings.Contains(err.Error(), "reservation not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Reservation not found"})
+                return
+            }
+            log.Printf("Error getting reservation: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(reservation)
+    }
+}
+
+// ListReservationsHandler lista las reservas; acepta los filtros opcionales from, to y status
+func (h *ReservationHandler) ListReservationsHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        from, to, ok := parseReportRange(r)
+        if !ok {
+            http.Error(w, "Invalid date range, use YYYY-MM-DD or RFC3339", http.StatusBadRequest)
+            return
+        }
+
+        reservations, err := h.reservationSvc.ListReservations(from, to, r.URL.Query().Get("status"))
+        if err != nil {
+            if strings.Contains(err.Error(), "from must be before to") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "From must be before to"})
+                return
+            }
+            if strings.Contains(err.Error(), "invalid reservation status") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Invalid reservation status"})
+                return
+            }
+            log.Printf("Error listing reservations: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(reservations)
+    }
+}
+
+func (h *ReservationHandler) UpdateReservationHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        reservationID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid reservation ID", http.StatusBadRequest)
+            return
+        }
+
+        var reservation models.Reservation
+        if err := json.NewDecoder(r.Body).Decode(&reservation); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+        reservation.ID = reservationID
+
+        updatedReservation, err := h.reservationSvc.UpdateReservation(reservation)
+        if err != nil {
+            if strings.Contains(err.Error(), "guest name cannot be empty") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Guest name cannot be empty"})
+                return
+            }
+            if strings.Contains(err.Error(), "guest phone cannot be empty") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Guest phone cannot be empty"})
+                return
+            }
+            if strings.Contains(err.Error(), "party size must be greater than 0") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Party size must be greater than 0"})
+                return
+            }
+            if strings.Contains(err.Error(), "reservation time is required") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Reservation time is required"})
+                return
+            }
+            if strings.Contains(err.Error(), "reservation time must be in the future") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Reservation time must be in the future"})
+                return
+            }
+            if strings.Contains(err.Error(), "duration must be greater than 0") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Duration must be greater than 0"})
+                return
+            }
+            if strings.Contains(err.Error(), "reservation must have at least one table") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Reservation must have at least one table"})
+                return
+            }
+            if strings.Contains(err.Error(), "table duplicated in reservation") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Table duplicated in reservation"})
+                return
+            }
+            if strings.Contains(err.Error(), "table not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Table not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "table is inactive") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Table is inactive"})
+                return
+            }
+            if strings.Contains(err.Error(), "tables capacity is lower than party size") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Tables capacity is lower than party size"})
+                return
+            }
+            if strings.Contains(err.Error(), "table already booked for that time") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Table already booked for that time"})
+                return
+            }
+            if strings.Contains(err.Error(), "reservation not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Reservation not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "reservation status does not allow this operation") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Reservation status does not allow this operation"})
+                return
+            }
+            log.Printf("Error updating reservation: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(updatedReservation)
+    }
+}
+
+// SearchAvailabilityHandler busca mesas libres para reserved_at, party_size y duration_minutes (opcional)
+func (h *ReservationHandler) SearchAvailabilityHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        query := r.URL.Query()
+        reservedAt, err := time.Parse(time.RFC3339, query.Get("reserved_at"))
+        if err != nil {
+            http.Error(w, "Invalid reserved_at, use RFC3339", http.StatusBadRequest)
+            return
+        }
+        partySize, err := strconv.Atoi(query.Get("party_size"))
+        if err != nil {
+            http.Error(w, "Invalid party_size", http.StatusBadRequest)
+            return
+        }
+        durationMinutes := 0
+        if value := query.Get("duration_minutes"); value != "" {
+            durationMinutes, err = strconv.Atoi(value)
+            if err != nil {
+                http.Error(w, "Invalid duration_minutes", http.StatusBadRequest)
+                return
+            }
+        }
+
+        availability, err := h.reservationSvc.SearchAvailability(reservedAt, partySize, durationMinutes)
+        if err != nil {
+            if strings.Contains(err.Error(), "party size must be greater than 0") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Party size must be greater than 0"})
+                return
+            }
+            if strings.Contains(err.Error(), "duration must be greater than 0") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Duration must be greater than 0"})
+                return
+            }
+            log.Printf("Error searching reservation availability: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(availability)
+    }
+}
+
+// SeatReservationHandler sienta a los clientes y abre su pedido en la mesa
+func (h *ReservationHandler) SeatReservationHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        reservationID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid reservation ID", http.StatusBadRequest)
+            return
+        }
+
+        // Obtener el employee_id del token JWT
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        reservation, err := h.reservationSvc.SeatReservation(reservationID, employeeID)
+        if err != nil {
+            if strings.Contains(err.Error(), "reservation not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Reservation not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "reservation status does not allow this operation") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Reservation status does not allow this operation"})
+                return
+            }
+            if strings.Contains(err.Error(), "reservation is too early to be seated") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Reservation is too early to be seated"})
+                return
+            }
+            if strings.Contains(err.Error(), "reservation has no tables") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Reservation has no tables"})
+                return
+            }
+            if strings.Contains(err.Error(), "table is inactive") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Table is inactive"})
+                return
+            }
+            if strings.Contains(err.Error(), "table has a pending order") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Table has a pending order"})
+                return
+            }
+            log.Printf("Error seating reservation: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(reservation)
+    }
+}
+
+func (h *ReservationHandler) MarkNoShowHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        reservationID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid reservation ID", http.StatusBadRequest)
+            return
+        }
+
+        reservation, err := h.reservationSvc.MarkNoShow(reservationID)
+        if err != nil {
+            if strings.Contains(err.Error(), "reservation not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Reservation not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "reservation status does not allow this operation") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Reservation status does not allow this operation"})
+                return
+            }
+            if strings.Contains(err.Error(), "reservation time has not arrived yet") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Reservation time has not arrived yet"})
+                return
+            }
+            log.Printf("Error marking reservation as no-show: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(reservation)
+    }
+}
+
+func (h *ReservationHandler) CancelReservationHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        reservationID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid reservation ID", http.StatusBadRequest)
+            return
+        }
+
+        reservation, err := h.reservationSvc.CancelReservation(reservationID)
+        if err != nil {
+            if strings.Contains(err.Error(), "reservation not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Reservation not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "reservation status does not allow this operation") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Reservation status does not allow this operation"})
+                return
+            }
+            log.Printf("Error cancelling reservation: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(reservation)
+    }
+}
@@ -0,0 +1,769 @@
+package handlers
+
+import (
+    "encoding/json"
+    "io"
+    "log"
+    "net/http"
+    "strconv"
+    "strings"
+
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/internal/services"
+
+    "github.com/gorilla/mux"
+)
+
+// ScheduleHandler maneja las solicitudes de plantillas de turno, horarios semanales y turnos
+type ScheduleHandler struct {
+    scheduleSvc services.ScheduleService
+}
+
+func NewScheduleHandler(scheduleSvc services.ScheduleService) *ScheduleHandler {
+    return &ScheduleHandler{
+        scheduleSvc: scheduleSvc,
+    }
+}
+
+func (h *ScheduleHandler) CreateTemplateHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        var template models.ShiftTemplate
+        if err := json.NewDecoder(r.Body).Decode(&template); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+
+        createdTemplate, err := h.scheduleSvc.CreateTemplate(template)
+        if err != nil {
+            if strings.Contains(err.Error(), "template name cannot be empty") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Template name cannot be empty"})
+                return
+            }
+            if strings.Contains(err.Error(), "invalid shift role") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Invalid shift role"})
+                return
+            }
+            if strings.Contains(err.Error(), "invalid start time") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Invalid start time, use HH:MM"})
+                return
+            }
+            if strings.Contains(err.Error(), "invalid end time") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Invalid end time, use HH:MM"})
+                return
+            }
+            if strings.Contains(err.Error(), "start and end time cannot be equal") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Start and end time cannot be equal"})
+                return
+            }
+            if strings.Contains(err.Error(), "template name already exists") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Template name already exists"})
+                return
+            }
+            log.Printf("Error creating shift template: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusCreated)
+        json.NewEncoder(w).Encode(createdTemplate)
+    }
+}
+
+func (h *ScheduleHandler) ListTemplatesHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        templates, err := h.scheduleSvc.ListTemplates()
+        if err != nil {
+            log.Printf("Error listing shift templates: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(templates)
+    }
+}
+
+// UpdateTemplateHandler modifica una plantilla; los turnos ya creados con ella no cambian
+func (h *ScheduleHandler) UpdateTemplateHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        templateID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid shift template ID", http.StatusBadRequest)
+            return
+        }
+
+        var template models.ShiftTemplate
+        if err := json.NewDecoder(r.Body).Decode(&template); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+        template.ID = templateID
+
+        updatedTemplate, err := h.scheduleSvc.UpdateTemplate(template)
+        if err != nil {
+            if strings.Contains(err.Error(), "shift template not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Shift template not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "template name cannot be empty") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Template name cannot be empty"})
+                return
+            }
+            if strings.Contains(err.Error(), "invalid shift role") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Invalid shift role"})
+                return
+            }
+            if strings.Contains(err.Error(), "invalid start time") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Invalid start time, use HH:MM"})
+                return
+            }
+            if strings.Contains(err.Error(), "invalid end time") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Invalid end time, use HH:MM"})
+                return
+            }
+            if strings.Contains(err.Error(), "start and end time cannot be equal") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Start and end time cannot be equal"})
+                return
+            }
+            if strings.Contains(err.Error(), "template name already exists") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Template name already exists"})
+                return
+            }
+            log.Printf("Error updating shift template: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(updatedTemplate)
+    }
+}
+
+func (h *ScheduleHandler) DeleteTemplateHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        templateID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid shift template ID", http.StatusBadRequest)
+            return
+        }
+
+        err = h.scheduleSvc.DeleteTemplate(templateID)
+        if err != nil {
+            if strings.Contains(err.Error(), "shift template not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Shift template not found"})
+                return
+            }
+            log.Printf("Error deleting shift template: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.WriteHeader(http.StatusOK)
+        w.Write([]byte("Shift template deleted successfully"))
+    }
+}
+
+// CreateScheduleHandler crea el horario en borrador de la semana que empieza en week_start (un lunes)
+func (h *ScheduleHandler) CreateScheduleHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        var schedule models.Schedule
+        if err := json.NewDecoder(r.Body).Decode(&schedule); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+
+        // Obtener el employee_id del token JWT
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        createdSchedule, err := h.scheduleSvc.CreateSchedule(schedule.WeekStart, employeeID)
+        if err != nil {
+            if strings.Contains(err.Error(), "invalid week start") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Invalid week start, use YYYY-MM-DD"})
+                return
+            }
+            if strings.Contains(err.Error(), "week start must be a monday") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Week start must be a Monday"})
+                return
+            }
+            if strings.Contains(err.Error(), "schedule already exists for that week") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Schedule already exists for that week"})
+                return
+            }
+            log.Printf("Error creating schedule: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusCreated)
+        json.NewEncoder(w).Encode(createdSchedule)
+    }
+}
+
+func (h *ScheduleHandler) ListSchedulesHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        schedules, err := h.scheduleSvc.ListSchedules()
+        if err != nil {
+            log.Printf("Error listing schedules: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(schedules)
+    }
+}
+
+// GetScheduleHandler devuelve el horario con todos sus turnos
+func (h *ScheduleHandler) GetScheduleHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        scheduleID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid schedule ID", http.StatusBadRequest)
+            return
+        }
+
+        schedule, err := h.scheduleSvc.GetSchedule(scheduleID)
+        if err != nil {
+            if strings.Contains(err.Error(), "schedule not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Schedule not found"})
+                return
+            }
+            log.Printf("Error getting schedule: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(schedule)
+    }
+}
+
+// DeleteScheduleHandler elimina un horario en borrador junto con sus turnos
+func (h *ScheduleHandler) DeleteScheduleHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        scheduleID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid schedule ID", http.StatusBadRequest)
+            return
+        }
+
+        err = h.scheduleSvc.DeleteSchedule(scheduleID)
+        if err != nil {
+            if strings.Contains(err.Error(), "schedule not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Schedule not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "schedule status does not allow this operation") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Schedule status does not allow this operation"})
+                return
+            }
+            log.Printf("Error deleting schedule: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.WriteHeader(http.StatusOK)
+        w.Write([]byte("Schedule deleted successfully"))
+    }
+}
+
+// AddShiftHandler agrega un turno al horario, con starts_at y ends_at o con shift_template_id y date
+func (h *ScheduleHandler) AddShiftHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        scheduleID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid schedule ID", http.StatusBadRequest)
+            return
+        }
+
+        var shift models.Shift
+        if err := json.NewDecoder(r.Body).Decode(&shift); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+        shift.ScheduleID = scheduleID
+
+        createdShift, err := h.scheduleSvc.AddShift(shift)
+        if err != nil {
+            if strings.Contains(err.Error(), "schedule not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Schedule not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "schedule status does not allow this operation") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Schedule status does not allow this operation"})
+                return
+            }
+            if strings.Contains(err.Error(), "shift template not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Shift template not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "employee not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "employee is inactive") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee is inactive"})
+                return
+            }
+            if strings.Contains(err.Error(), "shift date is required with a template") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Shift date is required with a template, use YYYY-MM-DD"})
+                return
+            }
+            if strings.Contains(err.Error(), "employee is required") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee is required"})
+                return
+            }
+            if strings.Contains(err.Error(), "invalid shift role") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Invalid shift role"})
+                return
+            }
+            if strings.Contains(err.Error(), "shift start and end are required") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Shift start and end are required"})
+                return
+            }
+            if strings.Contains(err.Error(), "shift end must be after start") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Shift end must be after start"})
+                return
+            }
+            if strings.Contains(err.Error(), "shift must start within the schedule week") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Shift must start within the schedule week"})
+                return
+            }
+            if strings.Contains(err.Error(), "shift overlaps another shift of the employee") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Shift overlaps another shift of the employee"})
+                return
+            }
+            log.Printf("Error adding shift: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusCreated)
+        json.NewEncoder(w).Encode(createdShift)
+    }
+}
+
+func (h *ScheduleHandler) UpdateShiftHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        shiftID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid shift ID", http.StatusBadRequest)
+            return
+        }
+
+        var shift models.Shift
+        if err := json.NewDecoder(r.Body).Decode(&shift); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+        shift.ID = shiftID
+
+        updatedShift, err := h.scheduleSvc.UpdateShift(shift)
+        if err != nil {
+            if strings.Contains(err.Error(), "shift not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Shift not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "schedule not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Schedule not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "schedule status does not allow this operation") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Schedule status does not allow this operation"})
+                return
+            }
+            if strings.Contains(err.Error(), "shift template not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Shift template not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "employee not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "employee is inactive") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee is inactive"})
+                return
+            }
+            if strings.Contains(err.Error(), "shift date is required with a template") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Shift date is required with a template, use YYYY-MM-DD"})
+                return
+            }
+            if strings.Contains(err.Error(), "employee is required") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee is required"})
+                return
+            }
+            if strings.Contains(err.Error(), "invalid shift role") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Invalid shift role"})
+                return
+            }
+            if strings.Contains(err.Error(), "shift start and end are required") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Shift start and end are required"})
+                return
+            }
+            if strings.Contains(err.Error(), "shift end must be after start") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Shift end must be after start"})
+                return
+            }
+            if strings.Contains(err.Error(), "shift must start within the schedule week") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Shift must start within the schedule week"})
+                return
+            }
+            if strings.Contains(err.Error(), "shift overlaps another shift of the employee") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Shift overlaps another shift of the employee"})
+                return
+            }
+            log.Printf("Error updating shift: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(updatedShift)
+    }
+}
+
+func (h *ScheduleHandler) DeleteShiftHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        shiftID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid shift ID", http.StatusBadRequest)
+            return
+        }
+
+        err = h.scheduleSvc.DeleteShift(shiftID)
+        if err != nil {
+            if strings.Contains(err.Error(), "shift not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Shift not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "schedule status does not allow this operation") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Schedule status does not allow this operation"})
+                return
+            }
+            log.Printf("Error deleting shift: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.WriteHeader(http.StatusOK)
+        w.Write([]byte("Shift deleted successfully"))
+    }
+}
+
+// GetConflictsHandler devuelve los turnos que se cruzan o que no respetan el descanso mínimo
+func (h *ScheduleHandler) GetConflictsHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        scheduleID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid schedule ID", http.StatusBadRequest)
+            return
+        }
+
+        conflicts, err := h.scheduleSvc.GetConflicts(scheduleID)
+        if err != nil {
+            if strings.Contains(err.Error(), "schedule not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Schedule not found"})
+                return
+            }
+            log.Printf("Error getting schedule conflicts: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(conflicts)
+    }
+}
+
+// PublishScheduleHandler publica el horario para que los empleados vean sus turnos
+func (h *ScheduleHandler) PublishScheduleHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        scheduleID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid schedule ID", http.StatusBadRequest)
+            return
+        }
+
+        // Obtener el employee_id del token JWT
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        schedule, err := h.scheduleSvc.PublishSchedule(scheduleID, employeeID)
+        if err != nil {
+            if strings.Contains(err.Error(), "schedule not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Schedule not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "schedule status does not allow this operation") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Schedule status does not allow this operation"})
+                return
+            }
+            if strings.Contains(err.Error(), "schedule has conflicts") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Schedule has conflicts"})
+                return
+            }
+            log.Printf("Error publishing schedule: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(schedule)
+    }
+}
+
+// UnpublishScheduleHandler devuelve un horario publicado a borrador para corregirlo
+func (h *ScheduleHandler) UnpublishScheduleHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        scheduleID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid schedule ID", http.StatusBadRequest)
+            return
+        }
+
+        schedule, err := h.scheduleSvc.UnpublishSchedule(scheduleID)
+        if err != nil {
+            if strings.Contains(err.Error(), "schedule not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Schedule not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "schedule status does not allow this operation") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Schedule status does not allow this operation"})
+                return
+            }
+            log.Printf("Error unpublishing schedule: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(schedule)
+    }
+}
+
+// CopyScheduleHandler copia los turnos del horario a otra semana como un borrador nuevo
+func (h *ScheduleHandler) CopyScheduleHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        scheduleID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid schedule ID", http.StatusBadRequest)
+            return
+        }
+
+        // El cuerpo es opcional: sin week_start se copia a la semana siguiente
+        var request models.ScheduleCopyRequest
+        if err := json.NewDecoder(r.Body).Decode(&request); err != nil && err != io.EOF {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+
+        // Obtener el employee_id del token JWT
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        copiedSchedule, err := h.scheduleSvc.CopySchedule(scheduleID, request.WeekStart, employeeID)
+        if err != nil {
+            if strings.Contains(err.Error(), "schedule not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Schedule not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "invalid week start") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Invalid week start, use YYYY-MM-DD"})
+                return
+            }
+            if strings.Contains(err.Error(), "week start must be a monday") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Week start must be a Monday"})
+                return
+            }
+            if strings.Contains(err.Error(), "schedule already exists for that week") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Schedule already exists for that week"})
+                return
+            }
+            if strings.Contains(err.Error(), "cannot copy a schedule onto its own week") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Cannot copy a schedule onto its own week"})
+                return
+            }
+            log.Printf("Error copying schedule: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusCreated)
+        json.NewEncoder(w).Encode(copiedSchedule)
+    }
+}
+
+// GetMyShiftsHandler devuelve los turnos publicados del empleado autenticado; acepta from y to opcionales
+func (h *ScheduleHandler) GetMyShiftsHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        from, to, ok := parseReportRange(r)
+        if !ok {
+            http.Error(w, "Invalid date range, use YYYY-MM-DD or RFC3339", http.StatusBadRequest)
+            return
+        }
+
+        // Obtener el employee_id del token JWT
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        shifts, err := h.scheduleSvc.GetMyShifts(employeeID, from, to)
+        if err != nil {
+            if strings.Contains(err.Error(), "from must be before to") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "From must be before to"})
+                return
+            }
+            log.Printf("Error getting employee shifts: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(shifts)
+    }
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+    "encoding/json"
+    "log"
+    "net/http"
+    "strconv"
+    "strings"
+
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/internal/services"
+
+    "github.com/gorilla/mux"
+)
+
+// StockMovementHandler maneja las solicitudes del libro de movimientos de inventario
+type StockMovementHandler struct {
+    stockMovementSvc services.StockMovementService
+}
+
+func NewStockMovementHandler(stockMovementSvc services.StockMovementService) *StockMovementHandler {
+    return &StockMovementHandler{
+        stockMovementSvc: stockMovementSvc,
+    }
+}
+
+// RecordStockMovementHandler registra una merma o un ajuste manual de inventario
+func (h *StockMovementHandler) RecordStockMovementHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        var movement models.StockMovement
+        if err := json.NewDecoder(r.Body).Decode(&movement); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+
+        // Obtener el employee_id del token JWT (del contexto, seteado por el middleware)
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+        movement.EmployeeID = &employeeID
+
+        createdMovement, err := h.stockMovementSvc.RecordMovement(movement)
+        if err != nil {
+            if strings.Contains(err.Error(), "item not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Item not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "ingredient not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Ingredient not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "movement type not allowed") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Only waste and adjustment movements can be recorded manually"})
+                return
+            }
+            if strings.Contains(err.Error(), "waste quantity must be greater than 0") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Waste quantity must be greater than 0"})
+                return
+            }
+            if strings.Contains(err.Error(), "adjustment quantity cannot be zero") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Adjustment quantity cannot be zero"})
+                return
+            }
+            if strings.Contains(err.Error(), "reason cannot be empty") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Reason cannot be empty"})
+                return
+            }
+            if strings.Contains(err.Error(), "movement must reference either a menu item or an ingredient") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Movement must reference either a menu item or an ingredient"})
+                return
+            }
+            if strings.Contains(err.Error(), "item stock is derived from its recipe ingredients") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Item stock is derived from its recipe ingredients, record the movement on the ingredients"})
+                return
+            }
+            if strings.Contains(err.Error(), "item quantity must be a whole number") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Item quantity must be a whole number"})
+                return
+            }
+            if strings.Contains(err.Error(), "resulting stock cannot be negative") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Resulting stock cannot be negative"})
+                return
+            }
+            log.Printf("Error recording stock movement: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusCreated)
+        json.NewEncoder(w).Encode(createdMovement)
+    }
+}
+
+func (h *StockMovementHandler) ListMenuItemStockMovementsHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        itemID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid item ID", http.StatusBadRequest)
+            return
+        }
+
+        movements, err := h.stockMovementSvc.ListMenuItemMovements(itemID)
+        if err != nil {
+            if strings.Contains(err.Error(), "item not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Item not found"})
+                return
+            }
+            log.Printf("Error listing item stock movements: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(movements)
+    }
+}
+
+func (h *StockMovementHandler) ListIngredientStockMovementsHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        ingredientID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid ingredient ID", http.StatusBadRequest)
+            return
+        }
+
+        movements, err := h.stockMovementSvc.ListIngredientMovements(ingredientID)
+        if err != nil {
+            if strings.Contains(err.Error(), "ingredient not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Ingredient not found"})
+                return
+            }
+            log.Printf("Error listing ingredient stock movements: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(movements)
+    }
+}
+
+// GetStockReconciliationHandler lista las diferencias entre el stock almacenado y el libro
+func (h *StockMovementHandler) GetStockReconciliationHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        entries, err := h.stockMovementSvc.Reconcile()
+        if err != nil {
+            log.Printf("Error reconciling stock: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(entries)
+    }
+}
@@ -0,0 +1,215 @@
+package handlers
+
+import (
+    "encoding/json"
+    "log"
+    "net/http"
+    "strconv"
+    "strings"
+
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/internal/services"
+
+    "github.com/gorilla/mux"
+)
+
+// SupplierHandler maneja las solicitudes de proveedores
+type SupplierHandler struct {
+    supplierSvc services.SupplierService
+}
+
+func NewSupplierHandler(supplierSvc services.SupplierService) *SupplierHandler {
+    return &SupplierHandler{
+        supplierSvc: supplierSvc,
+    }
+}
+
+func (h *SupplierHandler) CreateSupplierHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        var supplier models.Supplier
+        if err := json.NewDecoder(r.Body).Decode(&supplier); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+
+        createdSupplier, err := h.supplierSvc.CreateSupplier(supplier)
+        if err != nil {
+            if strings.Contains(err.Error(), "supplier name cannot be empty") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Supplier name cannot be empty"})
+                return
+            }
+            if strings.Contains(err.Error(), "invalid nit check digit") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Invalid NIT check digit"})
+                return
+            }
+            if strings.Contains(err.Error(), "invalid nit") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Invalid NIT"})
+                return
+            }
+            if strings.Contains(err.Error(), "nit already exists") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "NIT already exists"})
+                return
+            }
+            if strings.Contains(err.Error(), "invalid email") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Invalid email"})
+                return
+            }
+            log.Printf("Error creating supplier: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusCreated)
+        json.NewEncoder(w).Encode(createdSupplier)
+    }
+}
+
+func (h *SupplierHandler) GetSupplierHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        supplierID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid supplier ID", http.StatusBadRequest)
+            return
+        }
+
+        supplier, err := h.supplierSvc.GetSupplier(supplierID)
+        if err != nil {
+            if strings.Contains(err.Error(), "supplier not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Supplier not found"})
+                return
+            }
+            log.Printf("Error getting supplier: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(supplier)
+    }
+}
+
+func (h *SupplierHandler) ListSuppliersHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        suppliers, err := h.supplierSvc.ListSuppliers()
+        if err != nil {
+            log.Printf("Error listing suppliers: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(suppliers)
+    }
+}
+
+func (h *SupplierHandler) UpdateSupplierHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        supplierID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid supplier ID", http.StatusBadRequest)
+            return
+        }
+
+        var supplier models.Supplier
+        if err := json.NewDecoder(r.Body).Decode(&supplier); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+        supplier.ID = supplierID
+
+        updatedSupplier, err := h.supplierSvc.UpdateSupplier(supplier)
+        if err != nil {
+            if strings.Contains(err.Error(), "supplier name cannot be empty") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Supplier name cannot be empty"})
+                return
+            }
+            if strings.Contains(err.Error(), "invalid nit check digit") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Invalid NIT check digit"})
+                return
+            }
+            if strings.Contains(err.Error(), "invalid nit") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Invalid NIT"})
+                return
+            }
+            if strings.Contains(err.Error(), "nit already exists") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "NIT already exists"})
+                return
+            }
+            if strings.Contains(err.Error(), "invalid email") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Invalid email"})
+                return
+            }
+            if strings.Contains(err.Error(), "supplier not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Supplier not found"})
+                return
+            }
+            log.Printf("Error updating supplier: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusOK)
+        json.NewEncoder(w).Encode(updatedSupplier)
+    }
+}
+
+func (h *SupplierHandler) DeleteSupplierHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        supplierID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid supplier ID", http.StatusBadRequest)
+            return
+        }
+
+        err = h.supplierSvc.DeleteSupplier(supplierID)
+        if err != nil {
+            if strings.Contains(err.Error(), "supplier not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Supplier not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "supplier has purchase orders") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Supplier has purchase orders"})
+                return
+            }
+            log.Printf("Error deleting supplier: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.WriteHeader(http.StatusOK)
+        w.Write([]byte("Supplier deleted successfully"))
+    }
+}
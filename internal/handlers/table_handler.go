@@ -45,6 +45,24 @@ func (h *TableHandler) CreateTableHandler() http.HandlerFunc {
                 json.NewEncoder(w).Encode(map[string]string{"error": "Table name already exists"})
                 return
             }
+            if strings.Contains(err.Error(), "capacity must be greater than 0") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Capacity must be greater than 0"})
+                return
+            }
+            if strings.Contains(err.Error(), "invalid table zone") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Zone must be terraza, barra or salón"})
+                return
+            }
+            if strings.Contains(err.Error(), "table position cannot be negative") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Table position cannot be negative"})
+                return
+            }
             if strings.Contains(err.Error(), "maximum number of tables reached") {
                 w.Header().Set("Content-Type", "application/json")
                 w.WriteHeader(http.StatusBadRequest)
@@ -90,9 +108,12 @@ func (h *TableHandler) GetTableHandler() http.HandlerFunc {
     }
 }
 
+// ListTablesHandler lista las mesas; con ?include=status agrega el estado en vivo de cada una
 func (h *TableHandler) ListTablesHandler() http.HandlerFunc {
     return func(w http.ResponseWriter, r *http.Request) {
-        tables, err := h.tableSvc.ListTables()
+        includeStatus := r.URL.Query().Get("include") == "status"
+
+        tables, err := h.tableSvc.ListTables(includeStatus)
         if err != nil {
             log.Printf("Error listing tables: %v", err)
             http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -135,6 +156,24 @@ func (h *TableHandler) UpdateTableHandler() http.HandlerFunc {
                 json.NewEncoder(w).Encode(map[string]string{"error": "Table name already exists"})
                 return
             }
+            if strings.Contains(err.Error(), "capacity must be greater than 0") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Capacity must be greater than 0"})
+                return
+            }
+            if strings.Contains(err.Error(), "invalid table zone") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Zone must be terraza, barra or salón"})
+                return
+            }
+            if strings.Contains(err.Error(), "table position cannot be negative") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Table position cannot be negative"})
+                return
+            }
             if strings.Contains(err.Error(), "table not found") {
                 w.Header().Set("Content-Type", "application/json")
                 w.WriteHeader(http.StatusNotFound)
@@ -150,4 +189,104 @@ func (h *TableHandler) UpdateTableHandler() http.HandlerFunc {
         w.WriteHeader(http.StatusOK)
         json.NewEncoder(w).Encode(updatedTable)
     }
-}
\ No newline at end of file
+}
+// ActivateTableHandler vuelve a habilitar una mesa desactivada
+func (h *TableHandler) ActivateTableHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        tableID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid table ID", http.StatusBadRequest)
+            return
+        }
+
+        table, err := h.tableSvc.ActivateTable(tableID)
+        if err != nil {
+            if strings.Contains(err.Error(), "table not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Table not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "maximum number of tables reached") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Maximum number of tables reached"})
+                return
+            }
+            log.Printf("Error activating table: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(table)
+    }
+}
+
+// DeactivateTableHandler retira una mesa del servicio sin borrar su historial
+func (h *TableHandler) DeactivateTableHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        tableID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid table ID", http.StatusBadRequest)
+            return
+        }
+
+        table, err := h.tableSvc.DeactivateTable(tableID)
+        if err != nil {
+            if strings.Contains(err.Error(), "table not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Table not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "table has a pending order") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Table has a pending order"})
+                return
+            }
+            log.Printf("Error deactivating table: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(table)
+    }
+}
+
+func (h *TableHandler) DeleteTableHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        tableID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid table ID", http.StatusBadRequest)
+            return
+        }
+
+        err = h.tableSvc.DeleteTable(tableID)
+        if err != nil {
+            if strings.Contains(err.Error(), "table not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Table not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "table has order or reservation history") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Table has order or reservation history, deactivate it instead"})
+                return
+            }
+            log.Printf("Error deleting table: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.WriteHeader(http.StatusOK)
+        w.Write([]byte("Table deleted successfully"))
+    }
+}
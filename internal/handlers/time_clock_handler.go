@@ -0,0 +1,416 @@
+package handlers
+
+import (
+    "encoding/json"
+    "log"
+    "net/http"
+    "strconv"
+    "strings"
+
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/internal/services"
+
+    "github.com/gorilla/mux"
+)
+
+// TimeClockHandler maneja las solicitudes de marcación de asistencia y hojas de asistencia
+type TimeClockHandler struct {
+    timeClockSvc services.TimeClockService
+}
+
+func NewTimeClockHandler(timeClockSvc services.TimeClockService) *TimeClockHandler {
+    return &TimeClockHandler{
+        timeClockSvc: timeClockSvc,
+    }
+}
+
+// GetStatusHandler indica si el empleado autenticado está fuera, trabajando o en pausa
+func (h *TimeClockHandler) GetStatusHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        // Obtener el employee_id del token JWT
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        status, err := h.timeClockSvc.GetStatus(employeeID)
+        if err != nil {
+            log.Printf("Error getting time clock status: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(status)
+    }
+}
+
+// ClockInHandler marca la entrada del empleado autenticado
+func (h *TimeClockHandler) ClockInHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        // Obtener el employee_id del token JWT: solo se marca la asistencia propia
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        entry, err := h.timeClockSvc.ClockIn(employeeID)
+        if err != nil {
+            if strings.Contains(err.Error(), "employee not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "employee is already clocked in") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee is already clocked in"})
+                return
+            }
+            log.Printf("Error clocking in: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusCreated)
+        json.NewEncoder(w).Encode(entry)
+    }
+}
+
+// ClockOutHandler marca la salida del empleado autenticado; si estaba en pausa, la pausa termina
+func (h *TimeClockHandler) ClockOutHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        // Obtener el employee_id del token JWT: solo se marca la asistencia propia
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        entry, err := h.timeClockSvc.ClockOut(employeeID)
+        if err != nil {
+            if strings.Contains(err.Error(), "employee not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "employee is not clocked in") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee is not clocked in"})
+                return
+            }
+            log.Printf("Error clocking out: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(entry)
+    }
+}
+
+func (h *TimeClockHandler) StartBreakHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        // Obtener el employee_id del token JWT: solo se marca la asistencia propia
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        entry, err := h.timeClockSvc.StartBreak(employeeID)
+        if err != nil {
+            if strings.Contains(err.Error(), "employee not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "employee is not clocked in") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee is not clocked in"})
+                return
+            }
+            if strings.Contains(err.Error(), "break already in progress") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Break already in progress"})
+                return
+            }
+            log.Printf("Error starting break: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(entry)
+    }
+}
+
+func (h *TimeClockHandler) EndBreakHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        // Obtener el employee_id del token JWT: solo se marca la asistencia propia
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        entry, err := h.timeClockSvc.EndBreak(employeeID)
+        if err != nil {
+            if strings.Contains(err.Error(), "employee not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "employee is not clocked in") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee is not clocked in"})
+                return
+            }
+            if strings.Contains(err.Error(), "no break in progress") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "No break in progress"})
+                return
+            }
+            log.Printf("Error ending break: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(entry)
+    }
+}
+
+// CreateEntryHandler registra a mano una jornada completa (por ejemplo un turno sin marcación de entrada)
+func (h *TimeClockHandler) CreateEntryHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        var entry models.TimeEntry
+        if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+
+        // Obtener el employee_id del token JWT
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        createdEntry, err := h.timeClockSvc.CreateEntry(entry, employeeID)
+        if err != nil {
+            if strings.Contains(err.Error(), "employee not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "employee is required") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee is required"})
+                return
+            }
+            if strings.Contains(err.Error(), "clock in is required") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Clock in is required"})
+                return
+            }
+            if strings.Contains(err.Error(), "clock out is required") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Clock out is required"})
+                return
+            }
+            if strings.Contains(err.Error(), "clock out must be after clock in") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Clock out must be after clock in"})
+                return
+            }
+            if strings.Contains(err.Error(), "clock out cannot be in the future") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Clock out cannot be in the future"})
+                return
+            }
+            if strings.Contains(err.Error(), "time entry overlaps another entry of the employee") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Time entry overlaps another entry of the employee"})
+                return
+            }
+            log.Printf("Error creating time entry: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusCreated)
+        json.NewEncoder(w).Encode(createdEntry)
+    }
+}
+
+// AdjustEntryHandler corrige la entrada y la salida de una jornada (por ejemplo una salida no marcada)
+func (h *TimeClockHandler) AdjustEntryHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        entryID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid time entry ID", http.StatusBadRequest)
+            return
+        }
+
+        var entry models.TimeEntry
+        if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+        entry.ID = entryID
+
+        // Obtener el employee_id del token JWT
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        adjustedEntry, err := h.timeClockSvc.AdjustEntry(entry, employeeID)
+        if err != nil {
+            if strings.Contains(err.Error(), "time entry not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Time entry not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "employee is required") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee is required"})
+                return
+            }
+            if strings.Contains(err.Error(), "clock in is required") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Clock in is required"})
+                return
+            }
+            if strings.Contains(err.Error(), "clock out is required") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Clock out is required"})
+                return
+            }
+            if strings.Contains(err.Error(), "clock out must be after clock in") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Clock out must be after clock in"})
+                return
+            }
+            if strings.Contains(err.Error(), "clock out cannot be in the future") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Clock out cannot be in the future"})
+                return
+            }
+            if strings.Contains(err.Error(), "time entry overlaps another entry of the employee") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Time entry overlaps another entry of the employee"})
+                return
+            }
+            if strings.Contains(err.Error(), "breaks fall outside the adjusted entry") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Breaks fall outside the adjusted entry"})
+                return
+            }
+            log.Printf("Error adjusting time entry: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(adjustedEntry)
+    }
+}
+
+// ListMissedPunchesHandler lista los turnos sin entrada y las jornadas sin salida; acepta from y to opcionales
+func (h *TimeClockHandler) ListMissedPunchesHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        from, to, ok := parseReportRange(r)
+        if !ok {
+            http.Error(w, "Invalid date range, use YYYY-MM-DD or RFC3339", http.StatusBadRequest)
+            return
+        }
+
+        missed, err := h.timeClockSvc.ListMissedPunches(from, to)
+        if err != nil {
+            if strings.Contains(err.Error(), "from must be before to") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "From must be before to"})
+                return
+            }
+            log.Printf("Error listing missed punches: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(missed)
+    }
+}
+
+// GetTimesheetHandler devuelve la hoja de asistencia de un empleado; acepta from y to opcionales
+func (h *TimeClockHandler) GetTimesheetHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        employeeID, err := strconv.Atoi(vars["employee_id"])
+        if err != nil {
+            http.Error(w, "Invalid employee ID", http.StatusBadRequest)
+            return
+        }
+
+        from, to, ok := parseReportRange(r)
+        if !ok {
+            http.Error(w, "Invalid date range, use YYYY-MM-DD or RFC3339", http.StatusBadRequest)
+            return
+        }
+
+        timesheet, err := h.timeClockSvc.GetTimesheet(employeeID, from, to)
+        if err != nil {
+            if strings.Contains(err.Error(), "employee not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "from must be before to") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "From must be before to"})
+                return
+            }
+            log.Printf("Error getting timesheet: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(timesheet)
+    }
+}
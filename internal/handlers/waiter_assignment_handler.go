@@ -0,0 +1,188 @@
+package handlers
+
+import (
+    "encoding/json"
+    "log"
+    "net/http"
+    "strconv"
+    "strings"
+
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/internal/services"
+
+    "github.com/gorilla/mux"
+)
+
+// WaiterAssignmentHandler maneja las solicitudes de secciones asignadas a los meseros
+type WaiterAssignmentHandler struct {
+    waiterAssignmentSvc services.WaiterAssignmentService
+}
+
+func NewWaiterAssignmentHandler(waiterAssignmentSvc services.WaiterAssignmentService) *WaiterAssignmentHandler {
+    return &WaiterAssignmentHandler{
+        waiterAssignmentSvc: waiterAssignmentSvc,
+    }
+}
+
+// CreateAssignmentHandler asigna una sección de mesas a un mesero durante un turno
+func (h *WaiterAssignmentHandler) CreateAssignmentHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        var assignment models.WaiterAssignment
+        if err := json.NewDecoder(r.Body).Decode(&assignment); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+
+        // Obtener el employee_id del token JWT
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        createdAssignment, err := h.waiterAssignmentSvc.CreateAssignment(assignment, employeeID)
+        if err != nil {
+            if strings.Contains(err.Error(), "employee not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "employee is inactive") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Employee is inactive"})
+                return
+            }
+            if strings.Contains(err.Error(), "assignment start and end are required") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Assignment start and end are required"})
+                return
+            }
+            if strings.Contains(err.Error(), "assignment end must be after start") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Assignment end must be after start"})
+                return
+            }
+            if strings.Contains(err.Error(), "assignment must have at least one table") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Assignment must have at least one table"})
+                return
+            }
+            if strings.Contains(err.Error(), "table duplicated in assignment") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Table duplicated in assignment"})
+                return
+            }
+            if strings.Contains(err.Error(), "table not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Table not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "table already assigned to another waiter for that time") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Table already assigned to another waiter for that time"})
+                return
+            }
+            log.Printf("Error creating waiter assignment: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusCreated)
+        json.NewEncoder(w).Encode(createdAssignment)
+    }
+}
+
+// ListAssignmentsHandler lista las asignaciones; acepta los filtros opcionales from, to y employee_id
+func (h *WaiterAssignmentHandler) ListAssignmentsHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        from, to, ok := parseReportRange(r)
+        if !ok {
+            http.Error(w, "Invalid date range, use YYYY-MM-DD or RFC3339", http.StatusBadRequest)
+            return
+        }
+
+        employeeID := 0
+        if value := r.URL.Query().Get("employee_id"); value != "" {
+            var err error
+            employeeID, err = strconv.Atoi(value)
+            if err != nil {
+                http.Error(w, "Invalid employee ID", http.StatusBadRequest)
+                return
+            }
+        }
+
+        assignments, err := h.waiterAssignmentSvc.ListAssignments(from, to, employeeID)
+        if err != nil {
+            if strings.Contains(err.Error(), "from must be before to") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "From must be before to"})
+                return
+            }
+            log.Printf("Error listing waiter assignments: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(assignments)
+    }
+}
+
+func (h *WaiterAssignmentHandler) DeleteAssignmentHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        assignmentID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid waiter assignment ID", http.StatusBadRequest)
+            return
+        }
+
+        err = h.waiterAssignmentSvc.DeleteAssignment(assignmentID)
+        if err != nil {
+            if strings.Contains(err.Error(), "waiter assignment not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Waiter assignment not found"})
+                return
+            }
+            log.Printf("Error deleting waiter assignment: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.WriteHeader(http.StatusOK)
+        w.Write([]byte("Waiter assignment deleted successfully"))
+    }
+}
+
+// GetMyTablesHandler devuelve al empleado autenticado su sección vigente y sus pedidos abiertos
+func (h *WaiterAssignmentHandler) GetMyTablesHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        // Obtener el employee_id del token JWT
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        myTables, err := h.waiterAssignmentSvc.GetMyTables(employeeID)
+        if err != nil {
+            log.Printf("Error getting employee tables: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(myTables)
+    }
+}
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+    "encoding/json"
+    "log"
+    "net/http"
+    "strconv"
+    "strings"
+
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/internal/services"
+
+    "github.com/gorilla/mux"
+)
+
+// WaitlistHandler maneja las solicitudes de la lista de espera
+type WaitlistHandler struct {
+    waitlistSvc services.WaitlistService
+}
+
+func NewWaitlistHandler(waitlistSvc services.WaitlistService) *WaitlistHandler {
+    return &WaitlistHandler{
+        waitlistSvc: waitlistSvc,
+    }
+}
+
+// AddPartyHandler anota un grupo en la lista de espera
+func (h *WaitlistHandler) AddPartyHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        var entry models.WaitlistEntry
+        if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+
+        // Obtener el employee_id del token JWT
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        createdEntry, err := h.waitlistSvc.AddParty(entry, employeeID)
+        if err != nil {
+            if strings.Contains(err.Error(), "guest name cannot be empty") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Guest name cannot be empty"})
+                return
+            }
+            if strings.Contains(err.Error(), "party size must be greater than 0") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Party size must be greater than 0"})
+                return
+            }
+            if strings.Contains(err.Error(), "quoted wait cannot be negative") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Quoted wait cannot be negative"})
+                return
+            }
+            if strings.Contains(err.Error(), "party size exceeds the largest table") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Party size exceeds the largest table"})
+                return
+            }
+            log.Printf("Error adding party to waitlist: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        w.WriteHeader(http.StatusCreated)
+        json.NewEncoder(w).Encode(createdEntry)
+    }
+}
+
+// ListWaitlistHandler devuelve los grupos en espera con su posición y espera estimada
+func (h *WaitlistHandler) ListWaitlistHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        entries, err := h.waitlistSvc.ListWaiting()
+        if err != nil {
+            log.Printf("Error listing waitlist: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(entries)
+    }
+}
+
+func (h *WaitlistHandler) GetWaitlistEntryHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        entryID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid waitlist entry ID", http.StatusBadRequest)
+            return
+        }
+
+        entry, err := h.waitlistSvc.GetEntry(entryID)
+        if err != nil {
+            if strings.Contains(err.Error(), "waitlist entry not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Waitlist entry not found"})
+                return
+            }
+            log.Printf("Error getting waitlist entry: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(entry)
+    }
+}
+
+// EstimateWaitHandler estima la espera para un grupo de party_size personas que llega ahora
+func (h *WaitlistHandler) EstimateWaitHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        partySize, err := strconv.Atoi(r.URL.Query().Get("party_size"))
+        if err != nil {
+            http.Error(w, "Invalid party_size", http.StatusBadRequest)
+            return
+        }
+
+        estimate, err := h.waitlistSvc.EstimateWait(partySize)
+        if err != nil {
+            if strings.Contains(err.Error(), "party size must be greater than 0") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Party size must be greater than 0"})
+                return
+            }
+            if strings.Contains(err.Error(), "party size exceeds the largest table") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusBadRequest)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Party size exceeds the largest table"})
+                return
+            }
+            log.Printf("Error estimating wait: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(estimate)
+    }
+}
+
+// SeatNextPartyHandler sienta al siguiente grupo en espera en la mesa liberada
+func (h *WaitlistHandler) SeatNextPartyHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        var request models.WaitlistSeatRequest
+        if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+            http.Error(w, "Invalid request body", http.StatusBadRequest)
+            return
+        }
+
+        // Obtener el employee_id del token JWT
+        employeeID, ok := r.Context().Value("employee_id").(int)
+        if !ok {
+            http.Error(w, "Invalid employee ID in token", http.StatusUnauthorized)
+            return
+        }
+
+        entry, err := h.waitlistSvc.SeatNext(request.TableID, employeeID)
+        if err != nil {
+            if strings.Contains(err.Error(), "table not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Table not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "table is inactive") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Table is inactive"})
+                return
+            }
+            if strings.Contains(err.Error(), "table has a pending order") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Table has a pending order"})
+                return
+            }
+            if strings.Contains(err.Error(), "table is reserved") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Table is reserved"})
+                return
+            }
+            if strings.Contains(err.Error(), "no waiting party fits the table") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "No waiting party fits the table"})
+                return
+            }
+            log.Printf("Error seating next party: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(entry)
+    }
+}
+
+// MarkPartyLeftHandler retira de la lista a un grupo que se fue sin ser sentado
+func (h *WaitlistHandler) MarkPartyLeftHandler() http.HandlerFunc {
+    return func(w http.ResponseWriter, r *http.Request) {
+        vars := mux.Vars(r)
+        entryID, err := strconv.Atoi(vars["id"])
+        if err != nil {
+            http.Error(w, "Invalid waitlist entry ID", http.StatusBadRequest)
+            return
+        }
+
+        entry, err := h.waitlistSvc.MarkLeft(entryID)
+        if err != nil {
+            if strings.Contains(err.Error(), "waitlist entry not found") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusNotFound)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Waitlist entry not found"})
+                return
+            }
+            if strings.Contains(err.Error(), "waitlist entry is not waiting") {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusConflict)
+                json.NewEncoder(w).Encode(map[string]string{"error": "Waitlist entry is not waiting"})
+                return
+            }
+            log.Printf("Error removing party from waitlist: %v", err)
+            http.Error(w, "Internal server error", http.StatusInternalServerError)
+            return
+        }
+
+        w.Header().Set("Content-Type", "application/json")
+        json.NewEncoder(w).Encode(entry)
+    }
+}
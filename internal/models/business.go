@@ -10,6 +10,10 @@ type Business struct {
     PhoneNumber     string    `json:"phone_number"`
     Email           string    `json:"email"`
     CorporateReason string    `json:"corporate_reason"`
+    // GuestOrdersRequireConfirmation indica si los pedidos hechos por QR deben ser confirmados por un mesero
+    GuestOrdersRequireConfirmation bool `json:"guest_orders_require_confirmation"`
+    // MaxTables limita las mesas activas del local; nil significa sin límite
+    MaxTables *int `json:"max_tables"`
     CreatedAt       time.Time `json:"created_at"`
     UpdatedAt       time.Time `json:"updated_at"`
 }
\ No newline at end of file
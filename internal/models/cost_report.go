@@ -0,0 +1,49 @@
+package models
+
+import (
+    "time"
+
+    "github.com/shopspring/decimal"
+)
+
+// MenuItemCost muestra el costo unitario de un ítem (según compras y receta) y su margen bruto.
+// Los campos de costo son NULL cuando el ítem o alguno de sus ingredientes no tiene compras con costo
+type MenuItemCost struct {
+    MenuItemID      int               `json:"menu_item_id"`
+    ItemName        string            `json:"item_name"`
+    Category        string            `json:"category"`
+    StockMode       MenuItemStockMode `json:"stock_mode"`
+    Price           decimal.Decimal   `json:"price"`
+    UnitCost        *decimal.Decimal  `json:"unit_cost"`
+    GrossMargin     *decimal.Decimal  `json:"gross_margin"`      // Precio - costo unitario
+    MarginPercent   *decimal.Decimal  `json:"margin_percent"`    // Margen bruto / precio * 100
+    FoodCostPercent *decimal.Decimal  `json:"food_cost_percent"` // Costo unitario / precio * 100
+}
+
+// MenuItemSales son las unidades vendidas de un ítem en órdenes completadas
+type MenuItemSales struct {
+    MenuItemID   int
+    QuantitySold int
+}
+
+// MenuItemContribution es la contribución de un ítem al margen en un periodo
+type MenuItemContribution struct {
+    MenuItemCost
+    QuantitySold      int              `json:"quantity_sold"`
+    Revenue           decimal.Decimal  `json:"revenue"`
+    TheoreticalCost   *decimal.Decimal `json:"theoretical_cost"`   // Unidades vendidas * costo unitario
+    Contribution      *decimal.Decimal `json:"contribution"`       // Ingresos - costo teórico
+    ContributionShare *decimal.Decimal `json:"contribution_share"` // Porcentaje de la contribución total
+}
+
+// MarginReport resume el costo teórico y el margen de las órdenes completadas en un periodo
+type MarginReport struct {
+    From             *time.Time             `json:"from"`
+    To               *time.Time             `json:"to"`
+    Revenue          decimal.Decimal        `json:"revenue"`
+    TheoreticalCost  decimal.Decimal        `json:"theoretical_cost"`
+    FoodCostPercent  *decimal.Decimal       `json:"food_cost_percent"`  // Costo teórico / ingresos de los ítems con costo * 100
+    Contribution     decimal.Decimal        `json:"contribution"`       // Ingresos de los ítems con costo - costo teórico
+    ItemsWithoutCost int                    `json:"items_without_cost"` // Ítems vendidos sin costo conocido (no suman al costo ni a la contribución)
+    Items            []MenuItemContribution `json:"items"`
+}
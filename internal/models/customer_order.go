@@ -12,6 +12,11 @@ type CustomerOrder struct {
     TableID          int             `json:"table_id"`
     TotalAmount      decimal.Decimal `json:"total_amount"`
     Status           string          `json:"status"`
+    BillRequestedAt  *time.Time      `json:"bill_requested_at"` // NULL mientras no se pida la cuenta
+    CompletedAt      *time.Time      `json:"completed_at"`      // NULL mientras el pedido esté pendiente
+    OpenedBy         *int            `json:"opened_by"`         // NULL si lo abrió un cliente por QR
+    ServedBy         *int            `json:"served_by"`         // Mesero a cargo de la mesa
+    ClosedBy         *int            `json:"closed_by"`
     CreatedAt        time.Time       `json:"created_at"`
     OrderDetails    []OrderDetail   `json:"order_details"`
 }
\ No newline at end of file
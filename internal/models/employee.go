@@ -13,18 +13,57 @@ const (
 
 // Employee representa la tabla employees
 type Employee struct {
-    ID           int          `json:"id"`
-    EmployeeName string       `json:"employee_name"`
-    Email        string       `json:"email"`
-    PhoneNumber  string       `json:"phone_number"`
-    Role         EmployeeRole `json:"role"`
-    Username     string       `json:"username"`
-    Password     string       `json:"-"` // No se incluye en JSON
-    CreatedAt    time.Time    `json:"created_at"`
+    ID            int          `json:"id"`
+    EmployeeName  string       `json:"employee_name"`
+    Email         string       `json:"email"`
+    PhoneNumber   string       `json:"phone_number"`
+    Role          EmployeeRole `json:"role"`
+    Username      string       `json:"username"`
+    Password      string       `json:"-"` // No se incluye en JSON
+    TokenVersion  int          `json:"-"` // Versión de sesión: los tokens con otra versión se rechazan
+    Active        bool         `json:"active"`
+    DeactivatedAt *time.Time   `json:"deactivated_at"`
+    DeactivatedBy *int         `json:"deactivated_by"`
+    CreatedAt     time.Time    `json:"created_at"`
 }
 
 // EmployeeCreateResponse representa la respuesta al crear un empleado
 type EmployeeCreateResponse struct {
     Employee Employee `json:"employee"`
     Password string   `json:"password"` // Contraseña generada
-}
\ No newline at end of file
+}
+
+// ProfileUpdateRequest son los datos de contacto que un empleado puede editar de su propio perfil
+type ProfileUpdateRequest struct {
+    Email       string `json:"email"`
+    PhoneNumber string `json:"phone_number"`
+}
+
+// PasswordChangeRequest es el cuerpo para que un empleado cambie su propia contraseña
+type PasswordChangeRequest struct {
+    CurrentPassword string `json:"current_password"`
+    NewPassword     string `json:"new_password"`
+}
+
+// EmployeeDeactivationRequest indica a quién pasar las tareas abiertas del empleado que se da de
+// baja; si no se indica, las tareas se cancelan
+type EmployeeDeactivationRequest struct {
+    ReassignTasksTo *int `json:"reassign_tasks_to"`
+}
+
+// EmployeeDeactivation es el resultado de dar de baja a un empleado
+type EmployeeDeactivation struct {
+    Employee                 Employee `json:"employee"`
+    ReassignedTasks          int      `json:"reassigned_tasks"`
+    ReassignedTo             *int     `json:"reassigned_to"`
+    CancelledTasks           int      `json:"cancelled_tasks"`
+    RemovedShifts            int      `json:"removed_shifts"`             // Turnos futuros quitados de los horarios
+    RemovedWaiterAssignments int      `json:"removed_waiter_assignments"` // Secciones futuras quitadas; la vigente termina ahora
+}
+
+// OwnershipTransfer es el resultado de traspasar la propiedad del negocio: el dueño anterior pasa a
+// administrador y ambos deben iniciar sesión de nuevo
+type OwnershipTransfer struct {
+    PreviousOwner Employee `json:"previous_owner"`
+    NewOwner      Employee `json:"new_owner"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// EmployeeAuditAction define las acciones sobre empleados que se registran en employee_audit_logs
+type EmployeeAuditAction string
+
+const (
+    EmployeeAuditCreate            EmployeeAuditAction = "create"
+    EmployeeAuditUpdate            EmployeeAuditAction = "update"
+    EmployeeAuditProfileUpdate     EmployeeAuditAction = "profile_update"  // El empleado edita sus datos de contacto
+    EmployeeAuditPasswordReset     EmployeeAuditAction = "password_reset"  // Otro empleado genera una contraseña nueva
+    EmployeeAuditPasswordChange    EmployeeAuditAction = "password_change" // El empleado cambia su propia contraseña
+    EmployeeAuditDeactivate        EmployeeAuditAction = "deactivate"
+    EmployeeAuditReactivate        EmployeeAuditAction = "reactivate"
+    EmployeeAuditOwnershipTransfer EmployeeAuditAction = "ownership_transfer"
+)
+
+// EmployeeAuditLog representa la tabla employee_audit_logs. OldData y NewData son la fila del
+// empleado antes y después de la acción, sin la contraseña
+type EmployeeAuditLog struct {
+    ID         int                 `json:"id"`
+    EmployeeID int                 `json:"employee_id"`
+    ActorID    int                 `json:"actor_id"`
+    Action     EmployeeAuditAction `json:"action"`
+    OldData    *string             `json:"old_data"` // JSONB se maneja como string; NULL al crear
+    NewData    string              `json:"new_data"`
+    CreatedAt  time.Time           `json:"created_at"`
+}
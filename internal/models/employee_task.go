@@ -9,6 +9,7 @@ const (
     TaskStatusPending    EmployeeTaskStatus = "pendiente"
     TaskStatusInProgress EmployeeTaskStatus = "en_progreso"
     TaskStatusCompleted  EmployeeTaskStatus = "completada"
+    TaskStatusCancelled  EmployeeTaskStatus = "cancelada"
 )
 
 // EmployeeTask representa la tabla employee_tasks
@@ -20,4 +21,15 @@ type EmployeeTask struct {
     AssignedAt      time.Time         `json:"assigned_at"`
     CompletedAt     *time.Time        `json:"completed_at"` // Puede ser NULL, usamos un puntero
     CreatedAt       time.Time         `json:"created_at"`
-}
\ No newline at end of file
+}
+
+// EmployeeTaskStatusChange representa la tabla employee_task_status_history: cada cambio de estado
+// de una tarea con quién lo hizo y cuándo
+type EmployeeTaskStatusChange struct {
+    ID         int                `json:"id"`
+    TaskID     int                `json:"task_id"`
+    FromStatus EmployeeTaskStatus `json:"from_status"`
+    ToStatus   EmployeeTaskStatus `json:"to_status"`
+    ChangedBy  int                `json:"changed_by"`
+    ChangedAt  time.Time          `json:"changed_at"`
+}
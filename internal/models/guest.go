@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// TableQRToken representa el token firmado que se imprime en el código QR de una mesa
+type TableQRToken struct {
+    TableID   int    `json:"table_id"`
+    TableName string `json:"table_name"`
+    Token     string `json:"token"`
+}
+
+// GuestSessionRequest es la solicitud para abrir una sesión de cliente con el token del QR
+type GuestSessionRequest struct {
+    Token string `json:"token"`
+}
+
+// GuestSession es la sesión de un cliente, limitada a la mesa del QR escaneado
+type GuestSession struct {
+    Token     string    `json:"token"`
+    TableID   int       `json:"table_id"`
+    TableName string    `json:"table_name"`
+    ExpiresAt time.Time `json:"expires_at"`
+}
+
+// GuestOrderLineRequest es una línea que el cliente agrega al pedido de su mesa
+type GuestOrderLineRequest struct {
+    MenuItemID int `json:"menu_item_id"`
+    Quantity   int `json:"quantity"`
+}
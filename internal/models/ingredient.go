@@ -0,0 +1,34 @@
+package models
+
+import (
+    "time"
+
+    "github.com/shopspring/decimal"
+)
+
+// IngredientUnit define el tipo ENUM para las unidades de medida de los ingredientes
+type IngredientUnit string
+
+const (
+    IngredientUnitGram       IngredientUnit = "g"
+    IngredientUnitMilliliter IngredientUnit = "ml"
+    IngredientUnitUnit       IngredientUnit = "unit"
+)
+
+// Ingredient representa la tabla ingredients
+type Ingredient struct {
+    ID             int             `json:"id"`
+    IngredientName string          `json:"ingredient_name"`
+    Unit           IngredientUnit  `json:"unit"`
+    Stock          decimal.Decimal `json:"stock"`
+    CreatedAt      time.Time       `json:"created_at"`
+}
+
+// RecipeItem representa la tabla recipe_items: cantidad de un ingrediente por unidad del ítem
+type RecipeItem struct {
+    MenuItemID     int             `json:"menu_item_id"`
+    IngredientID   int             `json:"ingredient_id"`
+    IngredientName string          `json:"ingredient_name"` // Solo lectura, obtenido de ingredients
+    Unit           IngredientUnit  `json:"unit"`            // Solo lectura, obtenido de ingredients
+    Quantity       decimal.Decimal `json:"quantity"`
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+    "time"
+
+    "github.com/shopspring/decimal"
+)
+
+// InventoryCountStatus define el tipo ENUM para los estados de un conteo físico
+type InventoryCountStatus string
+
+const (
+    InventoryCountStatusOpen      InventoryCountStatus = "open"
+    InventoryCountStatusApproved  InventoryCountStatus = "approved"
+    InventoryCountStatusCancelled InventoryCountStatus = "cancelled"
+)
+
+// InventoryCount representa la tabla inventory_counts (una sesión de conteo físico)
+type InventoryCount struct {
+    ID         int                   `json:"id"`
+    Status     InventoryCountStatus  `json:"status"`
+    Notes      string                `json:"notes"`
+    OpenedBy   int                   `json:"opened_by"`
+    OpenedAt   time.Time             `json:"opened_at"`
+    ApprovedBy *int                  `json:"approved_by"` // NULL hasta que se aprueba
+    ApprovedAt *time.Time            `json:"approved_at"` // NULL hasta que se aprueba
+    ClosedAt   *time.Time            `json:"closed_at"`   // NULL mientras está abierto
+    Entries    []InventoryCountEntry `json:"entries,omitempty"`
+}
+
+// InventoryCountEntry representa la tabla inventory_count_entries (lo que contó un empleado de un ítem)
+type InventoryCountEntry struct {
+    ID               int       `json:"id"`
+    InventoryCountID int       `json:"inventory_count_id"`
+    MenuItemID       int       `json:"menu_item_id"`
+    EmployeeID       int       `json:"employee_id"`
+    CountedQuantity  int       `json:"counted_quantity"`
+    UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// InventoryCountVariance compara lo contado de un ítem con el stock del sistema.
+// Mientras el conteo está abierto se calcula con el stock actual; al aprobarlo se guarda en inventory_count_lines
+type InventoryCountVariance struct {
+    MenuItemID      int              `json:"menu_item_id"`
+    ItemName        string           `json:"item_name"`
+    SystemQuantity  int              `json:"system_quantity"`
+    CountedQuantity int              `json:"counted_quantity"`
+    Variance        int              `json:"variance"`       // Contado - sistema: negativo es faltante
+    UnitCost        *decimal.Decimal `json:"unit_cost"`      // Último costo de compra conocido (NULL si nunca se compró)
+    VarianceValue   *decimal.Decimal `json:"variance_value"` // Variance * UnitCost
+}
+
+// InventoryShrinkageReport resume los faltantes y sobrantes de un conteo aprobado
+type InventoryShrinkageReport struct {
+    InventoryCountID int                      `json:"inventory_count_id"`
+    ApprovedBy       *int                     `json:"approved_by"`
+    ApprovedAt       *time.Time               `json:"approved_at"`
+    Lines            []InventoryCountVariance `json:"lines"`
+    ShrinkageUnits   int                      `json:"shrinkage_units"` // Suma de las unidades faltantes (positiva)
+    SurplusUnits     int                      `json:"surplus_units"`   // Suma de las unidades sobrantes
+    ShrinkageValue   decimal.Decimal          `json:"shrinkage_value"` // Valor de los faltantes a costo de compra
+}
@@ -6,13 +6,24 @@ import (
     "github.com/shopspring/decimal"
 )
 
+// MenuItemStockMode define cómo se controla el inventario de un ítem
+type MenuItemStockMode string
+
+const (
+    MenuItemStockModeItem   MenuItemStockMode = "item"   // Stock propio (productos embotellados)
+    MenuItemStockModeRecipe MenuItemStockMode = "recipe" // Disponibilidad derivada de los ingredientes de la receta
+)
+
 // MenuItem representa la tabla menu_items
 type MenuItem struct {
-    ID          int             `json:"id"`
-    ItemName    string          `json:"item_name"`
-    Category    string          `json:"category"`
-    Price       decimal.Decimal `json:"price"` // Usamos decimal.Decimal para NUMERIC
-    Stock       int             `json:"stock"`
-    Description string          `json:"description"`
-    CreatedAt   time.Time       `json:"created_at"`
+    ID                int               `json:"id"`
+    ItemName          string            `json:"item_name"`
+    Category          string            `json:"category"`
+    Price             decimal.Decimal   `json:"price"` // Usamos decimal.Decimal para NUMERIC
+    Stock             int               `json:"stock"` // Se fija al crear; después solo cambia con movimientos de stock
+    StockMode         MenuItemStockMode `json:"stock_mode"`
+    AvailableQuantity int               `json:"available_quantity"` // Calculado: unidades que se pueden vender
+    ReorderThreshold  int               `json:"reorder_threshold"`  // 0 = sin alerta de stock bajo
+    Description       string            `json:"description"`
+    CreatedAt         time.Time         `json:"created_at"`
 }
\ No newline at end of file
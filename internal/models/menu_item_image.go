@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// MenuItemImage representa la tabla menu_item_images
+type MenuItemImage struct {
+    ID            int       `json:"id"`
+    MenuItemID    int       `json:"menu_item_id"`
+    ImagePath     string    `json:"-"`
+    ThumbnailPath string    `json:"-"`
+    ImageURL      string    `json:"image_url"`
+    ThumbnailURL  string    `json:"thumbnail_url"`
+    CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,17 @@
+package models
+
+// MenuItemImportRowError describe un error de validación en una fila del archivo importado
+type MenuItemImportRowError struct {
+    Row      int    `json:"row"` // Número de fila en el archivo (1 = primera fila de datos)
+    ItemName string `json:"item_name"`
+    Error    string `json:"error"`
+}
+
+// MenuItemImportResult resume el resultado (o la simulación) de una importación del menú
+type MenuItemImportResult struct {
+    DryRun  bool                     `json:"dry_run"`
+    Total   int                      `json:"total"`
+    Created int                      `json:"created"`
+    Updated int                      `json:"updated"`
+    Errors  []MenuItemImportRowError `json:"errors"`
+}
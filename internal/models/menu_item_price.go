@@ -0,0 +1,39 @@
+package models
+
+import (
+    "time"
+
+    "github.com/shopspring/decimal"
+)
+
+// ScheduledPriceStatus define el tipo ENUM para los estados de un cambio de precio programado
+type ScheduledPriceStatus string
+
+const (
+    ScheduledPriceStatusPending   ScheduledPriceStatus = "pending"
+    ScheduledPriceStatusApplied   ScheduledPriceStatus = "applied"
+    ScheduledPriceStatusCancelled ScheduledPriceStatus = "cancelled"
+)
+
+// MenuItemPriceHistory representa la tabla menu_item_price_history
+type MenuItemPriceHistory struct {
+    ID                     int             `json:"id"`
+    MenuItemID             int             `json:"menu_item_id"`
+    OldPrice               decimal.Decimal `json:"old_price"`
+    NewPrice               decimal.Decimal `json:"new_price"`
+    ChangedBy              int             `json:"changed_by"`
+    ScheduledPriceChangeID *int            `json:"scheduled_price_change_id"` // NULL si el cambio fue manual
+    ChangedAt              time.Time       `json:"changed_at"`
+}
+
+// ScheduledPriceChange representa la tabla menu_item_scheduled_prices
+type ScheduledPriceChange struct {
+    ID          int                  `json:"id"`
+    MenuItemID  int                  `json:"menu_item_id"`
+    NewPrice    decimal.Decimal      `json:"new_price"`
+    EffectiveAt time.Time            `json:"effective_at"`
+    Status      ScheduledPriceStatus `json:"status"`
+    CreatedBy   int                  `json:"created_by"`
+    AppliedAt   *time.Time           `json:"applied_at"` // Puede ser NULL, usamos un puntero
+    CreatedAt   time.Time            `json:"created_at"`
+}
@@ -0,0 +1,44 @@
+package models
+
+// MenuItemTag define las etiquetas de alérgenos y dietas de un ítem del menú
+type MenuItemTag string
+
+const (
+    // Etiquetas de dieta
+    TagVegan       MenuItemTag = "vegano"
+    TagVegetarian  MenuItemTag = "vegetariano"
+    TagGlutenFree  MenuItemTag = "sin_gluten"
+    TagLactoseFree MenuItemTag = "sin_lactosa"
+
+    // Etiquetas de alérgenos
+    AllergenGluten    MenuItemTag = "gluten"
+    AllergenDairy     MenuItemTag = "lacteos"
+    AllergenEgg       MenuItemTag = "huevo"
+    AllergenNuts      MenuItemTag = "frutos_secos"
+    AllergenPeanut    MenuItemTag = "mani"
+    AllergenShellfish MenuItemTag = "mariscos"
+    AllergenFish      MenuItemTag = "pescado"
+    AllergenSoy       MenuItemTag = "soya"
+    AllergenSesame    MenuItemTag = "sesamo"
+)
+
+// DietaryTags contiene las etiquetas de dieta permitidas
+var DietaryTags = map[MenuItemTag]bool{
+    TagVegan:       true,
+    TagVegetarian:  true,
+    TagGlutenFree:  true,
+    TagLactoseFree: true,
+}
+
+// AllergenTags contiene las etiquetas de alérgenos permitidas
+var AllergenTags = map[MenuItemTag]bool{
+    AllergenGluten:    true,
+    AllergenDairy:     true,
+    AllergenEgg:       true,
+    AllergenNuts:      true,
+    AllergenPeanut:    true,
+    AllergenShellfish: true,
+    AllergenFish:      true,
+    AllergenSoy:       true,
+    AllergenSesame:    true,
+}
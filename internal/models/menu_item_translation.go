@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// MenuItemTranslation representa la tabla menu_item_translations
+type MenuItemTranslation struct {
+    ID          int       `json:"id"`
+    MenuItemID  int       `json:"menu_item_id"`
+    Locale      string    `json:"locale"`
+    ItemName    string    `json:"item_name"`
+    Description string    `json:"description"`
+    UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -2,11 +2,30 @@ package models
 
 import "time"
 
+// OrderDetailStatus define los estados de una línea de pedido
+type OrderDetailStatus string
+
+const (
+    OrderDetailStatusPendingConfirmation OrderDetailStatus = "pending_confirmation"
+    OrderDetailStatusConfirmed           OrderDetailStatus = "confirmed"
+)
+
+// OrderDetailSource indica quién registró la línea de pedido
+type OrderDetailSource string
+
+const (
+    OrderDetailSourceStaff OrderDetailSource = "staff"
+    OrderDetailSourceGuest OrderDetailSource = "guest"
+)
+
 type OrderDetail struct {
-    ID         int       `json:"id"`
-    OrderID    int       `json:"order_id"`
-    MenuItemID int       `json:"menu_item_id,omitempty"`
-    MenuItem   MenuItem  `json:"menu_item"`              
-    Quantity   int       `json:"quantity"`
-    CreatedAt  time.Time `json:"created_at"`
-}
\ No newline at end of file
+    ID         int               `json:"id"`
+    OrderID    int               `json:"order_id"`
+    MenuItemID int               `json:"menu_item_id,omitempty"`
+    MenuItem   MenuItem          `json:"menu_item"`              
+    Quantity   int               `json:"quantity"`
+    Status     OrderDetailStatus `json:"status"`
+    Source     OrderDetailSource `json:"source"`
+    CreatedBy  *int              `json:"created_by"` // NULL para las líneas de clientes por QR
+    CreatedAt  time.Time         `json:"created_at"`
+}
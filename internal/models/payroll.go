@@ -0,0 +1,89 @@
+package models
+
+import (
+    "time"
+
+    "github.com/shopspring/decimal"
+)
+
+// PayRate representa la tarifa por hora de un empleado (tabla employee_pay_rates)
+type PayRate struct {
+    EmployeeID   int              `json:"employee_id"`
+    EmployeeName string           `json:"employee_name"`
+    HourlyRate   *decimal.Decimal `json:"hourly_rate"` // NULL si el empleado todavía no tiene tarifa
+    UpdatedBy    *int             `json:"updated_by"`
+    UpdatedAt    *time.Time       `json:"updated_at"`
+}
+
+// PayrollSettings representa la tabla payroll_settings. Los recargos son fracciones de la tarifa
+// que se suman a la hora base (0.35 es un 35% adicional)
+type PayrollSettings struct {
+    DailyOvertimeHours decimal.Decimal `json:"daily_overtime_hours"` // Horas diarias a partir de las cuales se pagan extras
+    OvertimeSurcharge  decimal.Decimal `json:"overtime_surcharge"`
+    NightStart         string          `json:"night_start"` // HH:MM
+    NightEnd           string          `json:"night_end"`   // HH:MM
+    NightSurcharge     decimal.Decimal `json:"night_surcharge"`
+    HolidaySurcharge   decimal.Decimal `json:"holiday_surcharge"`
+    SundayIsHoliday    bool            `json:"sunday_is_holiday"`
+    UpdatedBy          *int            `json:"updated_by"`
+    UpdatedAt          *time.Time      `json:"updated_at"`
+}
+
+// Holiday representa la tabla holidays
+type Holiday struct {
+    Date        string `json:"date"` // YYYY-MM-DD
+    Description string `json:"description"`
+}
+
+// TipPoolEntry representa la tabla tip_pool_entries
+type TipPoolEntry struct {
+    ID          int             `json:"id"`
+    CollectedOn string          `json:"collected_on"` // YYYY-MM-DD
+    Amount      decimal.Decimal `json:"amount"`
+    Notes       string          `json:"notes"`
+    CreatedBy   int             `json:"created_by"`
+    CreatedAt   time.Time       `json:"created_at"`
+}
+
+// PayrollLine es la liquidación de un empleado en el periodo. Las horas nocturnas y festivas
+// también cuentan dentro de las horas trabajadas; sus recargos se suman a la paga base
+type PayrollLine struct {
+    EmployeeID    int              `json:"employee_id"`
+    EmployeeName  string           `json:"employee_name"`
+    HourlyRate    *decimal.Decimal `json:"hourly_rate"`
+    MissingRate   bool             `json:"missing_rate"` // Sin tarifa: las horas se informan pero no se pagan
+    WorkedHours   decimal.Decimal  `json:"worked_hours"`
+    RegularHours  decimal.Decimal  `json:"regular_hours"`
+    OvertimeHours decimal.Decimal  `json:"overtime_hours"`
+    NightHours    decimal.Decimal  `json:"night_hours"`
+    HolidayHours  decimal.Decimal  `json:"holiday_hours"`
+    BasePay       decimal.Decimal  `json:"base_pay"`     // Horas trabajadas * tarifa
+    OvertimePay   decimal.Decimal  `json:"overtime_pay"` // Recargo de las horas extra
+    NightPay      decimal.Decimal  `json:"night_pay"`
+    HolidayPay    decimal.Decimal  `json:"holiday_pay"`
+    GrossPay      decimal.Decimal  `json:"gross_pay"`
+    TipShare      decimal.Decimal  `json:"tip_share"` // Parte del fondo de propinas según las horas trabajadas
+    TotalPay      decimal.Decimal  `json:"total_pay"`
+    OpenEntries   int              `json:"open_entries"` // Jornadas sin salida, que no se liquidan
+}
+
+// PayrollReport es la liquidación de todos los empleados con horas o tarifa en el periodo
+type PayrollReport struct {
+    From              time.Time       `json:"from"`
+    To                time.Time       `json:"to"`
+    Settings          PayrollSettings `json:"settings"`
+    Lines             []PayrollLine   `json:"lines"`
+    TipPool           decimal.Decimal `json:"tip_pool"`
+    WorkedHours       decimal.Decimal `json:"worked_hours"`
+    GrossPay          decimal.Decimal `json:"gross_pay"`
+    TipShare          decimal.Decimal `json:"tip_share"`
+    UndistributedTips decimal.Decimal `json:"undistributed_tips"` // Propinas del periodo sin repartir porque nadie registró horas
+    TotalPay          decimal.Decimal `json:"total_pay"`
+    MissingRates      int             `json:"missing_rates"`
+    OpenEntries       int             `json:"open_entries"`
+}
+
+// PayRateRequest es el cuerpo para fijar la tarifa de un empleado
+type PayRateRequest struct {
+    HourlyRate decimal.Decimal `json:"hourly_rate"`
+}
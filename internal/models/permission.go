@@ -0,0 +1,106 @@
+package models
+
+// Permission identifica una acción del personal que se autoriza por rol (tabla permissions)
+type Permission string
+
+const (
+    PermissionBusinessView               Permission = "business.view"
+    PermissionBusinessEdit               Permission = "business.edit"
+    PermissionEmployeesView              Permission = "employees.view"
+    PermissionEmployeesManage            Permission = "employees.manage"
+    PermissionEmployeesAudit             Permission = "employees.audit"
+    PermissionEmployeesTransferOwnership Permission = "employees.transfer_ownership"
+    PermissionTasksView                  Permission = "tasks.view"
+    PermissionTasksManage                Permission = "tasks.manage"
+    PermissionTablesView                 Permission = "tables.view"
+    PermissionTablesUpdate               Permission = "tables.update"
+    PermissionTablesManage               Permission = "tables.manage"
+    PermissionReservationsManage         Permission = "reservations.manage"
+    PermissionWaitlistManage             Permission = "waitlist.manage"
+    PermissionWaiterAssignmentsManage    Permission = "waiter_assignments.manage"
+    PermissionSchedulesManage            Permission = "schedules.manage"
+    PermissionTimeClockManage            Permission = "timeclock.manage"
+    PermissionPayrollView                Permission = "payroll.view"
+    PermissionPayrollManage              Permission = "payroll.manage"
+    PermissionPayrollConfigure           Permission = "payroll.configure"
+    PermissionMenuView                   Permission = "menu.view"
+    PermissionMenuEdit                   Permission = "menu.edit"
+    PermissionMenuExport                 Permission = "menu.export"
+    PermissionStockViewAlerts            Permission = "stock.view_alerts"
+    PermissionInventoryView              Permission = "inventory.view"
+    PermissionInventoryEdit              Permission = "inventory.edit"
+    PermissionInventoryAdjust            Permission = "inventory.adjust"
+    PermissionSuppliersManage            Permission = "suppliers.manage"
+    PermissionPurchasingManage           Permission = "purchasing.manage"
+    PermissionInventoryCountsRecord      Permission = "inventory_counts.record"
+    PermissionInventoryCountsManage      Permission = "inventory_counts.manage"
+    PermissionReportsView                Permission = "reports.view"
+    PermissionOrdersView                 Permission = "orders.view"
+    PermissionOrdersEdit                 Permission = "orders.edit"
+    PermissionOrdersVoid                 Permission = "orders.void"
+    PermissionOrdersClose                Permission = "orders.close"
+    PermissionPermissionsManage          Permission = "permissions.manage"
+)
+
+// AllPermissions es el catálogo de permisos (las filas que siembra database.sql en permissions)
+var AllPermissions = []Permission{
+    PermissionBusinessView, PermissionBusinessEdit,
+    PermissionEmployeesView, PermissionEmployeesManage, PermissionEmployeesAudit, PermissionEmployeesTransferOwnership,
+    PermissionTasksView, PermissionTasksManage,
+    PermissionTablesView, PermissionTablesUpdate, PermissionTablesManage,
+    PermissionReservationsManage, PermissionWaitlistManage, PermissionWaiterAssignmentsManage,
+    PermissionSchedulesManage, PermissionTimeClockManage,
+    PermissionPayrollView, PermissionPayrollManage, PermissionPayrollConfigure,
+    PermissionMenuView, PermissionMenuEdit, PermissionMenuExport,
+    PermissionStockViewAlerts, PermissionInventoryView, PermissionInventoryEdit, PermissionInventoryAdjust,
+    PermissionSuppliersManage, PermissionPurchasingManage,
+    PermissionInventoryCountsRecord, PermissionInventoryCountsManage,
+    PermissionReportsView,
+    PermissionOrdersView, PermissionOrdersEdit, PermissionOrdersVoid, PermissionOrdersClose,
+    PermissionPermissionsManage,
+}
+
+// DefaultRolePermissions devuelve los permisos con los que se siembra cada rol cuando role_permissions
+// está vacía: el dueño tiene todos, el administrador todos salvo la configuración de la nómina, los
+// reportes, los permisos y el traspaso de propiedad, y el empleado los del servicio en sala
+func DefaultRolePermissions() map[EmployeeRole][]Permission {
+    adminExcluded := map[Permission]bool{
+        PermissionPayrollConfigure:           true,
+        PermissionReportsView:                true,
+        PermissionPermissionsManage:          true,
+        PermissionEmployeesTransferOwnership: true,
+    }
+    var admin []Permission
+    for _, permission := range AllPermissions {
+        if !adminExcluded[permission] {
+            admin = append(admin, permission)
+        }
+    }
+    return map[EmployeeRole][]Permission{
+        EmployeeRoleOwner: append([]Permission{}, AllPermissions...),
+        EmployeeRoleAdmin: admin,
+        EmployeeRoleEmployee: {
+            PermissionTablesView, PermissionTablesUpdate,
+            PermissionReservationsManage, PermissionWaitlistManage,
+            PermissionStockViewAlerts, PermissionInventoryCountsRecord,
+            PermissionOrdersView, PermissionOrdersEdit, PermissionOrdersVoid, PermissionOrdersClose,
+        },
+    }
+}
+
+// PermissionInfo representa una fila de la tabla permissions
+type PermissionInfo struct {
+    Code        Permission `json:"code"`
+    Description string     `json:"description"`
+}
+
+// RolePermissions son los permisos concedidos a un rol (tabla role_permissions)
+type RolePermissions struct {
+    Role        EmployeeRole `json:"role"`
+    Permissions []Permission `json:"permissions"`
+}
+
+// RolePermissionsRequest es el cuerpo para reemplazar los permisos de un rol
+type RolePermissionsRequest struct {
+    Permissions []Permission `json:"permissions"`
+}
@@ -0,0 +1,28 @@
+package models
+
+import "github.com/shopspring/decimal"
+
+// PublicMenuItem representa un ítem tal como se muestra en la carta digital
+type PublicMenuItem struct {
+    ID           int             `json:"id"`
+    ItemName     string          `json:"item_name"`
+    Description  string          `json:"description"`
+    Price        decimal.Decimal `json:"price"`
+    Available    bool            `json:"available"`
+    ImageURL     string          `json:"image_url,omitempty"`
+    ThumbnailURL string          `json:"thumbnail_url,omitempty"`
+    DietaryTags  []MenuItemTag   `json:"dietary_tags"`
+    Allergens    []MenuItemTag   `json:"allergens"`
+}
+
+// PublicMenuCategory agrupa los ítems de la carta digital por categoría
+type PublicMenuCategory struct {
+    Category string           `json:"category"`
+    Items    []PublicMenuItem `json:"items"`
+}
+
+// PublicMenu representa la carta digital en un idioma
+type PublicMenu struct {
+    Locale     string               `json:"locale"`
+    Categories []PublicMenuCategory `json:"categories"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+    "time"
+
+    "github.com/shopspring/decimal"
+)
+
+// PurchaseOrderStatus define el tipo ENUM para los estados de una orden de compra
+type PurchaseOrderStatus string
+
+const (
+    PurchaseOrderStatusDraft             PurchaseOrderStatus = "draft"
+    PurchaseOrderStatusSent              PurchaseOrderStatus = "sent"
+    PurchaseOrderStatusPartiallyReceived PurchaseOrderStatus = "partially_received"
+    PurchaseOrderStatusReceived          PurchaseOrderStatus = "received"
+)
+
+// PurchaseOrder representa la tabla purchase_orders
+type PurchaseOrder struct {
+    ID            int                 `json:"id"`
+    SupplierID    int                 `json:"supplier_id"`
+    Status        PurchaseOrderStatus `json:"status"`
+    Notes         string              `json:"notes"`
+    CreatedBy     int                 `json:"created_by"`
+    SentAt        *time.Time          `json:"sent_at"`     // Puede ser NULL, usamos un puntero
+    ReceivedAt    *time.Time          `json:"received_at"` // Puede ser NULL, usamos un puntero
+    CreatedAt     time.Time           `json:"created_at"`
+    ExpectedTotal decimal.Decimal     `json:"expected_total"` // Calculado: suma de cantidad * costo esperado
+    Lines         []PurchaseOrderLine `json:"lines,omitempty"`
+}
+
+// PurchaseOrderLine representa la tabla purchase_order_lines (un ítem o un ingrediente por línea)
+type PurchaseOrderLine struct {
+    ID               int              `json:"id"`
+    PurchaseOrderID  int              `json:"purchase_order_id"`
+    MenuItemID       *int             `json:"menu_item_id"`  // NULL si la línea es de un ingrediente
+    IngredientID     *int             `json:"ingredient_id"` // NULL si la línea es de un ítem
+    Quantity         decimal.Decimal  `json:"quantity"`
+    ExpectedUnitCost decimal.Decimal  `json:"expected_unit_cost"`
+    ReceivedQuantity decimal.Decimal  `json:"received_quantity"`
+    ActualUnitCost   *decimal.Decimal `json:"actual_unit_cost"` // NULL hasta la primera recepción
+}
+
+// PurchaseOrderReceiptLine indica cuánto se recibió de una línea y a qué costo unitario real
+type PurchaseOrderReceiptLine struct {
+    LineID   int             `json:"line_id"`
+    Quantity decimal.Decimal `json:"quantity"`
+    UnitCost decimal.Decimal `json:"unit_cost"`
+}
+
+// PurchaseOrderReceipt es el cuerpo de la operación de recepción de mercancía
+type PurchaseOrderReceipt struct {
+    Lines []PurchaseOrderReceiptLine `json:"lines"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// ReservationStatus define el tipo ENUM para los estados de una reserva
+type ReservationStatus string
+
+const (
+    ReservationStatusBooked    ReservationStatus = "booked"
+    ReservationStatusSeated    ReservationStatus = "seated"
+    ReservationStatusNoShow    ReservationStatus = "no_show"
+    ReservationStatusCancelled ReservationStatus = "cancelled"
+)
+
+// Reservation representa la tabla reservations junto con sus mesas asignadas
+type Reservation struct {
+    ID              int               `json:"id"`
+    GuestName       string            `json:"guest_name"`
+    GuestPhone      string            `json:"guest_phone"`
+    PartySize       int               `json:"party_size"`
+    ReservedAt      time.Time         `json:"reserved_at"`
+    DurationMinutes int               `json:"duration_minutes"`
+    Status          ReservationStatus `json:"status"`
+    Notes           string            `json:"notes"`
+    TableIDs        []int             `json:"table_ids"`
+    CustomerOrderID *int              `json:"customer_order_id"` // Pedido abierto al sentar a los clientes
+    CreatedBy       int               `json:"created_by"`
+    SeatedAt        *time.Time        `json:"seated_at"` // Puede ser NULL, usamos un puntero
+    CreatedAt       time.Time         `json:"created_at"`
+}
+
+// EndsAt devuelve la hora hasta la que la reserva ocupa sus mesas
+func (r Reservation) EndsAt() time.Time {
+    return r.ReservedAt.Add(time.Duration(r.DurationMinutes) * time.Minute)
+}
+
+// ReservationAvailability es el resultado de buscar mesas libres para una reserva
+type ReservationAvailability struct {
+    ReservedAt        time.Time `json:"reserved_at"`
+    DurationMinutes   int       `json:"duration_minutes"`
+    PartySize         int       `json:"party_size"`
+    Available         bool      `json:"available"`
+    AvailableTables   []Table   `json:"available_tables"`    // Mesas activas sin reservas que se crucen
+    SuggestedTableIDs []int     `json:"suggested_table_ids"` // Mesas sugeridas para el tamaño del grupo
+}
@@ -0,0 +1,81 @@
+package models
+
+import "time"
+
+// ShiftRole define el tipo ENUM para el rol que cumple un empleado durante un turno
+type ShiftRole string
+
+const (
+    ShiftRoleWaiter     ShiftRole = "mesero"
+    ShiftRoleKitchen    ShiftRole = "cocina"
+    ShiftRoleBar        ShiftRole = "barra"
+    ShiftRoleCashier    ShiftRole = "caja"
+    ShiftRoleSupervisor ShiftRole = "encargado"
+)
+
+// ScheduleStatus define el tipo ENUM para los estados de un horario semanal
+type ScheduleStatus string
+
+const (
+    ScheduleStatusDraft     ScheduleStatus = "draft"
+    ScheduleStatusPublished ScheduleStatus = "published"
+)
+
+// ShiftConflictType define el tipo de conflicto detectado entre dos turnos de un empleado
+type ShiftConflictType string
+
+const (
+    ShiftConflictOverlap ShiftConflictType = "overlap"
+    ShiftConflictMinRest ShiftConflictType = "min_rest"
+)
+
+// ShiftTemplate representa la tabla shift_templates
+type ShiftTemplate struct {
+    ID           int       `json:"id"`
+    TemplateName string    `json:"template_name"`
+    Role         ShiftRole `json:"role"`
+    StartTime    string    `json:"start_time"` // Formato HH:MM
+    EndTime      string    `json:"end_time"`   // Si es menor o igual que start_time termina al día siguiente
+    CreatedAt    time.Time `json:"created_at"`
+}
+
+// Schedule representa la tabla schedules junto con sus turnos
+type Schedule struct {
+    ID          int            `json:"id"`
+    WeekStart   string         `json:"week_start"` // Lunes de la semana, formato YYYY-MM-DD
+    Status      ScheduleStatus `json:"status"`
+    PublishedAt *time.Time     `json:"published_at"`
+    PublishedBy *int           `json:"published_by"`
+    CreatedBy   int            `json:"created_by"`
+    CreatedAt   time.Time      `json:"created_at"`
+    Shifts      []Shift        `json:"shifts,omitempty"`
+}
+
+// Shift representa la tabla shifts
+type Shift struct {
+    ID              int       `json:"id"`
+    ScheduleID      int       `json:"schedule_id"`
+    EmployeeID      int       `json:"employee_id"`
+    EmployeeName    string    `json:"employee_name"`
+    ShiftTemplateID *int      `json:"shift_template_id"`
+    Role            ShiftRole `json:"role"`
+    StartsAt        time.Time `json:"starts_at"`
+    EndsAt          time.Time `json:"ends_at"`
+    Notes           string    `json:"notes"`
+    CreatedAt       time.Time `json:"created_at"`
+    Date            string    `json:"date,omitempty"` // Solo al crear desde una plantilla: día del turno (YYYY-MM-DD)
+}
+
+// ShiftConflict es un conflicto entre un turno del horario y otro turno del mismo empleado
+type ShiftConflict struct {
+    Type               ShiftConflictType `json:"type"`
+    EmployeeID         int               `json:"employee_id"`
+    ShiftID            int               `json:"shift_id"`
+    ConflictingShiftID int               `json:"conflicting_shift_id"`
+    RestMinutes        int               `json:"rest_minutes,omitempty"` // Descanso entre ambos turnos (solo min_rest)
+}
+
+// ScheduleCopyRequest indica la semana a la que se copia un horario; por defecto la siguiente
+type ScheduleCopyRequest struct {
+    WeekStart string `json:"week_start"`
+}
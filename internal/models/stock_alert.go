@@ -0,0 +1,10 @@
+package models
+
+// LowStockAlert es el contenido de la alerta emitida cuando un ítem queda por debajo de su umbral
+type LowStockAlert struct {
+    MenuItemID        int    `json:"menu_item_id"`
+    ItemName          string `json:"item_name"`
+    AvailableQuantity int    `json:"available_quantity"`
+    ReorderThreshold  int    `json:"reorder_threshold"`
+    OrderDetailID     int    `json:"order_detail_id"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+    "time"
+
+    "github.com/shopspring/decimal"
+)
+
+// StockMovementType define el tipo ENUM para los tipos de movimiento de inventario
+type StockMovementType string
+
+const (
+    StockMovementTypeSale            StockMovementType = "sale"
+    StockMovementTypeReturn          StockMovementType = "return"
+    StockMovementTypePurchase        StockMovementType = "purchase"
+    StockMovementTypeWaste           StockMovementType = "waste"
+    StockMovementTypeAdjustment      StockMovementType = "adjustment"
+    StockMovementTypeCountCorrection StockMovementType = "count_correction"
+)
+
+// StockMovement representa la tabla stock_movements (libro de inventario de solo inserción)
+type StockMovement struct {
+    ID                  int               `json:"id"`
+    MenuItemID          *int              `json:"menu_item_id"`  // NULL si el movimiento es de un ingrediente
+    IngredientID        *int              `json:"ingredient_id"` // NULL si el movimiento es de un ítem
+    MovementType        StockMovementType `json:"movement_type"`
+    Quantity            decimal.Decimal   `json:"quantity"` // Cambio con signo: negativo para salidas
+    EmployeeID          *int              `json:"employee_id"` // NULL para movimientos automáticos (ventas)
+    Reason              string            `json:"reason"`
+    OrderDetailID       *int              `json:"order_detail_id"`
+    PurchaseOrderLineID *int              `json:"purchase_order_line_id"`
+    UnitCost            *decimal.Decimal  `json:"unit_cost"` // Solo compras: costo unitario real
+    InventoryCountID    *int              `json:"inventory_count_id"` // Solo correcciones de conteo
+    CreatedAt           time.Time         `json:"created_at"`
+}
+
+// StockReconciliationEntry compara el stock almacenado con el que resulta de sumar el libro
+type StockReconciliationEntry struct {
+    MenuItemID   *int            `json:"menu_item_id"`
+    IngredientID *int            `json:"ingredient_id"`
+    Name         string          `json:"name"`
+    Stock        decimal.Decimal `json:"stock"`
+    LedgerStock  decimal.Decimal `json:"ledger_stock"`
+    Difference   decimal.Decimal `json:"difference"`
+}
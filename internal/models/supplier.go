@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Supplier representa la tabla suppliers
+type Supplier struct {
+    ID           int       `json:"id"`
+    SupplierName string    `json:"supplier_name"`
+    NIT          string    `json:"nit"` // Número de identificación tributaria, con o sin dígito de verificación
+    ContactName  string    `json:"contact_name"`
+    PhoneNumber  string    `json:"phone_number"`
+    Email        string    `json:"email"`
+    CreatedAt    time.Time `json:"created_at"`
+}
@@ -2,9 +2,36 @@ package models
 
 import "time"
 
+// TableZone define el tipo ENUM para las zonas del local
+type TableZone string
+
+const (
+    TableZoneTerrace TableZone = "terraza"
+    TableZoneBar     TableZone = "barra"
+    TableZoneHall    TableZone = "salón"
+)
+
+// TableStatus define el estado en vivo de una mesa, derivado de su pedido pendiente y sus reservas
+type TableStatus string
+
+const (
+    TableStatusFree            TableStatus = "free"
+    TableStatusOccupied        TableStatus = "occupied"
+    TableStatusAwaitingPayment TableStatus = "awaiting_payment"
+    TableStatusReserved        TableStatus = "reserved"
+)
+
 // Table representa la tabla tables
 type Table struct {
-    ID          int       `json:"id"`
-    TableName   string    `json:"table_name"`
-    CreatedAt   time.Time `json:"created_at"`
-}
\ No newline at end of file
+    ID          int          `json:"id"`
+    TableName   string       `json:"table_name"`
+    Capacity    int          `json:"capacity"`
+    Zone        TableZone    `json:"zone"`
+    PosX        int          `json:"pos_x"` // Posición en el plano del local
+    PosY        int          `json:"pos_y"`
+    QRVersion   int          `json:"-"` // Versión del código QR, se incrementa al rotarlo
+    Active      bool         `json:"active"`
+    CreatedAt   time.Time    `json:"created_at"`
+    Status      *TableStatus `json:"status,omitempty"`        // Solo con ?include=status
+    OpenOrderID *int         `json:"open_order_id,omitempty"` // Pedido pendiente de la mesa (solo con ?include=status)
+}
@@ -0,0 +1,130 @@
+package models
+
+import "time"
+
+// TimeClockState define el estado de asistencia en que se encuentra un empleado
+type TimeClockState string
+
+const (
+    TimeClockStateClockedOut TimeClockState = "clocked_out"
+    TimeClockStateWorking    TimeClockState = "working"
+    TimeClockStateOnBreak    TimeClockState = "on_break"
+)
+
+// MissedPunchType define qué marcación falta
+type MissedPunchType string
+
+const (
+    MissedPunchClockIn  MissedPunchType = "clock_in"  // Turno publicado sin ninguna jornada registrada
+    MissedPunchClockOut MissedPunchType = "clock_out" // Jornada que quedó abierta
+)
+
+// TimesheetLineStatus define el resultado de comparar un turno con las jornadas trabajadas
+type TimesheetLineStatus string
+
+const (
+    TimesheetLineOK          TimesheetLineStatus = "ok"
+    TimesheetLineInProgress  TimesheetLineStatus = "in_progress"
+    TimesheetLineUpcoming    TimesheetLineStatus = "upcoming"
+    TimesheetLineAbsent      TimesheetLineStatus = "absent"
+    TimesheetLineMissedPunch TimesheetLineStatus = "missed_punch"
+    TimesheetLineUnscheduled TimesheetLineStatus = "unscheduled"
+)
+
+// TimeEntry representa la tabla time_entries junto con sus pausas
+type TimeEntry struct {
+    ID            int              `json:"id"`
+    EmployeeID    int              `json:"employee_id"`
+    ClockInAt     time.Time        `json:"clock_in_at"`
+    ClockOutAt    *time.Time       `json:"clock_out_at"` // NULL mientras la jornada sigue abierta
+    Breaks        []TimeEntryBreak `json:"breaks"`
+    WorkedMinutes int              `json:"worked_minutes"` // Tiempo de la jornada sin las pausas
+    BreakMinutes  int              `json:"break_minutes"`
+    AdjustedBy    *int             `json:"adjusted_by"` // Empleado que corrigió la jornada a mano
+    AdjustedAt    *time.Time       `json:"adjusted_at"`
+    CreatedAt     time.Time        `json:"created_at"`
+}
+
+// TimeEntryBreak representa la tabla time_entry_breaks
+type TimeEntryBreak struct {
+    ID          int        `json:"id"`
+    TimeEntryID int        `json:"time_entry_id"`
+    StartedAt   time.Time  `json:"started_at"`
+    EndedAt     *time.Time `json:"ended_at"` // NULL mientras la pausa sigue en curso
+}
+
+// End devuelve el fin de la jornada; si sigue abierta se toma now
+func (e TimeEntry) End(now time.Time) time.Time {
+    if e.ClockOutAt != nil {
+        return *e.ClockOutAt
+    }
+    return now
+}
+
+// BreakDuration suma las pausas de la jornada; una pausa en curso cuenta hasta el fin de la jornada
+func (e TimeEntry) BreakDuration(now time.Time) time.Duration {
+    var total time.Duration
+    for _, b := range e.Breaks {
+        end := e.End(now)
+        if b.EndedAt != nil {
+            end = *b.EndedAt
+        }
+        if end.After(b.StartedAt) {
+            total += end.Sub(b.StartedAt)
+        }
+    }
+    return total
+}
+
+// WorkedDuration devuelve el tiempo trabajado de la jornada descontando las pausas
+func (e TimeEntry) WorkedDuration(now time.Time) time.Duration {
+    return e.End(now).Sub(e.ClockInAt) - e.BreakDuration(now)
+}
+
+// TimeClockStatus es el estado de asistencia actual de un empleado
+type TimeClockStatus struct {
+    EmployeeID int            `json:"employee_id"`
+    State      TimeClockState `json:"state"`
+    Entry      *TimeEntry     `json:"entry"` // Jornada abierta, si la hay
+}
+
+// MissedPunch es una marcación que falta: un turno sin entrada o una jornada sin salida
+type MissedPunch struct {
+    Type         MissedPunchType `json:"type"`
+    EmployeeID   int             `json:"employee_id"`
+    EmployeeName string          `json:"employee_name"`
+    TimeEntryID  *int            `json:"time_entry_id"` // Solo para clock_out
+    ShiftID      *int            `json:"shift_id"`
+    ExpectedAt   time.Time       `json:"expected_at"` // Hora a la que se esperaba la marcación
+}
+
+// TimesheetLine compara un turno publicado con las jornadas trabajadas en él; las jornadas fuera de
+// turno aparecen como líneas sin turno
+type TimesheetLine struct {
+    Date              string              `json:"date"` // YYYY-MM-DD
+    Shift             *Shift              `json:"shift"`
+    Entries           []TimeEntry         `json:"entries"`
+    Status            TimesheetLineStatus `json:"status"`
+    ScheduledMinutes  int                 `json:"scheduled_minutes"`
+    WorkedMinutes     int                 `json:"worked_minutes"`
+    BreakMinutes      int                 `json:"break_minutes"`
+    DifferenceMinutes int                 `json:"difference_minutes"` // Trabajado menos programado
+    LateMinutes       int                 `json:"late_minutes"`
+    EarlyLeaveMinutes int                 `json:"early_leave_minutes"`
+}
+
+// Timesheet es la hoja de asistencia de un empleado en un periodo
+type Timesheet struct {
+    EmployeeID        int             `json:"employee_id"`
+    EmployeeName      string          `json:"employee_name"`
+    From              time.Time       `json:"from"`
+    To                time.Time       `json:"to"`
+    Lines             []TimesheetLine `json:"lines"`
+    ScheduledMinutes  int             `json:"scheduled_minutes"`
+    WorkedMinutes     int             `json:"worked_minutes"`
+    BreakMinutes      int             `json:"break_minutes"`
+    DifferenceMinutes int             `json:"difference_minutes"`
+    LateCount         int             `json:"late_count"`
+    AbsentCount       int             `json:"absent_count"`
+    MissedPunchCount  int             `json:"missed_punch_count"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "strings"
+
+// FieldError describe un campo inválido de una solicitud
+type FieldError struct {
+    Field   string `json:"field"`
+    Message string `json:"message"`
+}
+
+// ValidationError agrupa los errores por campo de una solicitud para devolverlos todos juntos
+type ValidationError struct {
+    Fields []FieldError `json:"fields"`
+}
+
+// Add registra un error en un campo
+func (e *ValidationError) Add(field, message string) {
+    e.Fields = append(e.Fields, FieldError{Field: field, Message: message})
+}
+
+// HasErrors indica si se registró algún error
+func (e *ValidationError) HasErrors() bool {
+    return len(e.Fields) > 0
+}
+
+func (e *ValidationError) Error() string {
+    messages := make([]string, len(e.Fields))
+    for i, field := range e.Fields {
+        messages[i] = field.Field + ": " + field.Message
+    }
+    return "validation failed: " + strings.Join(messages, "; ")
+}
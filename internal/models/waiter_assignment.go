@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// WaiterAssignment representa la tabla waiter_assignments junto con las mesas de la sección
+type WaiterAssignment struct {
+    ID         int       `json:"id"`
+    EmployeeID int       `json:"employee_id"`
+    TableIDs   []int     `json:"table_ids"`
+    StartsAt   time.Time `json:"starts_at"`
+    EndsAt     time.Time `json:"ends_at"`
+    CreatedBy  int       `json:"created_by"`
+    CreatedAt  time.Time `json:"created_at"`
+}
+
+// MyTables es lo que ve un mesero de su turno: la sección asignada con el estado en vivo de sus
+// mesas y los pedidos abiertos que tiene a cargo
+type MyTables struct {
+    EmployeeID  int                `json:"employee_id"`
+    Assignments []WaiterAssignment `json:"assignments"` // Asignaciones vigentes en este momento
+    Tables      []Table            `json:"tables"`
+    OpenOrders  []CustomerOrder    `json:"open_orders"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// WaitlistStatus define el tipo ENUM para los estados de un grupo en la lista de espera
+type WaitlistStatus string
+
+const (
+    WaitlistStatusWaiting WaitlistStatus = "waiting"
+    WaitlistStatusSeated  WaitlistStatus = "seated"
+    WaitlistStatusLeft    WaitlistStatus = "left"
+)
+
+// WaitlistEntry representa la tabla waitlist_entries
+type WaitlistEntry struct {
+    ID                   int            `json:"id"`
+    GuestName            string         `json:"guest_name"`
+    GuestPhone           string         `json:"guest_phone"`
+    PartySize            int            `json:"party_size"`
+    QuotedWaitMinutes    *int           `json:"quoted_wait_minutes"` // Si no se indica se usa la espera estimada
+    Status               WaitlistStatus `json:"status"`
+    TableID              *int           `json:"table_id"`          // Mesa asignada al sentar al grupo
+    CustomerOrderID      *int           `json:"customer_order_id"` // Pedido abierto al sentar al grupo
+    CreatedBy            int            `json:"created_by"`
+    SeatedAt             *time.Time     `json:"seated_at"`
+    LeftAt               *time.Time     `json:"left_at"`
+    CreatedAt            time.Time      `json:"created_at"`
+    Position             int            `json:"position,omitempty"`               // Solo en la lista de espera actual
+    EstimatedWaitMinutes *int           `json:"estimated_wait_minutes,omitempty"` // Solo en la lista de espera actual
+}
+
+// WaitEstimate es la espera estimada para un grupo que llega ahora
+type WaitEstimate struct {
+    PartySize             int `json:"party_size"`
+    EstimatedWaitMinutes  int `json:"estimated_wait_minutes"`
+    AverageSeatingMinutes int `json:"average_seating_minutes"` // Tiempo promedio de mesa (apertura a cierre del pedido)
+    PartiesAhead          int `json:"parties_ahead"`
+}
+
+// TableOccupancy describe cuándo puede quedar libre una mesa activa para la lista de espera
+type TableOccupancy struct {
+    TableID       int        `json:"table_id"`
+    Capacity      int        `json:"capacity"`
+    OccupiedSince *time.Time `json:"occupied_since"` // Apertura del pedido pendiente; NULL si está libre
+    ReservedUntil *time.Time `json:"reserved_until"` // Fin de una reserva próxima o en curso
+}
+
+// WaitlistSeatRequest indica la mesa que se liberó para sentar al siguiente grupo
+type WaitlistSeatRequest struct {
+    TableID int `json:"table_id"`
+}
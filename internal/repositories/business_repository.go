@@ -23,34 +23,38 @@ func NewBusinessRepository(db *sql.DB) BusinessRepository {
 
 func (r *businessRepository) Find() (models.Business, error) {
     var business models.Business
+    var maxTables sql.NullInt64
     err := r.db.QueryRow(`
-        SELECT id, business_name, address, phone_number, email, corporate_reason, created_at, updated_at
+        SELECT id, business_name, address, phone_number, email, corporate_reason, guest_orders_require_confirmation, max_tables, created_at, updated_at
         FROM business
         LIMIT 1`,
-    ).Scan(&business.ID, &business.BusinessName, &business.Address, &business.PhoneNumber, &business.Email, &business.CorporateReason, &business.CreatedAt, &business.UpdatedAt)
+    ).Scan(&business.ID, &business.BusinessName, &business.Address, &business.PhoneNumber, &business.Email, &business.CorporateReason, &business.GuestOrdersRequireConfirmation, &maxTables, &business.CreatedAt, &business.UpdatedAt)
     if err != nil {
         if err == sql.ErrNoRows {
             return models.Business{}, errors.Wrap(err, "business not found")
         }
         return models.Business{}, errors.Wrap(err, "failed to query business")
     }
+    business.MaxTables = nullIntToPtr(maxTables)
     return business, nil
 }
 
 func (r *businessRepository) Update(business models.Business) (models.Business, error) {
     var updatedBusiness models.Business
+    var maxTables sql.NullInt64
     err := r.db.QueryRow(`
         UPDATE business
-        SET business_name = $1, address = $2, phone_number = $3, email = $4, corporate_reason = $5, updated_at = CURRENT_TIMESTAMP
-        WHERE id = $6
-        RETURNING id, business_name, address, phone_number, email, corporate_reason, created_at, updated_at`,
-        business.BusinessName, business.Address, business.PhoneNumber, business.Email, business.CorporateReason, business.ID,
-    ).Scan(&updatedBusiness.ID, &updatedBusiness.BusinessName, &updatedBusiness.Address, &updatedBusiness.PhoneNumber, &updatedBusiness.Email, &updatedBusiness.CorporateReason, &updatedBusiness.CreatedAt, &updatedBusiness.UpdatedAt)
+        SET business_name = $1, address = $2, phone_number = $3, email = $4, corporate_reason = $5, guest_orders_require_confirmation = $6, max_tables = $7, updated_at = CURRENT_TIMESTAMP
+        WHERE id = $8
+        RETURNING id, business_name, address, phone_number, email, corporate_reason, guest_orders_require_confirmation, max_tables, created_at, updated_at`,
+        business.BusinessName, business.Address, business.PhoneNumber, business.Email, business.CorporateReason, business.GuestOrdersRequireConfirmation, business.MaxTables, business.ID,
+    ).Scan(&updatedBusiness.ID, &updatedBusiness.BusinessName, &updatedBusiness.Address, &updatedBusiness.PhoneNumber, &updatedBusiness.Email, &updatedBusiness.CorporateReason, &updatedBusiness.GuestOrdersRequireConfirmation, &maxTables, &updatedBusiness.CreatedAt, &updatedBusiness.UpdatedAt)
     if err != nil {
         if err == sql.ErrNoRows {
             return models.Business{}, errors.Wrap(err, "business not found")
         }
         return models.Business{}, errors.Wrap(err, "failed to update business")
     }
+    updatedBusiness.MaxTables = nullIntToPtr(maxTables)
     return updatedBusiness, nil
 }
\ No newline at end of file
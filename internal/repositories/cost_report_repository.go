@@ -0,0 +1,77 @@
+package repositories
+
+import (
+    "database/sql"
+    "time"
+
+    "gastrobar-backend/internal/models"
+
+    "github.com/pkg/errors"
+    "github.com/shopspring/decimal"
+)
+
+type CostReportRepository interface {
+    FindMenuItemCosts() ([]models.MenuItemCost, error)
+    FindCompletedSalesByItem(from, to *time.Time) ([]models.MenuItemSales, error)
+}
+
+type costReportRepository struct {
+    db *sql.DB
+}
+
+func NewCostReportRepository(db *sql.DB) CostReportRepository {
+    return &costReportRepository{db: db}
+}
+
+// FindMenuItemCosts devuelve el precio y el costo unitario (menu_item_unit_cost) de todos los ítems
+func (r *costReportRepository) FindMenuItemCosts() ([]models.MenuItemCost, error) {
+    rows, err := r.db.Query(`
+        SELECT id, item_name, category, stock_mode, price, menu_item_unit_cost(id)
+        FROM menu_items
+        ORDER BY item_name`)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query menu item costs")
+    }
+    defer rows.Close()
+
+    var costs []models.MenuItemCost
+    for rows.Next() {
+        var cost models.MenuItemCost
+        var unitCost decimal.NullDecimal
+        if err := rows.Scan(&cost.MenuItemID, &cost.ItemName, &cost.Category, &cost.StockMode, &cost.Price, &unitCost); err != nil {
+            return nil, errors.Wrap(err, "failed to scan menu item cost")
+        }
+        cost.UnitCost = nullDecimalToPtr(unitCost)
+        costs = append(costs, cost)
+    }
+    return costs, nil
+}
+
+// FindCompletedSalesByItem suma las unidades vendidas por ítem en las órdenes completadas del periodo
+// (límites opcionales; el final es exclusivo)
+func (r *costReportRepository) FindCompletedSalesByItem(from, to *time.Time) ([]models.MenuItemSales, error) {
+    rows, err := r.db.Query(`
+        SELECT od.menu_item_id, SUM(od.quantity)::INTEGER
+        FROM order_details od
+        JOIN customer_orders co ON co.id = od.order_id
+        WHERE co.status = 'completed'
+          AND ($1::TIMESTAMPTZ IS NULL OR co.created_at >= $1)
+          AND ($2::TIMESTAMPTZ IS NULL OR co.created_at < $2)
+        GROUP BY od.menu_item_id`,
+        from, to,
+    )
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query completed sales by item")
+    }
+    defer rows.Close()
+
+    var sales []models.MenuItemSales
+    for rows.Next() {
+        var sale models.MenuItemSales
+        if err := rows.Scan(&sale.MenuItemID, &sale.QuantitySold); err != nil {
+            return nil, errors.Wrap(err, "failed to scan completed sales")
+        }
+        sales = append(sales, sale)
+    }
+    return sales, nil
+}
@@ -13,6 +13,8 @@ type CustomerOrderRepository interface {
     FindPendingByTableID(tableID int) (models.CustomerOrder, error)
     FindCompletedByTableID(tableID int) (models.CustomerOrder, error)
     UpdateStatus(id int, status string) (models.CustomerOrder, error)
+    Complete(id, closedBy int, servedBy *int) (models.CustomerOrder, error)
+    RequestBill(id int) (models.CustomerOrder, error)
 }
 
 type customerOrderRepository struct {
@@ -25,29 +27,43 @@ func NewCustomerOrderRepository(db *sql.DB) CustomerOrderRepository {
 
 func (r *customerOrderRepository) Create(order models.CustomerOrder) (models.CustomerOrder, error) {
     var createdOrder models.CustomerOrder
+    var billRequestedAt, completedAt sql.NullTime
+    var openedBy, servedBy, closedBy sql.NullInt64
     err := r.db.QueryRow(`
-        INSERT INTO customer_orders (table_id, total_amount, status, created_at)
-        VALUES ($1, $2, $3, $4)
-        RETURNING id, table_id, total_amount, status, created_at`,
-        order.TableID, order.TotalAmount, order.Status, order.CreatedAt,
+        INSERT INTO customer_orders (table_id, total_amount, status, opened_by, created_at)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id, table_id, total_amount, status, bill_requested_at, completed_at, opened_by, served_by, closed_by, created_at`,
+        order.TableID, order.TotalAmount, order.Status, order.OpenedBy, order.CreatedAt,
     ).Scan(
         &createdOrder.ID,
         &createdOrder.TableID,
         &createdOrder.TotalAmount,
         &createdOrder.Status,
+        &billRequestedAt,
+        &completedAt,
+        &openedBy,
+        &servedBy,
+        &closedBy,
         &createdOrder.CreatedAt,
     )
     if err != nil {
         return models.CustomerOrder{}, errors.Wrap(err, "failed to create customer order")
     }
 
+    createdOrder.BillRequestedAt = nullTimeToPtr(billRequestedAt)
+    createdOrder.CompletedAt = nullTimeToPtr(completedAt)
+    createdOrder.OpenedBy = nullIntToPtr(openedBy)
+    createdOrder.ServedBy = nullIntToPtr(servedBy)
+    createdOrder.ClosedBy = nullIntToPtr(closedBy)
     return createdOrder, nil
 }
 
 func (r *customerOrderRepository) FindByID(id int) (models.CustomerOrder, error) {
     var order models.CustomerOrder
+    var billRequestedAt, completedAt sql.NullTime
+    var openedBy, servedBy, closedBy sql.NullInt64
     err := r.db.QueryRow(`
-        SELECT id, table_id, total_amount, status, created_at
+        SELECT id, table_id, total_amount, status, bill_requested_at, completed_at, opened_by, served_by, closed_by, created_at
         FROM customer_orders
         WHERE id = $1`,
         id,
@@ -56,6 +72,11 @@ func (r *customerOrderRepository) FindByID(id int) (models.CustomerOrder, error)
         &order.TableID,
         &order.TotalAmount,
         &order.Status,
+        &billRequestedAt,
+        &completedAt,
+        &openedBy,
+        &servedBy,
+        &closedBy,
         &order.CreatedAt,
     )
     if err != nil {
@@ -64,13 +85,20 @@ func (r *customerOrderRepository) FindByID(id int) (models.CustomerOrder, error)
         }
         return models.CustomerOrder{}, errors.Wrap(err, "failed to find customer order")
     }
+    order.BillRequestedAt = nullTimeToPtr(billRequestedAt)
+    order.CompletedAt = nullTimeToPtr(completedAt)
+    order.OpenedBy = nullIntToPtr(openedBy)
+    order.ServedBy = nullIntToPtr(servedBy)
+    order.ClosedBy = nullIntToPtr(closedBy)
     return order, nil
 }
 
 func (r *customerOrderRepository) FindPendingByTableID(tableID int) (models.CustomerOrder, error) {
     var order models.CustomerOrder
+    var billRequestedAt, completedAt sql.NullTime
+    var openedBy, servedBy, closedBy sql.NullInt64
     err := r.db.QueryRow(`
-        SELECT id, table_id, total_amount, status, created_at
+        SELECT id, table_id, total_amount, status, bill_requested_at, completed_at, opened_by, served_by, closed_by, created_at
         FROM customer_orders
         WHERE table_id = $1 AND status = 'pending'`,
         tableID,
@@ -79,6 +107,11 @@ func (r *customerOrderRepository) FindPendingByTableID(tableID int) (models.Cust
         &order.TableID,
         &order.TotalAmount,
         &order.Status,
+        &billRequestedAt,
+        &completedAt,
+        &openedBy,
+        &servedBy,
+        &closedBy,
         &order.CreatedAt,
     )
     if err != nil {
@@ -87,14 +120,21 @@ func (r *customerOrderRepository) FindPendingByTableID(tableID int) (models.Cust
         }
         return models.CustomerOrder{}, errors.Wrap(err, "failed to find pending customer order")
     }
+    order.BillRequestedAt = nullTimeToPtr(billRequestedAt)
+    order.CompletedAt = nullTimeToPtr(completedAt)
+    order.OpenedBy = nullIntToPtr(openedBy)
+    order.ServedBy = nullIntToPtr(servedBy)
+    order.ClosedBy = nullIntToPtr(closedBy)
     return order, nil
 }
 
 // internal/repositories/customer_order_repository.go
 func (r *customerOrderRepository) FindCompletedByTableID(tableID int) (models.CustomerOrder, error) {
     var order models.CustomerOrder
+    var billRequestedAt, completedAt sql.NullTime
+    var openedBy, servedBy, closedBy sql.NullInt64
     err := r.db.QueryRow(`
-        SELECT id, table_id, total_amount, status, created_at
+        SELECT id, table_id, total_amount, status, bill_requested_at, completed_at, opened_by, served_by, closed_by, created_at
         FROM customer_orders
         WHERE table_id = $1 AND status = 'completed'
         ORDER BY created_at DESC
@@ -105,6 +145,11 @@ func (r *customerOrderRepository) FindCompletedByTableID(tableID int) (models.Cu
         &order.TableID,
         &order.TotalAmount,
         &order.Status,
+        &billRequestedAt,
+        &completedAt,
+        &openedBy,
+        &servedBy,
+        &closedBy,
         &order.CreatedAt,
     )
     if err != nil {
@@ -113,22 +158,35 @@ func (r *customerOrderRepository) FindCompletedByTableID(tableID int) (models.Cu
         }
         return models.CustomerOrder{}, errors.Wrap(err, "failed to query completed customer order by table id")
     }
+    order.BillRequestedAt = nullTimeToPtr(billRequestedAt)
+    order.CompletedAt = nullTimeToPtr(completedAt)
+    order.OpenedBy = nullIntToPtr(openedBy)
+    order.ServedBy = nullIntToPtr(servedBy)
+    order.ClosedBy = nullIntToPtr(closedBy)
     return order, nil
 }
 
 func (r *customerOrderRepository) UpdateStatus(id int, status string) (models.CustomerOrder, error) {
     var updatedOrder models.CustomerOrder
+    var billRequestedAt, completedAt sql.NullTime
+    var openedBy, servedBy, closedBy sql.NullInt64
     err := r.db.QueryRow(`
         UPDATE customer_orders
-        SET status = $2
+        SET status = $2,
+            completed_at = CASE WHEN $2 = 'completed' THEN COALESCE(completed_at, CURRENT_TIMESTAMP) ELSE NULL END
         WHERE id = $1
-        RETURNING id, table_id, total_amount, status, created_at`,
+        RETURNING id, table_id, total_amount, status, bill_requested_at, completed_at, opened_by, served_by, closed_by, created_at`,
         id, status,
     ).Scan(
         &updatedOrder.ID,
         &updatedOrder.TableID,
         &updatedOrder.TotalAmount,
         &updatedOrder.Status,
+        &billRequestedAt,
+        &completedAt,
+        &openedBy,
+        &servedBy,
+        &closedBy,
         &updatedOrder.CreatedAt,
     )
     if err != nil {
@@ -137,5 +195,88 @@ func (r *customerOrderRepository) UpdateStatus(id int, status string) (models.Cu
         }
         return models.CustomerOrder{}, errors.Wrap(err, "failed to update customer order status")
     }
+    updatedOrder.BillRequestedAt = nullTimeToPtr(billRequestedAt)
+    updatedOrder.CompletedAt = nullTimeToPtr(completedAt)
+    updatedOrder.OpenedBy = nullIntToPtr(openedBy)
+    updatedOrder.ServedBy = nullIntToPtr(servedBy)
+    updatedOrder.ClosedBy = nullIntToPtr(closedBy)
     return updatedOrder, nil
-}
\ No newline at end of file
+}
+
+// Complete cierra la orden registrando quién la cerró; servedBy solo se asigna si la orden aún no
+// tenía un mesero a cargo
+func (r *customerOrderRepository) Complete(id, closedBy int, servedBy *int) (models.CustomerOrder, error) {
+    var updatedOrder models.CustomerOrder
+    var billRequestedAt, completedAt sql.NullTime
+    var openedBy, orderServedBy, orderClosedBy sql.NullInt64
+    err := r.db.QueryRow(`
+        UPDATE customer_orders
+        SET status = 'completed',
+            completed_at = COALESCE(completed_at, CURRENT_TIMESTAMP),
+            closed_by = $2,
+            served_by = COALESCE(served_by, $3)
+        WHERE id = $1
+        RETURNING id, table_id, total_amount, status, bill_requested_at, completed_at, opened_by, served_by, closed_by, created_at`,
+        id, closedBy, servedBy,
+    ).Scan(
+        &updatedOrder.ID,
+        &updatedOrder.TableID,
+        &updatedOrder.TotalAmount,
+        &updatedOrder.Status,
+        &billRequestedAt,
+        &completedAt,
+        &openedBy,
+        &orderServedBy,
+        &orderClosedBy,
+        &updatedOrder.CreatedAt,
+    )
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return models.CustomerOrder{}, errors.New("customer order not found")
+        }
+        return models.CustomerOrder{}, errors.Wrap(err, "failed to complete customer order")
+    }
+    updatedOrder.BillRequestedAt = nullTimeToPtr(billRequestedAt)
+    updatedOrder.CompletedAt = nullTimeToPtr(completedAt)
+    updatedOrder.OpenedBy = nullIntToPtr(openedBy)
+    updatedOrder.ServedBy = nullIntToPtr(orderServedBy)
+    updatedOrder.ClosedBy = nullIntToPtr(orderClosedBy)
+    return updatedOrder, nil
+}
+
+// RequestBill marca que la mesa pidió la cuenta; si ya se había pedido se conserva la hora original
+func (r *customerOrderRepository) RequestBill(id int) (models.CustomerOrder, error) {
+    var updatedOrder models.CustomerOrder
+    var billRequestedAt, completedAt sql.NullTime
+    var openedBy, servedBy, closedBy sql.NullInt64
+    err := r.db.QueryRow(`
+        UPDATE customer_orders
+        SET bill_requested_at = COALESCE(bill_requested_at, CURRENT_TIMESTAMP)
+        WHERE id = $1
+        RETURNING id, table_id, total_amount, status, bill_requested_at, completed_at, opened_by, served_by, closed_by, created_at`,
+        id,
+    ).Scan(
+        &updatedOrder.ID,
+        &updatedOrder.TableID,
+        &updatedOrder.TotalAmount,
+        &updatedOrder.Status,
+        &billRequestedAt,
+        &completedAt,
+        &openedBy,
+        &servedBy,
+        &closedBy,
+        &updatedOrder.CreatedAt,
+    )
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return models.CustomerOrder{}, errors.New("customer order not found")
+        }
+        return models.CustomerOrder{}, errors.Wrap(err, "failed to request bill")
+    }
+    updatedOrder.BillRequestedAt = nullTimeToPtr(billRequestedAt)
+    updatedOrder.CompletedAt = nullTimeToPtr(completedAt)
+    updatedOrder.OpenedBy = nullIntToPtr(openedBy)
+    updatedOrder.ServedBy = nullIntToPtr(servedBy)
+    updatedOrder.ClosedBy = nullIntToPtr(closedBy)
+    return updatedOrder, nil
+}
@@ -2,9 +2,11 @@ package repositories
 
 import (
     "database/sql"
+    "strings"
 
     "gastrobar-backend/internal/models"
 
+    "github.com/lib/pq"
     "github.com/pkg/errors"
 )
 
@@ -14,9 +16,14 @@ type EmployeeRepository interface {
     FindByID(employeeID int) (models.Employee, error)
     FindAll() ([]models.Employee, error)
     FindAllByRole(role models.EmployeeRole) ([]models.Employee, error)
-    Create(employee models.Employee) (models.Employee, error)
-    Update(employee models.Employee) (models.Employee, error)
-    UpdatePassword(employeeID int, password string) error
+    Create(employee models.Employee, actorID int) (models.Employee, error)
+    Update(employee models.Employee, actorID int) (models.Employee, error)
+    UpdateContact(employeeID int, email, phoneNumber string) (models.Employee, error)
+    UpdatePassword(employeeID int, password string, actorID int) error
+    Deactivate(employeeID, deactivatedBy int, reassignTasksTo *int) (models.EmployeeDeactivation, error)
+    Reactivate(employeeID, actorID int) (models.Employee, error)
+    TransferOwnership(ownerID, newOwnerID int) (models.OwnershipTransfer, error)
+    FindAuditLogs(employeeID int) ([]models.EmployeeAuditLog, error)
 }
 
 type employeeRepository struct {
@@ -28,13 +35,12 @@ func NewEmployeeRepository(db *sql.DB) EmployeeRepository {
 }
 
 func (r *employeeRepository) FindByEmail(email string) (models.Employee, error) {
-    var employee models.Employee
-    err := r.db.QueryRow(`
-        SELECT id, employee_name, email, phone_number, role, username, password, created_at
+    employee, err := scanEmployee(r.db.QueryRow(`
+        SELECT id, employee_name, email, phone_number, role, username, password, token_version, active, deactivated_at, deactivated_by, created_at
         FROM employees
-        WHERE email = $1`,
+        WHERE lower(email) = lower($1)`,
         email,
-    ).Scan(&employee.ID, &employee.EmployeeName, &employee.Email, &employee.PhoneNumber, &employee.Role, &employee.Username, &employee.Password, &employee.CreatedAt)
+    ))
     if err != nil {
         if err == sql.ErrNoRows {
             return models.Employee{}, errors.Wrap(err, "employee not found")
@@ -45,13 +51,12 @@ func (r *employeeRepository) FindByEmail(email string) (models.Employee, error)
 }
 
 func (r *employeeRepository) FindByUsername(username string) (models.Employee, error) {
-    var employee models.Employee
-    err := r.db.QueryRow(`
-        SELECT id, employee_name, email, phone_number, role, username, password, created_at
+    employee, err := scanEmployee(r.db.QueryRow(`
+        SELECT id, employee_name, email, phone_number, role, username, password, token_version, active, deactivated_at, deactivated_by, created_at
         FROM employees
-        WHERE username = $1`,
+        WHERE lower(username) = lower($1)`,
         username,
-    ).Scan(&employee.ID, &employee.EmployeeName, &employee.Email, &employee.PhoneNumber, &employee.Role, &employee.Username, &employee.Password, &employee.CreatedAt)
+    ))
     if err != nil {
         if err == sql.ErrNoRows {
             return models.Employee{}, errors.Wrap(err, "employee not found")
@@ -62,13 +67,12 @@ func (r *employeeRepository) FindByUsername(username string) (models.Employee, e
 }
 
 func (r *employeeRepository) FindByID(employeeID int) (models.Employee, error) {
-    var employee models.Employee
-    err := r.db.QueryRow(`
-        SELECT id, employee_name, email, phone_number, role, username, password, created_at
+    employee, err := scanEmployee(r.db.QueryRow(`
+        SELECT id, employee_name, email, phone_number, role, username, password, token_version, active, deactivated_at, deactivated_by, created_at
         FROM employees
         WHERE id = $1`,
         employeeID,
-    ).Scan(&employee.ID, &employee.EmployeeName, &employee.Email, &employee.PhoneNumber, &employee.Role, &employee.Username, &employee.Password, &employee.CreatedAt)
+    ))
     if err != nil {
         if err == sql.ErrNoRows {
             return models.Employee{}, errors.Wrap(err, "employee not found")
@@ -80,7 +84,7 @@ func (r *employeeRepository) FindByID(employeeID int) (models.Employee, error) {
 
 func (r *employeeRepository) FindAll() ([]models.Employee, error) {
     rows, err := r.db.Query(`
-        SELECT id, employee_name, email, phone_number, role, username, password, created_at
+        SELECT id, employee_name, email, phone_number, role, username, password, token_version, active, deactivated_at, deactivated_by, created_at
         FROM employees`)
     if err != nil {
         return nil, errors.Wrap(err, "failed to query employees")
@@ -89,8 +93,8 @@ func (r *employeeRepository) FindAll() ([]models.Employee, error) {
 
     var employees []models.Employee
     for rows.Next() {
-        var employee models.Employee
-        if err := rows.Scan(&employee.ID, &employee.EmployeeName, &employee.Email, &employee.PhoneNumber, &employee.Role, &employee.Username, &employee.Password, &employee.CreatedAt); err != nil {
+        employee, err := scanEmployee(rows)
+        if err != nil {
             return nil, errors.Wrap(err, "failed to scan employee")
         }
         employees = append(employees, employee)
@@ -100,7 +104,7 @@ func (r *employeeRepository) FindAll() ([]models.Employee, error) {
 
 func (r *employeeRepository) FindAllByRole(role models.EmployeeRole) ([]models.Employee, error) {
     rows, err := r.db.Query(`
-        SELECT id, employee_name, email, phone_number, role, username, password, created_at
+        SELECT id, employee_name, email, phone_number, role, username, password, token_version, active, deactivated_at, deactivated_by, created_at
         FROM employees
         WHERE role = $1`,
         role,
@@ -112,8 +116,8 @@ func (r *employeeRepository) FindAllByRole(role models.EmployeeRole) ([]models.E
 
     var employees []models.Employee
     for rows.Next() {
-        var employee models.Employee
-        if err := rows.Scan(&employee.ID, &employee.EmployeeName, &employee.Email, &employee.PhoneNumber, &employee.Role, &employee.Username, &employee.Password, &employee.CreatedAt); err != nil {
+        employee, err := scanEmployee(rows)
+        if err != nil {
             return nil, errors.Wrap(err, "failed to scan employee")
         }
         employees = append(employees, employee)
@@ -122,54 +126,492 @@ func (r *employeeRepository) FindAllByRole(role models.EmployeeRole) ([]models.E
 }
 
 
-func (r *employeeRepository) Create(employee models.Employee) (models.Employee, error) {
-    var createdEmployee models.Employee
-    err := r.db.QueryRow(`
+// Create crea el empleado y registra la acción en employee_audit_logs en la misma transacción
+func (r *employeeRepository) Create(employee models.Employee, actorID int) (models.Employee, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.Employee{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    createdEmployee, err := scanEmployee(tx.QueryRow(`
         INSERT INTO employees (employee_name, email, phone_number, role, username, password, created_at)
         VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP)
-        RETURNING id, employee_name, email, phone_number, role, username, password, created_at`,
+        RETURNING id, employee_name, email, phone_number, role, username, password, token_version, active, deactivated_at, deactivated_by, created_at`,
         employee.EmployeeName, employee.Email, employee.PhoneNumber, employee.Role, employee.Username, employee.Password,
-    ).Scan(&createdEmployee.ID, &createdEmployee.EmployeeName, &createdEmployee.Email, &createdEmployee.PhoneNumber, &createdEmployee.Role, &createdEmployee.Username, &createdEmployee.Password, &createdEmployee.CreatedAt)
+    ))
     if err != nil {
+        if validationErr := uniqueViolation(err); validationErr != nil {
+            return models.Employee{}, validationErr
+        }
         return models.Employee{}, errors.Wrap(err, "failed to create employee")
     }
+
+    if err := insertEmployeeAudit(tx, createdEmployee.ID, actorID, models.EmployeeAuditCreate, sql.NullString{}); err != nil {
+        return models.Employee{}, err
+    }
+    if err := tx.Commit(); err != nil {
+        return models.Employee{}, errors.Wrap(err, "failed to commit transaction")
+    }
     return createdEmployee, nil
 }
 
-func (r *employeeRepository) Update(employee models.Employee) (models.Employee, error) {
-    var updatedEmployee models.Employee
-    err := r.db.QueryRow(`
+func (r *employeeRepository) Update(employee models.Employee, actorID int) (models.Employee, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.Employee{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    oldData, err := employeeSnapshot(tx, employee.ID)
+    if err != nil {
+        return models.Employee{}, err
+    }
+
+    // El rol viaja en el token: si cambia, se incrementa token_version para invalidar las sesiones
+    // emitidas con el rol anterior
+    updatedEmployee, err := scanEmployee(tx.QueryRow(`
         UPDATE employees
-        SET employee_name = $1, email = $2, phone_number = $3, role = $4, username = $5
+        SET employee_name = $1, email = $2, phone_number = $3, role = $4, username = $5,
+            token_version = CASE WHEN role <> $4 THEN token_version + 1 ELSE token_version END
         WHERE id = $6
-        RETURNING id, employee_name, email, phone_number, role, username, password, created_at`,
+        RETURNING id, employee_name, email, phone_number, role, username, password, token_version, active, deactivated_at, deactivated_by, created_at`,
         employee.EmployeeName, employee.Email, employee.PhoneNumber, employee.Role, employee.Username, employee.ID,
-    ).Scan(&updatedEmployee.ID, &updatedEmployee.EmployeeName, &updatedEmployee.Email, &updatedEmployee.PhoneNumber, &updatedEmployee.Role, &updatedEmployee.Username, &updatedEmployee.Password, &updatedEmployee.CreatedAt)
+    ))
     if err != nil {
-        if err == sql.ErrNoRows {
-            return models.Employee{}, errors.Wrap(err, "employee not found")
+        if validationErr := uniqueViolation(err); validationErr != nil {
+            return models.Employee{}, validationErr
         }
         return models.Employee{}, errors.Wrap(err, "failed to update employee")
     }
+
+    if err := insertEmployeeAudit(tx, employee.ID, actorID, models.EmployeeAuditUpdate, oldData); err != nil {
+        return models.Employee{}, err
+    }
+    if err := tx.Commit(); err != nil {
+        return models.Employee{}, errors.Wrap(err, "failed to commit transaction")
+    }
+    return updatedEmployee, nil
+}
+
+// UpdateContact actualiza solo el email y el teléfono de un empleado (edición del propio perfil)
+func (r *employeeRepository) UpdateContact(employeeID int, email, phoneNumber string) (models.Employee, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.Employee{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    oldData, err := employeeSnapshot(tx, employeeID)
+    if err != nil {
+        return models.Employee{}, err
+    }
+
+    updatedEmployee, err := scanEmployee(tx.QueryRow(`
+        UPDATE employees
+        SET email = $1, phone_number = $2
+        WHERE id = $3
+        RETURNING id, employee_name, email, phone_number, role, username, password, token_version, active, deactivated_at, deactivated_by, created_at`,
+        email, phoneNumber, employeeID,
+    ))
+    if err != nil {
+        if validationErr := uniqueViolation(err); validationErr != nil {
+            return models.Employee{}, validationErr
+        }
+        return models.Employee{}, errors.Wrap(err, "failed to update employee contact data")
+    }
+
+    if err := insertEmployeeAudit(tx, employeeID, employeeID, models.EmployeeAuditProfileUpdate, oldData); err != nil {
+        return models.Employee{}, err
+    }
+    if err := tx.Commit(); err != nil {
+        return models.Employee{}, errors.Wrap(err, "failed to commit transaction")
+    }
     return updatedEmployee, nil
 }
 
-func (r *employeeRepository) UpdatePassword(employeeID int, password string) error {
-    result, err := r.db.Exec(`
+// UpdatePassword reemplaza la contraseña e incrementa la versión de sesión, con lo que todos los
+// tokens emitidos antes del cambio dejan de ser válidos. Se audita como cambio propio si actorID es
+// el mismo empleado y como restablecimiento si no
+func (r *employeeRepository) UpdatePassword(employeeID int, password string, actorID int) error {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    oldData, err := employeeSnapshot(tx, employeeID)
+    if err != nil {
+        return err
+    }
+
+    if _, err := tx.Exec(`
         UPDATE employees
-        SET password = $1
+        SET password = $1, token_version = token_version + 1
         WHERE id = $2`,
         password, employeeID,
+    ); err != nil {
+        return errors.Wrap(err, "failed to update employee password")
+    }
+
+    action := models.EmployeeAuditPasswordReset
+    if actorID == employeeID {
+        action = models.EmployeeAuditPasswordChange
+    }
+    if err := insertEmployeeAudit(tx, employeeID, actorID, action, oldData); err != nil {
+        return err
+    }
+    if err := tx.Commit(); err != nil {
+        return errors.Wrap(err, "failed to commit transaction")
+    }
+    return nil
+}
+
+// Deactivate da de baja a un empleado: sus tareas abiertas pasan a reassignTasksTo (como pendientes)
+// o se cancelan, se quitan sus turnos y secciones futuras (la sección vigente termina ahora) y sus
+// sesiones se invalidan. Sus pedidos, jornadas y demás historial se conservan
+func (r *employeeRepository) Deactivate(employeeID, deactivatedBy int, reassignTasksTo *int) (models.EmployeeDeactivation, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.EmployeeDeactivation{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    if err := lockActiveEmployee(tx, employeeID); err != nil {
+        if strings.Contains(err.Error(), "employee is inactive") {
+            return models.EmployeeDeactivation{}, errors.New("employee is already inactive")
+        }
+        return models.EmployeeDeactivation{}, err
+    }
+    oldData, err := employeeSnapshot(tx, employeeID)
+    if err != nil {
+        return models.EmployeeDeactivation{}, err
+    }
+
+    result := models.EmployeeDeactivation{ReassignedTo: reassignTasksTo}
+
+    // Reasignar o cancelar las tareas abiertas, registrando los cambios de estado en el historial
+    newStatus := models.TaskStatusCancelled
+    if reassignTasksTo != nil {
+        newStatus = models.TaskStatusPending
+    }
+    if _, err := tx.Exec(`
+        INSERT INTO employee_task_status_history (task_id, from_status, to_status, changed_by, changed_at)
+        SELECT id, status, $2, $3, CURRENT_TIMESTAMP
+        FROM employee_tasks
+        WHERE employee_id = $1 AND status NOT IN ('completada', 'cancelada') AND status <> $2`,
+        employeeID, newStatus, deactivatedBy,
+    ); err != nil {
+        return models.EmployeeDeactivation{}, errors.Wrap(err, "failed to record task status changes")
+    }
+
+    var tasks sql.Result
+    if reassignTasksTo != nil {
+        if err := lockActiveEmployee(tx, *reassignTasksTo); err != nil {
+            return models.EmployeeDeactivation{}, errors.Wrap(err, "failed to lock task reassignment target")
+        }
+        tasks, err = tx.Exec(`
+            UPDATE employee_tasks
+            SET employee_id = $2, status = 'pendiente', assigned_at = CURRENT_TIMESTAMP
+            WHERE employee_id = $1 AND status NOT IN ('completada', 'cancelada')`,
+            employeeID, *reassignTasksTo,
+        )
+    } else {
+        tasks, err = tx.Exec(`
+            UPDATE employee_tasks
+            SET status = 'cancelada', completed_at = NULL
+            WHERE employee_id = $1 AND status NOT IN ('completada', 'cancelada')`,
+            employeeID,
+        )
+    }
+    if err != nil {
+        return models.EmployeeDeactivation{}, errors.Wrap(err, "failed to update open tasks")
+    }
+    taskCount, err := tasks.RowsAffected()
+    if err != nil {
+        return models.EmployeeDeactivation{}, errors.Wrap(err, "failed to check rows affected")
+    }
+    if reassignTasksTo != nil {
+        result.ReassignedTasks = int(taskCount)
+    } else {
+        result.CancelledTasks = int(taskCount)
+    }
+
+    // Quitar los turnos y las secciones que todavía no empiezan y terminar la sección vigente
+    shifts, err := tx.Exec(`
+        DELETE FROM shifts
+        WHERE employee_id = $1 AND starts_at > CURRENT_TIMESTAMP`,
+        employeeID,
     )
     if err != nil {
-        return errors.Wrap(err, "failed to update employee password")
+        return models.EmployeeDeactivation{}, errors.Wrap(err, "failed to remove upcoming shifts")
     }
-    rowsAffected, err := result.RowsAffected()
+    shiftCount, err := shifts.RowsAffected()
     if err != nil {
-        return errors.Wrap(err, "failed to check rows affected")
+        return models.EmployeeDeactivation{}, errors.Wrap(err, "failed to check rows affected")
     }
-    if rowsAffected == 0 {
-        return errors.New("employee not found")
+    result.RemovedShifts = int(shiftCount)
+
+    assignments, err := tx.Exec(`
+        DELETE FROM waiter_assignments
+        WHERE employee_id = $1 AND starts_at > CURRENT_TIMESTAMP`,
+        employeeID,
+    )
+    if err != nil {
+        return models.EmployeeDeactivation{}, errors.Wrap(err, "failed to remove upcoming waiter assignments")
+    }
+    assignmentCount, err := assignments.RowsAffected()
+    if err != nil {
+        return models.EmployeeDeactivation{}, errors.Wrap(err, "failed to check rows affected")
+    }
+    result.RemovedWaiterAssignments = int(assignmentCount)
+
+    if _, err := tx.Exec(`
+        UPDATE waiter_assignments
+        SET ends_at = CURRENT_TIMESTAMP
+        WHERE employee_id = $1 AND starts_at <= CURRENT_TIMESTAMP AND ends_at > CURRENT_TIMESTAMP`,
+        employeeID,
+    ); err != nil {
+        return models.EmployeeDeactivation{}, errors.Wrap(err, "failed to end current waiter assignment")
+    }
+
+    // Dar de baja al empleado e invalidar sus sesiones
+    if _, err := tx.Exec(`
+        UPDATE employees
+        SET active = FALSE, deactivated_at = CURRENT_TIMESTAMP, deactivated_by = $2, token_version = token_version + 1
+        WHERE id = $1`,
+        employeeID, deactivatedBy,
+    ); err != nil {
+        return models.EmployeeDeactivation{}, errors.Wrap(err, "failed to deactivate employee")
+    }
+    if err := insertEmployeeAudit(tx, employeeID, deactivatedBy, models.EmployeeAuditDeactivate, oldData); err != nil {
+        return models.EmployeeDeactivation{}, err
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.EmployeeDeactivation{}, errors.Wrap(err, "failed to commit transaction")
+    }
+
+    result.Employee, err = r.FindByID(employeeID)
+    if err != nil {
+        return models.EmployeeDeactivation{}, err
+    }
+    return result, nil
+}
+
+// Reactivate vuelve a habilitar a un empleado dado de baja
+func (r *employeeRepository) Reactivate(employeeID, actorID int) (models.Employee, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.Employee{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    if err := lockActiveEmployee(tx, employeeID); err == nil {
+        return models.Employee{}, errors.New("employee is already active")
+    } else if !strings.Contains(err.Error(), "employee is inactive") {
+        return models.Employee{}, err
+    }
+    oldData, err := employeeSnapshot(tx, employeeID)
+    if err != nil {
+        return models.Employee{}, err
+    }
+
+    if _, err := tx.Exec(`
+        UPDATE employees
+        SET active = TRUE, deactivated_at = NULL, deactivated_by = NULL
+        WHERE id = $1`,
+        employeeID,
+    ); err != nil {
+        return models.Employee{}, errors.Wrap(err, "failed to reactivate employee")
+    }
+    if err := insertEmployeeAudit(tx, employeeID, actorID, models.EmployeeAuditReactivate, oldData); err != nil {
+        return models.Employee{}, err
+    }
+    if err := tx.Commit(); err != nil {
+        return models.Employee{}, errors.Wrap(err, "failed to commit transaction")
+    }
+    return r.FindByID(employeeID)
+}
+
+// TransferOwnership traspasa la propiedad del negocio: newOwnerID pasa a dueño y ownerID a
+// administrador. Las sesiones de ambos se invalidan porque el rol viaja en el token
+func (r *employeeRepository) TransferOwnership(ownerID, newOwnerID int) (models.OwnershipTransfer, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.OwnershipTransfer{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    // Bloquear siempre en el mismo orden para no interbloquearse con otro traspaso
+    first, second := ownerID, newOwnerID
+    if second < first {
+        first, second = second, first
+    }
+    for _, employeeID := range []int{first, second} {
+        if err := lockActiveEmployee(tx, employeeID); err != nil {
+            return models.OwnershipTransfer{}, err
+        }
+    }
+
+    changes := []struct {
+        employeeID int
+        role       models.EmployeeRole
+    }{
+        {newOwnerID, models.EmployeeRoleOwner},
+        {ownerID, models.EmployeeRoleAdmin},
+    }
+    for _, change := range changes {
+        oldData, err := employeeSnapshot(tx, change.employeeID)
+        if err != nil {
+            return models.OwnershipTransfer{}, err
+        }
+        if _, err := tx.Exec(`
+            UPDATE employees
+            SET role = $2, token_version = token_version + 1
+            WHERE id = $1`,
+            change.employeeID, change.role,
+        ); err != nil {
+            return models.OwnershipTransfer{}, errors.Wrap(err, "failed to change employee role")
+        }
+        if err := insertEmployeeAudit(tx, change.employeeID, ownerID, models.EmployeeAuditOwnershipTransfer, oldData); err != nil {
+            return models.OwnershipTransfer{}, err
+        }
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.OwnershipTransfer{}, errors.Wrap(err, "failed to commit transaction")
+    }
+
+    var transfer models.OwnershipTransfer
+    if transfer.PreviousOwner, err = r.FindByID(ownerID); err != nil {
+        return models.OwnershipTransfer{}, err
+    }
+    if transfer.NewOwner, err = r.FindByID(newOwnerID); err != nil {
+        return models.OwnershipTransfer{}, err
+    }
+    return transfer, nil
+}
+
+// FindAuditLogs devuelve el historial de acciones sobre un empleado, del más reciente al más antiguo
+func (r *employeeRepository) FindAuditLogs(employeeID int) ([]models.EmployeeAuditLog, error) {
+    rows, err := r.db.Query(`
+        SELECT id, employee_id, actor_id, action, old_data, new_data, created_at
+        FROM employee_audit_logs
+        WHERE employee_id = $1
+        ORDER BY created_at DESC, id DESC`,
+        employeeID,
+    )
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query employee audit logs")
+    }
+    defer rows.Close()
+
+    logs := []models.EmployeeAuditLog{}
+    for rows.Next() {
+        var auditLog models.EmployeeAuditLog
+        var oldData sql.NullString
+        if err := rows.Scan(&auditLog.ID, &auditLog.EmployeeID, &auditLog.ActorID, &auditLog.Action, &oldData, &auditLog.NewData, &auditLog.CreatedAt); err != nil {
+            return nil, errors.Wrap(err, "failed to scan employee audit log")
+        }
+        if oldData.Valid {
+            auditLog.OldData = &oldData.String
+        }
+        logs = append(logs, auditLog)
+    }
+    return logs, nil
+}
+
+// rowScanner permite leer una fila tanto de *sql.Row como de *sql.Rows
+type rowScanner interface {
+    Scan(dest ...interface{}) error
+}
+
+// scanEmployee lee las columnas de employees en el orden en que las seleccionan las consultas de este archivo
+func scanEmployee(row rowScanner) (models.Employee, error) {
+    var employee models.Employee
+    var deactivatedAt sql.NullTime
+    var deactivatedBy sql.NullInt64
+    if err := row.Scan(&employee.ID, &employee.EmployeeName, &employee.Email, &employee.PhoneNumber, &employee.Role, &employee.Username, &employee.Password, &employee.TokenVersion, &employee.Active, &deactivatedAt, &deactivatedBy, &employee.CreatedAt); err != nil {
+        return models.Employee{}, err
+    }
+    employee.DeactivatedAt = nullTimeToPtr(deactivatedAt)
+    employee.DeactivatedBy = nullIntToPtr(deactivatedBy)
+    return employee, nil
+}
+
+// lockActiveEmployee bloquea la fila del empleado dentro de la transacción y verifica que esté activo
+func lockActiveEmployee(tx *sql.Tx, employeeID int) error {
+    var active bool
+    err := tx.QueryRow(`
+        SELECT active
+        FROM employees
+        WHERE id = $1
+        FOR UPDATE`,
+        employeeID,
+    ).Scan(&active)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return errors.Wrap(err, "employee not found")
+        }
+        return errors.Wrap(err, "failed to lock employee")
+    }
+    if !active {
+        return errors.New("employee is inactive")
     }
     return nil
-}
\ No newline at end of file
+}
+
+// employeeSnapshot devuelve la fila del empleado como JSON, sin la contraseña, para el registro de
+// auditoría
+func employeeSnapshot(tx *sql.Tx, employeeID int) (sql.NullString, error) {
+    var snapshot sql.NullString
+    err := tx.QueryRow(`
+        SELECT to_jsonb(e) - 'password'
+        FROM employees e
+        WHERE id = $1`,
+        employeeID,
+    ).Scan(&snapshot)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return sql.NullString{}, errors.Wrap(err, "employee not found")
+        }
+        return sql.NullString{}, errors.Wrap(err, "failed to read employee for audit")
+    }
+    return snapshot, nil
+}
+
+// insertEmployeeAudit registra una acción sobre un empleado con la fila anterior (oldData) y la
+// fila tal como queda dentro de la transacción
+func insertEmployeeAudit(tx *sql.Tx, employeeID, actorID int, action models.EmployeeAuditAction, oldData sql.NullString) error {
+    if _, err := tx.Exec(`
+        INSERT INTO employee_audit_logs (employee_id, actor_id, action, old_data, new_data, created_at)
+        SELECT e.id, $2, $3, $4::JSONB, to_jsonb(e) - 'password', CURRENT_TIMESTAMP
+        FROM employees e
+        WHERE e.id = $1`,
+        employeeID, actorID, action, oldData,
+    ); err != nil {
+        return errors.Wrap(err, "failed to record employee audit log")
+    }
+    return nil
+}
+
+// uniqueViolation traduce la violación de los índices únicos de usuario o email (sin distinguir
+// mayúsculas) a un error de validación; devuelve nil para cualquier otro error. Cubre la carrera
+// entre la comprobación del servicio y la escritura
+func uniqueViolation(err error) *models.ValidationError {
+    pqErr, ok := err.(*pq.Error)
+    if !ok || pqErr.Code != "23505" {
+        return nil
+    }
+    validation := &models.ValidationError{}
+    switch pqErr.Constraint {
+    case "idx_employees_username_lower":
+        validation.Add("username", "is already in use")
+    case "idx_employees_email_lower":
+        validation.Add("email", "is already in use")
+    default:
+        return nil
+    }
+    return validation
+}
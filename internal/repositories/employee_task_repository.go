@@ -15,7 +15,8 @@ type EmployeeTaskRepository interface {
     FindByEmployeeID(employeeID int) ([]models.EmployeeTask, error)
     Create(task models.EmployeeTask) (models.EmployeeTask, error)
     Update(task models.EmployeeTask) (models.EmployeeTask, error)
-    UpdateStatus(taskID int, status models.EmployeeTaskStatus, completedAt *time.Time) error
+    UpdateStatus(taskID int, from, to models.EmployeeTaskStatus, completedAt *time.Time, changedBy int) error
+    FindStatusHistory(taskID int) ([]models.EmployeeTaskStatusChange, error)
     Delete(taskID int) error
 }
 
@@ -135,16 +136,17 @@ func (r *employeeTaskRepository) Create(task models.EmployeeTask) (models.Employ
     return createdTask, nil
 }
 
+// Update edita el empleado, la descripción y la fecha de asignación; el estado solo cambia con UpdateStatus
 func (r *employeeTaskRepository) Update(task models.EmployeeTask) (models.EmployeeTask, error) {
     var updatedTask models.EmployeeTask
     var completedAt sql.NullTime
 
     err := r.db.QueryRow(`
         UPDATE employee_tasks
-        SET employee_id = $1, task_description = $2, status = $3, assigned_at = $4, completed_at = $5
-        WHERE id = $6
+        SET employee_id = $1, task_description = $2, assigned_at = $3
+        WHERE id = $4
         RETURNING id, employee_id, task_description, status, assigned_at, completed_at, created_at`,
-        task.EmployeeID, task.TaskDescription, task.Status, task.AssignedAt, task.CompletedAt, task.ID,
+        task.EmployeeID, task.TaskDescription, task.AssignedAt, task.ID,
     ).Scan(&updatedTask.ID, &updatedTask.EmployeeID, &updatedTask.TaskDescription, &updatedTask.Status, &updatedTask.AssignedAt, &completedAt, &updatedTask.CreatedAt)
 
     if err != nil {
@@ -162,12 +164,20 @@ func (r *employeeTaskRepository) Update(task models.EmployeeTask) (models.Employ
     return updatedTask, nil
 }
 
-func (r *employeeTaskRepository) UpdateStatus(taskID int, status models.EmployeeTaskStatus, completedAt *time.Time) error {
-    result, err := r.db.Exec(`
+// UpdateStatus cambia el estado de la tarea solo si sigue en from (así dos cambios simultáneos no se
+// pisan) y registra el cambio en employee_task_status_history en la misma transacción
+func (r *employeeTaskRepository) UpdateStatus(taskID int, from, to models.EmployeeTaskStatus, completedAt *time.Time, changedBy int) error {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    result, err := tx.Exec(`
         UPDATE employee_tasks
         SET status = $1, completed_at = $2
-        WHERE id = $3`,
-        status, completedAt, taskID,
+        WHERE id = $3 AND status = $4`,
+        to, completedAt, taskID, from,
     )
     if err != nil {
         return errors.Wrap(err, "failed to update task status")
@@ -178,11 +188,60 @@ func (r *employeeTaskRepository) UpdateStatus(taskID int, status models.Employee
         return errors.Wrap(err, "failed to check rows affected")
     }
     if rowsAffected == 0 {
-        return errors.New("task not found")
+        // Distinguir si la tarea no existe o si otro cambio se adelantó
+        var exists bool
+        err := tx.QueryRow(`
+            SELECT EXISTS (SELECT 1 FROM employee_tasks WHERE id = $1)`,
+            taskID,
+        ).Scan(&exists)
+        if err != nil {
+            return errors.Wrap(err, "failed to check task")
+        }
+        if !exists {
+            return errors.New("task not found")
+        }
+        return errors.New("task status has changed")
+    }
+
+    if _, err := tx.Exec(`
+        INSERT INTO employee_task_status_history (task_id, from_status, to_status, changed_by, changed_at)
+        VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)`,
+        taskID, from, to, changedBy,
+    ); err != nil {
+        return errors.Wrap(err, "failed to record task status change")
+    }
+
+    if err := tx.Commit(); err != nil {
+        return errors.Wrap(err, "failed to commit transaction")
     }
     return nil
 }
 
+// FindStatusHistory devuelve los cambios de estado de una tarea en orden cronológico
+func (r *employeeTaskRepository) FindStatusHistory(taskID int) ([]models.EmployeeTaskStatusChange, error) {
+    rows, err := r.db.Query(`
+        SELECT id, task_id, from_status, to_status, changed_by, changed_at
+        FROM employee_task_status_history
+        WHERE task_id = $1
+        ORDER BY changed_at, id`,
+        taskID,
+    )
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query task status history")
+    }
+    defer rows.Close()
+
+    history := []models.EmployeeTaskStatusChange{}
+    for rows.Next() {
+        var change models.EmployeeTaskStatusChange
+        if err := rows.Scan(&change.ID, &change.TaskID, &change.FromStatus, &change.ToStatus, &change.ChangedBy, &change.ChangedAt); err != nil {
+            return nil, errors.Wrap(err, "failed to scan task status change")
+        }
+        history = append(history, change)
+    }
+    return history, nil
+}
+
 func (r *employeeTaskRepository) Delete(taskID int) error {
     result, err := r.db.Exec(`
         DELETE FROM employee_tasks
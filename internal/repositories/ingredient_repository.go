@@ -0,0 +1,200 @@
+package repositories
+
+import (
+    "database/sql"
+
+    "gastrobar-backend/internal/models"
+
+    "github.com/pkg/errors"
+    "github.com/shopspring/decimal"
+)
+
+type IngredientRepository interface {
+    FindByID(ingredientID int) (models.Ingredient, error)
+    FindByName(ingredientName string) (models.Ingredient, error)
+    FindAll() ([]models.Ingredient, error)
+    Create(ingredient models.Ingredient, employeeID int) (models.Ingredient, error)
+    Update(ingredient models.Ingredient, employeeID int) (models.Ingredient, error)
+    Delete(ingredientID int) error
+}
+
+type ingredientRepository struct {
+    db *sql.DB
+}
+
+func NewIngredientRepository(db *sql.DB) IngredientRepository {
+    return &ingredientRepository{db: db}
+}
+
+func (r *ingredientRepository) FindByID(ingredientID int) (models.Ingredient, error) {
+    var ingredient models.Ingredient
+    err := r.db.QueryRow(`
+        SELECT id, ingredient_name, unit, stock, created_at
+        FROM ingredients
+        WHERE id = $1`,
+        ingredientID,
+    ).Scan(&ingredient.ID, &ingredient.IngredientName, &ingredient.Unit, &ingredient.Stock, &ingredient.CreatedAt)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return models.Ingredient{}, errors.Wrap(err, "ingredient not found")
+        }
+        return models.Ingredient{}, errors.Wrap(err, "failed to query ingredient by ID")
+    }
+    return ingredient, nil
+}
+
+func (r *ingredientRepository) FindByName(ingredientName string) (models.Ingredient, error) {
+    var ingredient models.Ingredient
+    err := r.db.QueryRow(`
+        SELECT id, ingredient_name, unit, stock, created_at
+        FROM ingredients
+        WHERE ingredient_name = $1`,
+        ingredientName,
+    ).Scan(&ingredient.ID, &ingredient.IngredientName, &ingredient.Unit, &ingredient.Stock, &ingredient.CreatedAt)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return models.Ingredient{}, errors.Wrap(err, "ingredient not found")
+        }
+        return models.Ingredient{}, errors.Wrap(err, "failed to query ingredient by name")
+    }
+    return ingredient, nil
+}
+
+func (r *ingredientRepository) FindAll() ([]models.Ingredient, error) {
+    rows, err := r.db.Query(`
+        SELECT id, ingredient_name, unit, stock, created_at
+        FROM ingredients
+        ORDER BY ingredient_name`)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query ingredients")
+    }
+    defer rows.Close()
+
+    var ingredients []models.Ingredient
+    for rows.Next() {
+        var ingredient models.Ingredient
+        if err := rows.Scan(&ingredient.ID, &ingredient.IngredientName, &ingredient.Unit, &ingredient.Stock, &ingredient.CreatedAt); err != nil {
+            return nil, errors.Wrap(err, "failed to scan ingredient")
+        }
+        ingredients = append(ingredients, ingredient)
+    }
+    return ingredients, nil
+}
+
+// Create crea el ingrediente con stock 0 y registra el stock inicial como un movimiento de ajuste
+func (r *ingredientRepository) Create(ingredient models.Ingredient, employeeID int) (models.Ingredient, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.Ingredient{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    var ingredientID int
+    err = tx.QueryRow(`
+        INSERT INTO ingredients (ingredient_name, unit, stock, created_at)
+        VALUES ($1, $2, 0, CURRENT_TIMESTAMP)
+        RETURNING id`,
+        ingredient.IngredientName, ingredient.Unit,
+    ).Scan(&ingredientID)
+    if err != nil {
+        return models.Ingredient{}, errors.Wrap(err, "failed to create ingredient")
+    }
+
+    if err := adjustIngredientStock(tx, ingredientID, decimal.Zero, ingredient.Stock, employeeID, "Stock inicial"); err != nil {
+        return models.Ingredient{}, err
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.Ingredient{}, errors.Wrap(err, "failed to commit transaction")
+    }
+    return r.FindByID(ingredientID)
+}
+
+// Update actualiza los datos del ingrediente; la diferencia de stock se registra como un ajuste
+func (r *ingredientRepository) Update(ingredient models.Ingredient, employeeID int) (models.Ingredient, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.Ingredient{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    var currentStock decimal.Decimal
+    err = tx.QueryRow(`
+        SELECT stock
+        FROM ingredients
+        WHERE id = $1
+        FOR UPDATE`,
+        ingredient.ID,
+    ).Scan(&currentStock)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return models.Ingredient{}, errors.Wrap(err, "ingredient not found")
+        }
+        return models.Ingredient{}, errors.Wrap(err, "failed to lock ingredient")
+    }
+
+    if _, err := tx.Exec(`
+        UPDATE ingredients
+        SET ingredient_name = $1, unit = $2
+        WHERE id = $3`,
+        ingredient.IngredientName, ingredient.Unit, ingredient.ID,
+    ); err != nil {
+        return models.Ingredient{}, errors.Wrap(err, "failed to update ingredient")
+    }
+
+    if err := adjustIngredientStock(tx, ingredient.ID, currentStock, ingredient.Stock, employeeID, "Ajuste manual al editar el ingrediente"); err != nil {
+        return models.Ingredient{}, err
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.Ingredient{}, errors.Wrap(err, "failed to commit transaction")
+    }
+    return r.FindByID(ingredient.ID)
+}
+
+// adjustIngredientStock registra un movimiento de ajuste por la diferencia entre el stock actual y el deseado
+func adjustIngredientStock(tx *sql.Tx, ingredientID int, currentStock, targetStock decimal.Decimal, employeeID int, reason string) error {
+    if targetStock.Equal(currentStock) {
+        return nil
+    }
+    _, err := insertStockMovement(tx, models.StockMovement{
+        IngredientID: &ingredientID,
+        MovementType: models.StockMovementTypeAdjustment,
+        Quantity:     targetStock.Sub(currentStock),
+        EmployeeID:   &employeeID,
+        Reason:       reason,
+    })
+    return err
+}
+
+func (r *ingredientRepository) Delete(ingredientID int) error {
+    // No se puede eliminar un ingrediente que forme parte de alguna receta
+    var inUse bool
+    err := r.db.QueryRow(`
+        SELECT EXISTS (SELECT 1 FROM recipe_items WHERE ingredient_id = $1)`,
+        ingredientID,
+    ).Scan(&inUse)
+    if err != nil {
+        return errors.Wrap(err, "failed to check ingredient usage")
+    }
+    if inUse {
+        return errors.New("ingredient is used in a recipe")
+    }
+
+    result, err := r.db.Exec(`
+        DELETE FROM ingredients
+        WHERE id = $1`,
+        ingredientID,
+    )
+    if err != nil {
+        return errors.Wrap(err, "failed to delete ingredient")
+    }
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return errors.Wrap(err, "failed to check rows affected")
+    }
+    if rowsAffected == 0 {
+        return errors.New("ingredient not found")
+    }
+    return nil
+}
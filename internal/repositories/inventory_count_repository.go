@@ -0,0 +1,339 @@
+package repositories
+
+import (
+    "database/sql"
+    "fmt"
+    "strings"
+
+    "gastrobar-backend/internal/models"
+
+    "github.com/pkg/errors"
+    "github.com/shopspring/decimal"
+)
+
+type InventoryCountRepository interface {
+    Open(notes string, employeeID int) (models.InventoryCount, error)
+    FindByID(inventoryCountID int) (models.InventoryCount, error)
+    FindAll() ([]models.InventoryCount, error)
+    SaveEntry(entry models.InventoryCountEntry) (models.InventoryCountEntry, error)
+    FindVariances(inventoryCountID int) ([]models.InventoryCountVariance, error)
+    Approve(inventoryCountID, employeeID int) (models.InventoryCount, error)
+    Cancel(inventoryCountID int) (models.InventoryCount, error)
+    FindApprovedLines(inventoryCountID int) ([]models.InventoryCountVariance, error)
+}
+
+type inventoryCountRepository struct {
+    db *sql.DB
+}
+
+func NewInventoryCountRepository(db *sql.DB) InventoryCountRepository {
+    return &inventoryCountRepository{db: db}
+}
+
+// queryer permite ejecutar consultas tanto con la conexión como dentro de una transacción
+type queryer interface {
+    Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// liveVarianceQuery compara el total contado de cada ítem (suma de los registros de todos los
+// empleados) con su stock actual; el costo unitario es el de la última compra recibida
+const liveVarianceQuery = `
+        SELECT mi.id, mi.item_name, mi.stock, SUM(e.counted_quantity)::INTEGER,
+               (SELECT sm.unit_cost
+                FROM stock_movements sm
+                WHERE sm.menu_item_id = mi.id AND sm.movement_type = 'purchase'
+                ORDER BY sm.created_at DESC, sm.id DESC
+                LIMIT 1)
+        FROM inventory_count_entries e
+        JOIN menu_items mi ON mi.id = e.menu_item_id
+        WHERE e.inventory_count_id = $1
+        GROUP BY mi.id, mi.item_name, mi.stock
+        ORDER BY mi.item_name`
+
+// Open abre una nueva sesión de conteo; solo puede haber una abierta a la vez
+func (r *inventoryCountRepository) Open(notes string, employeeID int) (models.InventoryCount, error) {
+    var inventoryCountID int
+    err := r.db.QueryRow(`
+        INSERT INTO inventory_counts (status, notes, opened_by, opened_at)
+        VALUES ('open', NULLIF($1, ''), $2, CURRENT_TIMESTAMP)
+        RETURNING id`,
+        notes, employeeID,
+    ).Scan(&inventoryCountID)
+    if err != nil {
+        if strings.Contains(err.Error(), "idx_inventory_counts_single_open") {
+            return models.InventoryCount{}, errors.New("an inventory count is already open")
+        }
+        return models.InventoryCount{}, errors.Wrap(err, "failed to open inventory count")
+    }
+    return r.FindByID(inventoryCountID)
+}
+
+func (r *inventoryCountRepository) FindByID(inventoryCountID int) (models.InventoryCount, error) {
+    var count models.InventoryCount
+    var notes sql.NullString
+    var approvedBy sql.NullInt64
+    var approvedAt, closedAt sql.NullTime
+    err := r.db.QueryRow(`
+        SELECT id, status, notes, opened_by, opened_at, approved_by, approved_at, closed_at
+        FROM inventory_counts
+        WHERE id = $1`,
+        inventoryCountID,
+    ).Scan(&count.ID, &count.Status, &notes, &count.OpenedBy, &count.OpenedAt, &approvedBy, &approvedAt, &closedAt)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return models.InventoryCount{}, errors.Wrap(err, "inventory count not found")
+        }
+        return models.InventoryCount{}, errors.Wrap(err, "failed to query inventory count by ID")
+    }
+    count.Notes = notes.String
+    count.ApprovedBy = nullIntToPtr(approvedBy)
+    count.ApprovedAt = nullTimeToPtr(approvedAt)
+    count.ClosedAt = nullTimeToPtr(closedAt)
+
+    rows, err := r.db.Query(`
+        SELECT id, inventory_count_id, menu_item_id, employee_id, counted_quantity, updated_at
+        FROM inventory_count_entries
+        WHERE inventory_count_id = $1
+        ORDER BY menu_item_id, employee_id`,
+        inventoryCountID,
+    )
+    if err != nil {
+        return models.InventoryCount{}, errors.Wrap(err, "failed to query inventory count entries")
+    }
+    defer rows.Close()
+
+    count.Entries = []models.InventoryCountEntry{}
+    for rows.Next() {
+        var entry models.InventoryCountEntry
+        if err := rows.Scan(&entry.ID, &entry.InventoryCountID, &entry.MenuItemID, &entry.EmployeeID, &entry.CountedQuantity, &entry.UpdatedAt); err != nil {
+            return models.InventoryCount{}, errors.Wrap(err, "failed to scan inventory count entry")
+        }
+        count.Entries = append(count.Entries, entry)
+    }
+    return count, nil
+}
+
+// FindAll devuelve las sesiones de conteo (sin sus registros), de la más reciente a la más antigua
+func (r *inventoryCountRepository) FindAll() ([]models.InventoryCount, error) {
+    rows, err := r.db.Query(`
+        SELECT id, status, notes, opened_by, opened_at, approved_by, approved_at, closed_at
+        FROM inventory_counts
+        ORDER BY opened_at DESC`)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query inventory counts")
+    }
+    defer rows.Close()
+
+    var counts []models.InventoryCount
+    for rows.Next() {
+        var count models.InventoryCount
+        var notes sql.NullString
+        var approvedBy sql.NullInt64
+        var approvedAt, closedAt sql.NullTime
+        if err := rows.Scan(&count.ID, &count.Status, &notes, &count.OpenedBy, &count.OpenedAt, &approvedBy, &approvedAt, &closedAt); err != nil {
+            return nil, errors.Wrap(err, "failed to scan inventory count")
+        }
+        count.Notes = notes.String
+        count.ApprovedBy = nullIntToPtr(approvedBy)
+        count.ApprovedAt = nullTimeToPtr(approvedAt)
+        count.ClosedAt = nullTimeToPtr(closedAt)
+        counts = append(counts, count)
+    }
+    return counts, nil
+}
+
+// SaveEntry registra lo que contó un empleado de un ítem; si ya lo había registrado en este
+// conteo, se reemplaza su cantidad (los registros de otros empleados se conservan)
+func (r *inventoryCountRepository) SaveEntry(entry models.InventoryCountEntry) (models.InventoryCountEntry, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.InventoryCountEntry{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    if err := lockInventoryCount(tx, entry.InventoryCountID); err != nil {
+        return models.InventoryCountEntry{}, err
+    }
+
+    var saved models.InventoryCountEntry
+    err = tx.QueryRow(`
+        INSERT INTO inventory_count_entries (inventory_count_id, menu_item_id, employee_id, counted_quantity, updated_at)
+        VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
+        ON CONFLICT (inventory_count_id, menu_item_id, employee_id)
+        DO UPDATE SET counted_quantity = EXCLUDED.counted_quantity, updated_at = EXCLUDED.updated_at
+        RETURNING id, inventory_count_id, menu_item_id, employee_id, counted_quantity, updated_at`,
+        entry.InventoryCountID, entry.MenuItemID, entry.EmployeeID, entry.CountedQuantity,
+    ).Scan(&saved.ID, &saved.InventoryCountID, &saved.MenuItemID, &saved.EmployeeID, &saved.CountedQuantity, &saved.UpdatedAt)
+    if err != nil {
+        return models.InventoryCountEntry{}, errors.Wrap(err, "failed to save inventory count entry")
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.InventoryCountEntry{}, errors.Wrap(err, "failed to commit transaction")
+    }
+    return saved, nil
+}
+
+// FindVariances calcula las diferencias del conteo contra el stock actual de los ítems contados
+func (r *inventoryCountRepository) FindVariances(inventoryCountID int) ([]models.InventoryCountVariance, error) {
+    return queryVariances(r.db, liveVarianceQuery, inventoryCountID)
+}
+
+// Approve cierra el conteo: bloquea los ítems contados, guarda las diferencias como reporte y
+// registra una corrección de conteo por cada ítem con diferencia, todo en una sola transacción
+func (r *inventoryCountRepository) Approve(inventoryCountID, employeeID int) (models.InventoryCount, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.InventoryCount{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    if err := lockInventoryCount(tx, inventoryCountID); err != nil {
+        return models.InventoryCount{}, err
+    }
+
+    // Bloquear los ítems contados para que ninguna venta cambie su stock mientras se corrige
+    if _, err := tx.Exec(`
+        SELECT id
+        FROM menu_items
+        WHERE id IN (SELECT menu_item_id FROM inventory_count_entries WHERE inventory_count_id = $1)
+        ORDER BY id
+        FOR UPDATE`,
+        inventoryCountID,
+    ); err != nil {
+        return models.InventoryCount{}, errors.Wrap(err, "failed to lock counted items")
+    }
+
+    variances, err := queryVariances(tx, liveVarianceQuery, inventoryCountID)
+    if err != nil {
+        return models.InventoryCount{}, err
+    }
+    if len(variances) == 0 {
+        return models.InventoryCount{}, errors.New("inventory count has no entries")
+    }
+
+    for _, variance := range variances {
+        if _, err := tx.Exec(`
+            INSERT INTO inventory_count_lines (inventory_count_id, menu_item_id, system_quantity, counted_quantity, variance, unit_cost)
+            VALUES ($1, $2, $3, $4, $5, $6)`,
+            inventoryCountID, variance.MenuItemID, variance.SystemQuantity, variance.CountedQuantity, variance.Variance, nullableDecimal(variance.UnitCost),
+        ); err != nil {
+            return models.InventoryCount{}, errors.Wrap(err, "failed to save inventory count line")
+        }
+
+        if variance.Variance == 0 {
+            continue
+        }
+        menuItemID := variance.MenuItemID
+        if _, err := insertStockMovement(tx, models.StockMovement{
+            MenuItemID:       &menuItemID,
+            MovementType:     models.StockMovementTypeCountCorrection,
+            Quantity:         decimal.NewFromInt(int64(variance.Variance)),
+            EmployeeID:       &employeeID,
+            Reason:           fmt.Sprintf("Conteo físico #%d", inventoryCountID),
+            InventoryCountID: &inventoryCountID,
+        }); err != nil {
+            return models.InventoryCount{}, err
+        }
+    }
+
+    if _, err := tx.Exec(`
+        UPDATE inventory_counts
+        SET status = 'approved', approved_by = $1, approved_at = CURRENT_TIMESTAMP, closed_at = CURRENT_TIMESTAMP
+        WHERE id = $2`,
+        employeeID, inventoryCountID,
+    ); err != nil {
+        return models.InventoryCount{}, errors.Wrap(err, "failed to approve inventory count")
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.InventoryCount{}, errors.Wrap(err, "failed to commit transaction")
+    }
+    return r.FindByID(inventoryCountID)
+}
+
+// Cancel descarta un conteo abierto sin modificar el stock
+func (r *inventoryCountRepository) Cancel(inventoryCountID int) (models.InventoryCount, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.InventoryCount{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    if err := lockInventoryCount(tx, inventoryCountID); err != nil {
+        return models.InventoryCount{}, err
+    }
+
+    if _, err := tx.Exec(`
+        UPDATE inventory_counts
+        SET status = 'cancelled', closed_at = CURRENT_TIMESTAMP
+        WHERE id = $1`,
+        inventoryCountID,
+    ); err != nil {
+        return models.InventoryCount{}, errors.Wrap(err, "failed to cancel inventory count")
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.InventoryCount{}, errors.Wrap(err, "failed to commit transaction")
+    }
+    return r.FindByID(inventoryCountID)
+}
+
+// FindApprovedLines devuelve las diferencias guardadas al aprobar el conteo
+func (r *inventoryCountRepository) FindApprovedLines(inventoryCountID int) ([]models.InventoryCountVariance, error) {
+    return queryVariances(r.db, `
+        SELECT l.menu_item_id, mi.item_name, l.system_quantity, l.counted_quantity, l.unit_cost
+        FROM inventory_count_lines l
+        JOIN menu_items mi ON mi.id = l.menu_item_id
+        WHERE l.inventory_count_id = $1
+        ORDER BY mi.item_name`,
+        inventoryCountID,
+    )
+}
+
+func queryVariances(q queryer, query string, inventoryCountID int) ([]models.InventoryCountVariance, error) {
+    rows, err := q.Query(query, inventoryCountID)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query inventory count variances")
+    }
+    defer rows.Close()
+
+    variances := []models.InventoryCountVariance{}
+    for rows.Next() {
+        var variance models.InventoryCountVariance
+        var unitCost decimal.NullDecimal
+        if err := rows.Scan(&variance.MenuItemID, &variance.ItemName, &variance.SystemQuantity, &variance.CountedQuantity, &unitCost); err != nil {
+            return nil, errors.Wrap(err, "failed to scan inventory count variance")
+        }
+        variance.Variance = variance.CountedQuantity - variance.SystemQuantity
+        variance.UnitCost = nullDecimalToPtr(unitCost)
+        if variance.UnitCost != nil {
+            value := variance.UnitCost.Mul(decimal.NewFromInt(int64(variance.Variance)))
+            variance.VarianceValue = &value
+        }
+        variances = append(variances, variance)
+    }
+    return variances, nil
+}
+
+// lockInventoryCount bloquea el conteo y verifica que siga abierto
+func lockInventoryCount(tx *sql.Tx, inventoryCountID int) error {
+    var status models.InventoryCountStatus
+    err := tx.QueryRow(`
+        SELECT status
+        FROM inventory_counts
+        WHERE id = $1
+        FOR UPDATE`,
+        inventoryCountID,
+    ).Scan(&status)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return errors.Wrap(err, "inventory count not found")
+        }
+        return errors.Wrap(err, "failed to lock inventory count")
+    }
+    if status != models.InventoryCountStatusOpen {
+        return errors.New("inventory count is not open")
+    }
+    return nil
+}
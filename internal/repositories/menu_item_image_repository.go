@@ -0,0 +1,96 @@
+package repositories
+
+import (
+    "database/sql"
+
+    "gastrobar-backend/internal/models"
+
+    "github.com/pkg/errors"
+)
+
+type MenuItemImageRepository interface {
+    FindByMenuItemID(menuItemID int) (models.MenuItemImage, error)
+    FindAll() ([]models.MenuItemImage, error)
+    Upsert(image models.MenuItemImage) (models.MenuItemImage, error)
+    Delete(menuItemID int) error
+}
+
+type menuItemImageRepository struct {
+    db *sql.DB
+}
+
+func NewMenuItemImageRepository(db *sql.DB) MenuItemImageRepository {
+    return &menuItemImageRepository{db: db}
+}
+
+func (r *menuItemImageRepository) FindByMenuItemID(menuItemID int) (models.MenuItemImage, error) {
+    var image models.MenuItemImage
+    err := r.db.QueryRow(`
+        SELECT id, menu_item_id, image_path, thumbnail_path, created_at
+        FROM menu_item_images
+        WHERE menu_item_id = $1`,
+        menuItemID,
+    ).Scan(&image.ID, &image.MenuItemID, &image.ImagePath, &image.ThumbnailPath, &image.CreatedAt)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return models.MenuItemImage{}, errors.Wrap(err, "image not found")
+        }
+        return models.MenuItemImage{}, errors.Wrap(err, "failed to query image by menu item ID")
+    }
+    return image, nil
+}
+
+func (r *menuItemImageRepository) FindAll() ([]models.MenuItemImage, error) {
+    rows, err := r.db.Query(`
+        SELECT id, menu_item_id, image_path, thumbnail_path, created_at
+        FROM menu_item_images`)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query images")
+    }
+    defer rows.Close()
+
+    var images []models.MenuItemImage
+    for rows.Next() {
+        var image models.MenuItemImage
+        if err := rows.Scan(&image.ID, &image.MenuItemID, &image.ImagePath, &image.ThumbnailPath, &image.CreatedAt); err != nil {
+            return nil, errors.Wrap(err, "failed to scan image")
+        }
+        images = append(images, image)
+    }
+    return images, nil
+}
+
+func (r *menuItemImageRepository) Upsert(image models.MenuItemImage) (models.MenuItemImage, error) {
+    var savedImage models.MenuItemImage
+    err := r.db.QueryRow(`
+        INSERT INTO menu_item_images (menu_item_id, image_path, thumbnail_path, created_at)
+        VALUES ($1, $2, $3, CURRENT_TIMESTAMP)
+        ON CONFLICT (menu_item_id)
+        DO UPDATE SET image_path = EXCLUDED.image_path, thumbnail_path = EXCLUDED.thumbnail_path, created_at = CURRENT_TIMESTAMP
+        RETURNING id, menu_item_id, image_path, thumbnail_path, created_at`,
+        image.MenuItemID, image.ImagePath, image.ThumbnailPath,
+    ).Scan(&savedImage.ID, &savedImage.MenuItemID, &savedImage.ImagePath, &savedImage.ThumbnailPath, &savedImage.CreatedAt)
+    if err != nil {
+        return models.MenuItemImage{}, errors.Wrap(err, "failed to save image")
+    }
+    return savedImage, nil
+}
+
+func (r *menuItemImageRepository) Delete(menuItemID int) error {
+    result, err := r.db.Exec(`
+        DELETE FROM menu_item_images
+        WHERE menu_item_id = $1`,
+        menuItemID,
+    )
+    if err != nil {
+        return errors.Wrap(err, "failed to delete image")
+    }
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return errors.Wrap(err, "failed to check rows affected")
+    }
+    if rowsAffected == 0 {
+        return errors.New("image not found")
+    }
+    return nil
+}
@@ -0,0 +1,265 @@
+package repositories
+
+import (
+    "database/sql"
+    "time"
+
+    "gastrobar-backend/internal/models"
+
+    "github.com/pkg/errors"
+)
+
+type MenuItemPriceRepository interface {
+    FindHistoryByMenuItemID(menuItemID int) ([]models.MenuItemPriceHistory, error)
+    CreateScheduled(change models.ScheduledPriceChange) (models.ScheduledPriceChange, error)
+    FindScheduledByID(changeID int) (models.ScheduledPriceChange, error)
+    FindScheduledByMenuItemID(menuItemID int) ([]models.ScheduledPriceChange, error)
+    FindDueScheduled(now time.Time) ([]models.ScheduledPriceChange, error)
+    UpdateScheduledStatus(changeID int, status models.ScheduledPriceStatus) error
+    ApplyScheduled(change models.ScheduledPriceChange) error
+}
+
+type menuItemPriceRepository struct {
+    db *sql.DB
+}
+
+func NewMenuItemPriceRepository(db *sql.DB) MenuItemPriceRepository {
+    return &menuItemPriceRepository{db: db}
+}
+
+func (r *menuItemPriceRepository) FindHistoryByMenuItemID(menuItemID int) ([]models.MenuItemPriceHistory, error) {
+    rows, err := r.db.Query(`
+        SELECT id, menu_item_id, old_price, new_price, changed_by, scheduled_price_change_id, changed_at
+        FROM menu_item_price_history
+        WHERE menu_item_id = $1
+        ORDER BY changed_at DESC`,
+        menuItemID,
+    )
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query price history")
+    }
+    defer rows.Close()
+
+    var history []models.MenuItemPriceHistory
+    for rows.Next() {
+        var entry models.MenuItemPriceHistory
+        var scheduledID sql.NullInt64
+
+        if err := rows.Scan(&entry.ID, &entry.MenuItemID, &entry.OldPrice, &entry.NewPrice, &entry.ChangedBy, &scheduledID, &entry.ChangedAt); err != nil {
+            return nil, errors.Wrap(err, "failed to scan price history entry")
+        }
+
+        if scheduledID.Valid {
+            id := int(scheduledID.Int64)
+            entry.ScheduledPriceChangeID = &id
+        }
+
+        history = append(history, entry)
+    }
+    return history, nil
+}
+
+func (r *menuItemPriceRepository) CreateScheduled(change models.ScheduledPriceChange) (models.ScheduledPriceChange, error) {
+    var created models.ScheduledPriceChange
+    var appliedAt sql.NullTime
+
+    err := r.db.QueryRow(`
+        INSERT INTO menu_item_scheduled_prices (menu_item_id, new_price, effective_at, status, created_by, created_at)
+        VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP)
+        RETURNING id, menu_item_id, new_price, effective_at, status, created_by, applied_at, created_at`,
+        change.MenuItemID, change.NewPrice.String(), change.EffectiveAt, change.Status, change.CreatedBy,
+    ).Scan(&created.ID, &created.MenuItemID, &created.NewPrice, &created.EffectiveAt, &created.Status, &created.CreatedBy, &appliedAt, &created.CreatedAt)
+    if err != nil {
+        return models.ScheduledPriceChange{}, errors.Wrap(err, "failed to create scheduled price change")
+    }
+
+    if appliedAt.Valid {
+        created.AppliedAt = &appliedAt.Time
+    }
+
+    return created, nil
+}
+
+func (r *menuItemPriceRepository) FindScheduledByID(changeID int) (models.ScheduledPriceChange, error) {
+    var change models.ScheduledPriceChange
+    var appliedAt sql.NullTime
+
+    err := r.db.QueryRow(`
+        SELECT id, menu_item_id, new_price, effective_at, status, created_by, applied_at, created_at
+        FROM menu_item_scheduled_prices
+        WHERE id = $1`,
+        changeID,
+    ).Scan(&change.ID, &change.MenuItemID, &change.NewPrice, &change.EffectiveAt, &change.Status, &change.CreatedBy, &appliedAt, &change.CreatedAt)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return models.ScheduledPriceChange{}, errors.Wrap(err, "scheduled price change not found")
+        }
+        return models.ScheduledPriceChange{}, errors.Wrap(err, "failed to query scheduled price change by ID")
+    }
+
+    if appliedAt.Valid {
+        change.AppliedAt = &appliedAt.Time
+    }
+
+    return change, nil
+}
+
+func (r *menuItemPriceRepository) FindScheduledByMenuItemID(menuItemID int) ([]models.ScheduledPriceChange, error) {
+    rows, err := r.db.Query(`
+        SELECT id, menu_item_id, new_price, effective_at, status, created_by, applied_at, created_at
+        FROM menu_item_scheduled_prices
+        WHERE menu_item_id = $1
+        ORDER BY effective_at`,
+        menuItemID,
+    )
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query scheduled price changes")
+    }
+    defer rows.Close()
+
+    return scanScheduledPriceChanges(rows)
+}
+
+func (r *menuItemPriceRepository) FindDueScheduled(now time.Time) ([]models.ScheduledPriceChange, error) {
+    rows, err := r.db.Query(`
+        SELECT id, menu_item_id, new_price, effective_at, status, created_by, applied_at, created_at
+        FROM menu_item_scheduled_prices
+        WHERE status = 'pending' AND effective_at <= $1
+        ORDER BY effective_at`,
+        now,
+    )
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query due scheduled price changes")
+    }
+    defer rows.Close()
+
+    return scanScheduledPriceChanges(rows)
+}
+
+// UpdateScheduledStatus cambia el estado de un cambio programado que sigue pendiente; uno ya aplicado
+// o cancelado no se modifica
+func (r *menuItemPriceRepository) UpdateScheduledStatus(changeID int, status models.ScheduledPriceStatus) error {
+    result, err := r.db.Exec(`
+        UPDATE menu_item_scheduled_prices
+        SET status = $1
+        WHERE id = $2 AND status = 'pending'`,
+        status, changeID,
+    )
+    if err != nil {
+        return errors.Wrap(err, "failed to update scheduled price change status")
+    }
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return errors.Wrap(err, "failed to check rows affected")
+    }
+    if rowsAffected == 0 {
+        // Distinguir si el cambio no existe o si ya dejó de estar pendiente
+        if _, err := r.FindScheduledByID(changeID); err != nil {
+            return err
+        }
+        return errors.New("scheduled price change is not pending")
+    }
+    return nil
+}
+
+// ApplyScheduled actualiza el precio del ítem, registra el historial y marca el cambio como aplicado
+// dentro de una misma transacción. El cambio se bloquea y se vuelve a comprobar que siga pendiente,
+// para no aplicar uno cancelado mientras tanto ni aplicar dos veces el mismo
+func (r *menuItemPriceRepository) ApplyScheduled(change models.ScheduledPriceChange) error {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    var status models.ScheduledPriceStatus
+    err = tx.QueryRow(`
+        SELECT status
+        FROM menu_item_scheduled_prices
+        WHERE id = $1
+        FOR UPDATE`,
+        change.ID,
+    ).Scan(&status)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return errors.Wrap(err, "scheduled price change not found")
+        }
+        return errors.Wrap(err, "failed to lock scheduled price change")
+    }
+    if status != models.ScheduledPriceStatusPending {
+        return errors.New("scheduled price change is not pending")
+    }
+
+    var oldPrice string
+    err = tx.QueryRow(`
+        SELECT price
+        FROM menu_items
+        WHERE id = $1
+        FOR UPDATE`,
+        change.MenuItemID,
+    ).Scan(&oldPrice)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return errors.Wrap(err, "item not found")
+        }
+        return errors.Wrap(err, "failed to lock item")
+    }
+
+    if _, err := tx.Exec(`
+        UPDATE menu_items
+        SET price = $1
+        WHERE id = $2`,
+        change.NewPrice.String(), change.MenuItemID,
+    ); err != nil {
+        return errors.Wrap(err, "failed to update item price")
+    }
+
+    if err := insertPriceHistory(tx, change.MenuItemID, oldPrice, change.NewPrice.String(), change.CreatedBy, &change.ID); err != nil {
+        return err
+    }
+
+    if _, err := tx.Exec(`
+        UPDATE menu_item_scheduled_prices
+        SET status = 'applied', applied_at = CURRENT_TIMESTAMP
+        WHERE id = $1`,
+        change.ID,
+    ); err != nil {
+        return errors.Wrap(err, "failed to mark scheduled price change as applied")
+    }
+
+    if err := tx.Commit(); err != nil {
+        return errors.Wrap(err, "failed to commit transaction")
+    }
+    return nil
+}
+
+// insertPriceHistory registra un cambio de precio dentro de la transacción que lo aplica
+func insertPriceHistory(tx *sql.Tx, menuItemID int, oldPrice, newPrice string, changedBy int, scheduledPriceChangeID *int) error {
+    if _, err := tx.Exec(`
+        INSERT INTO menu_item_price_history (menu_item_id, old_price, new_price, changed_by, scheduled_price_change_id, changed_at)
+        VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP)`,
+        menuItemID, oldPrice, newPrice, changedBy, scheduledPriceChangeID,
+    ); err != nil {
+        return errors.Wrap(err, "failed to create price history entry")
+    }
+    return nil
+}
+
+func scanScheduledPriceChanges(rows *sql.Rows) ([]models.ScheduledPriceChange, error) {
+    var changes []models.ScheduledPriceChange
+    for rows.Next() {
+        var change models.ScheduledPriceChange
+        var appliedAt sql.NullTime
+
+        if err := rows.Scan(&change.ID, &change.MenuItemID, &change.NewPrice, &change.EffectiveAt, &change.Status, &change.CreatedBy, &appliedAt, &change.CreatedAt); err != nil {
+            return nil, errors.Wrap(err, "failed to scan scheduled price change")
+        }
+
+        if appliedAt.Valid {
+            change.AppliedAt = &appliedAt.Time
+        }
+
+        changes = append(changes, change)
+    }
+    return changes, nil
+}
@@ -13,9 +13,11 @@ type MenuItemRepository interface {
     FindByID(itemID int) (models.MenuItem, error)
     FindByName(itemName string) (models.MenuItem, error)
     FindAll() ([]models.MenuItem, error)
-    Create(item models.MenuItem) (models.MenuItem, error)
-    Update(item models.MenuItem) (models.MenuItem, error)
+    Create(item models.MenuItem, employeeID int) (models.MenuItem, error)
+    Update(item models.MenuItem, employeeID int) (models.MenuItem, error)
     Delete(itemID int) error
+    UpsertBatch(items []models.MenuItem, employeeID int) error
+    FindBelowReorderThreshold() ([]models.MenuItem, error)
 }
 
 type menuItemRepository struct {
@@ -29,11 +31,11 @@ func NewMenuItemRepository(db *sql.DB) MenuItemRepository {
 func (r *menuItemRepository) FindByID(itemID int) (models.MenuItem, error) {
     var item models.MenuItem
     err := r.db.QueryRow(`
-        SELECT id, item_name, category, price, stock, description, created_at
+        SELECT id, item_name, category, price, stock, stock_mode, menu_item_available_quantity(id), reorder_threshold, description, created_at
         FROM menu_items
         WHERE id = $1`,
         itemID,
-    ).Scan(&item.ID, &item.ItemName, &item.Category, &item.Price, &item.Stock, &item.Description, &item.CreatedAt)
+    ).Scan(&item.ID, &item.ItemName, &item.Category, &item.Price, &item.Stock, &item.StockMode, &item.AvailableQuantity, &item.ReorderThreshold, &item.Description, &item.CreatedAt)
     if err != nil {
         if err == sql.ErrNoRows {
             return models.MenuItem{}, errors.Wrap(err, "item not found")
@@ -46,11 +48,11 @@ func (r *menuItemRepository) FindByID(itemID int) (models.MenuItem, error) {
 func (r *menuItemRepository) FindByName(itemName string) (models.MenuItem, error) {
     var item models.MenuItem
     err := r.db.QueryRow(`
-        SELECT id, item_name, category, price, stock, description, created_at
+        SELECT id, item_name, category, price, stock, stock_mode, menu_item_available_quantity(id), reorder_threshold, description, created_at
         FROM menu_items
         WHERE item_name = $1`,
         itemName,
-    ).Scan(&item.ID, &item.ItemName, &item.Category, &item.Price, &item.Stock, &item.Description, &item.CreatedAt)
+    ).Scan(&item.ID, &item.ItemName, &item.Category, &item.Price, &item.Stock, &item.StockMode, &item.AvailableQuantity, &item.ReorderThreshold, &item.Description, &item.CreatedAt)
     if err != nil {
         if err == sql.ErrNoRows {
             return models.MenuItem{}, errors.Wrap(err, "item not found")
@@ -62,7 +64,7 @@ func (r *menuItemRepository) FindByName(itemName string) (models.MenuItem, error
 
 func (r *menuItemRepository) FindAll() ([]models.MenuItem, error) {
     rows, err := r.db.Query(`
-        SELECT id, item_name, category, price, stock, description, created_at
+        SELECT id, item_name, category, price, stock, stock_mode, menu_item_available_quantity(id), reorder_threshold, description, created_at
         FROM menu_items`)
     if err != nil {
         return nil, errors.Wrap(err, "failed to query items")
@@ -73,7 +75,7 @@ func (r *menuItemRepository) FindAll() ([]models.MenuItem, error) {
     for rows.Next() {
         var item models.MenuItem
         var price string // Temporal para manejar decimal.Decimal
-        if err := rows.Scan(&item.ID, &item.ItemName, &item.Category, &price, &item.Stock, &item.Description, &item.CreatedAt); err != nil {
+        if err := rows.Scan(&item.ID, &item.ItemName, &item.Category, &price, &item.Stock, &item.StockMode, &item.AvailableQuantity, &item.ReorderThreshold, &item.Description, &item.CreatedAt); err != nil {
             return nil, errors.Wrap(err, "failed to scan item")
         }
         item.Price, _ = decimal.NewFromString(price) // Convertir a decimal.Decimal
@@ -82,53 +84,226 @@ func (r *menuItemRepository) FindAll() ([]models.MenuItem, error) {
     return items, nil
 }
 
-func (r *menuItemRepository) Create(item models.MenuItem) (models.MenuItem, error) {
-    var createdItem models.MenuItem
-    err := r.db.QueryRow(`
-        INSERT INTO menu_items (item_name, category, price, stock, description, created_at)
-        VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP)
-        RETURNING id, item_name, category, price, stock, description, created_at`,
-        item.ItemName, item.Category, item.Price.String(), item.Stock, item.Description,
-    ).Scan(&createdItem.ID, &createdItem.ItemName, &createdItem.Category, &createdItem.Price, &createdItem.Stock, &createdItem.Description, &createdItem.CreatedAt)
+// Create crea el ítem con stock 0 y registra el stock inicial como un movimiento de ajuste,
+// para que el libro de inventario cuadre desde el primer momento
+func (r *menuItemRepository) Create(item models.MenuItem, employeeID int) (models.MenuItem, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.MenuItem{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    var itemID int
+    err = tx.QueryRow(`
+        INSERT INTO menu_items (item_name, category, price, stock, stock_mode, reorder_threshold, description, created_at)
+        VALUES ($1, $2, $3, 0, $4, $5, $6, CURRENT_TIMESTAMP)
+        RETURNING id`,
+        item.ItemName, item.Category, item.Price.String(), item.StockMode, item.ReorderThreshold, item.Description,
+    ).Scan(&itemID)
     if err != nil {
         return models.MenuItem{}, errors.Wrap(err, "failed to create item")
     }
-    return createdItem, nil
+
+    if err := recordInitialStock(tx, itemID, item.Stock, employeeID, "Stock inicial"); err != nil {
+        return models.MenuItem{}, err
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.MenuItem{}, errors.Wrap(err, "failed to commit transaction")
+    }
+
+    // La disponibilidad se calcula después de la escritura para que refleje el nuevo estado
+    return r.FindByID(itemID)
 }
 
-func (r *menuItemRepository) Update(item models.MenuItem) (models.MenuItem, error) {
-    var updatedItem models.MenuItem
-    err := r.db.QueryRow(`
-        UPDATE menu_items
-        SET item_name = $1, category = $2, price = $3, stock = $4, description = $5
-        WHERE id = $6
-        RETURNING id, item_name, category, price, stock, description, created_at`,
-        item.ItemName, item.Category, item.Price.String(), item.Stock, item.Description, item.ID,
-    ).Scan(&updatedItem.ID, &updatedItem.ItemName, &updatedItem.Category, &updatedItem.Price, &updatedItem.Stock, &updatedItem.Description, &updatedItem.CreatedAt)
+// Update actualiza los datos del ítem. El stock enviado se ignora: solo cambia con movimientos de
+// stock (POST /stock-movements), que registran el motivo y el responsable
+func (r *menuItemRepository) Update(item models.MenuItem, employeeID int) (models.MenuItem, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.MenuItem{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    var oldPrice string
+    err = tx.QueryRow(`
+        SELECT price
+        FROM menu_items
+        WHERE id = $1
+        FOR UPDATE`,
+        item.ID,
+    ).Scan(&oldPrice)
     if err != nil {
         if err == sql.ErrNoRows {
             return models.MenuItem{}, errors.Wrap(err, "item not found")
         }
+        return models.MenuItem{}, errors.Wrap(err, "failed to lock item")
+    }
+
+    if _, err := tx.Exec(`
+        UPDATE menu_items
+        SET item_name = $1, category = $2, price = $3, stock_mode = $4, reorder_threshold = $5, description = $6
+        WHERE id = $7`,
+        item.ItemName, item.Category, item.Price.String(), item.StockMode, item.ReorderThreshold, item.Description, item.ID,
+    ); err != nil {
         return models.MenuItem{}, errors.Wrap(err, "failed to update item")
     }
-    return updatedItem, nil
+
+    // Registrar el cambio de precio en el historial dentro de la misma transacción
+    currentPrice, _ := decimal.NewFromString(oldPrice)
+    if !currentPrice.Equal(item.Price) {
+        if err := insertPriceHistory(tx, item.ID, oldPrice, item.Price.String(), employeeID, nil); err != nil {
+            return models.MenuItem{}, err
+        }
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.MenuItem{}, errors.Wrap(err, "failed to commit transaction")
+    }
+    return r.FindByID(item.ID)
 }
 
+// recordInitialStock registra el stock con el que se crea un ítem como un movimiento de ajuste
+func recordInitialStock(tx *sql.Tx, itemID, stock, employeeID int, reason string) error {
+    if stock == 0 {
+        return nil
+    }
+    _, err := insertStockMovement(tx, models.StockMovement{
+        MenuItemID:   &itemID,
+        MovementType: models.StockMovementTypeAdjustment,
+        Quantity:     decimal.NewFromInt(int64(stock)),
+        EmployeeID:   &employeeID,
+        Reason:       reason,
+    })
+    return err
+}
+
+// Delete elimina el ítem si no tiene movimientos de stock; el libro de inventario es de solo
+// inserción, así que un ítem con historial de stock no se puede eliminar
 func (r *menuItemRepository) Delete(itemID int) error {
-    result, err := r.db.Exec(`
+    tx, err := r.db.Begin()
+    if err != nil {
+        return errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    // Bloquear el ítem para que no se registren movimientos mientras se elimina
+    var hasMovements bool
+    err = tx.QueryRow(`
+        SELECT EXISTS (SELECT 1 FROM stock_movements WHERE menu_item_id = menu_items.id)
+        FROM menu_items
+        WHERE id = $1
+        FOR UPDATE`,
+        itemID,
+    ).Scan(&hasMovements)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return errors.Wrap(err, "item not found")
+        }
+        return errors.Wrap(err, "failed to lock item")
+    }
+    if hasMovements {
+        return errors.New("item has stock history")
+    }
+
+    if _, err := tx.Exec(`
         DELETE FROM menu_items
         WHERE id = $1`,
         itemID,
-    )
-    if err != nil {
+    ); err != nil {
         return errors.Wrap(err, "failed to delete item")
     }
-    rowsAffected, err := result.RowsAffected()
+
+    if err := tx.Commit(); err != nil {
+        return errors.Wrap(err, "failed to commit transaction")
+    }
+    return nil
+}
+
+// UpsertBatch crea o actualiza (por nombre) todos los ítems dentro de una misma transacción,
+// registrando en el historial los cambios de precio. El stock solo se usa como stock inicial de los
+// ítems nuevos; el de los existentes se ignora y se cambia con movimientos de stock
+func (r *menuItemRepository) UpsertBatch(items []models.MenuItem, employeeID int) error {
+    tx, err := r.db.Begin()
     if err != nil {
-        return errors.Wrap(err, "failed to check rows affected")
+        return errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    for _, item := range items {
+        var itemID int
+        var oldPrice string
+        err := tx.QueryRow(`
+            SELECT id, price
+            FROM menu_items
+            WHERE item_name = $1
+            FOR UPDATE`,
+            item.ItemName,
+        ).Scan(&itemID, &oldPrice)
+        if err == sql.ErrNoRows {
+            if err := tx.QueryRow(`
+                INSERT INTO menu_items (item_name, category, price, stock, description, created_at)
+                VALUES ($1, $2, $3, 0, $4, CURRENT_TIMESTAMP)
+                RETURNING id`,
+                item.ItemName, item.Category, item.Price.String(), item.Description,
+            ).Scan(&itemID); err != nil {
+                return errors.Wrapf(err, "failed to create item %q", item.ItemName)
+            }
+            if err := recordInitialStock(tx, itemID, item.Stock, employeeID, "Importación del menú"); err != nil {
+                return errors.Wrapf(err, "failed to set stock of item %q", item.ItemName)
+            }
+            continue
+        }
+        if err != nil {
+            return errors.Wrapf(err, "failed to lock item %q", item.ItemName)
+        }
+
+        if _, err := tx.Exec(`
+            UPDATE menu_items
+            SET category = $1, price = $2, description = $3
+            WHERE id = $4`,
+            item.Category, item.Price.String(), item.Description, itemID,
+        ); err != nil {
+            return errors.Wrapf(err, "failed to update item %q", item.ItemName)
+        }
+
+        currentPrice, _ := decimal.NewFromString(oldPrice)
+        if !currentPrice.Equal(item.Price) {
+            if err := insertPriceHistory(tx, itemID, oldPrice, item.Price.String(), employeeID, nil); err != nil {
+                return errors.Wrapf(err, "failed to record price change of item %q", item.ItemName)
+            }
+        }
     }
-    if rowsAffected == 0 {
-        return errors.New("item not found")
+
+    if err := tx.Commit(); err != nil {
+        return errors.Wrap(err, "failed to commit transaction")
     }
     return nil
-}
\ No newline at end of file
+}
+
+// FindBelowReorderThreshold devuelve los ítems con umbral configurado cuya disponibilidad está por debajo de él
+func (r *menuItemRepository) FindBelowReorderThreshold() ([]models.MenuItem, error) {
+    rows, err := r.db.Query(`
+        SELECT id, item_name, category, price, stock, stock_mode, available_quantity, reorder_threshold, description, created_at
+        FROM (
+            SELECT *, menu_item_available_quantity(id) AS available_quantity
+            FROM menu_items
+            WHERE reorder_threshold > 0
+        ) items
+        WHERE available_quantity < reorder_threshold
+        ORDER BY available_quantity::NUMERIC / reorder_threshold, item_name`)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query low stock items")
+    }
+    defer rows.Close()
+
+    var items []models.MenuItem
+    for rows.Next() {
+        var item models.MenuItem
+        if err := rows.Scan(&item.ID, &item.ItemName, &item.Category, &item.Price, &item.Stock, &item.StockMode, &item.AvailableQuantity, &item.ReorderThreshold, &item.Description, &item.CreatedAt); err != nil {
+            return nil, errors.Wrap(err, "failed to scan item")
+        }
+        items = append(items, item)
+    }
+    return items, nil
+}
@@ -0,0 +1,99 @@
+package repositories
+
+import (
+    "database/sql"
+
+    "gastrobar-backend/internal/models"
+
+    "github.com/pkg/errors"
+)
+
+type MenuItemTagRepository interface {
+    FindByMenuItemID(menuItemID int) ([]models.MenuItemTag, error)
+    FindAll() (map[int][]models.MenuItemTag, error)
+    Replace(menuItemID int, tags []models.MenuItemTag) error
+}
+
+type menuItemTagRepository struct {
+    db *sql.DB
+}
+
+func NewMenuItemTagRepository(db *sql.DB) MenuItemTagRepository {
+    return &menuItemTagRepository{db: db}
+}
+
+func (r *menuItemTagRepository) FindByMenuItemID(menuItemID int) ([]models.MenuItemTag, error) {
+    rows, err := r.db.Query(`
+        SELECT tag
+        FROM menu_item_tags
+        WHERE menu_item_id = $1
+        ORDER BY tag`,
+        menuItemID,
+    )
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query tags by menu item ID")
+    }
+    defer rows.Close()
+
+    var tags []models.MenuItemTag
+    for rows.Next() {
+        var tag models.MenuItemTag
+        if err := rows.Scan(&tag); err != nil {
+            return nil, errors.Wrap(err, "failed to scan tag")
+        }
+        tags = append(tags, tag)
+    }
+    return tags, nil
+}
+
+// FindAll devuelve las etiquetas de todos los ítems agrupadas por menu_item_id
+func (r *menuItemTagRepository) FindAll() (map[int][]models.MenuItemTag, error) {
+    rows, err := r.db.Query(`
+        SELECT menu_item_id, tag
+        FROM menu_item_tags
+        ORDER BY menu_item_id, tag`)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query tags")
+    }
+    defer rows.Close()
+
+    tags := make(map[int][]models.MenuItemTag)
+    for rows.Next() {
+        var menuItemID int
+        var tag models.MenuItemTag
+        if err := rows.Scan(&menuItemID, &tag); err != nil {
+            return nil, errors.Wrap(err, "failed to scan tag")
+        }
+        tags[menuItemID] = append(tags[menuItemID], tag)
+    }
+    return tags, nil
+}
+
+// Replace reemplaza todas las etiquetas de un ítem dentro de una transacción
+func (r *menuItemTagRepository) Replace(menuItemID int, tags []models.MenuItemTag) error {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    if _, err := tx.Exec(`DELETE FROM menu_item_tags WHERE menu_item_id = $1`, menuItemID); err != nil {
+        return errors.Wrap(err, "failed to delete tags")
+    }
+
+    for _, tag := range tags {
+        if _, err := tx.Exec(`
+            INSERT INTO menu_item_tags (menu_item_id, tag)
+            VALUES ($1, $2)
+            ON CONFLICT DO NOTHING`,
+            menuItemID, tag,
+        ); err != nil {
+            return errors.Wrap(err, "failed to insert tag")
+        }
+    }
+
+    if err := tx.Commit(); err != nil {
+        return errors.Wrap(err, "failed to commit transaction")
+    }
+    return nil
+}
@@ -0,0 +1,124 @@
+package repositories
+
+import (
+    "database/sql"
+
+    "gastrobar-backend/internal/models"
+
+    "github.com/pkg/errors"
+)
+
+type MenuItemTranslationRepository interface {
+    FindByMenuItemID(menuItemID int) ([]models.MenuItemTranslation, error)
+    FindByLocale(locale string) ([]models.MenuItemTranslation, error)
+    FindLocales() ([]string, error)
+    Upsert(translation models.MenuItemTranslation) (models.MenuItemTranslation, error)
+    Delete(menuItemID int, locale string) error
+}
+
+type menuItemTranslationRepository struct {
+    db *sql.DB
+}
+
+func NewMenuItemTranslationRepository(db *sql.DB) MenuItemTranslationRepository {
+    return &menuItemTranslationRepository{db: db}
+}
+
+func (r *menuItemTranslationRepository) FindByMenuItemID(menuItemID int) ([]models.MenuItemTranslation, error) {
+    rows, err := r.db.Query(`
+        SELECT id, menu_item_id, locale, item_name, description, updated_at
+        FROM menu_item_translations
+        WHERE menu_item_id = $1
+        ORDER BY locale`,
+        menuItemID,
+    )
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query translations by menu item ID")
+    }
+    defer rows.Close()
+
+    return scanMenuItemTranslations(rows)
+}
+
+func (r *menuItemTranslationRepository) FindByLocale(locale string) ([]models.MenuItemTranslation, error) {
+    rows, err := r.db.Query(`
+        SELECT id, menu_item_id, locale, item_name, description, updated_at
+        FROM menu_item_translations
+        WHERE locale = $1`,
+        locale,
+    )
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query translations by locale")
+    }
+    defer rows.Close()
+
+    return scanMenuItemTranslations(rows)
+}
+
+func (r *menuItemTranslationRepository) FindLocales() ([]string, error) {
+    rows, err := r.db.Query(`
+        SELECT DISTINCT locale
+        FROM menu_item_translations
+        ORDER BY locale`)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query locales")
+    }
+    defer rows.Close()
+
+    var locales []string
+    for rows.Next() {
+        var locale string
+        if err := rows.Scan(&locale); err != nil {
+            return nil, errors.Wrap(err, "failed to scan locale")
+        }
+        locales = append(locales, locale)
+    }
+    return locales, nil
+}
+
+func (r *menuItemTranslationRepository) Upsert(translation models.MenuItemTranslation) (models.MenuItemTranslation, error) {
+    var saved models.MenuItemTranslation
+    err := r.db.QueryRow(`
+        INSERT INTO menu_item_translations (menu_item_id, locale, item_name, description, updated_at)
+        VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
+        ON CONFLICT (menu_item_id, locale)
+        DO UPDATE SET item_name = EXCLUDED.item_name, description = EXCLUDED.description, updated_at = CURRENT_TIMESTAMP
+        RETURNING id, menu_item_id, locale, item_name, description, updated_at`,
+        translation.MenuItemID, translation.Locale, translation.ItemName, translation.Description,
+    ).Scan(&saved.ID, &saved.MenuItemID, &saved.Locale, &saved.ItemName, &saved.Description, &saved.UpdatedAt)
+    if err != nil {
+        return models.MenuItemTranslation{}, errors.Wrap(err, "failed to save translation")
+    }
+    return saved, nil
+}
+
+func (r *menuItemTranslationRepository) Delete(menuItemID int, locale string) error {
+    result, err := r.db.Exec(`
+        DELETE FROM menu_item_translations
+        WHERE menu_item_id = $1 AND locale = $2`,
+        menuItemID, locale,
+    )
+    if err != nil {
+        return errors.Wrap(err, "failed to delete translation")
+    }
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return errors.Wrap(err, "failed to check rows affected")
+    }
+    if rowsAffected == 0 {
+        return errors.New("translation not found")
+    }
+    return nil
+}
+
+func scanMenuItemTranslations(rows *sql.Rows) ([]models.MenuItemTranslation, error) {
+    var translations []models.MenuItemTranslation
+    for rows.Next() {
+        var translation models.MenuItemTranslation
+        if err := rows.Scan(&translation.ID, &translation.MenuItemID, &translation.Locale, &translation.ItemName, &translation.Description, &translation.UpdatedAt); err != nil {
+            return nil, errors.Wrap(err, "failed to scan translation")
+        }
+        translations = append(translations, translation)
+    }
+    return translations, nil
+}
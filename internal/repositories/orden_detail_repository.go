@@ -12,7 +12,9 @@ type OrderDetailRepository interface {
     Create(orderDetail models.OrderDetail) (models.OrderDetail, error)
     FindByID(id int) (models.OrderDetail, error)
     FindByOrderID(orderID int) ([]models.OrderDetail, error)
+    FindByStatus(status models.OrderDetailStatus) ([]models.OrderDetail, error)
     Update(orderDetail models.OrderDetail) (models.OrderDetail, error)
+    UpdateStatus(id int, status models.OrderDetailStatus) (models.OrderDetail, error)
     Delete(id int) error
 }
 
@@ -29,29 +31,35 @@ func NewOrderDetailRepository(db *sql.DB) OrderDetailRepository {
 
 func (r *orderDetailRepository) Create(orderDetail models.OrderDetail) (models.OrderDetail, error) {
     var createdDetail models.OrderDetail
+    var createdBy sql.NullInt64
     err := r.db.QueryRow(
         `
-        INSERT INTO order_details (order_id, menu_item_id, quantity, created_at)
-        VALUES ($1, $2, $3, CURRENT_TIMESTAMP)
-        RETURNING id, order_id, menu_item_id, quantity, created_at`,
-        orderDetail.OrderID, orderDetail.MenuItemID, orderDetail.Quantity,
+        INSERT INTO order_details (order_id, menu_item_id, quantity, status, source, created_by, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP)
+        RETURNING id, order_id, menu_item_id, quantity, status, source, created_by, created_at`,
+        orderDetail.OrderID, orderDetail.MenuItemID, orderDetail.Quantity, orderDetail.Status, orderDetail.Source, orderDetail.CreatedBy,
     ).Scan(
         &createdDetail.ID,
         &createdDetail.OrderID,
         &createdDetail.MenuItemID,
         &createdDetail.Quantity,
+        &createdDetail.Status,
+        &createdDetail.Source,
+        &createdBy,
         &createdDetail.CreatedAt,
     )
     if err != nil {
         return models.OrderDetail{}, errors.Wrap(err, "failed to create order detail")
     }
+    createdDetail.CreatedBy = nullIntToPtr(createdBy)
     return createdDetail, nil
 }
 
 func (r *orderDetailRepository) FindByID(id int) (models.OrderDetail, error) {
     var orderDetail models.OrderDetail
+    var createdBy sql.NullInt64
     err := r.db.QueryRow(`
-        SELECT id, order_id, menu_item_id, quantity, created_at
+        SELECT id, order_id, menu_item_id, quantity, status, source, created_by, created_at
         FROM order_details
         WHERE id = $1`,
         id,
@@ -60,6 +68,9 @@ func (r *orderDetailRepository) FindByID(id int) (models.OrderDetail, error) {
         &orderDetail.OrderID,
         &orderDetail.MenuItemID,
         &orderDetail.Quantity,
+        &orderDetail.Status,
+        &orderDetail.Source,
+        &createdBy,
         &orderDetail.CreatedAt,
     )
     if err != nil {
@@ -68,6 +79,7 @@ func (r *orderDetailRepository) FindByID(id int) (models.OrderDetail, error) {
         }
         return models.OrderDetail{}, errors.Wrap(err, "failed to query order detail by ID")
     }
+    orderDetail.CreatedBy = nullIntToPtr(createdBy)
     return orderDetail, nil
 }
 
@@ -77,6 +89,9 @@ func (r *orderDetailRepository) FindByOrderID(orderID int) ([]models.OrderDetail
             od.id, 
             od.order_id, 
             od.quantity, 
+            od.status,
+            od.source,
+            od.created_by,
             od.created_at,
             mi.id AS menu_item_id, 
             mi.item_name, 
@@ -98,11 +113,15 @@ func (r *orderDetailRepository) FindByOrderID(orderID int) ([]models.OrderDetail
     var orderDetails []models.OrderDetail
     for rows.Next() {
         var od models.OrderDetail
+        var createdBy sql.NullInt64
         var menuItem models.MenuItem
         if err := rows.Scan(
             &od.ID,
             &od.OrderID,
             &od.Quantity,
+            &od.Status,
+            &od.Source,
+            &createdBy,
             &od.CreatedAt,
             &menuItem.ID,
             &menuItem.ItemName,
@@ -115,6 +134,7 @@ func (r *orderDetailRepository) FindByOrderID(orderID int) ([]models.OrderDetail
             return nil, errors.Wrap(err, "failed to scan order detail")
         }
         od.MenuItem = menuItem
+        od.CreatedBy = nullIntToPtr(createdBy)
         orderDetails = append(orderDetails, od)
     }
     return orderDetails, nil
@@ -122,18 +142,22 @@ func (r *orderDetailRepository) FindByOrderID(orderID int) ([]models.OrderDetail
 
 func (r *orderDetailRepository) Update(orderDetail models.OrderDetail) (models.OrderDetail, error) {
     var updatedDetail models.OrderDetail
+    var createdBy sql.NullInt64
     err := r.db.QueryRow(
         `
         UPDATE order_details
         SET order_id = $1, menu_item_id = $2, quantity = $3
         WHERE id = $4
-        RETURNING id, order_id, menu_item_id, quantity, created_at`,
+        RETURNING id, order_id, menu_item_id, quantity, status, source, created_by, created_at`,
         orderDetail.OrderID, orderDetail.MenuItemID, orderDetail.Quantity, orderDetail.ID,
     ).Scan(
         &updatedDetail.ID,
         &updatedDetail.OrderID,
         &updatedDetail.MenuItemID,
         &updatedDetail.Quantity,
+        &updatedDetail.Status,
+        &updatedDetail.Source,
+        &createdBy,
         &updatedDetail.CreatedAt,
     )
     if err != nil {
@@ -142,6 +166,95 @@ func (r *orderDetailRepository) Update(orderDetail models.OrderDetail) (models.O
         }
         return models.OrderDetail{}, errors.Wrap(err, "failed to update order detail")
     }
+    updatedDetail.CreatedBy = nullIntToPtr(createdBy)
+    return updatedDetail, nil
+}
+
+func (r *orderDetailRepository) FindByStatus(status models.OrderDetailStatus) ([]models.OrderDetail, error) {
+    rows, err := r.db.Query(`
+        SELECT
+            od.id,
+            od.order_id,
+            od.quantity,
+            od.status,
+            od.source,
+            od.created_by,
+            od.created_at,
+            mi.id AS menu_item_id,
+            mi.item_name,
+            mi.category,
+            mi.price,
+            mi.stock,
+            mi.description,
+            mi.created_at AS menu_item_created_at
+        FROM order_details od
+        JOIN menu_items mi ON od.menu_item_id = mi.id
+        WHERE od.status = $1
+        ORDER BY od.created_at`,
+        status,
+    )
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query order details by status")
+    }
+    defer rows.Close()
+
+    var orderDetails []models.OrderDetail
+    for rows.Next() {
+        var od models.OrderDetail
+        var createdBy sql.NullInt64
+        var menuItem models.MenuItem
+        if err := rows.Scan(
+            &od.ID,
+            &od.OrderID,
+            &od.Quantity,
+            &od.Status,
+            &od.Source,
+            &createdBy,
+            &od.CreatedAt,
+            &menuItem.ID,
+            &menuItem.ItemName,
+            &menuItem.Category,
+            &menuItem.Price,
+            &menuItem.Stock,
+            &menuItem.Description,
+            &menuItem.CreatedAt,
+        ); err != nil {
+            return nil, errors.Wrap(err, "failed to scan order detail")
+        }
+        od.MenuItemID = menuItem.ID
+        od.MenuItem = menuItem
+        od.CreatedBy = nullIntToPtr(createdBy)
+        orderDetails = append(orderDetails, od)
+    }
+    return orderDetails, nil
+}
+
+func (r *orderDetailRepository) UpdateStatus(id int, status models.OrderDetailStatus) (models.OrderDetail, error) {
+    var updatedDetail models.OrderDetail
+    var createdBy sql.NullInt64
+    err := r.db.QueryRow(`
+        UPDATE order_details
+        SET status = $1
+        WHERE id = $2
+        RETURNING id, order_id, menu_item_id, quantity, status, source, created_by, created_at`,
+        status, id,
+    ).Scan(
+        &updatedDetail.ID,
+        &updatedDetail.OrderID,
+        &updatedDetail.MenuItemID,
+        &updatedDetail.Quantity,
+        &updatedDetail.Status,
+        &updatedDetail.Source,
+        &createdBy,
+        &updatedDetail.CreatedAt,
+    )
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return models.OrderDetail{}, errors.Wrap(err, "order detail not found")
+        }
+        return models.OrderDetail{}, errors.Wrap(err, "failed to update order detail status")
+    }
+    updatedDetail.CreatedBy = nullIntToPtr(createdBy)
     return updatedDetail, nil
 }
 
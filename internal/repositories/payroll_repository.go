@@ -0,0 +1,286 @@
+package repositories
+
+import (
+    "database/sql"
+    "time"
+
+    "gastrobar-backend/internal/models"
+
+    "github.com/pkg/errors"
+    "github.com/shopspring/decimal"
+)
+
+type PayrollRepository interface {
+    FindRates() ([]models.PayRate, error)
+    FindRate(employeeID int) (models.PayRate, error)
+    SetRate(employeeID int, hourlyRate decimal.Decimal, updatedBy int) (models.PayRate, error)
+    FindSettings() (models.PayrollSettings, error)
+    UpdateSettings(settings models.PayrollSettings) (models.PayrollSettings, error)
+    FindHolidays(from, to *time.Time) ([]models.Holiday, error)
+    CreateHoliday(holiday models.Holiday) (models.Holiday, error)
+    DeleteHoliday(date string) error
+    FindTipEntries(from, to *time.Time) ([]models.TipPoolEntry, error)
+    CreateTipEntry(entry models.TipPoolEntry) (models.TipPoolEntry, error)
+    DeleteTipEntry(entryID int) error
+}
+
+type payrollRepository struct {
+    db *sql.DB
+}
+
+func NewPayrollRepository(db *sql.DB) PayrollRepository {
+    return &payrollRepository{db: db}
+}
+
+// FindRates devuelve a todos los empleados con su tarifa; los que no tienen tarifa la traen en NULL
+func (r *payrollRepository) FindRates() ([]models.PayRate, error) {
+    rows, err := r.db.Query(`
+        SELECT e.id, e.employee_name, pr.hourly_rate, pr.updated_by, pr.updated_at
+        FROM employees e
+        LEFT JOIN employee_pay_rates pr ON pr.employee_id = e.id
+        ORDER BY e.employee_name, e.id`)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query pay rates")
+    }
+    defer rows.Close()
+
+    rates := []models.PayRate{}
+    for rows.Next() {
+        rate, err := scanPayRate(rows)
+        if err != nil {
+            return nil, err
+        }
+        rates = append(rates, rate)
+    }
+    return rates, nil
+}
+
+func (r *payrollRepository) FindRate(employeeID int) (models.PayRate, error) {
+    rows, err := r.db.Query(`
+        SELECT e.id, e.employee_name, pr.hourly_rate, pr.updated_by, pr.updated_at
+        FROM employees e
+        LEFT JOIN employee_pay_rates pr ON pr.employee_id = e.id
+        WHERE e.id = $1`,
+        employeeID,
+    )
+    if err != nil {
+        return models.PayRate{}, errors.Wrap(err, "failed to query pay rate")
+    }
+    defer rows.Close()
+
+    if !rows.Next() {
+        return models.PayRate{}, errors.New("employee not found")
+    }
+    return scanPayRate(rows)
+}
+
+// SetRate crea o reemplaza la tarifa por hora de un empleado
+func (r *payrollRepository) SetRate(employeeID int, hourlyRate decimal.Decimal, updatedBy int) (models.PayRate, error) {
+    var savedEmployeeID int
+    err := r.db.QueryRow(`
+        INSERT INTO employee_pay_rates (employee_id, hourly_rate, updated_by, updated_at)
+        SELECT id, $2, $3, CURRENT_TIMESTAMP
+        FROM employees
+        WHERE id = $1
+        ON CONFLICT (employee_id) DO UPDATE
+        SET hourly_rate = EXCLUDED.hourly_rate, updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at
+        RETURNING employee_id`,
+        employeeID, hourlyRate, updatedBy,
+    ).Scan(&savedEmployeeID)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return models.PayRate{}, errors.Wrap(err, "employee not found")
+        }
+        return models.PayRate{}, errors.Wrap(err, "failed to save pay rate")
+    }
+    return r.FindRate(savedEmployeeID)
+}
+
+func (r *payrollRepository) FindSettings() (models.PayrollSettings, error) {
+    var settings models.PayrollSettings
+    var updatedBy sql.NullInt64
+    var updatedAt sql.NullTime
+    err := r.db.QueryRow(`
+        SELECT daily_overtime_hours, overtime_surcharge, to_char(night_start, 'HH24:MI'), to_char(night_end, 'HH24:MI'),
+               night_surcharge, holiday_surcharge, sunday_is_holiday, updated_by, updated_at
+        FROM payroll_settings
+        WHERE id = 1`,
+    ).Scan(&settings.DailyOvertimeHours, &settings.OvertimeSurcharge, &settings.NightStart, &settings.NightEnd,
+        &settings.NightSurcharge, &settings.HolidaySurcharge, &settings.SundayIsHoliday, &updatedBy, &updatedAt)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return models.PayrollSettings{}, errors.Wrap(err, "payroll settings not found")
+        }
+        return models.PayrollSettings{}, errors.Wrap(err, "failed to query payroll settings")
+    }
+    settings.UpdatedBy = nullIntToPtr(updatedBy)
+    settings.UpdatedAt = nullTimeToPtr(updatedAt)
+    return settings, nil
+}
+
+// UpdateSettings reemplaza los parámetros de la nómina; crea la fila si todavía no existe
+func (r *payrollRepository) UpdateSettings(settings models.PayrollSettings) (models.PayrollSettings, error) {
+    _, err := r.db.Exec(`
+        INSERT INTO payroll_settings (id, daily_overtime_hours, overtime_surcharge, night_start, night_end,
+                                      night_surcharge, holiday_surcharge, sunday_is_holiday, updated_by, updated_at)
+        VALUES (1, $1, $2, $3, $4, $5, $6, $7, $8, CURRENT_TIMESTAMP)
+        ON CONFLICT (id) DO UPDATE
+        SET daily_overtime_hours = EXCLUDED.daily_overtime_hours,
+            overtime_surcharge = EXCLUDED.overtime_surcharge,
+            night_start = EXCLUDED.night_start,
+            night_end = EXCLUDED.night_end,
+            night_surcharge = EXCLUDED.night_surcharge,
+            holiday_surcharge = EXCLUDED.holiday_surcharge,
+            sunday_is_holiday = EXCLUDED.sunday_is_holiday,
+            updated_by = EXCLUDED.updated_by,
+            updated_at = EXCLUDED.updated_at`,
+        settings.DailyOvertimeHours, settings.OvertimeSurcharge, settings.NightStart, settings.NightEnd,
+        settings.NightSurcharge, settings.HolidaySurcharge, settings.SundayIsHoliday, settings.UpdatedBy,
+    )
+    if err != nil {
+        return models.PayrollSettings{}, errors.Wrap(err, "failed to update payroll settings")
+    }
+    return r.FindSettings()
+}
+
+// FindHolidays devuelve los festivos entre las fechas de from y to (sin incluir la de to)
+func (r *payrollRepository) FindHolidays(from, to *time.Time) ([]models.Holiday, error) {
+    rows, err := r.db.Query(`
+        SELECT to_char(holiday_date, 'YYYY-MM-DD'), description
+        FROM holidays
+        WHERE ($1::TIMESTAMPTZ IS NULL OR holiday_date >= $1::DATE)
+          AND ($2::TIMESTAMPTZ IS NULL OR holiday_date < $2::DATE)
+        ORDER BY holiday_date`,
+        from, to,
+    )
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query holidays")
+    }
+    defer rows.Close()
+
+    holidays := []models.Holiday{}
+    for rows.Next() {
+        var holiday models.Holiday
+        if err := rows.Scan(&holiday.Date, &holiday.Description); err != nil {
+            return nil, errors.Wrap(err, "failed to scan holiday")
+        }
+        holidays = append(holidays, holiday)
+    }
+    return holidays, nil
+}
+
+func (r *payrollRepository) CreateHoliday(holiday models.Holiday) (models.Holiday, error) {
+    var createdHoliday models.Holiday
+    err := r.db.QueryRow(`
+        INSERT INTO holidays (holiday_date, description, created_at)
+        VALUES ($1, $2, CURRENT_TIMESTAMP)
+        ON CONFLICT (holiday_date) DO NOTHING
+        RETURNING to_char(holiday_date, 'YYYY-MM-DD'), description`,
+        holiday.Date, holiday.Description,
+    ).Scan(&createdHoliday.Date, &createdHoliday.Description)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return models.Holiday{}, errors.New("holiday already exists")
+        }
+        return models.Holiday{}, errors.Wrap(err, "failed to create holiday")
+    }
+    return createdHoliday, nil
+}
+
+func (r *payrollRepository) DeleteHoliday(date string) error {
+    result, err := r.db.Exec(`
+        DELETE FROM holidays
+        WHERE holiday_date = $1`,
+        date,
+    )
+    if err != nil {
+        return errors.Wrap(err, "failed to delete holiday")
+    }
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return errors.Wrap(err, "failed to check rows affected")
+    }
+    if rowsAffected == 0 {
+        return errors.New("holiday not found")
+    }
+    return nil
+}
+
+// FindTipEntries devuelve las propinas recaudadas entre las fechas de from y to (sin incluir la de to)
+func (r *payrollRepository) FindTipEntries(from, to *time.Time) ([]models.TipPoolEntry, error) {
+    rows, err := r.db.Query(`
+        SELECT id, to_char(collected_on, 'YYYY-MM-DD'), amount, notes, created_by, created_at
+        FROM tip_pool_entries
+        WHERE ($1::TIMESTAMPTZ IS NULL OR collected_on >= $1::DATE)
+          AND ($2::TIMESTAMPTZ IS NULL OR collected_on < $2::DATE)
+        ORDER BY collected_on, id`,
+        from, to,
+    )
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query tip pool entries")
+    }
+    defer rows.Close()
+
+    entries := []models.TipPoolEntry{}
+    for rows.Next() {
+        var entry models.TipPoolEntry
+        var notes sql.NullString
+        if err := rows.Scan(&entry.ID, &entry.CollectedOn, &entry.Amount, &notes, &entry.CreatedBy, &entry.CreatedAt); err != nil {
+            return nil, errors.Wrap(err, "failed to scan tip pool entry")
+        }
+        entry.Notes = notes.String
+        entries = append(entries, entry)
+    }
+    return entries, nil
+}
+
+func (r *payrollRepository) CreateTipEntry(entry models.TipPoolEntry) (models.TipPoolEntry, error) {
+    var createdEntry models.TipPoolEntry
+    var notes sql.NullString
+    err := r.db.QueryRow(`
+        INSERT INTO tip_pool_entries (collected_on, amount, notes, created_by, created_at)
+        VALUES ($1, $2, NULLIF($3, ''), $4, CURRENT_TIMESTAMP)
+        RETURNING id, to_char(collected_on, 'YYYY-MM-DD'), amount, notes, created_by, created_at`,
+        entry.CollectedOn, entry.Amount, entry.Notes, entry.CreatedBy,
+    ).Scan(&createdEntry.ID, &createdEntry.CollectedOn, &createdEntry.Amount, &notes, &createdEntry.CreatedBy, &createdEntry.CreatedAt)
+    if err != nil {
+        return models.TipPoolEntry{}, errors.Wrap(err, "failed to create tip pool entry")
+    }
+    createdEntry.Notes = notes.String
+    return createdEntry, nil
+}
+
+func (r *payrollRepository) DeleteTipEntry(entryID int) error {
+    result, err := r.db.Exec(`
+        DELETE FROM tip_pool_entries
+        WHERE id = $1`,
+        entryID,
+    )
+    if err != nil {
+        return errors.Wrap(err, "failed to delete tip pool entry")
+    }
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return errors.Wrap(err, "failed to check rows affected")
+    }
+    if rowsAffected == 0 {
+        return errors.New("tip pool entry not found")
+    }
+    return nil
+}
+
+func scanPayRate(rows *sql.Rows) (models.PayRate, error) {
+    var rate models.PayRate
+    var hourlyRate decimal.NullDecimal
+    var updatedBy sql.NullInt64
+    var updatedAt sql.NullTime
+    if err := rows.Scan(&rate.EmployeeID, &rate.EmployeeName, &hourlyRate, &updatedBy, &updatedAt); err != nil {
+        return models.PayRate{}, errors.Wrap(err, "failed to scan pay rate")
+    }
+    if hourlyRate.Valid {
+        rate.HourlyRate = &hourlyRate.Decimal
+    }
+    rate.UpdatedBy = nullIntToPtr(updatedBy)
+    rate.UpdatedAt = nullTimeToPtr(updatedAt)
+    return rate, nil
+}
@@ -0,0 +1,191 @@
+package repositories
+
+import (
+    "database/sql"
+
+    "gastrobar-backend/internal/models"
+
+    "github.com/lib/pq"
+    "github.com/pkg/errors"
+)
+
+type PermissionRepository interface {
+    FindAll() ([]models.PermissionInfo, error)
+    FindRolePermissions() ([]models.RolePermissions, error)
+    FindByRole(role models.EmployeeRole) (models.RolePermissions, error)
+    SetRolePermissions(role models.EmployeeRole, permissions []models.Permission, grantedBy int) (models.RolePermissions, error)
+    SeedRolePermissions(defaults map[models.EmployeeRole][]models.Permission) (bool, error)
+}
+
+type permissionRepository struct {
+    db *sql.DB
+}
+
+func NewPermissionRepository(db *sql.DB) PermissionRepository {
+    return &permissionRepository{db: db}
+}
+
+func (r *permissionRepository) FindAll() ([]models.PermissionInfo, error) {
+    rows, err := r.db.Query(`
+        SELECT code, description
+        FROM permissions
+        ORDER BY code`)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query permissions")
+    }
+    defer rows.Close()
+
+    permissions := []models.PermissionInfo{}
+    for rows.Next() {
+        var permission models.PermissionInfo
+        if err := rows.Scan(&permission.Code, &permission.Description); err != nil {
+            return nil, errors.Wrap(err, "failed to scan permission")
+        }
+        permissions = append(permissions, permission)
+    }
+    return permissions, nil
+}
+
+// FindRolePermissions devuelve los permisos de cada rol del ENUM employee_role, incluidos los roles
+// sin ningún permiso
+func (r *permissionRepository) FindRolePermissions() ([]models.RolePermissions, error) {
+    rows, err := r.db.Query(`
+        SELECT roles.role,
+               COALESCE(array_agg(rp.permission_code ORDER BY rp.permission_code) FILTER (WHERE rp.permission_code IS NOT NULL), '{}')
+        FROM unnest(enum_range(NULL::employee_role)) AS roles(role)
+        LEFT JOIN role_permissions rp ON rp.role = roles.role
+        GROUP BY roles.role
+        ORDER BY roles.role`)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query role permissions")
+    }
+    defer rows.Close()
+
+    rolePermissions := []models.RolePermissions{}
+    for rows.Next() {
+        var role models.EmployeeRole
+        var codes []string
+        if err := rows.Scan(&role, pq.Array(&codes)); err != nil {
+            return nil, errors.Wrap(err, "failed to scan role permissions")
+        }
+        rolePermissions = append(rolePermissions, newRolePermissions(role, codes))
+    }
+    return rolePermissions, nil
+}
+
+func (r *permissionRepository) FindByRole(role models.EmployeeRole) (models.RolePermissions, error) {
+    return findRolePermissions(r.db, role)
+}
+
+// SetRolePermissions reemplaza todos los permisos de un rol en una misma transacción
+func (r *permissionRepository) SetRolePermissions(role models.EmployeeRole, permissions []models.Permission, grantedBy int) (models.RolePermissions, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.RolePermissions{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    if _, err := tx.Exec(`
+        DELETE FROM role_permissions
+        WHERE role = $1`,
+        role,
+    ); err != nil {
+        return models.RolePermissions{}, errors.Wrap(err, "failed to clear role permissions")
+    }
+
+    codes := make([]string, len(permissions))
+    for i, permission := range permissions {
+        codes[i] = string(permission)
+    }
+    if _, err := tx.Exec(`
+        INSERT INTO role_permissions (role, permission_code, granted_by, granted_at)
+        SELECT $1, code, $3, CURRENT_TIMESTAMP
+        FROM unnest($2::TEXT[]) AS code`,
+        role, pq.Array(codes), grantedBy,
+    ); err != nil {
+        return models.RolePermissions{}, errors.Wrap(err, "failed to grant role permissions")
+    }
+
+    rolePermissions, err := findRolePermissions(tx, role)
+    if err != nil {
+        return models.RolePermissions{}, err
+    }
+    if err := tx.Commit(); err != nil {
+        return models.RolePermissions{}, errors.Wrap(err, "failed to commit transaction")
+    }
+    return rolePermissions, nil
+}
+
+// SeedRolePermissions concede los permisos por defecto de cada rol solo si role_permissions está
+// vacía, para no deshacer los cambios del dueño. Devuelve si se sembraron
+func (r *permissionRepository) SeedRolePermissions(defaults map[models.EmployeeRole][]models.Permission) (bool, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return false, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    // Bloquear la tabla para que dos instancias que arrancan a la vez no siembren ambas
+    if _, err := tx.Exec(`LOCK TABLE role_permissions IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+        return false, errors.Wrap(err, "failed to lock role permissions")
+    }
+    var seeded bool
+    if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM role_permissions)`).Scan(&seeded); err != nil {
+        return false, errors.Wrap(err, "failed to check role permissions")
+    }
+    if seeded {
+        return false, nil
+    }
+
+    for role, permissions := range defaults {
+        codes := make([]string, len(permissions))
+        for i, permission := range permissions {
+            codes[i] = string(permission)
+        }
+        if _, err := tx.Exec(`
+            INSERT INTO role_permissions (role, permission_code, granted_at)
+            SELECT $1, code, CURRENT_TIMESTAMP
+            FROM unnest($2::TEXT[]) AS code`,
+            role, pq.Array(codes),
+        ); err != nil {
+            return false, errors.Wrapf(err, "failed to seed permissions of role %s", role)
+        }
+    }
+
+    if err := tx.Commit(); err != nil {
+        return false, errors.Wrap(err, "failed to commit transaction")
+    }
+    return true, nil
+}
+
+func findRolePermissions(q queryer, role models.EmployeeRole) (models.RolePermissions, error) {
+    rows, err := q.Query(`
+        SELECT permission_code
+        FROM role_permissions
+        WHERE role = $1
+        ORDER BY permission_code`,
+        role,
+    )
+    if err != nil {
+        return models.RolePermissions{}, errors.Wrap(err, "failed to query role permissions")
+    }
+    defer rows.Close()
+
+    var codes []string
+    for rows.Next() {
+        var code string
+        if err := rows.Scan(&code); err != nil {
+            return models.RolePermissions{}, errors.Wrap(err, "failed to scan role permission")
+        }
+        codes = append(codes, code)
+    }
+    return newRolePermissions(role, codes), nil
+}
+
+func newRolePermissions(role models.EmployeeRole, codes []string) models.RolePermissions {
+    rolePermissions := models.RolePermissions{Role: role, Permissions: []models.Permission{}}
+    for _, code := range codes {
+        rolePermissions.Permissions = append(rolePermissions.Permissions, models.Permission(code))
+    }
+    return rolePermissions
+}
@@ -0,0 +1,369 @@
+package repositories
+
+import (
+    "database/sql"
+    "fmt"
+
+    "gastrobar-backend/internal/models"
+
+    "github.com/pkg/errors"
+    "github.com/shopspring/decimal"
+)
+
+type PurchaseOrderRepository interface {
+    FindByID(purchaseOrderID int) (models.PurchaseOrder, error)
+    FindAll(status string) ([]models.PurchaseOrder, error)
+    Create(order models.PurchaseOrder) (models.PurchaseOrder, error)
+    UpdateDraft(order models.PurchaseOrder) (models.PurchaseOrder, error)
+    MarkSent(purchaseOrderID int) (models.PurchaseOrder, error)
+    Delete(purchaseOrderID int) error
+    Receive(purchaseOrderID int, receipt models.PurchaseOrderReceipt, employeeID int) (models.PurchaseOrder, error)
+}
+
+type purchaseOrderRepository struct {
+    db *sql.DB
+}
+
+func NewPurchaseOrderRepository(db *sql.DB) PurchaseOrderRepository {
+    return &purchaseOrderRepository{db: db}
+}
+
+func (r *purchaseOrderRepository) FindByID(purchaseOrderID int) (models.PurchaseOrder, error) {
+    var order models.PurchaseOrder
+    var notes sql.NullString
+    var sentAt, receivedAt sql.NullTime
+    err := r.db.QueryRow(`
+        SELECT id, supplier_id, status, notes, created_by, sent_at, received_at, created_at
+        FROM purchase_orders
+        WHERE id = $1`,
+        purchaseOrderID,
+    ).Scan(&order.ID, &order.SupplierID, &order.Status, &notes, &order.CreatedBy, &sentAt, &receivedAt, &order.CreatedAt)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return models.PurchaseOrder{}, errors.Wrap(err, "purchase order not found")
+        }
+        return models.PurchaseOrder{}, errors.Wrap(err, "failed to query purchase order by ID")
+    }
+    order.Notes = notes.String
+    order.SentAt = nullTimeToPtr(sentAt)
+    order.ReceivedAt = nullTimeToPtr(receivedAt)
+
+    order.Lines, err = r.findLines(purchaseOrderID)
+    if err != nil {
+        return models.PurchaseOrder{}, err
+    }
+    order.ExpectedTotal = decimal.Zero
+    for _, line := range order.Lines {
+        order.ExpectedTotal = order.ExpectedTotal.Add(line.Quantity.Mul(line.ExpectedUnitCost))
+    }
+    return order, nil
+}
+
+func (r *purchaseOrderRepository) findLines(purchaseOrderID int) ([]models.PurchaseOrderLine, error) {
+    rows, err := r.db.Query(`
+        SELECT id, purchase_order_id, menu_item_id, ingredient_id, quantity, expected_unit_cost, received_quantity, actual_unit_cost
+        FROM purchase_order_lines
+        WHERE purchase_order_id = $1
+        ORDER BY id`,
+        purchaseOrderID,
+    )
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query purchase order lines")
+    }
+    defer rows.Close()
+
+    lines := []models.PurchaseOrderLine{}
+    for rows.Next() {
+        var line models.PurchaseOrderLine
+        var menuItemID, ingredientID sql.NullInt64
+        var actualUnitCost decimal.NullDecimal
+        if err := rows.Scan(&line.ID, &line.PurchaseOrderID, &menuItemID, &ingredientID, &line.Quantity, &line.ExpectedUnitCost, &line.ReceivedQuantity, &actualUnitCost); err != nil {
+            return nil, errors.Wrap(err, "failed to scan purchase order line")
+        }
+        line.MenuItemID = nullIntToPtr(menuItemID)
+        line.IngredientID = nullIntToPtr(ingredientID)
+        line.ActualUnitCost = nullDecimalToPtr(actualUnitCost)
+        lines = append(lines, line)
+    }
+    return lines, nil
+}
+
+// FindAll devuelve las órdenes de compra (sin líneas), opcionalmente filtradas por estado
+func (r *purchaseOrderRepository) FindAll(status string) ([]models.PurchaseOrder, error) {
+    rows, err := r.db.Query(`
+        SELECT po.id, po.supplier_id, po.status, po.notes, po.created_by, po.sent_at, po.received_at, po.created_at,
+               COALESCE(SUM(pol.quantity * pol.expected_unit_cost), 0)
+        FROM purchase_orders po
+        LEFT JOIN purchase_order_lines pol ON pol.purchase_order_id = po.id
+        WHERE $1 = '' OR po.status = $1
+        GROUP BY po.id
+        ORDER BY po.created_at DESC`,
+        status,
+    )
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query purchase orders")
+    }
+    defer rows.Close()
+
+    var orders []models.PurchaseOrder
+    for rows.Next() {
+        var order models.PurchaseOrder
+        var notes sql.NullString
+        var sentAt, receivedAt sql.NullTime
+        if err := rows.Scan(&order.ID, &order.SupplierID, &order.Status, &notes, &order.CreatedBy, &sentAt, &receivedAt, &order.CreatedAt, &order.ExpectedTotal); err != nil {
+            return nil, errors.Wrap(err, "failed to scan purchase order")
+        }
+        order.Notes = notes.String
+        order.SentAt = nullTimeToPtr(sentAt)
+        order.ReceivedAt = nullTimeToPtr(receivedAt)
+        orders = append(orders, order)
+    }
+    return orders, nil
+}
+
+// Create crea la orden de compra en borrador junto con sus líneas en una sola transacción
+func (r *purchaseOrderRepository) Create(order models.PurchaseOrder) (models.PurchaseOrder, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.PurchaseOrder{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    var purchaseOrderID int
+    err = tx.QueryRow(`
+        INSERT INTO purchase_orders (supplier_id, status, notes, created_by, created_at)
+        VALUES ($1, 'draft', NULLIF($2, ''), $3, CURRENT_TIMESTAMP)
+        RETURNING id`,
+        order.SupplierID, order.Notes, order.CreatedBy,
+    ).Scan(&purchaseOrderID)
+    if err != nil {
+        return models.PurchaseOrder{}, errors.Wrap(err, "failed to create purchase order")
+    }
+
+    if err := insertPurchaseOrderLines(tx, purchaseOrderID, order.Lines); err != nil {
+        return models.PurchaseOrder{}, err
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.PurchaseOrder{}, errors.Wrap(err, "failed to commit transaction")
+    }
+    return r.FindByID(purchaseOrderID)
+}
+
+// UpdateDraft reemplaza el proveedor, las notas y las líneas de una orden que sigue en borrador
+func (r *purchaseOrderRepository) UpdateDraft(order models.PurchaseOrder) (models.PurchaseOrder, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.PurchaseOrder{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    if err := lockPurchaseOrder(tx, order.ID, models.PurchaseOrderStatusDraft); err != nil {
+        return models.PurchaseOrder{}, err
+    }
+
+    if _, err := tx.Exec(`
+        UPDATE purchase_orders
+        SET supplier_id = $1, notes = NULLIF($2, '')
+        WHERE id = $3`,
+        order.SupplierID, order.Notes, order.ID,
+    ); err != nil {
+        return models.PurchaseOrder{}, errors.Wrap(err, "failed to update purchase order")
+    }
+
+    if _, err := tx.Exec(`DELETE FROM purchase_order_lines WHERE purchase_order_id = $1`, order.ID); err != nil {
+        return models.PurchaseOrder{}, errors.Wrap(err, "failed to delete purchase order lines")
+    }
+    if err := insertPurchaseOrderLines(tx, order.ID, order.Lines); err != nil {
+        return models.PurchaseOrder{}, err
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.PurchaseOrder{}, errors.Wrap(err, "failed to commit transaction")
+    }
+    return r.FindByID(order.ID)
+}
+
+// MarkSent pasa una orden en borrador a enviada
+func (r *purchaseOrderRepository) MarkSent(purchaseOrderID int) (models.PurchaseOrder, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.PurchaseOrder{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    if err := lockPurchaseOrder(tx, purchaseOrderID, models.PurchaseOrderStatusDraft); err != nil {
+        return models.PurchaseOrder{}, err
+    }
+
+    if _, err := tx.Exec(`
+        UPDATE purchase_orders
+        SET status = 'sent', sent_at = CURRENT_TIMESTAMP
+        WHERE id = $1`,
+        purchaseOrderID,
+    ); err != nil {
+        return models.PurchaseOrder{}, errors.Wrap(err, "failed to send purchase order")
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.PurchaseOrder{}, errors.Wrap(err, "failed to commit transaction")
+    }
+    return r.FindByID(purchaseOrderID)
+}
+
+// Delete elimina una orden de compra; solo se permite mientras está en borrador
+func (r *purchaseOrderRepository) Delete(purchaseOrderID int) error {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    if err := lockPurchaseOrder(tx, purchaseOrderID, models.PurchaseOrderStatusDraft); err != nil {
+        return err
+    }
+
+    if _, err := tx.Exec(`DELETE FROM purchase_orders WHERE id = $1`, purchaseOrderID); err != nil {
+        return errors.Wrap(err, "failed to delete purchase order")
+    }
+
+    if err := tx.Commit(); err != nil {
+        return errors.Wrap(err, "failed to commit transaction")
+    }
+    return nil
+}
+
+// Receive registra la mercancía recibida: cada línea recibida genera un movimiento de compra con
+// su costo unitario real (el trigger del libro de inventario incrementa el stock) y la orden pasa a
+// recibida o parcialmente recibida. Todo ocurre en una sola transacción
+func (r *purchaseOrderRepository) Receive(purchaseOrderID int, receipt models.PurchaseOrderReceipt, employeeID int) (models.PurchaseOrder, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.PurchaseOrder{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    if err := lockPurchaseOrder(tx, purchaseOrderID, models.PurchaseOrderStatusSent, models.PurchaseOrderStatusPartiallyReceived); err != nil {
+        return models.PurchaseOrder{}, err
+    }
+
+    for _, received := range receipt.Lines {
+        var line models.PurchaseOrderLine
+        var menuItemID, ingredientID sql.NullInt64
+        err := tx.QueryRow(`
+            SELECT id, menu_item_id, ingredient_id, quantity, received_quantity
+            FROM purchase_order_lines
+            WHERE id = $1 AND purchase_order_id = $2
+            FOR UPDATE`,
+            received.LineID, purchaseOrderID,
+        ).Scan(&line.ID, &menuItemID, &ingredientID, &line.Quantity, &line.ReceivedQuantity)
+        if err != nil {
+            if err == sql.ErrNoRows {
+                return models.PurchaseOrder{}, errors.Wrapf(err, "purchase order line not found: %d", received.LineID)
+            }
+            return models.PurchaseOrder{}, errors.Wrap(err, "failed to lock purchase order line")
+        }
+        line.MenuItemID = nullIntToPtr(menuItemID)
+        line.IngredientID = nullIntToPtr(ingredientID)
+
+        // No se puede recibir más de lo pedido
+        if line.ReceivedQuantity.Add(received.Quantity).GreaterThan(line.Quantity) {
+            return models.PurchaseOrder{}, errors.Errorf("received quantity exceeds ordered quantity for line %d", line.ID)
+        }
+
+        if _, err := tx.Exec(`
+            UPDATE purchase_order_lines
+            SET received_quantity = received_quantity + $1, actual_unit_cost = $2
+            WHERE id = $3`,
+            received.Quantity.String(), received.UnitCost.String(), line.ID,
+        ); err != nil {
+            return models.PurchaseOrder{}, errors.Wrap(err, "failed to update purchase order line")
+        }
+
+        unitCost := received.UnitCost
+        if _, err := insertStockMovement(tx, models.StockMovement{
+            MenuItemID:          line.MenuItemID,
+            IngredientID:        line.IngredientID,
+            MovementType:        models.StockMovementTypePurchase,
+            Quantity:            received.Quantity,
+            EmployeeID:          &employeeID,
+            Reason:              fmt.Sprintf("Recepción de la orden de compra #%d", purchaseOrderID),
+            PurchaseOrderLineID: &line.ID,
+            UnitCost:            &unitCost,
+        }); err != nil {
+            return models.PurchaseOrder{}, err
+        }
+    }
+
+    // La orden queda recibida cuando todas sus líneas se recibieron por completo
+    var pendingLines int
+    if err := tx.QueryRow(`
+        SELECT COUNT(*)
+        FROM purchase_order_lines
+        WHERE purchase_order_id = $1 AND received_quantity < quantity`,
+        purchaseOrderID,
+    ).Scan(&pendingLines); err != nil {
+        return models.PurchaseOrder{}, errors.Wrap(err, "failed to check pending purchase order lines")
+    }
+
+    if pendingLines == 0 {
+        _, err = tx.Exec(`
+            UPDATE purchase_orders
+            SET status = 'received', received_at = CURRENT_TIMESTAMP
+            WHERE id = $1`,
+            purchaseOrderID,
+        )
+    } else {
+        _, err = tx.Exec(`
+            UPDATE purchase_orders
+            SET status = 'partially_received'
+            WHERE id = $1`,
+            purchaseOrderID,
+        )
+    }
+    if err != nil {
+        return models.PurchaseOrder{}, errors.Wrap(err, "failed to update purchase order status")
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.PurchaseOrder{}, errors.Wrap(err, "failed to commit transaction")
+    }
+    return r.FindByID(purchaseOrderID)
+}
+
+// lockPurchaseOrder bloquea la orden y verifica que esté en alguno de los estados permitidos
+func lockPurchaseOrder(tx *sql.Tx, purchaseOrderID int, allowed ...models.PurchaseOrderStatus) error {
+    var status models.PurchaseOrderStatus
+    err := tx.QueryRow(`
+        SELECT status
+        FROM purchase_orders
+        WHERE id = $1
+        FOR UPDATE`,
+        purchaseOrderID,
+    ).Scan(&status)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return errors.Wrap(err, "purchase order not found")
+        }
+        return errors.Wrap(err, "failed to lock purchase order")
+    }
+    for _, s := range allowed {
+        if status == s {
+            return nil
+        }
+    }
+    return errors.Errorf("purchase order status does not allow this operation: %s", status)
+}
+
+func insertPurchaseOrderLines(tx *sql.Tx, purchaseOrderID int, lines []models.PurchaseOrderLine) error {
+    for _, line := range lines {
+        if _, err := tx.Exec(`
+            INSERT INTO purchase_order_lines (purchase_order_id, menu_item_id, ingredient_id, quantity, expected_unit_cost)
+            VALUES ($1, $2, $3, $4, $5)`,
+            purchaseOrderID, line.MenuItemID, line.IngredientID, line.Quantity.String(), line.ExpectedUnitCost.String(),
+        ); err != nil {
+            return errors.Wrap(err, "failed to create purchase order line")
+        }
+    }
+    return nil
+}
@@ -0,0 +1,75 @@
+package repositories
+
+import (
+    "database/sql"
+
+    "gastrobar-backend/internal/models"
+
+    "github.com/pkg/errors"
+)
+
+type RecipeRepository interface {
+    FindByMenuItemID(menuItemID int) ([]models.RecipeItem, error)
+    Replace(menuItemID int, items []models.RecipeItem) error
+}
+
+type recipeRepository struct {
+    db *sql.DB
+}
+
+func NewRecipeRepository(db *sql.DB) RecipeRepository {
+    return &recipeRepository{db: db}
+}
+
+func (r *recipeRepository) FindByMenuItemID(menuItemID int) ([]models.RecipeItem, error) {
+    rows, err := r.db.Query(`
+        SELECT r.menu_item_id, r.ingredient_id, i.ingredient_name, i.unit, r.quantity
+        FROM recipe_items r
+        JOIN ingredients i ON i.id = r.ingredient_id
+        WHERE r.menu_item_id = $1
+        ORDER BY i.ingredient_name`,
+        menuItemID,
+    )
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query recipe")
+    }
+    defer rows.Close()
+
+    var items []models.RecipeItem
+    for rows.Next() {
+        var item models.RecipeItem
+        if err := rows.Scan(&item.MenuItemID, &item.IngredientID, &item.IngredientName, &item.Unit, &item.Quantity); err != nil {
+            return nil, errors.Wrap(err, "failed to scan recipe item")
+        }
+        items = append(items, item)
+    }
+    return items, nil
+}
+
+// Replace reemplaza la receta completa del ítem dentro de una transacción
+func (r *recipeRepository) Replace(menuItemID int, items []models.RecipeItem) error {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    if _, err := tx.Exec(`DELETE FROM recipe_items WHERE menu_item_id = $1`, menuItemID); err != nil {
+        return errors.Wrap(err, "failed to clear recipe")
+    }
+
+    for _, item := range items {
+        if _, err := tx.Exec(`
+            INSERT INTO recipe_items (menu_item_id, ingredient_id, quantity)
+            VALUES ($1, $2, $3)`,
+            menuItemID, item.IngredientID, item.Quantity.String(),
+        ); err != nil {
+            return errors.Wrap(err, "failed to create recipe item")
+        }
+    }
+
+    if err := tx.Commit(); err != nil {
+        return errors.Wrap(err, "failed to commit transaction")
+    }
+    return nil
+}
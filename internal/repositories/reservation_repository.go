@@ -0,0 +1,413 @@
+package repositories
+
+import (
+    "database/sql"
+    "sort"
+    "time"
+
+    "gastrobar-backend/internal/models"
+
+    "github.com/lib/pq"
+    "github.com/pkg/errors"
+)
+
+type ReservationRepository interface {
+    FindByID(reservationID int) (models.Reservation, error)
+    FindAll(from, to *time.Time, status string) ([]models.Reservation, error)
+    FindAvailableTables(start, end time.Time, excludeReservationID int) ([]models.Table, error)
+    Create(reservation models.Reservation) (models.Reservation, error)
+    Update(reservation models.Reservation) (models.Reservation, error)
+    Seat(reservationID, employeeID int) (models.Reservation, error)
+    Close(reservationID int, status models.ReservationStatus) (models.Reservation, error)
+}
+
+type reservationRepository struct {
+    db *sql.DB
+}
+
+func NewReservationRepository(db *sql.DB) ReservationRepository {
+    return &reservationRepository{db: db}
+}
+
+func (r *reservationRepository) FindByID(reservationID int) (models.Reservation, error) {
+    var reservation models.Reservation
+    var notes sql.NullString
+    var customerOrderID sql.NullInt64
+    var seatedAt sql.NullTime
+    err := r.db.QueryRow(`
+        SELECT id, guest_name, guest_phone, party_size, reserved_at, duration_minutes, status, notes, customer_order_id, created_by, seated_at, created_at
+        FROM reservations
+        WHERE id = $1`,
+        reservationID,
+    ).Scan(&reservation.ID, &reservation.GuestName, &reservation.GuestPhone, &reservation.PartySize, &reservation.ReservedAt, &reservation.DurationMinutes, &reservation.Status, &notes, &customerOrderID, &reservation.CreatedBy, &seatedAt, &reservation.CreatedAt)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return models.Reservation{}, errors.Wrap(err, "reservation not found")
+        }
+        return models.Reservation{}, errors.Wrap(err, "failed to query reservation by ID")
+    }
+    reservation.Notes = notes.String
+    reservation.CustomerOrderID = nullIntToPtr(customerOrderID)
+    reservation.SeatedAt = nullTimeToPtr(seatedAt)
+
+    reservation.TableIDs, err = findReservationTableIDs(r.db, reservationID)
+    if err != nil {
+        return models.Reservation{}, err
+    }
+    return reservation, nil
+}
+
+// FindAll devuelve las reservas que empiezan en el rango indicado, opcionalmente filtradas por estado
+func (r *reservationRepository) FindAll(from, to *time.Time, status string) ([]models.Reservation, error) {
+    rows, err := r.db.Query(`
+        SELECT r.id, r.guest_name, r.guest_phone, r.party_size, r.reserved_at, r.duration_minutes, r.status, r.notes, r.customer_order_id, r.created_by, r.seated_at, r.created_at,
+               COALESCE(array_agg(rt.table_id ORDER BY rt.table_id) FILTER (WHERE rt.table_id IS NOT NULL), '{}')
+        FROM reservations r
+        LEFT JOIN reservation_tables rt ON rt.reservation_id = r.id
+        WHERE ($1::TIMESTAMPTZ IS NULL OR r.reserved_at >= $1)
+          AND ($2::TIMESTAMPTZ IS NULL OR r.reserved_at < $2)
+          AND ($3 = '' OR r.status = $3)
+        GROUP BY r.id
+        ORDER BY r.reserved_at, r.id`,
+        from, to, status,
+    )
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query reservations")
+    }
+    defer rows.Close()
+
+    reservations := []models.Reservation{}
+    for rows.Next() {
+        var reservation models.Reservation
+        var notes sql.NullString
+        var customerOrderID sql.NullInt64
+        var seatedAt sql.NullTime
+        var tableIDs []int64
+        if err := rows.Scan(&reservation.ID, &reservation.GuestName, &reservation.GuestPhone, &reservation.PartySize, &reservation.ReservedAt, &reservation.DurationMinutes, &reservation.Status, &notes, &customerOrderID, &reservation.CreatedBy, &seatedAt, &reservation.CreatedAt, pq.Array(&tableIDs)); err != nil {
+            return nil, errors.Wrap(err, "failed to scan reservation")
+        }
+        reservation.Notes = notes.String
+        reservation.CustomerOrderID = nullIntToPtr(customerOrderID)
+        reservation.SeatedAt = nullTimeToPtr(seatedAt)
+        reservation.TableIDs = []int{}
+        for _, tableID := range tableIDs {
+            reservation.TableIDs = append(reservation.TableIDs, int(tableID))
+        }
+        reservations = append(reservations, reservation)
+    }
+    return reservations, nil
+}
+
+// FindAvailableTables devuelve las mesas activas que no tienen reservas vigentes que se crucen con
+// el intervalo [start, end), ordenadas de menor a mayor capacidad
+func (r *reservationRepository) FindAvailableTables(start, end time.Time, excludeReservationID int) ([]models.Table, error) {
+    rows, err := r.db.Query(`
+        SELECT t.id, t.table_name, t.capacity, t.zone, t.pos_x, t.pos_y, t.qr_version, t.active, t.created_at
+        FROM tables t
+        WHERE t.active = TRUE
+          AND NOT EXISTS (
+              SELECT 1
+              FROM reservation_tables rt
+              JOIN reservations r ON r.id = rt.reservation_id
+              LEFT JOIN customer_orders co ON co.id = r.customer_order_id
+              WHERE rt.table_id = t.id
+                AND r.id <> $3
+                AND (r.status = 'booked' OR (r.status = 'seated' AND co.status = 'pending'))
+                AND r.reserved_at < $2
+                AND r.reserved_at + r.duration_minutes * INTERVAL '1 minute' > $1
+          )
+        ORDER BY t.capacity, t.id`,
+        start, end, excludeReservationID,
+    )
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query available tables")
+    }
+    defer rows.Close()
+
+    tables := []models.Table{}
+    for rows.Next() {
+        var table models.Table
+        if err := rows.Scan(&table.ID, &table.TableName, &table.Capacity, &table.Zone, &table.PosX, &table.PosY, &table.QRVersion, &table.Active, &table.CreatedAt); err != nil {
+            return nil, errors.Wrap(err, "failed to scan table")
+        }
+        tables = append(tables, table)
+    }
+    return tables, nil
+}
+
+// Create registra la reserva con sus mesas. Las mesas se bloquean para que dos reservas simultáneas
+// no puedan quedarse con la misma mesa en horarios que se cruzan
+func (r *reservationRepository) Create(reservation models.Reservation) (models.Reservation, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.Reservation{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    if err := lockReservationTables(tx, reservation, 0); err != nil {
+        return models.Reservation{}, err
+    }
+
+    var reservationID int
+    err = tx.QueryRow(`
+        INSERT INTO reservations (guest_name, guest_phone, party_size, reserved_at, duration_minutes, status, notes, created_by, created_at)
+        VALUES ($1, $2, $3, $4, $5, 'booked', NULLIF($6, ''), $7, CURRENT_TIMESTAMP)
+        RETURNING id`,
+        reservation.GuestName, reservation.GuestPhone, reservation.PartySize, reservation.ReservedAt, reservation.DurationMinutes, reservation.Notes, reservation.CreatedBy,
+    ).Scan(&reservationID)
+    if err != nil {
+        return models.Reservation{}, errors.Wrap(err, "failed to create reservation")
+    }
+
+    if err := insertReservationTables(tx, reservationID, reservation.TableIDs); err != nil {
+        return models.Reservation{}, err
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.Reservation{}, errors.Wrap(err, "failed to commit transaction")
+    }
+    return r.FindByID(reservationID)
+}
+
+// Update reemplaza los datos y las mesas de una reserva; solo se permite mientras está reservada
+func (r *reservationRepository) Update(reservation models.Reservation) (models.Reservation, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.Reservation{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    if err := lockReservation(tx, reservation.ID, models.ReservationStatusBooked); err != nil {
+        return models.Reservation{}, err
+    }
+    if err := lockReservationTables(tx, reservation, reservation.ID); err != nil {
+        return models.Reservation{}, err
+    }
+
+    if _, err := tx.Exec(`
+        UPDATE reservations
+        SET guest_name = $1, guest_phone = $2, party_size = $3, reserved_at = $4, duration_minutes = $5, notes = NULLIF($6, '')
+        WHERE id = $7`,
+        reservation.GuestName, reservation.GuestPhone, reservation.PartySize, reservation.ReservedAt, reservation.DurationMinutes, reservation.Notes, reservation.ID,
+    ); err != nil {
+        return models.Reservation{}, errors.Wrap(err, "failed to update reservation")
+    }
+
+    if _, err := tx.Exec(`DELETE FROM reservation_tables WHERE reservation_id = $1`, reservation.ID); err != nil {
+        return models.Reservation{}, errors.Wrap(err, "failed to delete reservation tables")
+    }
+    if err := insertReservationTables(tx, reservation.ID, reservation.TableIDs); err != nil {
+        return models.Reservation{}, err
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.Reservation{}, errors.Wrap(err, "failed to commit transaction")
+    }
+    return r.FindByID(reservation.ID)
+}
+
+// Seat sienta a los clientes de una reserva: abre un pedido pendiente en la primera de sus mesas
+// (la de menor id) a nombre del empleado que sienta y deja la reserva como sentada. Ninguna de las
+// mesas puede tener un pedido pendiente
+func (r *reservationRepository) Seat(reservationID, employeeID int) (models.Reservation, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.Reservation{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    if err := lockReservation(tx, reservationID, models.ReservationStatusBooked); err != nil {
+        return models.Reservation{}, err
+    }
+
+    tableIDs, err := findReservationTableIDs(tx, reservationID)
+    if err != nil {
+        return models.Reservation{}, err
+    }
+    if len(tableIDs) == 0 {
+        return models.Reservation{}, errors.New("reservation has no tables")
+    }
+
+    for _, tableID := range tableIDs {
+        var active, hasPendingOrder bool
+        err := tx.QueryRow(`
+            SELECT t.active, EXISTS (SELECT 1 FROM customer_orders WHERE table_id = t.id AND status = 'pending')
+            FROM tables t
+            WHERE t.id = $1
+            FOR UPDATE`,
+            tableID,
+        ).Scan(&active, &hasPendingOrder)
+        if err != nil {
+            return models.Reservation{}, errors.Wrap(err, "failed to lock table")
+        }
+        if !active {
+            return models.Reservation{}, errors.Errorf("table is inactive: %d", tableID)
+        }
+        if hasPendingOrder {
+            return models.Reservation{}, errors.Errorf("table has a pending order: %d", tableID)
+        }
+    }
+
+    var customerOrderID int
+    err = tx.QueryRow(`
+        INSERT INTO customer_orders (table_id, total_amount, status, opened_by, created_at)
+        VALUES ($1, 0, 'pending', $2, CURRENT_TIMESTAMP)
+        RETURNING id`,
+        tableIDs[0], employeeID,
+    ).Scan(&customerOrderID)
+    if err != nil {
+        return models.Reservation{}, errors.Wrap(err, "failed to create customer order")
+    }
+
+    if _, err := tx.Exec(`
+        UPDATE reservations
+        SET status = 'seated', seated_at = CURRENT_TIMESTAMP, customer_order_id = $1
+        WHERE id = $2`,
+        customerOrderID, reservationID,
+    ); err != nil {
+        return models.Reservation{}, errors.Wrap(err, "failed to seat reservation")
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.Reservation{}, errors.Wrap(err, "failed to commit transaction")
+    }
+    return r.FindByID(reservationID)
+}
+
+// Close marca una reserva pendiente como no presentada o cancelada, liberando sus mesas
+func (r *reservationRepository) Close(reservationID int, status models.ReservationStatus) (models.Reservation, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.Reservation{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    if err := lockReservation(tx, reservationID, models.ReservationStatusBooked); err != nil {
+        return models.Reservation{}, err
+    }
+
+    if _, err := tx.Exec(`
+        UPDATE reservations
+        SET status = $1
+        WHERE id = $2`,
+        status, reservationID,
+    ); err != nil {
+        return models.Reservation{}, errors.Wrap(err, "failed to update reservation status")
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.Reservation{}, errors.Wrap(err, "failed to commit transaction")
+    }
+    return r.FindByID(reservationID)
+}
+
+// lockReservation bloquea la reserva y verifica que esté en alguno de los estados permitidos
+func lockReservation(tx *sql.Tx, reservationID int, allowed ...models.ReservationStatus) error {
+    var status models.ReservationStatus
+    err := tx.QueryRow(`
+        SELECT status
+        FROM reservations
+        WHERE id = $1
+        FOR UPDATE`,
+        reservationID,
+    ).Scan(&status)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return errors.Wrap(err, "reservation not found")
+        }
+        return errors.Wrap(err, "failed to lock reservation")
+    }
+    for _, s := range allowed {
+        if status == s {
+            return nil
+        }
+    }
+    return errors.Errorf("reservation status does not allow this operation: %s", status)
+}
+
+// lockReservationTables bloquea las mesas de la reserva (en orden de id para evitar deadlocks) y
+// verifica que ninguna tenga otra reserva vigente que se cruce con el horario pedido
+func lockReservationTables(tx *sql.Tx, reservation models.Reservation, excludeReservationID int) error {
+    tableIDs := append([]int(nil), reservation.TableIDs...)
+    sort.Ints(tableIDs)
+
+    for _, tableID := range tableIDs {
+        var active bool
+        err := tx.QueryRow(`
+            SELECT active
+            FROM tables
+            WHERE id = $1
+            FOR UPDATE`,
+            tableID,
+        ).Scan(&active)
+        if err != nil {
+            if err == sql.ErrNoRows {
+                return errors.Wrapf(err, "table not found: %d", tableID)
+            }
+            return errors.Wrap(err, "failed to lock table")
+        }
+        if !active {
+            return errors.Errorf("table is inactive: %d", tableID)
+        }
+
+        var overlapping bool
+        err = tx.QueryRow(`
+            SELECT EXISTS (
+                SELECT 1
+                FROM reservation_tables rt
+                JOIN reservations r ON r.id = rt.reservation_id
+                LEFT JOIN customer_orders co ON co.id = r.customer_order_id
+                WHERE rt.table_id = $1
+                  AND r.id <> $4
+                  AND (r.status = 'booked' OR (r.status = 'seated' AND co.status = 'pending'))
+                  AND r.reserved_at < $3
+                  AND r.reserved_at + r.duration_minutes * INTERVAL '1 minute' > $2
+            )`,
+            tableID, reservation.ReservedAt, reservation.EndsAt(), excludeReservationID,
+        ).Scan(&overlapping)
+        if err != nil {
+            return errors.Wrap(err, "failed to check overlapping reservations")
+        }
+        if overlapping {
+            return errors.Errorf("table already booked for that time: %d", tableID)
+        }
+    }
+    return nil
+}
+
+func insertReservationTables(tx *sql.Tx, reservationID int, tableIDs []int) error {
+    for _, tableID := range tableIDs {
+        if _, err := tx.Exec(`
+            INSERT INTO reservation_tables (reservation_id, table_id)
+            VALUES ($1, $2)`,
+            reservationID, tableID,
+        ); err != nil {
+            return errors.Wrap(err, "failed to create reservation table")
+        }
+    }
+    return nil
+}
+
+func findReservationTableIDs(q queryer, reservationID int) ([]int, error) {
+    rows, err := q.Query(`
+        SELECT table_id
+        FROM reservation_tables
+        WHERE reservation_id = $1
+        ORDER BY table_id`,
+        reservationID,
+    )
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query reservation tables")
+    }
+    defer rows.Close()
+
+    tableIDs := []int{}
+    for rows.Next() {
+        var tableID int
+        if err := rows.Scan(&tableID); err != nil {
+            return nil, errors.Wrap(err, "failed to scan reservation table")
+        }
+        tableIDs = append(tableIDs, tableID)
+    }
+    return tableIDs, nil
+}
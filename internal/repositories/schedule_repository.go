@@ -0,0 +1,485 @@
+package repositories
+
+import (
+    "database/sql"
+    "time"
+
+    "gastrobar-backend/internal/models"
+
+    "github.com/pkg/errors"
+)
+
+type ScheduleRepository interface {
+    FindByID(scheduleID int) (models.Schedule, error)
+    FindByWeek(weekStart string) (models.Schedule, error)
+    FindAll() ([]models.Schedule, error)
+    Create(schedule models.Schedule) (models.Schedule, error)
+    Delete(scheduleID int) error
+    Publish(scheduleID, employeeID int) (models.Schedule, error)
+    Unpublish(scheduleID int) (models.Schedule, error)
+    Copy(sourceScheduleID int, weekStart string, createdBy int) (models.Schedule, error)
+    FindShiftByID(shiftID int) (models.Shift, error)
+    FindShifts(scheduleID int) ([]models.Shift, error)
+    FindShiftsInRange(from, to time.Time, employeeID int, publishedOnly bool) ([]models.Shift, error)
+    CreateShift(shift models.Shift) (models.Shift, error)
+    UpdateShift(shift models.Shift) (models.Shift, error)
+    DeleteShift(shiftID int) error
+}
+
+type scheduleRepository struct {
+    db *sql.DB
+}
+
+func NewScheduleRepository(db *sql.DB) ScheduleRepository {
+    return &scheduleRepository{db: db}
+}
+
+func (r *scheduleRepository) FindByID(scheduleID int) (models.Schedule, error) {
+    rows, err := r.db.Query(`
+        SELECT id, to_char(week_start, 'YYYY-MM-DD'), status, published_at, published_by, created_by, created_at
+        FROM schedules
+        WHERE id = $1`,
+        scheduleID,
+    )
+    if err != nil {
+        return models.Schedule{}, errors.Wrap(err, "failed to query schedule by ID")
+    }
+    defer rows.Close()
+
+    schedules, err := scanSchedules(rows)
+    if err != nil {
+        return models.Schedule{}, err
+    }
+    if len(schedules) == 0 {
+        return models.Schedule{}, errors.New("schedule not found")
+    }
+    return schedules[0], nil
+}
+
+func (r *scheduleRepository) FindByWeek(weekStart string) (models.Schedule, error) {
+    rows, err := r.db.Query(`
+        SELECT id, to_char(week_start, 'YYYY-MM-DD'), status, published_at, published_by, created_by, created_at
+        FROM schedules
+        WHERE week_start = $1::DATE`,
+        weekStart,
+    )
+    if err != nil {
+        return models.Schedule{}, errors.Wrap(err, "failed to query schedule by week")
+    }
+    defer rows.Close()
+
+    schedules, err := scanSchedules(rows)
+    if err != nil {
+        return models.Schedule{}, err
+    }
+    if len(schedules) == 0 {
+        return models.Schedule{}, errors.New("schedule not found")
+    }
+    return schedules[0], nil
+}
+
+// FindAll devuelve los horarios de la semana más reciente a la más antigua
+func (r *scheduleRepository) FindAll() ([]models.Schedule, error) {
+    rows, err := r.db.Query(`
+        SELECT id, to_char(week_start, 'YYYY-MM-DD'), status, published_at, published_by, created_by, created_at
+        FROM schedules
+        ORDER BY week_start DESC`)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query schedules")
+    }
+    defer rows.Close()
+
+    return scanSchedules(rows)
+}
+
+func (r *scheduleRepository) Create(schedule models.Schedule) (models.Schedule, error) {
+    var scheduleID int
+    err := r.db.QueryRow(`
+        INSERT INTO schedules (week_start, status, created_by, created_at)
+        VALUES ($1::DATE, 'draft', $2, CURRENT_TIMESTAMP)
+        RETURNING id`,
+        schedule.WeekStart, schedule.CreatedBy,
+    ).Scan(&scheduleID)
+    if err != nil {
+        return models.Schedule{}, errors.Wrap(err, "failed to create schedule")
+    }
+    return r.FindByID(scheduleID)
+}
+
+// Delete elimina un horario en borrador junto con sus turnos
+func (r *scheduleRepository) Delete(scheduleID int) error {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    if err := lockSchedule(tx, scheduleID, models.ScheduleStatusDraft); err != nil {
+        return err
+    }
+    if _, err := tx.Exec(`
+        DELETE FROM schedules
+        WHERE id = $1`,
+        scheduleID,
+    ); err != nil {
+        return errors.Wrap(err, "failed to delete schedule")
+    }
+
+    if err := tx.Commit(); err != nil {
+        return errors.Wrap(err, "failed to commit transaction")
+    }
+    return nil
+}
+
+func (r *scheduleRepository) Publish(scheduleID, employeeID int) (models.Schedule, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.Schedule{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    if err := lockSchedule(tx, scheduleID, models.ScheduleStatusDraft); err != nil {
+        return models.Schedule{}, err
+    }
+    if _, err := tx.Exec(`
+        UPDATE schedules
+        SET status = 'published', published_at = CURRENT_TIMESTAMP, published_by = $1
+        WHERE id = $2`,
+        employeeID, scheduleID,
+    ); err != nil {
+        return models.Schedule{}, errors.Wrap(err, "failed to publish schedule")
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.Schedule{}, errors.Wrap(err, "failed to commit transaction")
+    }
+    return r.FindByID(scheduleID)
+}
+
+// Unpublish devuelve un horario publicado a borrador para poder corregirlo
+func (r *scheduleRepository) Unpublish(scheduleID int) (models.Schedule, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.Schedule{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    if err := lockSchedule(tx, scheduleID, models.ScheduleStatusPublished); err != nil {
+        return models.Schedule{}, err
+    }
+    if _, err := tx.Exec(`
+        UPDATE schedules
+        SET status = 'draft', published_at = NULL, published_by = NULL
+        WHERE id = $1`,
+        scheduleID,
+    ); err != nil {
+        return models.Schedule{}, errors.Wrap(err, "failed to unpublish schedule")
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.Schedule{}, errors.Wrap(err, "failed to commit transaction")
+    }
+    return r.FindByID(scheduleID)
+}
+
+// Copy crea un horario en borrador para la semana indicada con los mismos turnos del horario de
+// origen, desplazados la cantidad de días que hay entre ambas semanas. Los turnos de empleados dados
+// de baja no se copian
+func (r *scheduleRepository) Copy(sourceScheduleID int, weekStart string, createdBy int) (models.Schedule, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.Schedule{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    if err := lockSchedule(tx, sourceScheduleID, models.ScheduleStatusDraft, models.ScheduleStatusPublished); err != nil {
+        return models.Schedule{}, err
+    }
+
+    var scheduleID int
+    err = tx.QueryRow(`
+        INSERT INTO schedules (week_start, status, created_by, created_at)
+        VALUES ($1::DATE, 'draft', $2, CURRENT_TIMESTAMP)
+        RETURNING id`,
+        weekStart, createdBy,
+    ).Scan(&scheduleID)
+    if err != nil {
+        return models.Schedule{}, errors.Wrap(err, "failed to create schedule")
+    }
+
+    if _, err := tx.Exec(`
+        INSERT INTO shifts (schedule_id, employee_id, shift_template_id, role, starts_at, ends_at, notes, created_at)
+        SELECT $1, s.employee_id, s.shift_template_id, s.role,
+               s.starts_at + ($3::DATE - src.week_start) * INTERVAL '1 day',
+               s.ends_at + ($3::DATE - src.week_start) * INTERVAL '1 day',
+               s.notes, CURRENT_TIMESTAMP
+        FROM shifts s
+        JOIN schedules src ON src.id = s.schedule_id
+        JOIN employees e ON e.id = s.employee_id
+        WHERE s.schedule_id = $2 AND e.active = TRUE`,
+        scheduleID, sourceScheduleID, weekStart,
+    ); err != nil {
+        return models.Schedule{}, errors.Wrap(err, "failed to copy shifts")
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.Schedule{}, errors.Wrap(err, "failed to commit transaction")
+    }
+    return r.FindByID(scheduleID)
+}
+
+func (r *scheduleRepository) FindShiftByID(shiftID int) (models.Shift, error) {
+    rows, err := r.db.Query(`
+        SELECT s.id, s.schedule_id, s.employee_id, e.employee_name, s.shift_template_id, s.role, s.starts_at, s.ends_at, s.notes, s.created_at
+        FROM shifts s
+        JOIN employees e ON e.id = s.employee_id
+        WHERE s.id = $1`,
+        shiftID,
+    )
+    if err != nil {
+        return models.Shift{}, errors.Wrap(err, "failed to query shift by ID")
+    }
+    defer rows.Close()
+
+    shifts, err := scanShifts(rows)
+    if err != nil {
+        return models.Shift{}, err
+    }
+    if len(shifts) == 0 {
+        return models.Shift{}, errors.New("shift not found")
+    }
+    return shifts[0], nil
+}
+
+func (r *scheduleRepository) FindShifts(scheduleID int) ([]models.Shift, error) {
+    rows, err := r.db.Query(`
+        SELECT s.id, s.schedule_id, s.employee_id, e.employee_name, s.shift_template_id, s.role, s.starts_at, s.ends_at, s.notes, s.created_at
+        FROM shifts s
+        JOIN employees e ON e.id = s.employee_id
+        WHERE s.schedule_id = $1
+        ORDER BY s.starts_at, e.employee_name, s.id`,
+        scheduleID,
+    )
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query shifts")
+    }
+    defer rows.Close()
+
+    return scanShifts(rows)
+}
+
+// FindShiftsInRange devuelve los turnos que se cruzan con [from, to) de cualquier horario, opcionalmente
+// de un solo empleado (employeeID 0 devuelve todos) y solo de horarios publicados
+func (r *scheduleRepository) FindShiftsInRange(from, to time.Time, employeeID int, publishedOnly bool) ([]models.Shift, error) {
+    rows, err := r.db.Query(`
+        SELECT s.id, s.schedule_id, s.employee_id, e.employee_name, s.shift_template_id, s.role, s.starts_at, s.ends_at, s.notes, s.created_at
+        FROM shifts s
+        JOIN employees e ON e.id = s.employee_id
+        JOIN schedules sc ON sc.id = s.schedule_id
+        WHERE s.starts_at < $2
+          AND s.ends_at > $1
+          AND ($3 = 0 OR s.employee_id = $3)
+          AND (NOT $4 OR sc.status = 'published')
+        ORDER BY s.starts_at, s.id`,
+        from, to, employeeID, publishedOnly,
+    )
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query shifts")
+    }
+    defer rows.Close()
+
+    return scanShifts(rows)
+}
+
+// CreateShift agrega un turno a un horario en borrador; el empleado no puede tener otro turno que
+// se cruce con el nuevo
+func (r *scheduleRepository) CreateShift(shift models.Shift) (models.Shift, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.Shift{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    if err := lockSchedule(tx, shift.ScheduleID, models.ScheduleStatusDraft); err != nil {
+        return models.Shift{}, err
+    }
+    if err := checkShiftOverlap(tx, shift); err != nil {
+        return models.Shift{}, err
+    }
+
+    var shiftID int
+    err = tx.QueryRow(`
+        INSERT INTO shifts (schedule_id, employee_id, shift_template_id, role, starts_at, ends_at, notes, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), CURRENT_TIMESTAMP)
+        RETURNING id`,
+        shift.ScheduleID, shift.EmployeeID, shift.ShiftTemplateID, shift.Role, shift.StartsAt, shift.EndsAt, shift.Notes,
+    ).Scan(&shiftID)
+    if err != nil {
+        return models.Shift{}, errors.Wrap(err, "failed to create shift")
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.Shift{}, errors.Wrap(err, "failed to commit transaction")
+    }
+    return r.FindShiftByID(shiftID)
+}
+
+func (r *scheduleRepository) UpdateShift(shift models.Shift) (models.Shift, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.Shift{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    shift.ScheduleID, err = lockShiftSchedule(tx, shift.ID)
+    if err != nil {
+        return models.Shift{}, err
+    }
+    if err := checkShiftOverlap(tx, shift); err != nil {
+        return models.Shift{}, err
+    }
+
+    if _, err := tx.Exec(`
+        UPDATE shifts
+        SET employee_id = $1, shift_template_id = $2, role = $3, starts_at = $4, ends_at = $5, notes = NULLIF($6, '')
+        WHERE id = $7`,
+        shift.EmployeeID, shift.ShiftTemplateID, shift.Role, shift.StartsAt, shift.EndsAt, shift.Notes, shift.ID,
+    ); err != nil {
+        return models.Shift{}, errors.Wrap(err, "failed to update shift")
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.Shift{}, errors.Wrap(err, "failed to commit transaction")
+    }
+    return r.FindShiftByID(shift.ID)
+}
+
+func (r *scheduleRepository) DeleteShift(shiftID int) error {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    if _, err := lockShiftSchedule(tx, shiftID); err != nil {
+        return err
+    }
+    if _, err := tx.Exec(`
+        DELETE FROM shifts
+        WHERE id = $1`,
+        shiftID,
+    ); err != nil {
+        return errors.Wrap(err, "failed to delete shift")
+    }
+
+    if err := tx.Commit(); err != nil {
+        return errors.Wrap(err, "failed to commit transaction")
+    }
+    return nil
+}
+
+// lockSchedule bloquea el horario y verifica que esté en alguno de los estados permitidos
+func lockSchedule(tx *sql.Tx, scheduleID int, allowed ...models.ScheduleStatus) error {
+    var status models.ScheduleStatus
+    err := tx.QueryRow(`
+        SELECT status
+        FROM schedules
+        WHERE id = $1
+        FOR UPDATE`,
+        scheduleID,
+    ).Scan(&status)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return errors.Wrap(err, "schedule not found")
+        }
+        return errors.Wrap(err, "failed to lock schedule")
+    }
+    for _, s := range allowed {
+        if status == s {
+            return nil
+        }
+    }
+    return errors.Errorf("schedule status does not allow this operation: %s", status)
+}
+
+// lockShiftSchedule bloquea el turno y su horario, que debe estar en borrador; devuelve el id del horario
+func lockShiftSchedule(tx *sql.Tx, shiftID int) (int, error) {
+    var scheduleID int
+    err := tx.QueryRow(`
+        SELECT schedule_id
+        FROM shifts
+        WHERE id = $1
+        FOR UPDATE`,
+        shiftID,
+    ).Scan(&scheduleID)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return 0, errors.Wrap(err, "shift not found")
+        }
+        return 0, errors.Wrap(err, "failed to lock shift")
+    }
+    if err := lockSchedule(tx, scheduleID, models.ScheduleStatusDraft); err != nil {
+        return 0, err
+    }
+    return scheduleID, nil
+}
+
+// checkShiftOverlap bloquea al empleado (para serializar la carga de sus turnos), verifica que siga
+// activo y que no tenga otro turno, de cualquier horario, que se cruce con el indicado
+func checkShiftOverlap(tx *sql.Tx, shift models.Shift) error {
+    if err := lockActiveEmployee(tx, shift.EmployeeID); err != nil {
+        return err
+    }
+
+    var overlapping bool
+    err := tx.QueryRow(`
+        SELECT EXISTS (
+            SELECT 1
+            FROM shifts
+            WHERE employee_id = $1
+              AND id <> $2
+              AND starts_at < $4
+              AND ends_at > $3
+        )`,
+        shift.EmployeeID, shift.ID, shift.StartsAt, shift.EndsAt,
+    ).Scan(&overlapping)
+    if err != nil {
+        return errors.Wrap(err, "failed to check overlapping shifts")
+    }
+    if overlapping {
+        return errors.New("shift overlaps another shift of the employee")
+    }
+    return nil
+}
+
+func scanSchedules(rows *sql.Rows) ([]models.Schedule, error) {
+    schedules := []models.Schedule{}
+    for rows.Next() {
+        var schedule models.Schedule
+        var publishedAt sql.NullTime
+        var publishedBy sql.NullInt64
+        if err := rows.Scan(&schedule.ID, &schedule.WeekStart, &schedule.Status, &publishedAt, &publishedBy, &schedule.CreatedBy, &schedule.CreatedAt); err != nil {
+            return nil, errors.Wrap(err, "failed to scan schedule")
+        }
+        schedule.PublishedAt = nullTimeToPtr(publishedAt)
+        schedule.PublishedBy = nullIntToPtr(publishedBy)
+        schedules = append(schedules, schedule)
+    }
+    return schedules, nil
+}
+
+func scanShifts(rows *sql.Rows) ([]models.Shift, error) {
+    shifts := []models.Shift{}
+    for rows.Next() {
+        var shift models.Shift
+        var shiftTemplateID sql.NullInt64
+        var notes sql.NullString
+        if err := rows.Scan(&shift.ID, &shift.ScheduleID, &shift.EmployeeID, &shift.EmployeeName, &shiftTemplateID, &shift.Role, &shift.StartsAt, &shift.EndsAt, &notes, &shift.CreatedAt); err != nil {
+            return nil, errors.Wrap(err, "failed to scan shift")
+        }
+        shift.ShiftTemplateID = nullIntToPtr(shiftTemplateID)
+        shift.Notes = notes.String
+        shifts = append(shifts, shift)
+    }
+    return shifts, nil
+}
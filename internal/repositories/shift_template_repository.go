@@ -0,0 +1,133 @@
+package repositories
+
+import (
+    "database/sql"
+
+    "gastrobar-backend/internal/models"
+
+    "github.com/pkg/errors"
+)
+
+type ShiftTemplateRepository interface {
+    FindByID(templateID int) (models.ShiftTemplate, error)
+    FindByName(templateName string) (models.ShiftTemplate, error)
+    FindAll() ([]models.ShiftTemplate, error)
+    Create(template models.ShiftTemplate) (models.ShiftTemplate, error)
+    Update(template models.ShiftTemplate) (models.ShiftTemplate, error)
+    Delete(templateID int) error
+}
+
+type shiftTemplateRepository struct {
+    db *sql.DB
+}
+
+func NewShiftTemplateRepository(db *sql.DB) ShiftTemplateRepository {
+    return &shiftTemplateRepository{db: db}
+}
+
+func (r *shiftTemplateRepository) FindByID(templateID int) (models.ShiftTemplate, error) {
+    var template models.ShiftTemplate
+    err := r.db.QueryRow(`
+        SELECT id, template_name, role, to_char(start_time, 'HH24:MI'), to_char(end_time, 'HH24:MI'), created_at
+        FROM shift_templates
+        WHERE id = $1`,
+        templateID,
+    ).Scan(&template.ID, &template.TemplateName, &template.Role, &template.StartTime, &template.EndTime, &template.CreatedAt)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return models.ShiftTemplate{}, errors.Wrap(err, "shift template not found")
+        }
+        return models.ShiftTemplate{}, errors.Wrap(err, "failed to query shift template by ID")
+    }
+    return template, nil
+}
+
+func (r *shiftTemplateRepository) FindByName(templateName string) (models.ShiftTemplate, error) {
+    var template models.ShiftTemplate
+    err := r.db.QueryRow(`
+        SELECT id, template_name, role, to_char(start_time, 'HH24:MI'), to_char(end_time, 'HH24:MI'), created_at
+        FROM shift_templates
+        WHERE template_name = $1`,
+        templateName,
+    ).Scan(&template.ID, &template.TemplateName, &template.Role, &template.StartTime, &template.EndTime, &template.CreatedAt)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return models.ShiftTemplate{}, errors.Wrap(err, "shift template not found")
+        }
+        return models.ShiftTemplate{}, errors.Wrap(err, "failed to query shift template by name")
+    }
+    return template, nil
+}
+
+func (r *shiftTemplateRepository) FindAll() ([]models.ShiftTemplate, error) {
+    rows, err := r.db.Query(`
+        SELECT id, template_name, role, to_char(start_time, 'HH24:MI'), to_char(end_time, 'HH24:MI'), created_at
+        FROM shift_templates
+        ORDER BY start_time, template_name`)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query shift templates")
+    }
+    defer rows.Close()
+
+    templates := []models.ShiftTemplate{}
+    for rows.Next() {
+        var template models.ShiftTemplate
+        if err := rows.Scan(&template.ID, &template.TemplateName, &template.Role, &template.StartTime, &template.EndTime, &template.CreatedAt); err != nil {
+            return nil, errors.Wrap(err, "failed to scan shift template")
+        }
+        templates = append(templates, template)
+    }
+    return templates, nil
+}
+
+func (r *shiftTemplateRepository) Create(template models.ShiftTemplate) (models.ShiftTemplate, error) {
+    var createdTemplate models.ShiftTemplate
+    err := r.db.QueryRow(`
+        INSERT INTO shift_templates (template_name, role, start_time, end_time, created_at)
+        VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
+        RETURNING id, template_name, role, to_char(start_time, 'HH24:MI'), to_char(end_time, 'HH24:MI'), created_at`,
+        template.TemplateName, template.Role, template.StartTime, template.EndTime,
+    ).Scan(&createdTemplate.ID, &createdTemplate.TemplateName, &createdTemplate.Role, &createdTemplate.StartTime, &createdTemplate.EndTime, &createdTemplate.CreatedAt)
+    if err != nil {
+        return models.ShiftTemplate{}, errors.Wrap(err, "failed to create shift template")
+    }
+    return createdTemplate, nil
+}
+
+func (r *shiftTemplateRepository) Update(template models.ShiftTemplate) (models.ShiftTemplate, error) {
+    var updatedTemplate models.ShiftTemplate
+    err := r.db.QueryRow(`
+        UPDATE shift_templates
+        SET template_name = $1, role = $2, start_time = $3, end_time = $4
+        WHERE id = $5
+        RETURNING id, template_name, role, to_char(start_time, 'HH24:MI'), to_char(end_time, 'HH24:MI'), created_at`,
+        template.TemplateName, template.Role, template.StartTime, template.EndTime, template.ID,
+    ).Scan(&updatedTemplate.ID, &updatedTemplate.TemplateName, &updatedTemplate.Role, &updatedTemplate.StartTime, &updatedTemplate.EndTime, &updatedTemplate.CreatedAt)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return models.ShiftTemplate{}, errors.Wrap(err, "shift template not found")
+        }
+        return models.ShiftTemplate{}, errors.Wrap(err, "failed to update shift template")
+    }
+    return updatedTemplate, nil
+}
+
+// Delete elimina la plantilla; los turnos ya creados con ella se conservan sin plantilla
+func (r *shiftTemplateRepository) Delete(templateID int) error {
+    result, err := r.db.Exec(`
+        DELETE FROM shift_templates
+        WHERE id = $1`,
+        templateID,
+    )
+    if err != nil {
+        return errors.Wrap(err, "failed to delete shift template")
+    }
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return errors.Wrap(err, "failed to check rows affected")
+    }
+    if rowsAffected == 0 {
+        return errors.New("shift template not found")
+    }
+    return nil
+}
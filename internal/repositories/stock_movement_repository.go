@@ -0,0 +1,195 @@
+package repositories
+
+import (
+    "database/sql"
+    "strings"
+    "time"
+
+    "gastrobar-backend/internal/models"
+
+    "github.com/pkg/errors"
+    "github.com/shopspring/decimal"
+)
+
+type StockMovementRepository interface {
+    Create(movement models.StockMovement) (models.StockMovement, error)
+    FindByMenuItemID(menuItemID int) ([]models.StockMovement, error)
+    FindByIngredientID(ingredientID int) ([]models.StockMovement, error)
+    Reconcile() ([]models.StockReconciliationEntry, error)
+}
+
+type stockMovementRepository struct {
+    db *sql.DB
+}
+
+func NewStockMovementRepository(db *sql.DB) StockMovementRepository {
+    return &stockMovementRepository{db: db}
+}
+
+// queryRower permite insertar movimientos tanto con la conexión como dentro de una transacción
+type queryRower interface {
+    QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// insertStockMovement registra un movimiento; el trigger apply_stock_movement lo aplica al stock
+func insertStockMovement(q queryRower, movement models.StockMovement) (models.StockMovement, error) {
+    var created models.StockMovement
+    var menuItemID, ingredientID, employeeID, orderDetailID, purchaseOrderLineID, inventoryCountID sql.NullInt64
+    var reason sql.NullString
+    var unitCost decimal.NullDecimal
+
+    err := q.QueryRow(`
+        INSERT INTO stock_movements (menu_item_id, ingredient_id, movement_type, quantity, employee_id, reason, order_detail_id, purchase_order_line_id, unit_cost, inventory_count_id, created_at)
+        VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $9, $10, CURRENT_TIMESTAMP)
+        RETURNING id, menu_item_id, ingredient_id, movement_type, quantity, employee_id, reason, order_detail_id, purchase_order_line_id, unit_cost, inventory_count_id, created_at`,
+        movement.MenuItemID, movement.IngredientID, movement.MovementType, movement.Quantity.String(),
+        movement.EmployeeID, movement.Reason, movement.OrderDetailID, movement.PurchaseOrderLineID, nullableDecimal(movement.UnitCost), movement.InventoryCountID,
+    ).Scan(&created.ID, &menuItemID, &ingredientID, &created.MovementType, &created.Quantity, &employeeID, &reason, &orderDetailID, &purchaseOrderLineID, &unitCost, &inventoryCountID, &created.CreatedAt)
+    if err != nil {
+        if strings.Contains(err.Error(), "Resulting stock cannot be negative") {
+            return models.StockMovement{}, errors.New("resulting stock cannot be negative")
+        }
+        return models.StockMovement{}, errors.Wrap(err, "failed to create stock movement")
+    }
+
+    created.MenuItemID = nullIntToPtr(menuItemID)
+    created.IngredientID = nullIntToPtr(ingredientID)
+    created.EmployeeID = nullIntToPtr(employeeID)
+    created.OrderDetailID = nullIntToPtr(orderDetailID)
+    created.PurchaseOrderLineID = nullIntToPtr(purchaseOrderLineID)
+    created.UnitCost = nullDecimalToPtr(unitCost)
+    created.InventoryCountID = nullIntToPtr(inventoryCountID)
+    created.Reason = reason.String
+    return created, nil
+}
+
+// nullIntToPtr convierte un sql.NullInt64 a *int
+func nullIntToPtr(value sql.NullInt64) *int {
+    if !value.Valid {
+        return nil
+    }
+    v := int(value.Int64)
+    return &v
+}
+
+// nullTimeToPtr convierte un sql.NullTime a *time.Time
+func nullTimeToPtr(value sql.NullTime) *time.Time {
+    if !value.Valid {
+        return nil
+    }
+    return &value.Time
+}
+
+// nullDecimalToPtr convierte un decimal.NullDecimal a *decimal.Decimal
+func nullDecimalToPtr(value decimal.NullDecimal) *decimal.Decimal {
+    if !value.Valid {
+        return nil
+    }
+    return &value.Decimal
+}
+
+// nullableDecimal prepara un *decimal.Decimal como parámetro SQL (NULL si es nil)
+func nullableDecimal(value *decimal.Decimal) interface{} {
+    if value == nil {
+        return nil
+    }
+    return value.String()
+}
+
+func (r *stockMovementRepository) Create(movement models.StockMovement) (models.StockMovement, error) {
+    return insertStockMovement(r.db, movement)
+}
+
+func (r *stockMovementRepository) FindByMenuItemID(menuItemID int) ([]models.StockMovement, error) {
+    rows, err := r.db.Query(`
+        SELECT id, menu_item_id, ingredient_id, movement_type, quantity, employee_id, reason, order_detail_id, purchase_order_line_id, unit_cost, inventory_count_id, created_at
+        FROM stock_movements
+        WHERE menu_item_id = $1
+        ORDER BY created_at DESC, id DESC`,
+        menuItemID,
+    )
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query stock movements by item")
+    }
+    defer rows.Close()
+
+    return scanStockMovements(rows)
+}
+
+func (r *stockMovementRepository) FindByIngredientID(ingredientID int) ([]models.StockMovement, error) {
+    rows, err := r.db.Query(`
+        SELECT id, menu_item_id, ingredient_id, movement_type, quantity, employee_id, reason, order_detail_id, purchase_order_line_id, unit_cost, inventory_count_id, created_at
+        FROM stock_movements
+        WHERE ingredient_id = $1
+        ORDER BY created_at DESC, id DESC`,
+        ingredientID,
+    )
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query stock movements by ingredient")
+    }
+    defer rows.Close()
+
+    return scanStockMovements(rows)
+}
+
+// Reconcile devuelve los ítems (con stock propio) e ingredientes cuyo stock no coincide con la
+// suma de sus movimientos en el libro
+func (r *stockMovementRepository) Reconcile() ([]models.StockReconciliationEntry, error) {
+    rows, err := r.db.Query(`
+        SELECT mi.id, NULL::INTEGER, mi.item_name, mi.stock::NUMERIC, COALESCE(SUM(sm.quantity), 0)
+        FROM menu_items mi
+        LEFT JOIN stock_movements sm ON sm.menu_item_id = mi.id
+        WHERE mi.stock_mode = 'item'
+        GROUP BY mi.id, mi.item_name, mi.stock
+        HAVING mi.stock <> COALESCE(SUM(sm.quantity), 0)
+        UNION ALL
+        SELECT NULL::INTEGER, i.id, i.ingredient_name, i.stock, COALESCE(SUM(sm.quantity), 0)
+        FROM ingredients i
+        LEFT JOIN stock_movements sm ON sm.ingredient_id = i.id
+        GROUP BY i.id, i.ingredient_name, i.stock
+        HAVING i.stock <> COALESCE(SUM(sm.quantity), 0)
+        ORDER BY 3`)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to reconcile stock")
+    }
+    defer rows.Close()
+
+    entries := []models.StockReconciliationEntry{}
+    for rows.Next() {
+        var entry models.StockReconciliationEntry
+        var menuItemID, ingredientID sql.NullInt64
+        if err := rows.Scan(&menuItemID, &ingredientID, &entry.Name, &entry.Stock, &entry.LedgerStock); err != nil {
+            return nil, errors.Wrap(err, "failed to scan reconciliation entry")
+        }
+        entry.MenuItemID = nullIntToPtr(menuItemID)
+        entry.IngredientID = nullIntToPtr(ingredientID)
+        entry.Difference = entry.Stock.Sub(entry.LedgerStock)
+        entries = append(entries, entry)
+    }
+    return entries, nil
+}
+
+func scanStockMovements(rows *sql.Rows) ([]models.StockMovement, error) {
+    var movements []models.StockMovement
+    for rows.Next() {
+        var movement models.StockMovement
+        var menuItemID, ingredientID, employeeID, orderDetailID, purchaseOrderLineID, inventoryCountID sql.NullInt64
+        var reason sql.NullString
+        var unitCost decimal.NullDecimal
+
+        if err := rows.Scan(&movement.ID, &menuItemID, &ingredientID, &movement.MovementType, &movement.Quantity, &employeeID, &reason, &orderDetailID, &purchaseOrderLineID, &unitCost, &inventoryCountID, &movement.CreatedAt); err != nil {
+            return nil, errors.Wrap(err, "failed to scan stock movement")
+        }
+
+        movement.MenuItemID = nullIntToPtr(menuItemID)
+        movement.IngredientID = nullIntToPtr(ingredientID)
+        movement.EmployeeID = nullIntToPtr(employeeID)
+        movement.OrderDetailID = nullIntToPtr(orderDetailID)
+        movement.PurchaseOrderLineID = nullIntToPtr(purchaseOrderLineID)
+        movement.UnitCost = nullDecimalToPtr(unitCost)
+        movement.InventoryCountID = nullIntToPtr(inventoryCountID)
+        movement.Reason = reason.String
+        movements = append(movements, movement)
+    }
+    return movements, nil
+}
@@ -0,0 +1,145 @@
+package repositories
+
+import (
+    "database/sql"
+
+    "gastrobar-backend/internal/models"
+
+    "github.com/pkg/errors"
+)
+
+type SupplierRepository interface {
+    FindByID(supplierID int) (models.Supplier, error)
+    FindByNIT(nit string) (models.Supplier, error)
+    FindAll() ([]models.Supplier, error)
+    Create(supplier models.Supplier) (models.Supplier, error)
+    Update(supplier models.Supplier) (models.Supplier, error)
+    Delete(supplierID int) error
+}
+
+type supplierRepository struct {
+    db *sql.DB
+}
+
+func NewSupplierRepository(db *sql.DB) SupplierRepository {
+    return &supplierRepository{db: db}
+}
+
+func (r *supplierRepository) FindByID(supplierID int) (models.Supplier, error) {
+    var supplier models.Supplier
+    err := r.db.QueryRow(`
+        SELECT id, supplier_name, nit, COALESCE(contact_name, ''), COALESCE(phone_number, ''), COALESCE(email, ''), created_at
+        FROM suppliers
+        WHERE id = $1`,
+        supplierID,
+    ).Scan(&supplier.ID, &supplier.SupplierName, &supplier.NIT, &supplier.ContactName, &supplier.PhoneNumber, &supplier.Email, &supplier.CreatedAt)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return models.Supplier{}, errors.Wrap(err, "supplier not found")
+        }
+        return models.Supplier{}, errors.Wrap(err, "failed to query supplier by ID")
+    }
+    return supplier, nil
+}
+
+func (r *supplierRepository) FindByNIT(nit string) (models.Supplier, error) {
+    var supplier models.Supplier
+    err := r.db.QueryRow(`
+        SELECT id, supplier_name, nit, COALESCE(contact_name, ''), COALESCE(phone_number, ''), COALESCE(email, ''), created_at
+        FROM suppliers
+        WHERE nit = $1`,
+        nit,
+    ).Scan(&supplier.ID, &supplier.SupplierName, &supplier.NIT, &supplier.ContactName, &supplier.PhoneNumber, &supplier.Email, &supplier.CreatedAt)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return models.Supplier{}, errors.Wrap(err, "supplier not found")
+        }
+        return models.Supplier{}, errors.Wrap(err, "failed to query supplier by NIT")
+    }
+    return supplier, nil
+}
+
+func (r *supplierRepository) FindAll() ([]models.Supplier, error) {
+    rows, err := r.db.Query(`
+        SELECT id, supplier_name, nit, COALESCE(contact_name, ''), COALESCE(phone_number, ''), COALESCE(email, ''), created_at
+        FROM suppliers
+        ORDER BY supplier_name`)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query suppliers")
+    }
+    defer rows.Close()
+
+    var suppliers []models.Supplier
+    for rows.Next() {
+        var supplier models.Supplier
+        if err := rows.Scan(&supplier.ID, &supplier.SupplierName, &supplier.NIT, &supplier.ContactName, &supplier.PhoneNumber, &supplier.Email, &supplier.CreatedAt); err != nil {
+            return nil, errors.Wrap(err, "failed to scan supplier")
+        }
+        suppliers = append(suppliers, supplier)
+    }
+    return suppliers, nil
+}
+
+func (r *supplierRepository) Create(supplier models.Supplier) (models.Supplier, error) {
+    var created models.Supplier
+    err := r.db.QueryRow(`
+        INSERT INTO suppliers (supplier_name, nit, contact_name, phone_number, email, created_at)
+        VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP)
+        RETURNING id, supplier_name, nit, contact_name, phone_number, email, created_at`,
+        supplier.SupplierName, supplier.NIT, supplier.ContactName, supplier.PhoneNumber, supplier.Email,
+    ).Scan(&created.ID, &created.SupplierName, &created.NIT, &created.ContactName, &created.PhoneNumber, &created.Email, &created.CreatedAt)
+    if err != nil {
+        return models.Supplier{}, errors.Wrap(err, "failed to create supplier")
+    }
+    return created, nil
+}
+
+func (r *supplierRepository) Update(supplier models.Supplier) (models.Supplier, error) {
+    var updated models.Supplier
+    err := r.db.QueryRow(`
+        UPDATE suppliers
+        SET supplier_name = $1, nit = $2, contact_name = $3, phone_number = $4, email = $5
+        WHERE id = $6
+        RETURNING id, supplier_name, nit, contact_name, phone_number, email, created_at`,
+        supplier.SupplierName, supplier.NIT, supplier.ContactName, supplier.PhoneNumber, supplier.Email, supplier.ID,
+    ).Scan(&updated.ID, &updated.SupplierName, &updated.NIT, &updated.ContactName, &updated.PhoneNumber, &updated.Email, &updated.CreatedAt)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return models.Supplier{}, errors.Wrap(err, "supplier not found")
+        }
+        return models.Supplier{}, errors.Wrap(err, "failed to update supplier")
+    }
+    return updated, nil
+}
+
+func (r *supplierRepository) Delete(supplierID int) error {
+    // No se puede eliminar un proveedor con órdenes de compra (se conserva la trazabilidad)
+    var hasOrders bool
+    err := r.db.QueryRow(`
+        SELECT EXISTS (SELECT 1 FROM purchase_orders WHERE supplier_id = $1)`,
+        supplierID,
+    ).Scan(&hasOrders)
+    if err != nil {
+        return errors.Wrap(err, "failed to check supplier purchase orders")
+    }
+    if hasOrders {
+        return errors.New("supplier has purchase orders")
+    }
+
+    result, err := r.db.Exec(`
+        DELETE FROM suppliers
+        WHERE id = $1`,
+        supplierID,
+    )
+    if err != nil {
+        return errors.Wrap(err, "failed to delete supplier")
+    }
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return errors.Wrap(err, "failed to check rows affected")
+    }
+    if rowsAffected == 0 {
+        return errors.New("supplier not found")
+    }
+    return nil
+}
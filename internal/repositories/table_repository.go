@@ -11,9 +11,13 @@ type TableRepository interface {
     FindByID(tableID int) (models.Table, error)
     FindByName(tableName string) (models.Table, error)
     FindAll() ([]models.Table, error)
-    Count() (int, error)
+    FindAllWithStatus() ([]models.Table, error)
+    CountActive() (int, error)
     Create(table models.Table) (models.Table, error)
     Update(table models.Table) (models.Table, error)
+    IncrementQRVersion(tableID int) (models.Table, error)
+    SetActive(tableID int, active bool) (models.Table, error)
+    Delete(tableID int) error
 }
 
 type tableRepository struct {
@@ -27,11 +31,11 @@ func NewTableRepository(db *sql.DB) TableRepository {
 func (r *tableRepository) FindByID(tableID int) (models.Table, error) {
     var table models.Table
     err := r.db.QueryRow(`
-        SELECT id, table_name, created_at
+        SELECT id, table_name, capacity, zone, pos_x, pos_y, qr_version, active, created_at
         FROM tables
         WHERE id = $1`,
         tableID,
-    ).Scan(&table.ID, &table.TableName, &table.CreatedAt)
+    ).Scan(&table.ID, &table.TableName, &table.Capacity, &table.Zone, &table.PosX, &table.PosY, &table.QRVersion, &table.Active, &table.CreatedAt)
     if err != nil {
         if err == sql.ErrNoRows {
             return models.Table{}, errors.Wrap(err, "table not found")
@@ -44,11 +48,11 @@ func (r *tableRepository) FindByID(tableID int) (models.Table, error) {
 func (r *tableRepository) FindByName(tableName string) (models.Table, error) {
     var table models.Table
     err := r.db.QueryRow(`
-        SELECT id, table_name, created_at
+        SELECT id, table_name, capacity, zone, pos_x, pos_y, qr_version, active, created_at
         FROM tables
         WHERE table_name = $1`,
         tableName,
-    ).Scan(&table.ID, &table.TableName, &table.CreatedAt)
+    ).Scan(&table.ID, &table.TableName, &table.Capacity, &table.Zone, &table.PosX, &table.PosY, &table.QRVersion, &table.Active, &table.CreatedAt)
     if err != nil {
         if err == sql.ErrNoRows {
             return models.Table{}, errors.Wrap(err, "table not found")
@@ -60,7 +64,7 @@ func (r *tableRepository) FindByName(tableName string) (models.Table, error) {
 
 func (r *tableRepository) FindAll() ([]models.Table, error) {
     rows, err := r.db.Query(`
-        SELECT id, table_name, created_at
+        SELECT id, table_name, capacity, zone, pos_x, pos_y, qr_version, active, created_at
         FROM tables`)
     if err != nil {
         return nil, errors.Wrap(err, "failed to query tables")
@@ -70,7 +74,7 @@ func (r *tableRepository) FindAll() ([]models.Table, error) {
     var tables []models.Table
     for rows.Next() {
         var table models.Table
-        if err := rows.Scan(&table.ID, &table.TableName, &table.CreatedAt); err != nil {
+        if err := rows.Scan(&table.ID, &table.TableName, &table.Capacity, &table.Zone, &table.PosX, &table.PosY, &table.QRVersion, &table.Active, &table.CreatedAt); err != nil {
             return nil, errors.Wrap(err, "failed to scan table")
         }
         tables = append(tables, table)
@@ -78,14 +82,60 @@ func (r *tableRepository) FindAll() ([]models.Table, error) {
     return tables, nil
 }
 
-func (r *tableRepository) Count() (int, error) {
+// FindAllWithStatus devuelve las mesas con su estado en vivo, derivado del pedido pendiente:
+// sin pedido está libre, con pedido está ocupada y si además se pidió la cuenta espera el pago.
+// Una mesa sin pedido queda reservada desde una hora antes de una reserva hasta que esta termina
+func (r *tableRepository) FindAllWithStatus() ([]models.Table, error) {
+    rows, err := r.db.Query(`
+        SELECT t.id, t.table_name, t.capacity, t.zone, t.pos_x, t.pos_y, t.qr_version, t.active, t.created_at,
+               co.id,
+               CASE
+                   WHEN co.id IS NOT NULL AND co.bill_requested_at IS NOT NULL THEN 'awaiting_payment'
+                   WHEN co.id IS NOT NULL THEN 'occupied'
+                   WHEN EXISTS (
+                       SELECT 1
+                       FROM reservation_tables rt
+                       JOIN reservations rs ON rs.id = rt.reservation_id
+                       WHERE rt.table_id = t.id
+                         AND rs.status = 'booked'
+                         AND rs.reserved_at <= CURRENT_TIMESTAMP + INTERVAL '1 hour'
+                         AND rs.reserved_at + rs.duration_minutes * INTERVAL '1 minute' > CURRENT_TIMESTAMP
+                   ) THEN 'reserved'
+                   ELSE 'free'
+               END
+        FROM tables t
+        LEFT JOIN customer_orders co ON co.table_id = t.id AND co.status = 'pending'
+        ORDER BY t.id`)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query tables with status")
+    }
+    defer rows.Close()
+
+    var tables []models.Table
+    for rows.Next() {
+        var table models.Table
+        var openOrderID sql.NullInt64
+        var status models.TableStatus
+        if err := rows.Scan(&table.ID, &table.TableName, &table.Capacity, &table.Zone, &table.PosX, &table.PosY, &table.QRVersion, &table.Active, &table.CreatedAt, &openOrderID, &status); err != nil {
+            return nil, errors.Wrap(err, "failed to scan table")
+        }
+        table.OpenOrderID = nullIntToPtr(openOrderID)
+        table.Status = &status
+        tables = append(tables, table)
+    }
+    return tables, nil
+}
+
+// CountActive cuenta las mesas activas, que son las que consumen el límite del negocio
+func (r *tableRepository) CountActive() (int, error) {
     var count int
     err := r.db.QueryRow(`
         SELECT COUNT(*)
-        FROM tables`).
+        FROM tables
+        WHERE active = TRUE`).
         Scan(&count)
     if err != nil {
-        return 0, errors.Wrap(err, "failed to count tables")
+        return 0, errors.Wrap(err, "failed to count active tables")
     }
     return count, nil
 }
@@ -93,11 +143,11 @@ func (r *tableRepository) Count() (int, error) {
 func (r *tableRepository) Create(table models.Table) (models.Table, error) {
     var createdTable models.Table
     err := r.db.QueryRow(`
-        INSERT INTO tables (table_name, created_at)
-        VALUES ($1, CURRENT_TIMESTAMP)
-        RETURNING id, table_name, created_at`,
-        table.TableName,
-    ).Scan(&createdTable.ID, &createdTable.TableName, &createdTable.CreatedAt)
+        INSERT INTO tables (table_name, capacity, zone, pos_x, pos_y, created_at)
+        VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP)
+        RETURNING id, table_name, capacity, zone, pos_x, pos_y, qr_version, active, created_at`,
+        table.TableName, table.Capacity, table.Zone, table.PosX, table.PosY,
+    ).Scan(&createdTable.ID, &createdTable.TableName, &createdTable.Capacity, &createdTable.Zone, &createdTable.PosX, &createdTable.PosY, &createdTable.QRVersion, &createdTable.Active, &createdTable.CreatedAt)
     if err != nil {
         return models.Table{}, errors.Wrap(err, "failed to create table")
     }
@@ -108,11 +158,11 @@ func (r *tableRepository) Update(table models.Table) (models.Table, error) {
     var updatedTable models.Table
     err := r.db.QueryRow(`
         UPDATE tables
-        SET table_name = $1
-        WHERE id = $2
-        RETURNING id, table_name, created_at`,
-        table.TableName, table.ID,
-    ).Scan(&updatedTable.ID, &updatedTable.TableName, &updatedTable.CreatedAt)
+        SET table_name = $1, capacity = $2, zone = $3, pos_x = $4, pos_y = $5
+        WHERE id = $6
+        RETURNING id, table_name, capacity, zone, pos_x, pos_y, qr_version, active, created_at`,
+        table.TableName, table.Capacity, table.Zone, table.PosX, table.PosY, table.ID,
+    ).Scan(&updatedTable.ID, &updatedTable.TableName, &updatedTable.Capacity, &updatedTable.Zone, &updatedTable.PosX, &updatedTable.PosY, &updatedTable.QRVersion, &updatedTable.Active, &updatedTable.CreatedAt)
     if err != nil {
         if err == sql.ErrNoRows {
             return models.Table{}, errors.Wrap(err, "table not found")
@@ -120,4 +170,74 @@ func (r *tableRepository) Update(table models.Table) (models.Table, error) {
         return models.Table{}, errors.Wrap(err, "failed to update table")
     }
     return updatedTable, nil
-}
\ No newline at end of file
+}
+
+func (r *tableRepository) IncrementQRVersion(tableID int) (models.Table, error) {
+    var updatedTable models.Table
+    err := r.db.QueryRow(`
+        UPDATE tables
+        SET qr_version = qr_version + 1
+        WHERE id = $1
+        RETURNING id, table_name, capacity, zone, pos_x, pos_y, qr_version, active, created_at`,
+        tableID,
+    ).Scan(&updatedTable.ID, &updatedTable.TableName, &updatedTable.Capacity, &updatedTable.Zone, &updatedTable.PosX, &updatedTable.PosY, &updatedTable.QRVersion, &updatedTable.Active, &updatedTable.CreatedAt)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return models.Table{}, errors.Wrap(err, "table not found")
+        }
+        return models.Table{}, errors.Wrap(err, "failed to rotate table QR version")
+    }
+    return updatedTable, nil
+}
+
+func (r *tableRepository) SetActive(tableID int, active bool) (models.Table, error) {
+    var updatedTable models.Table
+    err := r.db.QueryRow(`
+        UPDATE tables
+        SET active = $1
+        WHERE id = $2
+        RETURNING id, table_name, capacity, zone, pos_x, pos_y, qr_version, active, created_at`,
+        active, tableID,
+    ).Scan(&updatedTable.ID, &updatedTable.TableName, &updatedTable.Capacity, &updatedTable.Zone, &updatedTable.PosX, &updatedTable.PosY, &updatedTable.QRVersion, &updatedTable.Active, &updatedTable.CreatedAt)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return models.Table{}, errors.Wrap(err, "table not found")
+        }
+        return models.Table{}, errors.Wrap(err, "failed to update table active flag")
+    }
+    return updatedTable, nil
+}
+
+func (r *tableRepository) Delete(tableID int) error {
+    // No se puede eliminar una mesa con pedidos o reservas: el ON DELETE CASCADE borraría el
+    // historial de ventas
+    var hasOrders bool
+    err := r.db.QueryRow(`
+        SELECT EXISTS (SELECT 1 FROM customer_orders WHERE table_id = $1)
+            OR EXISTS (SELECT 1 FROM reservation_tables WHERE table_id = $1)`,
+        tableID,
+    ).Scan(&hasOrders)
+    if err != nil {
+        return errors.Wrap(err, "failed to check table orders")
+    }
+    if hasOrders {
+        return errors.New("table has order or reservation history")
+    }
+
+    result, err := r.db.Exec(`
+        DELETE FROM tables
+        WHERE id = $1`,
+        tableID,
+    )
+    if err != nil {
+        return errors.Wrap(err, "failed to delete table")
+    }
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return errors.Wrap(err, "failed to check rows affected")
+    }
+    if rowsAffected == 0 {
+        return errors.New("table not found")
+    }
+    return nil
+}
@@ -0,0 +1,473 @@
+package repositories
+
+import (
+    "database/sql"
+    "time"
+
+    "gastrobar-backend/internal/models"
+
+    "github.com/lib/pq"
+    "github.com/pkg/errors"
+)
+
+type TimeClockRepository interface {
+    FindEntryByID(entryID int) (models.TimeEntry, error)
+    FindOpenEntry(employeeID int) (models.TimeEntry, error)
+    FindOpenEntries() ([]models.TimeEntry, error)
+    FindEntries(employeeID int, from, to time.Time) ([]models.TimeEntry, error)
+    ClockIn(employeeID int) (models.TimeEntry, error)
+    ClockOut(employeeID int) (models.TimeEntry, error)
+    StartBreak(employeeID int) (models.TimeEntry, error)
+    EndBreak(employeeID int) (models.TimeEntry, error)
+    CreateEntry(entry models.TimeEntry) (models.TimeEntry, error)
+    AdjustEntry(entry models.TimeEntry) (models.TimeEntry, error)
+}
+
+type timeClockRepository struct {
+    db *sql.DB
+}
+
+func NewTimeClockRepository(db *sql.DB) TimeClockRepository {
+    return &timeClockRepository{db: db}
+}
+
+func (r *timeClockRepository) FindEntryByID(entryID int) (models.TimeEntry, error) {
+    entries, err := findTimeEntries(r.db, `
+        SELECT id, employee_id, clock_in_at, clock_out_at, adjusted_by, adjusted_at, created_at
+        FROM time_entries
+        WHERE id = $1`,
+        entryID,
+    )
+    if err != nil {
+        return models.TimeEntry{}, err
+    }
+    if len(entries) == 0 {
+        return models.TimeEntry{}, errors.New("time entry not found")
+    }
+    return entries[0], nil
+}
+
+// FindOpenEntry devuelve la jornada abierta del empleado (a lo sumo hay una)
+func (r *timeClockRepository) FindOpenEntry(employeeID int) (models.TimeEntry, error) {
+    entries, err := findTimeEntries(r.db, `
+        SELECT id, employee_id, clock_in_at, clock_out_at, adjusted_by, adjusted_at, created_at
+        FROM time_entries
+        WHERE employee_id = $1 AND clock_out_at IS NULL`,
+        employeeID,
+    )
+    if err != nil {
+        return models.TimeEntry{}, err
+    }
+    if len(entries) == 0 {
+        return models.TimeEntry{}, errors.New("no open time entry")
+    }
+    return entries[0], nil
+}
+
+// FindOpenEntries devuelve las jornadas abiertas de todos los empleados, de la más antigua a la más reciente
+func (r *timeClockRepository) FindOpenEntries() ([]models.TimeEntry, error) {
+    return findTimeEntries(r.db, `
+        SELECT id, employee_id, clock_in_at, clock_out_at, adjusted_by, adjusted_at, created_at
+        FROM time_entries
+        WHERE clock_out_at IS NULL
+        ORDER BY clock_in_at, id`)
+}
+
+// FindEntries devuelve las jornadas que se cruzan con [from, to), opcionalmente de un solo empleado
+// (employeeID 0 devuelve todas); las jornadas abiertas se consideran en curso hasta ahora
+func (r *timeClockRepository) FindEntries(employeeID int, from, to time.Time) ([]models.TimeEntry, error) {
+    return findTimeEntries(r.db, `
+        SELECT id, employee_id, clock_in_at, clock_out_at, adjusted_by, adjusted_at, created_at
+        FROM time_entries
+        WHERE clock_in_at < $2
+          AND COALESCE(clock_out_at, CURRENT_TIMESTAMP) > $1
+          AND ($3 = 0 OR employee_id = $3)
+        ORDER BY clock_in_at, id`,
+        from, to, employeeID,
+    )
+}
+
+// ClockIn abre una jornada para el empleado; no puede tener otra jornada abierta
+func (r *timeClockRepository) ClockIn(employeeID int) (models.TimeEntry, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.TimeEntry{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    if err := lockEmployee(tx, employeeID); err != nil {
+        return models.TimeEntry{}, err
+    }
+    if _, err := findOpenEntryID(tx, employeeID); err == nil {
+        return models.TimeEntry{}, errors.New("employee is already clocked in")
+    } else if errors.Cause(err) != sql.ErrNoRows {
+        return models.TimeEntry{}, err
+    }
+
+    var entryID int
+    err = tx.QueryRow(`
+        INSERT INTO time_entries (employee_id, clock_in_at, created_at)
+        VALUES ($1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+        RETURNING id`,
+        employeeID,
+    ).Scan(&entryID)
+    if err != nil {
+        return models.TimeEntry{}, errors.Wrap(err, "failed to clock in")
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.TimeEntry{}, errors.Wrap(err, "failed to commit transaction")
+    }
+    return r.FindEntryByID(entryID)
+}
+
+// ClockOut cierra la jornada abierta del empleado; si estaba en pausa, la pausa termina con la salida
+func (r *timeClockRepository) ClockOut(employeeID int) (models.TimeEntry, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.TimeEntry{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    if err := lockEmployee(tx, employeeID); err != nil {
+        return models.TimeEntry{}, err
+    }
+    entryID, err := findOpenEntryID(tx, employeeID)
+    if err != nil {
+        if errors.Cause(err) == sql.ErrNoRows {
+            return models.TimeEntry{}, errors.New("employee is not clocked in")
+        }
+        return models.TimeEntry{}, err
+    }
+
+    if _, err := tx.Exec(`
+        UPDATE time_entry_breaks
+        SET ended_at = CURRENT_TIMESTAMP
+        WHERE time_entry_id = $1 AND ended_at IS NULL`,
+        entryID,
+    ); err != nil {
+        return models.TimeEntry{}, errors.Wrap(err, "failed to end break")
+    }
+    if _, err := tx.Exec(`
+        UPDATE time_entries
+        SET clock_out_at = CURRENT_TIMESTAMP
+        WHERE id = $1`,
+        entryID,
+    ); err != nil {
+        return models.TimeEntry{}, errors.Wrap(err, "failed to clock out")
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.TimeEntry{}, errors.Wrap(err, "failed to commit transaction")
+    }
+    return r.FindEntryByID(entryID)
+}
+
+// StartBreak inicia una pausa en la jornada abierta del empleado
+func (r *timeClockRepository) StartBreak(employeeID int) (models.TimeEntry, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.TimeEntry{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    if err := lockEmployee(tx, employeeID); err != nil {
+        return models.TimeEntry{}, err
+    }
+    entryID, err := findOpenEntryID(tx, employeeID)
+    if err != nil {
+        if errors.Cause(err) == sql.ErrNoRows {
+            return models.TimeEntry{}, errors.New("employee is not clocked in")
+        }
+        return models.TimeEntry{}, err
+    }
+
+    var onBreak bool
+    err = tx.QueryRow(`
+        SELECT EXISTS (SELECT 1 FROM time_entry_breaks WHERE time_entry_id = $1 AND ended_at IS NULL)`,
+        entryID,
+    ).Scan(&onBreak)
+    if err != nil {
+        return models.TimeEntry{}, errors.Wrap(err, "failed to check break in progress")
+    }
+    if onBreak {
+        return models.TimeEntry{}, errors.New("break already in progress")
+    }
+
+    if _, err := tx.Exec(`
+        INSERT INTO time_entry_breaks (time_entry_id, started_at)
+        VALUES ($1, CURRENT_TIMESTAMP)`,
+        entryID,
+    ); err != nil {
+        return models.TimeEntry{}, errors.Wrap(err, "failed to start break")
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.TimeEntry{}, errors.Wrap(err, "failed to commit transaction")
+    }
+    return r.FindEntryByID(entryID)
+}
+
+// EndBreak termina la pausa en curso de la jornada abierta del empleado
+func (r *timeClockRepository) EndBreak(employeeID int) (models.TimeEntry, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.TimeEntry{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    if err := lockEmployee(tx, employeeID); err != nil {
+        return models.TimeEntry{}, err
+    }
+    entryID, err := findOpenEntryID(tx, employeeID)
+    if err != nil {
+        if errors.Cause(err) == sql.ErrNoRows {
+            return models.TimeEntry{}, errors.New("employee is not clocked in")
+        }
+        return models.TimeEntry{}, err
+    }
+
+    result, err := tx.Exec(`
+        UPDATE time_entry_breaks
+        SET ended_at = CURRENT_TIMESTAMP
+        WHERE time_entry_id = $1 AND ended_at IS NULL`,
+        entryID,
+    )
+    if err != nil {
+        return models.TimeEntry{}, errors.Wrap(err, "failed to end break")
+    }
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return models.TimeEntry{}, errors.Wrap(err, "failed to check rows affected")
+    }
+    if rowsAffected == 0 {
+        return models.TimeEntry{}, errors.New("no break in progress")
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.TimeEntry{}, errors.Wrap(err, "failed to commit transaction")
+    }
+    return r.FindEntryByID(entryID)
+}
+
+// CreateEntry registra a mano una jornada completa (por ejemplo cuando el empleado olvidó marcar la
+// entrada); no puede cruzarse con otra jornada del empleado
+func (r *timeClockRepository) CreateEntry(entry models.TimeEntry) (models.TimeEntry, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.TimeEntry{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    if err := lockEmployee(tx, entry.EmployeeID); err != nil {
+        return models.TimeEntry{}, err
+    }
+    if err := checkTimeEntryOverlap(tx, entry); err != nil {
+        return models.TimeEntry{}, err
+    }
+
+    var entryID int
+    err = tx.QueryRow(`
+        INSERT INTO time_entries (employee_id, clock_in_at, clock_out_at, adjusted_by, adjusted_at, created_at)
+        VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+        RETURNING id`,
+        entry.EmployeeID, entry.ClockInAt, entry.ClockOutAt, entry.AdjustedBy,
+    ).Scan(&entryID)
+    if err != nil {
+        return models.TimeEntry{}, errors.Wrap(err, "failed to create time entry")
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.TimeEntry{}, errors.Wrap(err, "failed to commit transaction")
+    }
+    return r.FindEntryByID(entryID)
+}
+
+// AdjustEntry corrige la entrada y la salida de una jornada (por ejemplo una salida no marcada). Las
+// pausas deben quedar dentro de la jornada corregida; una pausa en curso termina con la salida
+func (r *timeClockRepository) AdjustEntry(entry models.TimeEntry) (models.TimeEntry, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.TimeEntry{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    err = tx.QueryRow(`
+        SELECT employee_id
+        FROM time_entries
+        WHERE id = $1`,
+        entry.ID,
+    ).Scan(&entry.EmployeeID)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return models.TimeEntry{}, errors.Wrap(err, "time entry not found")
+        }
+        return models.TimeEntry{}, errors.Wrap(err, "failed to query time entry")
+    }
+    if err := lockEmployee(tx, entry.EmployeeID); err != nil {
+        return models.TimeEntry{}, err
+    }
+    if err := checkTimeEntryOverlap(tx, entry); err != nil {
+        return models.TimeEntry{}, err
+    }
+
+    if _, err := tx.Exec(`
+        UPDATE time_entry_breaks
+        SET ended_at = $2
+        WHERE time_entry_id = $1 AND ended_at IS NULL`,
+        entry.ID, entry.ClockOutAt,
+    ); err != nil {
+        return models.TimeEntry{}, errors.Wrap(err, "failed to end break")
+    }
+
+    var breaksOutside bool
+    err = tx.QueryRow(`
+        SELECT EXISTS (
+            SELECT 1
+            FROM time_entry_breaks
+            WHERE time_entry_id = $1
+              AND (started_at < $2 OR ended_at > $3)
+        )`,
+        entry.ID, entry.ClockInAt, entry.ClockOutAt,
+    ).Scan(&breaksOutside)
+    if err != nil {
+        return models.TimeEntry{}, errors.Wrap(err, "failed to check breaks")
+    }
+    if breaksOutside {
+        return models.TimeEntry{}, errors.New("breaks fall outside the adjusted entry")
+    }
+
+    if _, err := tx.Exec(`
+        UPDATE time_entries
+        SET clock_in_at = $1, clock_out_at = $2, adjusted_by = $3, adjusted_at = CURRENT_TIMESTAMP
+        WHERE id = $4`,
+        entry.ClockInAt, entry.ClockOutAt, entry.AdjustedBy, entry.ID,
+    ); err != nil {
+        return models.TimeEntry{}, errors.Wrap(err, "failed to adjust time entry")
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.TimeEntry{}, errors.Wrap(err, "failed to commit transaction")
+    }
+    return r.FindEntryByID(entry.ID)
+}
+
+// lockEmployee bloquea al empleado para serializar las operaciones sobre sus turnos y jornadas
+func lockEmployee(tx *sql.Tx, employeeID int) error {
+    var id int
+    err := tx.QueryRow(`
+        SELECT id
+        FROM employees
+        WHERE id = $1
+        FOR UPDATE`,
+        employeeID,
+    ).Scan(&id)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return errors.Wrap(err, "employee not found")
+        }
+        return errors.Wrap(err, "failed to lock employee")
+    }
+    return nil
+}
+
+// findOpenEntryID devuelve la jornada abierta del empleado; sql.ErrNoRows (envuelto) si no tiene
+func findOpenEntryID(tx *sql.Tx, employeeID int) (int, error) {
+    var entryID int
+    err := tx.QueryRow(`
+        SELECT id
+        FROM time_entries
+        WHERE employee_id = $1 AND clock_out_at IS NULL
+        FOR UPDATE`,
+        employeeID,
+    ).Scan(&entryID)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return 0, errors.Wrap(err, "no open time entry")
+        }
+        return 0, errors.Wrap(err, "failed to query open time entry")
+    }
+    return entryID, nil
+}
+
+// checkTimeEntryOverlap verifica que la jornada no se cruce con otra jornada del empleado (las
+// jornadas abiertas se consideran sin fin)
+func checkTimeEntryOverlap(tx *sql.Tx, entry models.TimeEntry) error {
+    var overlapping bool
+    err := tx.QueryRow(`
+        SELECT EXISTS (
+            SELECT 1
+            FROM time_entries
+            WHERE employee_id = $1
+              AND id <> $2
+              AND clock_in_at < $4
+              AND COALESCE(clock_out_at, 'infinity') > $3
+        )`,
+        entry.EmployeeID, entry.ID, entry.ClockInAt, entry.ClockOutAt,
+    ).Scan(&overlapping)
+    if err != nil {
+        return errors.Wrap(err, "failed to check overlapping time entries")
+    }
+    if overlapping {
+        return errors.New("time entry overlaps another entry of the employee")
+    }
+    return nil
+}
+
+// findTimeEntries ejecuta la consulta de jornadas y carga las pausas de cada una
+func findTimeEntries(q queryer, query string, args ...interface{}) ([]models.TimeEntry, error) {
+    rows, err := q.Query(query, args...)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query time entries")
+    }
+    defer rows.Close()
+
+    entries := []models.TimeEntry{}
+    index := make(map[int]int)
+    var entryIDs []int64
+    for rows.Next() {
+        var entry models.TimeEntry
+        var clockOutAt, adjustedAt sql.NullTime
+        var adjustedBy sql.NullInt64
+        if err := rows.Scan(&entry.ID, &entry.EmployeeID, &entry.ClockInAt, &clockOutAt, &adjustedBy, &adjustedAt, &entry.CreatedAt); err != nil {
+            return nil, errors.Wrap(err, "failed to scan time entry")
+        }
+        entry.ClockOutAt = nullTimeToPtr(clockOutAt)
+        entry.AdjustedBy = nullIntToPtr(adjustedBy)
+        entry.AdjustedAt = nullTimeToPtr(adjustedAt)
+        entry.Breaks = []models.TimeEntryBreak{}
+        index[entry.ID] = len(entries)
+        entryIDs = append(entryIDs, int64(entry.ID))
+        entries = append(entries, entry)
+    }
+    if err := rows.Err(); err != nil {
+        return nil, errors.Wrap(err, "failed to read time entries")
+    }
+    if len(entries) == 0 {
+        return entries, nil
+    }
+
+    breakRows, err := q.Query(`
+        SELECT id, time_entry_id, started_at, ended_at
+        FROM time_entry_breaks
+        WHERE time_entry_id = ANY($1)
+        ORDER BY started_at, id`,
+        pq.Array(entryIDs),
+    )
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query time entry breaks")
+    }
+    defer breakRows.Close()
+
+    for breakRows.Next() {
+        var b models.TimeEntryBreak
+        var endedAt sql.NullTime
+        if err := breakRows.Scan(&b.ID, &b.TimeEntryID, &b.StartedAt, &endedAt); err != nil {
+            return nil, errors.Wrap(err, "failed to scan time entry break")
+        }
+        b.EndedAt = nullTimeToPtr(endedAt)
+        i := index[b.TimeEntryID]
+        entries[i].Breaks = append(entries[i].Breaks, b)
+    }
+    return entries, nil
+}
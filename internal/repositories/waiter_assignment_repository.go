@@ -0,0 +1,279 @@
+package repositories
+
+import (
+    "database/sql"
+    "sort"
+    "time"
+
+    "gastrobar-backend/internal/models"
+
+    "github.com/lib/pq"
+    "github.com/pkg/errors"
+)
+
+type WaiterAssignmentRepository interface {
+    FindByID(assignmentID int) (models.WaiterAssignment, error)
+    FindAll(from, to *time.Time, employeeID int) ([]models.WaiterAssignment, error)
+    Create(assignment models.WaiterAssignment) (models.WaiterAssignment, error)
+    Delete(assignmentID int) error
+    FindTablesForEmployee(employeeID int, at time.Time) ([]models.Table, error)
+    FindOpenOrdersForEmployee(employeeID int, at time.Time) ([]models.CustomerOrder, error)
+}
+
+type waiterAssignmentRepository struct {
+    db *sql.DB
+}
+
+func NewWaiterAssignmentRepository(db *sql.DB) WaiterAssignmentRepository {
+    return &waiterAssignmentRepository{db: db}
+}
+
+func (r *waiterAssignmentRepository) FindByID(assignmentID int) (models.WaiterAssignment, error) {
+    rows, err := r.db.Query(`
+        SELECT wa.id, wa.employee_id, wa.starts_at, wa.ends_at, wa.created_by, wa.created_at,
+               COALESCE(array_agg(wat.table_id ORDER BY wat.table_id) FILTER (WHERE wat.table_id IS NOT NULL), '{}')
+        FROM waiter_assignments wa
+        LEFT JOIN waiter_assignment_tables wat ON wat.assignment_id = wa.id
+        WHERE wa.id = $1
+        GROUP BY wa.id`,
+        assignmentID,
+    )
+    if err != nil {
+        return models.WaiterAssignment{}, errors.Wrap(err, "failed to query waiter assignment by ID")
+    }
+    defer rows.Close()
+
+    assignments, err := scanWaiterAssignments(rows)
+    if err != nil {
+        return models.WaiterAssignment{}, err
+    }
+    if len(assignments) == 0 {
+        return models.WaiterAssignment{}, errors.New("waiter assignment not found")
+    }
+    return assignments[0], nil
+}
+
+// FindAll devuelve las asignaciones que se cruzan con el rango indicado, opcionalmente de un solo
+// empleado (employeeID 0 devuelve todas)
+func (r *waiterAssignmentRepository) FindAll(from, to *time.Time, employeeID int) ([]models.WaiterAssignment, error) {
+    rows, err := r.db.Query(`
+        SELECT wa.id, wa.employee_id, wa.starts_at, wa.ends_at, wa.created_by, wa.created_at,
+               COALESCE(array_agg(wat.table_id ORDER BY wat.table_id) FILTER (WHERE wat.table_id IS NOT NULL), '{}')
+        FROM waiter_assignments wa
+        LEFT JOIN waiter_assignment_tables wat ON wat.assignment_id = wa.id
+        WHERE ($1::TIMESTAMPTZ IS NULL OR wa.ends_at > $1)
+          AND ($2::TIMESTAMPTZ IS NULL OR wa.starts_at < $2)
+          AND ($3 = 0 OR wa.employee_id = $3)
+        GROUP BY wa.id
+        ORDER BY wa.starts_at, wa.id`,
+        from, to, employeeID,
+    )
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query waiter assignments")
+    }
+    defer rows.Close()
+
+    return scanWaiterAssignments(rows)
+}
+
+// Create registra la sección de un mesero. Ninguna de sus mesas puede estar asignada a otro mesero
+// en un horario que se cruce con el nuevo
+func (r *waiterAssignmentRepository) Create(assignment models.WaiterAssignment) (models.WaiterAssignment, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.WaiterAssignment{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    // Bloquear las mesas en orden de id para evitar interbloqueos entre asignaciones simultáneas
+    tableIDs := append([]int(nil), assignment.TableIDs...)
+    sort.Ints(tableIDs)
+    for _, tableID := range tableIDs {
+        var exists bool
+        err := tx.QueryRow(`
+            SELECT TRUE
+            FROM tables
+            WHERE id = $1
+            FOR UPDATE`,
+            tableID,
+        ).Scan(&exists)
+        if err != nil {
+            if err == sql.ErrNoRows {
+                return models.WaiterAssignment{}, errors.Wrapf(err, "table not found: %d", tableID)
+            }
+            return models.WaiterAssignment{}, errors.Wrap(err, "failed to lock table")
+        }
+
+        var assignedTo sql.NullInt64
+        err = tx.QueryRow(`
+            SELECT wa.employee_id
+            FROM waiter_assignment_tables wat
+            JOIN waiter_assignments wa ON wa.id = wat.assignment_id
+            WHERE wat.table_id = $1
+              AND wa.employee_id <> $2
+              AND wa.starts_at < $4
+              AND wa.ends_at > $3
+            LIMIT 1`,
+            tableID, assignment.EmployeeID, assignment.StartsAt, assignment.EndsAt,
+        ).Scan(&assignedTo)
+        if err != nil && err != sql.ErrNoRows {
+            return models.WaiterAssignment{}, errors.Wrap(err, "failed to check overlapping assignments")
+        }
+        if assignedTo.Valid {
+            return models.WaiterAssignment{}, errors.Errorf("table already assigned to another waiter for that time: %d", tableID)
+        }
+    }
+
+    var assignmentID int
+    err = tx.QueryRow(`
+        INSERT INTO waiter_assignments (employee_id, starts_at, ends_at, created_by, created_at)
+        VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
+        RETURNING id`,
+        assignment.EmployeeID, assignment.StartsAt, assignment.EndsAt, assignment.CreatedBy,
+    ).Scan(&assignmentID)
+    if err != nil {
+        return models.WaiterAssignment{}, errors.Wrap(err, "failed to create waiter assignment")
+    }
+
+    for _, tableID := range tableIDs {
+        if _, err := tx.Exec(`
+            INSERT INTO waiter_assignment_tables (assignment_id, table_id)
+            VALUES ($1, $2)`,
+            assignmentID, tableID,
+        ); err != nil {
+            return models.WaiterAssignment{}, errors.Wrap(err, "failed to create waiter assignment table")
+        }
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.WaiterAssignment{}, errors.Wrap(err, "failed to commit transaction")
+    }
+    return r.FindByID(assignmentID)
+}
+
+func (r *waiterAssignmentRepository) Delete(assignmentID int) error {
+    result, err := r.db.Exec(`
+        DELETE FROM waiter_assignments
+        WHERE id = $1`,
+        assignmentID,
+    )
+    if err != nil {
+        return errors.Wrap(err, "failed to delete waiter assignment")
+    }
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return errors.Wrap(err, "failed to check rows affected")
+    }
+    if rowsAffected == 0 {
+        return errors.New("waiter assignment not found")
+    }
+    return nil
+}
+
+// FindTablesForEmployee devuelve las mesas de las secciones asignadas al empleado en el momento at,
+// con el mismo estado en vivo que el plano del local
+func (r *waiterAssignmentRepository) FindTablesForEmployee(employeeID int, at time.Time) ([]models.Table, error) {
+    rows, err := r.db.Query(`
+        SELECT t.id, t.table_name, t.capacity, t.zone, t.pos_x, t.pos_y, t.qr_version, t.active, t.created_at,
+               co.id,
+               CASE
+                   WHEN co.id IS NOT NULL AND co.bill_requested_at IS NOT NULL THEN 'awaiting_payment'
+                   WHEN co.id IS NOT NULL THEN 'occupied'
+                   WHEN EXISTS (
+                       SELECT 1
+                       FROM reservation_tables rt
+                       JOIN reservations rs ON rs.id = rt.reservation_id
+                       WHERE rt.table_id = t.id
+                         AND rs.status = 'booked'
+                         AND rs.reserved_at <= CURRENT_TIMESTAMP + INTERVAL '1 hour'
+                         AND rs.reserved_at + rs.duration_minutes * INTERVAL '1 minute' > CURRENT_TIMESTAMP
+                   ) THEN 'reserved'
+                   ELSE 'free'
+               END
+        FROM tables t
+        LEFT JOIN customer_orders co ON co.table_id = t.id AND co.status = 'pending'
+        WHERE t.id IN (
+            SELECT wat.table_id
+            FROM waiter_assignment_tables wat
+            JOIN waiter_assignments wa ON wa.id = wat.assignment_id
+            WHERE wa.employee_id = $1 AND wa.starts_at <= $2 AND wa.ends_at > $2
+        )
+        ORDER BY t.id`,
+        employeeID, at,
+    )
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query assigned tables")
+    }
+    defer rows.Close()
+
+    tables := []models.Table{}
+    for rows.Next() {
+        var table models.Table
+        var openOrderID sql.NullInt64
+        var status models.TableStatus
+        if err := rows.Scan(&table.ID, &table.TableName, &table.Capacity, &table.Zone, &table.PosX, &table.PosY, &table.QRVersion, &table.Active, &table.CreatedAt, &openOrderID, &status); err != nil {
+            return nil, errors.Wrap(err, "failed to scan table")
+        }
+        table.OpenOrderID = nullIntToPtr(openOrderID)
+        table.Status = &status
+        tables = append(tables, table)
+    }
+    return tables, nil
+}
+
+// FindOpenOrdersForEmployee devuelve los pedidos pendientes que atiende el empleado o que están en
+// alguna de las mesas de su sección en el momento at
+func (r *waiterAssignmentRepository) FindOpenOrdersForEmployee(employeeID int, at time.Time) ([]models.CustomerOrder, error) {
+    rows, err := r.db.Query(`
+        SELECT co.id, co.table_id, co.total_amount, co.status, co.bill_requested_at, co.completed_at, co.opened_by, co.served_by, co.closed_by, co.created_at
+        FROM customer_orders co
+        WHERE co.status = 'pending'
+          AND (co.served_by = $1
+               OR co.table_id IN (
+                   SELECT wat.table_id
+                   FROM waiter_assignment_tables wat
+                   JOIN waiter_assignments wa ON wa.id = wat.assignment_id
+                   WHERE wa.employee_id = $1 AND wa.starts_at <= $2 AND wa.ends_at > $2
+               ))
+        ORDER BY co.created_at, co.id`,
+        employeeID, at,
+    )
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query open orders")
+    }
+    defer rows.Close()
+
+    orders := []models.CustomerOrder{}
+    for rows.Next() {
+        var order models.CustomerOrder
+        var billRequestedAt, completedAt sql.NullTime
+        var openedBy, servedBy, closedBy sql.NullInt64
+        if err := rows.Scan(&order.ID, &order.TableID, &order.TotalAmount, &order.Status, &billRequestedAt, &completedAt, &openedBy, &servedBy, &closedBy, &order.CreatedAt); err != nil {
+            return nil, errors.Wrap(err, "failed to scan customer order")
+        }
+        order.BillRequestedAt = nullTimeToPtr(billRequestedAt)
+        order.CompletedAt = nullTimeToPtr(completedAt)
+        order.OpenedBy = nullIntToPtr(openedBy)
+        order.ServedBy = nullIntToPtr(servedBy)
+        order.ClosedBy = nullIntToPtr(closedBy)
+        orders = append(orders, order)
+    }
+    return orders, nil
+}
+
+func scanWaiterAssignments(rows *sql.Rows) ([]models.WaiterAssignment, error) {
+    assignments := []models.WaiterAssignment{}
+    for rows.Next() {
+        var assignment models.WaiterAssignment
+        var tableIDs []int64
+        if err := rows.Scan(&assignment.ID, &assignment.EmployeeID, &assignment.StartsAt, &assignment.EndsAt, &assignment.CreatedBy, &assignment.CreatedAt, pq.Array(&tableIDs)); err != nil {
+            return nil, errors.Wrap(err, "failed to scan waiter assignment")
+        }
+        assignment.TableIDs = []int{}
+        for _, tableID := range tableIDs {
+            assignment.TableIDs = append(assignment.TableIDs, int(tableID))
+        }
+        assignments = append(assignments, assignment)
+    }
+    return assignments, nil
+}
@@ -0,0 +1,266 @@
+package repositories
+
+import (
+    "database/sql"
+    "time"
+
+    "gastrobar-backend/internal/models"
+
+    "github.com/pkg/errors"
+)
+
+type WaitlistRepository interface {
+    FindByID(entryID int) (models.WaitlistEntry, error)
+    FindWaiting() ([]models.WaitlistEntry, error)
+    Create(entry models.WaitlistEntry) (models.WaitlistEntry, error)
+    FindTableOccupancy() ([]models.TableOccupancy, error)
+    FindAverageSeatingMinutes(since time.Time) (float64, int, error)
+    SeatNext(tableID, employeeID int) (models.WaitlistEntry, error)
+    Leave(entryID int) (models.WaitlistEntry, error)
+}
+
+type waitlistRepository struct {
+    db *sql.DB
+}
+
+func NewWaitlistRepository(db *sql.DB) WaitlistRepository {
+    return &waitlistRepository{db: db}
+}
+
+func (r *waitlistRepository) FindByID(entryID int) (models.WaitlistEntry, error) {
+    rows, err := r.db.Query(`
+        SELECT id, guest_name, guest_phone, party_size, quoted_wait_minutes, status, table_id, customer_order_id, created_by, seated_at, left_at, created_at
+        FROM waitlist_entries
+        WHERE id = $1`,
+        entryID,
+    )
+    if err != nil {
+        return models.WaitlistEntry{}, errors.Wrap(err, "failed to query waitlist entry by ID")
+    }
+    defer rows.Close()
+
+    entries, err := scanWaitlistEntries(rows)
+    if err != nil {
+        return models.WaitlistEntry{}, err
+    }
+    if len(entries) == 0 {
+        return models.WaitlistEntry{}, errors.New("waitlist entry not found")
+    }
+    return entries[0], nil
+}
+
+// FindWaiting devuelve los grupos que siguen esperando, en orden de llegada
+func (r *waitlistRepository) FindWaiting() ([]models.WaitlistEntry, error) {
+    rows, err := r.db.Query(`
+        SELECT id, guest_name, guest_phone, party_size, quoted_wait_minutes, status, table_id, customer_order_id, created_by, seated_at, left_at, created_at
+        FROM waitlist_entries
+        WHERE status = 'waiting'
+        ORDER BY created_at, id`)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query waitlist")
+    }
+    defer rows.Close()
+
+    return scanWaitlistEntries(rows)
+}
+
+func (r *waitlistRepository) Create(entry models.WaitlistEntry) (models.WaitlistEntry, error) {
+    var entryID int
+    err := r.db.QueryRow(`
+        INSERT INTO waitlist_entries (guest_name, guest_phone, party_size, quoted_wait_minutes, status, created_by, created_at)
+        VALUES ($1, NULLIF($2, ''), $3, $4, 'waiting', $5, CURRENT_TIMESTAMP)
+        RETURNING id`,
+        entry.GuestName, entry.GuestPhone, entry.PartySize, entry.QuotedWaitMinutes, entry.CreatedBy,
+    ).Scan(&entryID)
+    if err != nil {
+        return models.WaitlistEntry{}, errors.Wrap(err, "failed to create waitlist entry")
+    }
+    return r.FindByID(entryID)
+}
+
+// FindTableOccupancy devuelve cada mesa activa con la apertura de su pedido pendiente y, si tiene
+// una reserva en la próxima hora o en curso, la hora en que esta termina
+func (r *waitlistRepository) FindTableOccupancy() ([]models.TableOccupancy, error) {
+    rows, err := r.db.Query(`
+        SELECT t.id, t.capacity, co.created_at,
+               (SELECT MAX(rs.reserved_at + rs.duration_minutes * INTERVAL '1 minute')
+                FROM reservation_tables rt
+                JOIN reservations rs ON rs.id = rt.reservation_id
+                WHERE rt.table_id = t.id
+                  AND rs.status = 'booked'
+                  AND rs.reserved_at <= CURRENT_TIMESTAMP + INTERVAL '1 hour'
+                  AND rs.reserved_at + rs.duration_minutes * INTERVAL '1 minute' > CURRENT_TIMESTAMP)
+        FROM tables t
+        LEFT JOIN customer_orders co ON co.table_id = t.id AND co.status = 'pending'
+        WHERE t.active = TRUE
+        ORDER BY t.capacity, t.id`)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to query table occupancy")
+    }
+    defer rows.Close()
+
+    var occupancy []models.TableOccupancy
+    for rows.Next() {
+        var table models.TableOccupancy
+        var occupiedSince, reservedUntil sql.NullTime
+        if err := rows.Scan(&table.TableID, &table.Capacity, &occupiedSince, &reservedUntil); err != nil {
+            return nil, errors.Wrap(err, "failed to scan table occupancy")
+        }
+        table.OccupiedSince = nullTimeToPtr(occupiedSince)
+        table.ReservedUntil = nullTimeToPtr(reservedUntil)
+        occupancy = append(occupancy, table)
+    }
+    return occupancy, nil
+}
+
+// FindAverageSeatingMinutes calcula el tiempo promedio de mesa (de la apertura al cierre del pedido)
+// de los pedidos completados desde since; devuelve también cuántos pedidos se promediaron
+func (r *waitlistRepository) FindAverageSeatingMinutes(since time.Time) (float64, int, error) {
+    var average sql.NullFloat64
+    var samples int
+    err := r.db.QueryRow(`
+        SELECT AVG(EXTRACT(EPOCH FROM (completed_at - created_at)) / 60), COUNT(*)
+        FROM customer_orders
+        WHERE status = 'completed' AND completed_at IS NOT NULL AND completed_at >= $1`,
+        since,
+    ).Scan(&average, &samples)
+    if err != nil {
+        return 0, 0, errors.Wrap(err, "failed to compute average seating duration")
+    }
+    return average.Float64, samples, nil
+}
+
+// SeatNext sienta en la mesa liberada al primer grupo en espera que quepa en ella y le abre un
+// pedido pendiente a nombre del empleado que lo sienta. La mesa debe estar activa, sin pedido
+// pendiente y sin una reserva próxima
+func (r *waitlistRepository) SeatNext(tableID, employeeID int) (models.WaitlistEntry, error) {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return models.WaitlistEntry{}, errors.Wrap(err, "failed to begin transaction")
+    }
+    defer tx.Rollback()
+
+    var capacity int
+    var active, hasPendingOrder, reserved bool
+    err = tx.QueryRow(`
+        SELECT t.capacity, t.active,
+               EXISTS (SELECT 1 FROM customer_orders WHERE table_id = t.id AND status = 'pending'),
+               EXISTS (
+                   SELECT 1
+                   FROM reservation_tables rt
+                   JOIN reservations rs ON rs.id = rt.reservation_id
+                   WHERE rt.table_id = t.id
+                     AND rs.status = 'booked'
+                     AND rs.reserved_at <= CURRENT_TIMESTAMP + INTERVAL '1 hour'
+                     AND rs.reserved_at + rs.duration_minutes * INTERVAL '1 minute' > CURRENT_TIMESTAMP
+               )
+        FROM tables t
+        WHERE t.id = $1
+        FOR UPDATE`,
+        tableID,
+    ).Scan(&capacity, &active, &hasPendingOrder, &reserved)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return models.WaitlistEntry{}, errors.Wrap(err, "table not found")
+        }
+        return models.WaitlistEntry{}, errors.Wrap(err, "failed to lock table")
+    }
+    if !active {
+        return models.WaitlistEntry{}, errors.New("table is inactive")
+    }
+    if hasPendingOrder {
+        return models.WaitlistEntry{}, errors.New("table has a pending order")
+    }
+    if reserved {
+        return models.WaitlistEntry{}, errors.New("table is reserved")
+    }
+
+    var entryID int
+    err = tx.QueryRow(`
+        SELECT id
+        FROM waitlist_entries
+        WHERE status = 'waiting' AND party_size <= $1
+        ORDER BY created_at, id
+        LIMIT 1
+        FOR UPDATE`,
+        capacity,
+    ).Scan(&entryID)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            return models.WaitlistEntry{}, errors.New("no waiting party fits the table")
+        }
+        return models.WaitlistEntry{}, errors.Wrap(err, "failed to find next waiting party")
+    }
+
+    var customerOrderID int
+    err = tx.QueryRow(`
+        INSERT INTO customer_orders (table_id, total_amount, status, opened_by, created_at)
+        VALUES ($1, 0, 'pending', $2, CURRENT_TIMESTAMP)
+        RETURNING id`,
+        tableID, employeeID,
+    ).Scan(&customerOrderID)
+    if err != nil {
+        return models.WaitlistEntry{}, errors.Wrap(err, "failed to create customer order")
+    }
+
+    if _, err := tx.Exec(`
+        UPDATE waitlist_entries
+        SET status = 'seated', table_id = $1, customer_order_id = $2, seated_at = CURRENT_TIMESTAMP
+        WHERE id = $3`,
+        tableID, customerOrderID, entryID,
+    ); err != nil {
+        return models.WaitlistEntry{}, errors.Wrap(err, "failed to seat waitlist entry")
+    }
+
+    if err := tx.Commit(); err != nil {
+        return models.WaitlistEntry{}, errors.Wrap(err, "failed to commit transaction")
+    }
+    return r.FindByID(entryID)
+}
+
+// Leave retira de la lista a un grupo que se fue sin ser sentado
+func (r *waitlistRepository) Leave(entryID int) (models.WaitlistEntry, error) {
+    result, err := r.db.Exec(`
+        UPDATE waitlist_entries
+        SET status = 'left', left_at = CURRENT_TIMESTAMP
+        WHERE id = $1 AND status = 'waiting'`,
+        entryID,
+    )
+    if err != nil {
+        return models.WaitlistEntry{}, errors.Wrap(err, "failed to update waitlist entry")
+    }
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return models.WaitlistEntry{}, errors.Wrap(err, "failed to check rows affected")
+    }
+    if rowsAffected == 0 {
+        // Distinguir si el grupo no existe o si ya no está esperando
+        if _, err := r.FindByID(entryID); err != nil {
+            return models.WaitlistEntry{}, err
+        }
+        return models.WaitlistEntry{}, errors.New("waitlist entry is not waiting")
+    }
+    return r.FindByID(entryID)
+}
+
+func scanWaitlistEntries(rows *sql.Rows) ([]models.WaitlistEntry, error) {
+    entries := []models.WaitlistEntry{}
+    for rows.Next() {
+        var entry models.WaitlistEntry
+        var guestPhone sql.NullString
+        var quotedWaitMinutes int
+        var tableID, customerOrderID sql.NullInt64
+        var seatedAt, leftAt sql.NullTime
+        if err := rows.Scan(&entry.ID, &entry.GuestName, &guestPhone, &entry.PartySize, &quotedWaitMinutes, &entry.Status, &tableID, &customerOrderID, &entry.CreatedBy, &seatedAt, &leftAt, &entry.CreatedAt); err != nil {
+            return nil, errors.Wrap(err, "failed to scan waitlist entry")
+        }
+        entry.GuestPhone = guestPhone.String
+        entry.QuotedWaitMinutes = &quotedWaitMinutes
+        entry.TableID = nullIntToPtr(tableID)
+        entry.CustomerOrderID = nullIntToPtr(customerOrderID)
+        entry.SeatedAt = nullTimeToPtr(seatedAt)
+        entry.LeftAt = nullTimeToPtr(leftAt)
+        entries = append(entries, entry)
+    }
+    return entries, nil
+}
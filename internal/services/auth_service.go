@@ -1,7 +1,9 @@
 package services
 
 import (
+	"strings"
 	"time"
+	"unicode"
 
 	"gastrobar-backend/config"
 	"gastrobar-backend/internal/models"
@@ -18,8 +20,13 @@ type AuthService interface {
 	CheckPasswordHash(password, hash string) bool
 	GenerateJWT(employee models.Employee) (string, error)
 	Login(loginReq models.LoginRequest) (models.LoginResponse, error)
+	ChangePassword(employeeID int, request models.PasswordChangeRequest) (models.LoginResponse, error)
+	ValidateSession(employeeID, tokenVersion int) error
 }
 
+// MinPasswordLength es la longitud mínima de una contraseña elegida por el empleado
+const MinPasswordLength = 10
+
 type authService struct {
 	employeeRepo repositories.EmployeeRepository
 }
@@ -61,6 +68,7 @@ func (s *authService) GenerateJWT(employee models.Employee) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"employee_id": employee.ID,
 		"role":        string(employee.Role),
+		"tv":          employee.TokenVersion,
 		"exp":         time.Now().Add(expireDuration).Unix(),
 	})
 
@@ -84,6 +92,11 @@ func (s *authService) Login(loginReq models.LoginRequest) (models.LoginResponse,
 		return models.LoginResponse{}, errors.New("invalid email or password")
 	}
 
+	// Los empleados dados de baja no pueden iniciar sesión
+	if !employee.Active {
+		return models.LoginResponse{}, errors.New("employee is inactive")
+	}
+
 	// Generar el token JWT
 	token, err := s.GenerateJWT(employee)
 	if err != nil {
@@ -92,3 +105,84 @@ func (s *authService) Login(loginReq models.LoginRequest) (models.LoginResponse,
 
 	return models.LoginResponse{Token: token}, nil
 }
+
+// validatePasswordStrength exige una longitud mínima, mayúsculas, minúsculas y dígitos, y que la
+// contraseña no contenga el nombre de usuario
+func validatePasswordStrength(password string, employee models.Employee) error {
+	if len([]rune(password)) < MinPasswordLength {
+		return errors.Errorf("password must be at least %d characters long", MinPasswordLength)
+	}
+	var hasUpper, hasLower, hasDigit bool
+	for _, c := range password {
+		switch {
+		case unicode.IsUpper(c):
+			hasUpper = true
+		case unicode.IsLower(c):
+			hasLower = true
+		case unicode.IsDigit(c):
+			hasDigit = true
+		}
+	}
+	if !hasUpper || !hasLower || !hasDigit {
+		return errors.New("password must contain uppercase and lowercase letters and digits")
+	}
+	if employee.Username != "" && strings.Contains(strings.ToLower(password), strings.ToLower(employee.Username)) {
+		return errors.New("password must not contain the username")
+	}
+	return nil
+}
+
+// ChangePassword cambia la contraseña del propio empleado tras verificar la actual. El cambio
+// invalida todas las sesiones abiertas, por lo que se devuelve un token nuevo para la sesión actual
+func (s *authService) ChangePassword(employeeID int, request models.PasswordChangeRequest) (models.LoginResponse, error) {
+	employee, err := s.employeeRepo.FindByID(employeeID)
+	if err != nil {
+		return models.LoginResponse{}, errors.Wrap(err, "failed to find employee")
+	}
+
+	// Verificar la contraseña actual y validar la nueva
+	if !s.CheckPasswordHash(request.CurrentPassword, employee.Password) {
+		return models.LoginResponse{}, errors.New("current password is incorrect")
+	}
+	if err := validatePasswordStrength(request.NewPassword, employee); err != nil {
+		return models.LoginResponse{}, err
+	}
+	if request.NewPassword == request.CurrentPassword {
+		return models.LoginResponse{}, errors.New("new password must differ from the current one")
+	}
+
+	passwordHash, err := s.HashPassword(request.NewPassword)
+	if err != nil {
+		return models.LoginResponse{}, err
+	}
+	if err := s.employeeRepo.UpdatePassword(employeeID, passwordHash, employeeID); err != nil {
+		return models.LoginResponse{}, errors.Wrap(err, "failed to update password")
+	}
+
+	// Emitir el token con la nueva versión de sesión
+	employee, err = s.employeeRepo.FindByID(employeeID)
+	if err != nil {
+		return models.LoginResponse{}, errors.Wrap(err, "failed to find employee")
+	}
+	token, err := s.GenerateJWT(employee)
+	if err != nil {
+		return models.LoginResponse{}, errors.Wrap(err, "failed to generate JWT")
+	}
+	return models.LoginResponse{Token: token}, nil
+}
+
+// ValidateSession verifica que el token siga vigente: el empleado existe, sigue activo y la versión
+// de sesión del token coincide con la actual (cambia con cada cambio de contraseña y con la baja)
+func (s *authService) ValidateSession(employeeID, tokenVersion int) error {
+	employee, err := s.employeeRepo.FindByID(employeeID)
+	if err != nil {
+		return errors.Wrap(err, "failed to validate session")
+	}
+	if !employee.Active {
+		return errors.New("employee is inactive")
+	}
+	if employee.TokenVersion != tokenVersion {
+		return errors.New("session has been revoked")
+	}
+	return nil
+}
@@ -15,12 +15,14 @@ type BusinessService interface {
 
 type businessService struct {
     businessRepo repositories.BusinessRepository
+    tableRepo    repositories.TableRepository
 }
 
 // NewBusinessService crea una nueva instancia del servicio de negocio
-func NewBusinessService(businessRepo repositories.BusinessRepository) BusinessService {
+func NewBusinessService(businessRepo repositories.BusinessRepository, tableRepo repositories.TableRepository) BusinessService {
     return &businessService{
         businessRepo: businessRepo,
+        tableRepo:    tableRepo,
     }
 }
 
@@ -35,6 +37,20 @@ func (s *businessService) GetBusiness() (models.Business, error) {
 
 // UpdateBusiness actualiza los datos del negocio
 func (s *businessService) UpdateBusiness(business models.Business) (models.Business, error) {
+    // Validar el límite de mesas (nil = sin límite); no puede quedar por debajo de las mesas activas
+    if business.MaxTables != nil {
+        if *business.MaxTables <= 0 {
+            return models.Business{}, errors.New("max tables must be greater than 0")
+        }
+        count, err := s.tableRepo.CountActive()
+        if err != nil {
+            return models.Business{}, errors.Wrap(err, "failed to count tables")
+        }
+        if *business.MaxTables < count {
+            return models.Business{}, errors.New("max tables is lower than the number of active tables")
+        }
+    }
+
     updatedBusiness, err := s.businessRepo.Update(business)
     if err != nil {
         return models.Business{}, errors.Wrap(err, "failed to update business")
@@ -0,0 +1,134 @@
+package services
+
+import (
+    "sort"
+    "time"
+
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/internal/repositories"
+
+    "github.com/pkg/errors"
+    "github.com/shopspring/decimal"
+)
+
+type CostReportService interface {
+    GetMenuItemCosts() ([]models.MenuItemCost, error)
+    GetMarginReport(from, to *time.Time) (models.MarginReport, error)
+}
+
+type costReportService struct {
+    costReportRepo repositories.CostReportRepository
+}
+
+func NewCostReportService(costReportRepo repositories.CostReportRepository) CostReportService {
+    return &costReportService{
+        costReportRepo: costReportRepo,
+    }
+}
+
+var hundred = decimal.NewFromInt(100)
+
+// percentOf calcula value / total * 100 redondeado a dos decimales (nil si el total es cero)
+func percentOf(value, total decimal.Decimal) *decimal.Decimal {
+    if total.IsZero() {
+        return nil
+    }
+    percent := value.Div(total).Mul(hundred).Round(2)
+    return &percent
+}
+
+// withMargins completa el margen bruto y los porcentajes de un ítem con costo conocido
+func withMargins(cost models.MenuItemCost) models.MenuItemCost {
+    if cost.UnitCost == nil {
+        return cost
+    }
+    margin := cost.Price.Sub(*cost.UnitCost)
+    cost.GrossMargin = &margin
+    cost.MarginPercent = percentOf(margin, cost.Price)
+    cost.FoodCostPercent = percentOf(*cost.UnitCost, cost.Price)
+    return cost
+}
+
+// GetMenuItemCosts devuelve el costo unitario y el margen bruto de cada ítem del menú
+func (s *costReportService) GetMenuItemCosts() ([]models.MenuItemCost, error) {
+    costs, err := s.costReportRepo.FindMenuItemCosts()
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to get menu item costs")
+    }
+    for i := range costs {
+        costs[i] = withMargins(costs[i])
+    }
+    return costs, nil
+}
+
+// GetMarginReport calcula el costo teórico (unidades vendidas * costo unitario actual) y la
+// contribución al margen de cada ítem vendido en órdenes completadas del periodo. Los ingresos se
+// calculan con el precio vigente, igual que el total de las órdenes
+func (s *costReportService) GetMarginReport(from, to *time.Time) (models.MarginReport, error) {
+    // Validar el rango de fechas
+    if from != nil && to != nil && !from.Before(*to) {
+        return models.MarginReport{}, errors.New("from must be before to")
+    }
+
+    costs, err := s.costReportRepo.FindMenuItemCosts()
+    if err != nil {
+        return models.MarginReport{}, errors.Wrap(err, "failed to get menu item costs")
+    }
+    costsByItem := make(map[int]models.MenuItemCost, len(costs))
+    for _, cost := range costs {
+        costsByItem[cost.MenuItemID] = withMargins(cost)
+    }
+
+    sales, err := s.costReportRepo.FindCompletedSalesByItem(from, to)
+    if err != nil {
+        return models.MarginReport{}, errors.Wrap(err, "failed to get completed sales")
+    }
+
+    report := models.MarginReport{
+        From:            from,
+        To:              to,
+        Revenue:         decimal.Zero,
+        TheoreticalCost: decimal.Zero,
+        Contribution:    decimal.Zero,
+        Items:           []models.MenuItemContribution{},
+    }
+    costedRevenue := decimal.Zero
+    for _, sale := range sales {
+        quantity := decimal.NewFromInt(int64(sale.QuantitySold))
+        item := models.MenuItemContribution{
+            MenuItemCost: costsByItem[sale.MenuItemID],
+            QuantitySold: sale.QuantitySold,
+        }
+        item.Revenue = item.Price.Mul(quantity)
+        report.Revenue = report.Revenue.Add(item.Revenue)
+
+        if item.UnitCost == nil {
+            report.ItemsWithoutCost++
+        } else {
+            theoreticalCost := item.UnitCost.Mul(quantity)
+            contribution := item.Revenue.Sub(theoreticalCost)
+            item.TheoreticalCost = &theoreticalCost
+            item.Contribution = &contribution
+            costedRevenue = costedRevenue.Add(item.Revenue)
+            report.TheoreticalCost = report.TheoreticalCost.Add(theoreticalCost)
+            report.Contribution = report.Contribution.Add(contribution)
+        }
+        report.Items = append(report.Items, item)
+    }
+    report.FoodCostPercent = percentOf(report.TheoreticalCost, costedRevenue)
+
+    // Participación de cada ítem en la contribución total, ordenando de mayor a menor aporte
+    for i := range report.Items {
+        if report.Items[i].Contribution != nil {
+            report.Items[i].ContributionShare = percentOf(*report.Items[i].Contribution, report.Contribution)
+        }
+    }
+    sort.SliceStable(report.Items, func(i, j int) bool {
+        a, b := report.Items[i].Contribution, report.Items[j].Contribution
+        if a == nil || b == nil {
+            return b == nil && a != nil
+        }
+        return a.GreaterThan(*b)
+    })
+    return report, nil
+}
@@ -9,8 +9,9 @@ import (
 
 type CustomerOrderService interface {
     GetOrderWithDetails(orderID int) (models.CustomerOrder, error)
-    CompleteOrder(orderID int) (models.CustomerOrder, error)
-    CompleteOrderByEmployee(orderID int) (models.CustomerOrder, error)
+    CompleteOrder(orderID, employeeID int) (models.CustomerOrder, error)
+    CompleteOrderByEmployee(orderID, employeeID int) (models.CustomerOrder, error)
+    RequestBill(orderID int) (models.CustomerOrder, error)
 }
 
 type customerOrderService struct {
@@ -50,7 +51,18 @@ func (s *customerOrderService) GetOrderWithDetails(orderID int) (models.Customer
     return order, nil
 }
 
-func (s *customerOrderService) CompleteOrder(orderID int) (models.CustomerOrder, error) {
+// CompleteOrder cierra la orden y registra al empleado que la cerró
+func (s *customerOrderService) CompleteOrder(orderID, employeeID int) (models.CustomerOrder, error) {
+    return s.completeOrder(orderID, employeeID, nil)
+}
+
+// CompleteOrderByEmployee cierra la orden desde el piso; si la mesa no tenía mesero asignado, el
+// empleado que la cierra queda como quien la atendió
+func (s *customerOrderService) CompleteOrderByEmployee(orderID, employeeID int) (models.CustomerOrder, error) {
+    return s.completeOrder(orderID, employeeID, &employeeID)
+}
+
+func (s *customerOrderService) completeOrder(orderID, employeeID int, servedBy *int) (models.CustomerOrder, error) {
     // Validar que la orden existe
     order, err := s.customerOrderRepo.FindByID(orderID)
     if err != nil {
@@ -63,7 +75,7 @@ func (s *customerOrderService) CompleteOrder(orderID int) (models.CustomerOrder,
     }
 
     // Actualizar el estado a 'completed'
-    updatedOrder, err := s.customerOrderRepo.UpdateStatus(orderID, "completed")
+    updatedOrder, err := s.customerOrderRepo.Complete(orderID, employeeID, servedBy)
     if err != nil {
         return models.CustomerOrder{}, errors.Wrap(err, "failed to complete order")
     }
@@ -71,6 +83,22 @@ func (s *customerOrderService) CompleteOrder(orderID int) (models.CustomerOrder,
     return updatedOrder, nil
 }
 
-func (s *customerOrderService) CompleteOrderByEmployee(orderID int) (models.CustomerOrder, error) {
-    return s.CompleteOrder(orderID)
-}
\ No newline at end of file
+// RequestBill marca que la mesa pidió la cuenta; la mesa pasa a esperar el pago hasta que se cierre la orden
+func (s *customerOrderService) RequestBill(orderID int) (models.CustomerOrder, error) {
+    // Validar que la orden existe
+    order, err := s.customerOrderRepo.FindByID(orderID)
+    if err != nil {
+        return models.CustomerOrder{}, errors.Wrap(err, "failed to find customer order")
+    }
+
+    // Validar que la orden esté en estado 'pending'
+    if order.Status != "pending" {
+        return models.CustomerOrder{}, errors.New("order is not in 'pending' state")
+    }
+
+    updatedOrder, err := s.customerOrderRepo.RequestBill(orderID)
+    if err != nil {
+        return models.CustomerOrder{}, errors.Wrap(err, "failed to request bill")
+    }
+    return updatedOrder, nil
+}
@@ -3,6 +3,9 @@ package services
 import (
     "crypto/rand"
     "encoding/base64"
+    "net/mail"
+    "strings"
+
     "gastrobar-backend/internal/models"
     "gastrobar-backend/internal/repositories"
 
@@ -12,12 +15,17 @@ import (
 
 // EmployeeService define las operaciones relacionadas con los empleados
 type EmployeeService interface {
-    CreateEmployee(employee models.Employee) (models.EmployeeCreateResponse, error)
+    CreateEmployee(employee models.Employee, actorID int) (models.EmployeeCreateResponse, error)
     GetEmployee(employeeID int) (models.Employee, error)
     ListEmployees() ([]models.Employee, error)
     ListEmployeesByRole(role models.EmployeeRole) ([]models.Employee, error) // Nuevo
-    UpdateEmployee(employee models.Employee) (models.Employee, error)
-    UpdateEmployeePassword(employeeID int) (string, error)
+    UpdateEmployee(employee models.Employee, actorID int) (models.Employee, error)
+    UpdateEmployeePassword(employeeID, actorID int) (string, error)
+    UpdateProfile(employeeID int, profile models.ProfileUpdateRequest) (models.Employee, error)
+    DeactivateEmployee(employeeID, deactivatedBy int, request models.EmployeeDeactivationRequest) (models.EmployeeDeactivation, error)
+    ReactivateEmployee(employeeID, actorID int) (models.Employee, error)
+    TransferOwnership(ownerID, newOwnerID int) (models.OwnershipTransfer, error)
+    ListAuditLogs(employeeID int) ([]models.EmployeeAuditLog, error)
 }
 
 type employeeService struct {
@@ -31,6 +39,53 @@ func NewEmployeeService(employeeRepo repositories.EmployeeRepository) EmployeeSe
     }
 }
 
+// roleRank ordena los roles: cada empleado solo gestiona a empleados de rango estrictamente inferior
+func roleRank(role models.EmployeeRole) int {
+    switch role {
+    case models.EmployeeRoleOwner:
+        return 3
+    case models.EmployeeRoleAdmin:
+        return 2
+    case models.EmployeeRoleEmployee:
+        return 1
+    }
+    return 0
+}
+
+// checkOutranks verifica que el actor siga activo y tenga un rango estrictamente superior al rol
+// indicado. El rol de dueño solo se asigna con TransferOwnership
+func (s *employeeService) checkOutranks(actorID int, role models.EmployeeRole) error {
+    if role == models.EmployeeRoleOwner {
+        return errors.New("owner role can only be assigned through ownership transfer")
+    }
+    actor, err := s.employeeRepo.FindByID(actorID)
+    if err != nil {
+        return errors.Wrap(err, "failed to find acting employee")
+    }
+    if !actor.Active || roleRank(actor.Role) <= roleRank(role) {
+        return errors.New("insufficient rank to manage this employee")
+    }
+    return nil
+}
+
+// checkManages verifica que el actor pueda gestionar al empleado indicado según su rol actual
+func (s *employeeService) checkManages(actorID, employeeID int) (models.Employee, error) {
+    if actorID == employeeID {
+        return models.Employee{}, errors.New("insufficient rank to manage this employee")
+    }
+    employee, err := s.employeeRepo.FindByID(employeeID)
+    if err != nil {
+        return models.Employee{}, errors.Wrap(err, "failed to find employee")
+    }
+    if employee.Role == models.EmployeeRoleOwner {
+        return models.Employee{}, errors.New("insufficient rank to manage this employee")
+    }
+    if err := s.checkOutranks(actorID, employee.Role); err != nil {
+        return models.Employee{}, err
+    }
+    return employee, nil
+}
+
 // generateRandomPassword genera una contraseña aleatoria segura
 func generateRandomPassword(length int) (string, error) {
     bytes := make([]byte, length)
@@ -41,7 +96,18 @@ func generateRandomPassword(length int) (string, error) {
 }
 
 // CreateEmployee crea un nuevo empleado con una contraseña aleatoria
-func (s *employeeService) CreateEmployee(employee models.Employee) (models.EmployeeCreateResponse, error) {
+func (s *employeeService) CreateEmployee(employee models.Employee, actorID int) (models.EmployeeCreateResponse, error) {
+    // Validar los campos y la unicidad del usuario y del email
+    employee.ID = 0
+    if err := s.validateEmployee(&employee); err != nil {
+        return models.EmployeeCreateResponse{}, err
+    }
+
+    // Solo se crean empleados de rango inferior al de quien los crea
+    if err := s.checkOutranks(actorID, employee.Role); err != nil {
+        return models.EmployeeCreateResponse{}, err
+    }
+
     // Generar una contraseña aleatoria de 12 caracteres
     password, err := generateRandomPassword(12)
     if err != nil {
@@ -56,7 +122,7 @@ func (s *employeeService) CreateEmployee(employee models.Employee) (models.Emplo
     employee.Password = string(passwordHash)
 
     // Crear el empleado en el repositorio
-    createdEmployee, err := s.employeeRepo.Create(employee)
+    createdEmployee, err := s.employeeRepo.Create(employee, actorID)
     if err != nil {
         return models.EmployeeCreateResponse{}, errors.Wrap(err, "failed to create employee")
     }
@@ -94,17 +160,34 @@ func (s *employeeService) ListEmployeesByRole(role models.EmployeeRole) ([]model
     return employees, nil
 }
 
-// UpdateEmployee actualiza los datos de un empleado (sin contraseña)
-func (s *employeeService) UpdateEmployee(employee models.Employee) (models.Employee, error) {
-    updatedEmployee, err := s.employeeRepo.Update(employee)
+// UpdateEmployee actualiza los datos de un empleado (sin contraseña); tanto su rol actual como el
+// nuevo deben ser de rango inferior al del actor
+func (s *employeeService) UpdateEmployee(employee models.Employee, actorID int) (models.Employee, error) {
+    if _, err := s.checkManages(actorID, employee.ID); err != nil {
+        return models.Employee{}, err
+    }
+    if err := s.validateEmployee(&employee); err != nil {
+        return models.Employee{}, err
+    }
+    if err := s.checkOutranks(actorID, employee.Role); err != nil {
+        return models.Employee{}, err
+    }
+
+    updatedEmployee, err := s.employeeRepo.Update(employee, actorID)
     if err != nil {
         return models.Employee{}, errors.Wrap(err, "failed to update employee")
     }
     return updatedEmployee, nil
 }
 
-// UpdateEmployeePassword cambia la contraseña de un empleado y devuelve la nueva contraseña generada
-func (s *employeeService) UpdateEmployeePassword(employeeID int) (string, error) {
+// UpdateEmployeePassword cambia la contraseña de un empleado y devuelve la nueva contraseña generada;
+// las sesiones abiertas del empleado quedan invalidadas. Solo se restablece la de empleados de rango
+// inferior; la propia se cambia con AuthService.ChangePassword
+func (s *employeeService) UpdateEmployeePassword(employeeID, actorID int) (string, error) {
+    if _, err := s.checkManages(actorID, employeeID); err != nil {
+        return "", err
+    }
+
     // Generar una contraseña aleatoria de 12 caracteres
     newPassword, err := generateRandomPassword(12)
     if err != nil {
@@ -118,10 +201,251 @@ func (s *employeeService) UpdateEmployeePassword(employeeID int) (string, error)
     }
 
     // Actualizar la contraseña en el repositorio
-    err = s.employeeRepo.UpdatePassword(employeeID, string(passwordHash))
+    err = s.employeeRepo.UpdatePassword(employeeID, string(passwordHash), actorID)
     if err != nil {
         return "", errors.Wrap(err, "failed to update employee password")
     }
 
     return newPassword, nil
-}
\ No newline at end of file
+}
+
+// validRole indica si el rol es uno de los del ENUM employee_role
+func validRole(role models.EmployeeRole) bool {
+    switch role {
+    case models.EmployeeRoleOwner, models.EmployeeRoleAdmin, models.EmployeeRoleEmployee:
+        return true
+    }
+    return false
+}
+
+// validUsername acepta de 3 a 50 letras, dígitos, puntos, guiones y guiones bajos
+func validUsername(username string) bool {
+    if len(username) < 3 || len(username) > 50 {
+        return false
+    }
+    for _, c := range username {
+        switch {
+        case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '.', c == '-', c == '_':
+        default:
+            return false
+        }
+    }
+    return true
+}
+
+// validEmail acepta una dirección simple (sin nombre ni <>) de hasta 255 caracteres
+func validEmail(email string) bool {
+    address, err := mail.ParseAddress(email)
+    return err == nil && address.Address == email && len(email) <= 255
+}
+
+// validPhoneNumber acepta dígitos con un + inicial opcional y espacios, guiones o paréntesis
+func validPhoneNumber(phoneNumber string) bool {
+    digits := 0
+    for i, c := range phoneNumber {
+        switch {
+        case c >= '0' && c <= '9':
+            digits++
+        case c == '+' && i == 0, c == ' ', c == '-', c == '(', c == ')':
+        default:
+            return false
+        }
+    }
+    return digits >= 7 && len(phoneNumber) <= 20
+}
+
+// checkUnique registra un error en el campo si el valor ya lo usa otro empleado; existing y err son
+// el resultado de buscar al empleado por ese valor
+func checkUnique(validation *models.ValidationError, field string, existing models.Employee, err error, employeeID int) error {
+    if err != nil {
+        if strings.Contains(err.Error(), "employee not found") {
+            return nil
+        }
+        return errors.Wrap(err, "failed to check "+field+" uniqueness")
+    }
+    if existing.ID != employeeID {
+        validation.Add(field, "is already in use")
+    }
+    return nil
+}
+
+// validateEmployee normaliza y valida los datos de un empleado, incluida la unicidad del usuario y
+// del email (sin distinguir mayúsculas). Si hay campos inválidos devuelve un *models.ValidationError
+// con todos ellos
+func (s *employeeService) validateEmployee(employee *models.Employee) error {
+    employee.EmployeeName = strings.TrimSpace(employee.EmployeeName)
+    employee.Username = strings.TrimSpace(employee.Username)
+    employee.Email = strings.TrimSpace(employee.Email)
+    employee.PhoneNumber = strings.TrimSpace(employee.PhoneNumber)
+    validation := &models.ValidationError{}
+
+    if employee.EmployeeName == "" {
+        validation.Add("employee_name", "is required")
+    } else if len(employee.EmployeeName) > 100 {
+        validation.Add("employee_name", "must be at most 100 characters")
+    }
+
+    if !validUsername(employee.Username) {
+        validation.Add("username", "must be 3 to 50 letters, digits, '.', '-' or '_'")
+    } else {
+        existing, err := s.employeeRepo.FindByUsername(employee.Username)
+        if err := checkUnique(validation, "username", existing, err, employee.ID); err != nil {
+            return err
+        }
+    }
+
+    if !validEmail(employee.Email) {
+        validation.Add("email", "must be a valid email address")
+    } else {
+        existing, err := s.employeeRepo.FindByEmail(employee.Email)
+        if err := checkUnique(validation, "email", existing, err, employee.ID); err != nil {
+            return err
+        }
+    }
+
+    if !validPhoneNumber(employee.PhoneNumber) {
+        validation.Add("phone_number", "must have at least 7 digits and only '+', spaces, '-' or parentheses")
+    }
+
+    if !validRole(employee.Role) {
+        validation.Add("role", "must be one of dueño, administrador, empleado")
+    }
+
+    if validation.HasErrors() {
+        return validation
+    }
+    return nil
+}
+
+// UpdateProfile actualiza los datos de contacto del propio empleado; el nombre, el rol y el usuario
+// solo los cambia la administración
+func (s *employeeService) UpdateProfile(employeeID int, profile models.ProfileUpdateRequest) (models.Employee, error) {
+    email := strings.TrimSpace(profile.Email)
+    phoneNumber := strings.TrimSpace(profile.PhoneNumber)
+    validation := &models.ValidationError{}
+
+    // Validar el email y que no lo use otro empleado
+    if !validEmail(email) {
+        validation.Add("email", "must be a valid email address")
+    } else {
+        existing, err := s.employeeRepo.FindByEmail(email)
+        if err := checkUnique(validation, "email", existing, err, employeeID); err != nil {
+            return models.Employee{}, err
+        }
+    }
+
+    // Validar el teléfono
+    if !validPhoneNumber(phoneNumber) {
+        validation.Add("phone_number", "must have at least 7 digits and only '+', spaces, '-' or parentheses")
+    }
+    if validation.HasErrors() {
+        return models.Employee{}, validation
+    }
+
+    updatedEmployee, err := s.employeeRepo.UpdateContact(employeeID, email, phoneNumber)
+    if err != nil {
+        return models.Employee{}, errors.Wrap(err, "failed to update profile")
+    }
+    return updatedEmployee, nil
+}
+
+// DeactivateEmployee da de baja a un empleado que deja el negocio. Sus tareas abiertas se reasignan
+// al empleado indicado o se cancelan; sus pedidos y reportes conservan la referencia
+func (s *employeeService) DeactivateEmployee(employeeID, deactivatedBy int, request models.EmployeeDeactivationRequest) (models.EmployeeDeactivation, error) {
+    if employeeID == deactivatedBy {
+        return models.EmployeeDeactivation{}, errors.New("cannot deactivate yourself")
+    }
+
+    // Solo se da de baja a empleados de rango inferior, así que el dueño nunca queda sin reemplazo
+    employee, err := s.checkManages(deactivatedBy, employeeID)
+    if err != nil {
+        return models.EmployeeDeactivation{}, err
+    }
+    if !employee.Active {
+        return models.EmployeeDeactivation{}, errors.New("employee is already inactive")
+    }
+
+    // Validar a quién se reasignan las tareas: las mismas reglas que al asignar una tarea
+    if request.ReassignTasksTo != nil {
+        if *request.ReassignTasksTo == employeeID {
+            return models.EmployeeDeactivation{}, errors.New("cannot reassign tasks to the same employee")
+        }
+        target, err := s.employeeRepo.FindByID(*request.ReassignTasksTo)
+        if err != nil {
+            if strings.Contains(err.Error(), "employee not found") {
+                return models.EmployeeDeactivation{}, errors.New("reassignment target not found")
+            }
+            return models.EmployeeDeactivation{}, errors.Wrap(err, "failed to find reassignment target")
+        }
+        if !target.Active {
+            return models.EmployeeDeactivation{}, errors.New("reassignment target is inactive")
+        }
+        if target.Role != models.EmployeeRoleEmployee {
+            return models.EmployeeDeactivation{}, errors.New("tasks can only be assigned to employees with role 'empleado'")
+        }
+    }
+
+    result, err := s.employeeRepo.Deactivate(employeeID, deactivatedBy, request.ReassignTasksTo)
+    if err != nil {
+        return models.EmployeeDeactivation{}, errors.Wrap(err, "failed to deactivate employee")
+    }
+    return result, nil
+}
+
+// ReactivateEmployee vuelve a habilitar a un empleado dado de baja; debe iniciar sesión de nuevo
+func (s *employeeService) ReactivateEmployee(employeeID, actorID int) (models.Employee, error) {
+    if _, err := s.checkManages(actorID, employeeID); err != nil {
+        return models.Employee{}, err
+    }
+
+    employee, err := s.employeeRepo.Reactivate(employeeID, actorID)
+    if err != nil {
+        return models.Employee{}, errors.Wrap(err, "failed to reactivate employee")
+    }
+    return employee, nil
+}
+
+// TransferOwnership traspasa la propiedad del negocio del dueño actual a otro empleado activo, y el
+// dueño anterior pasa a administrador. Es exclusivo del dueño aunque se conceda el permiso a otro rol
+func (s *employeeService) TransferOwnership(ownerID, newOwnerID int) (models.OwnershipTransfer, error) {
+    if ownerID == newOwnerID {
+        return models.OwnershipTransfer{}, errors.New("cannot transfer ownership to yourself")
+    }
+
+    owner, err := s.employeeRepo.FindByID(ownerID)
+    if err != nil {
+        return models.OwnershipTransfer{}, errors.Wrap(err, "failed to find acting employee")
+    }
+    if owner.Role != models.EmployeeRoleOwner {
+        return models.OwnershipTransfer{}, errors.New("only the owner can transfer ownership")
+    }
+
+    newOwner, err := s.employeeRepo.FindByID(newOwnerID)
+    if err != nil {
+        return models.OwnershipTransfer{}, errors.Wrap(err, "failed to find employee")
+    }
+    if !newOwner.Active {
+        return models.OwnershipTransfer{}, errors.New("employee is inactive")
+    }
+    if newOwner.Role == models.EmployeeRoleOwner {
+        return models.OwnershipTransfer{}, errors.New("employee is already an owner")
+    }
+
+    transfer, err := s.employeeRepo.TransferOwnership(ownerID, newOwnerID)
+    if err != nil {
+        return models.OwnershipTransfer{}, errors.Wrap(err, "failed to transfer ownership")
+    }
+    return transfer, nil
+}
+
+// ListAuditLogs devuelve el historial de acciones sobre un empleado
+func (s *employeeService) ListAuditLogs(employeeID int) ([]models.EmployeeAuditLog, error) {
+    if _, err := s.employeeRepo.FindByID(employeeID); err != nil {
+        return nil, errors.Wrap(err, "failed to find employee")
+    }
+    logs, err := s.employeeRepo.FindAuditLogs(employeeID)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to list employee audit logs")
+    }
+    return logs, nil
+}
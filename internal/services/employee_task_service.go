@@ -16,21 +16,63 @@ type EmployeeTaskService interface {
     ListTasks() ([]models.EmployeeTask, error)
     ListTasksByEmployee(employeeID int) ([]models.EmployeeTask, error)
     UpdateTask(task models.EmployeeTask) (models.EmployeeTask, error)
-    UpdateTaskStatus(taskID int, status models.EmployeeTaskStatus) error
+    UpdateTaskStatus(taskID int, status models.EmployeeTaskStatus, actorID int) error
+    GetTaskHistory(taskID, actorID int) ([]models.EmployeeTaskStatusChange, error)
     DeleteTask(taskID int) error
 }
 
+// taskTransitions es el grafo de estados de las tareas: los estados a los que se puede pasar desde cada uno
+var taskTransitions = map[models.EmployeeTaskStatus][]models.EmployeeTaskStatus{
+    models.TaskStatusPending:    {models.TaskStatusInProgress, models.TaskStatusCompleted, models.TaskStatusCancelled},
+    models.TaskStatusInProgress: {models.TaskStatusPending, models.TaskStatusCompleted, models.TaskStatusCancelled},
+    models.TaskStatusCompleted:  {models.TaskStatusInProgress}, // Reabrir
+    models.TaskStatusCancelled:  {models.TaskStatusPending},    // Restaurar
+}
+
+// assigneeTransitions son las transiciones que el empleado asignado puede hacer sobre su propia tarea
+// sin el permiso tasks.manage: empezarla, soltarla y terminarla. Cancelar, reabrir y restaurar
+// quedan para quien gestiona las tareas
+var assigneeTransitions = map[models.EmployeeTaskStatus][]models.EmployeeTaskStatus{
+    models.TaskStatusPending:    {models.TaskStatusInProgress, models.TaskStatusCompleted},
+    models.TaskStatusInProgress: {models.TaskStatusPending, models.TaskStatusCompleted},
+}
+
+// allowsTransition indica si el grafo permite pasar de from a to
+func allowsTransition(graph map[models.EmployeeTaskStatus][]models.EmployeeTaskStatus, from, to models.EmployeeTaskStatus) bool {
+    for _, status := range graph[from] {
+        if status == to {
+            return true
+        }
+    }
+    return false
+}
+
 type employeeTaskService struct {
     employeeTaskRepo repositories.EmployeeTaskRepository
     employeeRepo     repositories.EmployeeRepository
+    permissionSvc    PermissionService
 }
 
 // NewEmployeeTaskService crea una nueva instancia del servicio de tareas
-func NewEmployeeTaskService(employeeTaskRepo repositories.EmployeeTaskRepository, employeeRepo repositories.EmployeeRepository) EmployeeTaskService {
+func NewEmployeeTaskService(employeeTaskRepo repositories.EmployeeTaskRepository, employeeRepo repositories.EmployeeRepository, permissionSvc PermissionService) EmployeeTaskService {
     return &employeeTaskService{
         employeeTaskRepo: employeeTaskRepo,
         employeeRepo:     employeeRepo,
+        permissionSvc:    permissionSvc,
+    }
+}
+
+// actorHasPermission indica si el rol actual del empleado tiene el permiso
+func (s *employeeTaskService) actorHasPermission(actorID int, permission models.Permission) (bool, error) {
+    actor, err := s.employeeRepo.FindByID(actorID)
+    if err != nil {
+        return false, errors.Wrap(err, "failed to find acting employee")
+    }
+    allowed, err := s.permissionSvc.HasPermission(actor.Role, permission)
+    if err != nil {
+        return false, errors.Wrap(err, "failed to check permission")
     }
+    return allowed, nil
 }
 
 // CreateTask crea una nueva tarea para un empleado
@@ -41,7 +83,10 @@ func (s *employeeTaskService) CreateTask(task models.EmployeeTask) (models.Emplo
         return models.EmployeeTask{}, errors.Wrap(err, "employee does not exist")
     }
 
-    // Validar que el empleado tenga el rol "empleado"
+    // Validar que el empleado siga activo y tenga el rol "empleado"
+    if !employee.Active {
+        return models.EmployeeTask{}, errors.New("employee is inactive")
+    }
     if employee.Role != models.EmployeeRoleEmployee {
         return models.EmployeeTask{}, errors.New("tasks can only be assigned to employees with role 'empleado'")
     }
@@ -87,7 +132,7 @@ func (s *employeeTaskService) ListTasksByEmployee(employeeID int) ([]models.Empl
     return tasks, nil
 }
 
-// UpdateTask actualiza una tarea (para admin y dueño)
+// UpdateTask actualiza una tarea (para admin y dueño); el estado se cambia con UpdateTaskStatus
 func (s *employeeTaskService) UpdateTask(task models.EmployeeTask) (models.EmployeeTask, error) {
     // Validar que el empleado exista
     employee, err := s.employeeRepo.FindByID(task.EmployeeID)
@@ -95,7 +140,10 @@ func (s *employeeTaskService) UpdateTask(task models.EmployeeTask) (models.Emplo
         return models.EmployeeTask{}, errors.Wrap(err, "employee does not exist")
     }
 
-    // Validar que el empleado tenga el rol "empleado"
+    // Validar que el empleado siga activo y tenga el rol "empleado"
+    if !employee.Active {
+        return models.EmployeeTask{}, errors.New("employee is inactive")
+    }
     if employee.Role != models.EmployeeRoleEmployee {
         return models.EmployeeTask{}, errors.New("tasks can only be assigned to employees with role 'empleado'")
     }
@@ -107,34 +155,74 @@ func (s *employeeTaskService) UpdateTask(task models.EmployeeTask) (models.Emplo
     return updatedTask, nil
 }
 
-// UpdateTaskStatus actualiza el estado de una tarea (para admin y dueño)
-func (s *employeeTaskService) UpdateTaskStatus(taskID int, status models.EmployeeTaskStatus) error {
-    // Obtener la tarea para validar su existencia
+// UpdateTaskStatus cambia el estado de una tarea siguiendo taskTransitions. Quien tiene tasks.manage
+// puede hacer cualquier transición del grafo; el empleado asignado, solo las de assigneeTransitions
+// sobre sus propias tareas. Cada cambio queda en el historial de la tarea
+func (s *employeeTaskService) UpdateTaskStatus(taskID int, status models.EmployeeTaskStatus, actorID int) error {
+    if _, ok := taskTransitions[status]; !ok {
+        return errors.New("invalid task status")
+    }
+
+    // Obtener la tarea para validar su existencia y su estado actual
     task, err := s.employeeTaskRepo.FindByID(taskID)
     if err != nil {
         return errors.Wrap(err, "failed to find task")
     }
+    if !allowsTransition(taskTransitions, task.Status, status) {
+        return errors.Errorf("invalid status transition from %s to %s", task.Status, status)
+    }
+
+    // Validar que el actor gestione las tareas o sea el asignado y la transición le esté permitida
+    manages, err := s.actorHasPermission(actorID, models.PermissionTasksManage)
+    if err != nil {
+        return err
+    }
+    if !manages {
+        if task.EmployeeID != actorID {
+            return errors.New("task is not assigned to you")
+        }
+        if !allowsTransition(assigneeTransitions, task.Status, status) {
+            return errors.Errorf("status transition from %s to %s requires tasks.manage", task.Status, status)
+        }
+    }
 
     // Determinar el valor de completed_at según el estado
     var completedAt *time.Time
     if status == models.TaskStatusCompleted {
         now := time.Now()
         completedAt = &now
-    } else {
-        completedAt = nil
     }
 
-    // Actualizar el estado y completed_at
-    err = s.employeeTaskRepo.UpdateStatus(taskID, status, completedAt)
+    // Actualizar el estado y completed_at y registrar el cambio
+    err = s.employeeTaskRepo.UpdateStatus(taskID, task.Status, status, completedAt, actorID)
     if err != nil {
         return errors.Wrap(err, "failed to update task status")
     }
+    return nil
+}
 
-    // Actualizar el campo en la tarea
-    task.Status = status
-    task.CompletedAt = completedAt
+// GetTaskHistory devuelve los cambios de estado de una tarea; el empleado asignado ve los de sus
+// propias tareas y quien tiene tasks.view, los de todas
+func (s *employeeTaskService) GetTaskHistory(taskID, actorID int) ([]models.EmployeeTaskStatusChange, error) {
+    task, err := s.employeeTaskRepo.FindByID(taskID)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to find task")
+    }
+    if task.EmployeeID != actorID {
+        canView, err := s.actorHasPermission(actorID, models.PermissionTasksView)
+        if err != nil {
+            return nil, err
+        }
+        if !canView {
+            return nil, errors.New("task is not assigned to you")
+        }
+    }
 
-    return nil
+    history, err := s.employeeTaskRepo.FindStatusHistory(taskID)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to get task history")
+    }
+    return history, nil
 }
 
 // DeleteTask elimina una tarea (para admin y dueño)
@@ -0,0 +1,259 @@
+package services
+
+import (
+    "crypto/hmac"
+    "crypto/sha256"
+    "encoding/base64"
+    "fmt"
+    "strconv"
+    "strings"
+    "time"
+
+    "gastrobar-backend/config"
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/internal/repositories"
+
+    "github.com/golang-jwt/jwt/v5"
+    "github.com/pkg/errors"
+)
+
+// GuestSessionDuration es la duración de una sesión de cliente abierta desde el QR de la mesa
+const GuestSessionDuration = 3 * time.Hour
+
+// GuestOrderingService define las operaciones del autopedido por QR en la mesa
+type GuestOrderingService interface {
+    GetTableQRToken(tableID int) (models.TableQRToken, error)
+    RotateTableQRToken(tableID int) (models.TableQRToken, error)
+    StartSession(qrToken string) (models.GuestSession, error)
+    GetTableOrder(tableID int, qrVersion int) (models.CustomerOrder, error)
+    AddOrderLine(tableID int, qrVersion int, line models.GuestOrderLineRequest) (models.OrderDetail, error)
+    RequestBill(tableID int, qrVersion int) (models.CustomerOrder, error)
+}
+
+type guestOrderingService struct {
+    tableRepo         repositories.TableRepository
+    customerOrderRepo repositories.CustomerOrderRepository
+    orderDetailRepo   repositories.OrderDetailRepository
+    businessRepo      repositories.BusinessRepository
+    orderDetailSvc    OrderDetailService
+}
+
+// NewGuestOrderingService crea una nueva instancia del servicio de autopedido
+func NewGuestOrderingService(
+    tableRepo repositories.TableRepository,
+    customerOrderRepo repositories.CustomerOrderRepository,
+    orderDetailRepo repositories.OrderDetailRepository,
+    businessRepo repositories.BusinessRepository,
+    orderDetailSvc OrderDetailService,
+) GuestOrderingService {
+    return &guestOrderingService{
+        tableRepo:         tableRepo,
+        customerOrderRepo: customerOrderRepo,
+        orderDetailRepo:   orderDetailRepo,
+        businessRepo:      businessRepo,
+        orderDetailSvc:    orderDetailSvc,
+    }
+}
+
+// GetTableQRToken devuelve el token firmado de la mesa para imprimir en su QR
+func (s *guestOrderingService) GetTableQRToken(tableID int) (models.TableQRToken, error) {
+    table, err := s.tableRepo.FindByID(tableID)
+    if err != nil {
+        return models.TableQRToken{}, errors.Wrap(err, "failed to find table")
+    }
+
+    return models.TableQRToken{
+        TableID:   table.ID,
+        TableName: table.TableName,
+        Token:     signTableToken(table),
+    }, nil
+}
+
+// RotateTableQRToken invalida el QR actual de la mesa (y las sesiones abiertas con él) y genera uno nuevo
+func (s *guestOrderingService) RotateTableQRToken(tableID int) (models.TableQRToken, error) {
+    table, err := s.tableRepo.IncrementQRVersion(tableID)
+    if err != nil {
+        return models.TableQRToken{}, errors.Wrap(err, "failed to find table")
+    }
+
+    return models.TableQRToken{
+        TableID:   table.ID,
+        TableName: table.TableName,
+        Token:     signTableToken(table),
+    }, nil
+}
+
+// StartSession valida el token del QR y emite un JWT de cliente limitado a esa mesa
+func (s *guestOrderingService) StartSession(qrToken string) (models.GuestSession, error) {
+    tableID, qrVersion, err := parseTableToken(qrToken)
+    if err != nil {
+        return models.GuestSession{}, err
+    }
+
+    table, err := s.tableRepo.FindByID(tableID)
+    if err != nil {
+        if strings.Contains(err.Error(), "table not found") {
+            return models.GuestSession{}, errors.New("invalid table token")
+        }
+        return models.GuestSession{}, errors.Wrap(err, "failed to find table")
+    }
+    if table.QRVersion != qrVersion {
+        return models.GuestSession{}, errors.New("invalid table token")
+    }
+    if !table.Active {
+        return models.GuestSession{}, errors.New("table is inactive")
+    }
+
+    expiresAt := time.Now().Add(GuestSessionDuration)
+    token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+        "scope":      "guest",
+        "table_id":   table.ID,
+        "qr_version": table.QRVersion,
+        "exp":        expiresAt.Unix(),
+    })
+    tokenString, err := token.SignedString([]byte(config.GetJWTSecret()))
+    if err != nil {
+        return models.GuestSession{}, errors.Wrap(err, "failed to generate guest token")
+    }
+
+    return models.GuestSession{
+        Token:     tokenString,
+        TableID:   table.ID,
+        TableName: table.TableName,
+        ExpiresAt: expiresAt,
+    }, nil
+}
+
+// GetTableOrder devuelve el pedido pendiente de la mesa con sus líneas
+func (s *guestOrderingService) GetTableOrder(tableID int, qrVersion int) (models.CustomerOrder, error) {
+    if err := s.validateSession(tableID, qrVersion); err != nil {
+        return models.CustomerOrder{}, err
+    }
+
+    order, err := s.customerOrderRepo.FindPendingByTableID(tableID)
+    if err != nil {
+        return models.CustomerOrder{}, errors.Wrap(err, "failed to find customer order")
+    }
+
+    orderDetails, err := s.orderDetailRepo.FindByOrderID(order.ID)
+    if err != nil {
+        return models.CustomerOrder{}, errors.Wrap(err, "failed to find order details")
+    }
+    order.OrderDetails = orderDetails
+
+    return order, nil
+}
+
+// AddOrderLine agrega una línea al pedido pendiente de la mesa (o abre uno nuevo); si el negocio
+// lo exige, la línea queda pendiente de confirmación por un mesero antes de pasar a cocina
+func (s *guestOrderingService) AddOrderLine(tableID int, qrVersion int, line models.GuestOrderLineRequest) (models.OrderDetail, error) {
+    if err := s.validateSession(tableID, qrVersion); err != nil {
+        return models.OrderDetail{}, err
+    }
+
+    if line.MenuItemID <= 0 {
+        return models.OrderDetail{}, errors.New("menu_item_id is required")
+    }
+    if line.Quantity <= 0 {
+        return models.OrderDetail{}, errors.New("quantity must be greater than 0")
+    }
+
+    business, err := s.businessRepo.Find()
+    if err != nil {
+        return models.OrderDetail{}, errors.Wrap(err, "failed to get business settings")
+    }
+
+    orderDetail := models.OrderDetail{
+        MenuItemID: line.MenuItemID,
+        Quantity:   line.Quantity,
+        Source:     models.OrderDetailSourceGuest,
+        Status:     models.OrderDetailStatusConfirmed,
+    }
+    if business.GuestOrdersRequireConfirmation {
+        orderDetail.Status = models.OrderDetailStatusPendingConfirmation
+    }
+
+    // Agregar al pedido pendiente de la mesa si ya existe
+    pendingOrder, err := s.customerOrderRepo.FindPendingByTableID(tableID)
+    if err == nil {
+        orderDetail.OrderID = pendingOrder.ID
+    } else if !strings.Contains(err.Error(), "no pending customer order found") {
+        return models.OrderDetail{}, errors.Wrap(err, "failed to find pending customer order")
+    }
+
+    createdDetail, err := s.orderDetailSvc.CreateOrderDetail(orderDetail, tableID)
+    if err != nil {
+        return models.OrderDetail{}, err
+    }
+    return createdDetail, nil
+}
+
+// RequestBill pide la cuenta del pedido pendiente de la mesa del cliente
+func (s *guestOrderingService) RequestBill(tableID int, qrVersion int) (models.CustomerOrder, error) {
+    if err := s.validateSession(tableID, qrVersion); err != nil {
+        return models.CustomerOrder{}, err
+    }
+
+    order, err := s.customerOrderRepo.FindPendingByTableID(tableID)
+    if err != nil {
+        return models.CustomerOrder{}, errors.Wrap(err, "failed to find customer order")
+    }
+
+    updatedOrder, err := s.customerOrderRepo.RequestBill(order.ID)
+    if err != nil {
+        return models.CustomerOrder{}, errors.Wrap(err, "failed to request bill")
+    }
+    return updatedOrder, nil
+}
+
+// validateSession verifica que el QR con el que se abrió la sesión siga vigente
+func (s *guestOrderingService) validateSession(tableID int, qrVersion int) error {
+    table, err := s.tableRepo.FindByID(tableID)
+    if err != nil {
+        return errors.Wrap(err, "failed to find table")
+    }
+    // Una mesa desactivada deja sin efecto las sesiones abiertas desde su QR
+    if table.QRVersion != qrVersion || !table.Active {
+        return errors.New("guest session has been revoked")
+    }
+    return nil
+}
+
+// signTableToken genera el token "<payload>.<firma>" donde payload codifica "table_id:qr_version"
+func signTableToken(table models.Table) string {
+    payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d", table.ID, table.QRVersion)))
+    return payload + "." + tableTokenSignature(payload)
+}
+
+// parseTableToken valida la firma del token y devuelve la mesa y la versión del QR
+func parseTableToken(token string) (int, int, error) {
+    payload, signature, found := strings.Cut(token, ".")
+    if !found || !hmac.Equal([]byte(signature), []byte(tableTokenSignature(payload))) {
+        return 0, 0, errors.New("invalid table token")
+    }
+
+    decoded, err := base64.RawURLEncoding.DecodeString(payload)
+    if err != nil {
+        return 0, 0, errors.New("invalid table token")
+    }
+    tableIDStr, versionStr, found := strings.Cut(string(decoded), ":")
+    if !found {
+        return 0, 0, errors.New("invalid table token")
+    }
+    tableID, err := strconv.Atoi(tableIDStr)
+    if err != nil {
+        return 0, 0, errors.New("invalid table token")
+    }
+    qrVersion, err := strconv.Atoi(versionStr)
+    if err != nil {
+        return 0, 0, errors.New("invalid table token")
+    }
+    return tableID, qrVersion, nil
+}
+
+// tableTokenSignature firma el payload con una clave derivada del secreto JWT
+func tableTokenSignature(payload string) string {
+    mac := hmac.New(sha256.New, []byte("table-qr:"+config.GetJWTSecret()))
+    mac.Write([]byte(payload))
+    return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+    "strings"
+
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/internal/repositories"
+
+    "github.com/pkg/errors"
+    "github.com/shopspring/decimal"
+)
+
+type IngredientService interface {
+    CreateIngredient(ingredient models.Ingredient, employeeID int) (models.Ingredient, error)
+    GetIngredient(ingredientID int) (models.Ingredient, error)
+    ListIngredients() ([]models.Ingredient, error)
+    UpdateIngredient(ingredient models.Ingredient, employeeID int) (models.Ingredient, error)
+    DeleteIngredient(ingredientID int) error
+    GetRecipe(menuItemID int) ([]models.RecipeItem, error)
+    SetRecipe(menuItemID int, items []models.RecipeItem) ([]models.RecipeItem, error)
+}
+
+type ingredientService struct {
+    ingredientRepo repositories.IngredientRepository
+    recipeRepo     repositories.RecipeRepository
+    menuItemRepo   repositories.MenuItemRepository
+}
+
+func NewIngredientService(ingredientRepo repositories.IngredientRepository, recipeRepo repositories.RecipeRepository, menuItemRepo repositories.MenuItemRepository) IngredientService {
+    return &ingredientService{
+        ingredientRepo: ingredientRepo,
+        recipeRepo:     recipeRepo,
+        menuItemRepo:   menuItemRepo,
+    }
+}
+
+// validateIngredient aplica las reglas de validación de los campos de un ingrediente
+func validateIngredient(ingredient models.Ingredient) error {
+    // Validar que el nombre no esté vacío
+    if strings.TrimSpace(ingredient.IngredientName) == "" {
+        return errors.New("ingredient name cannot be empty")
+    }
+
+    // Validar la unidad de medida
+    switch ingredient.Unit {
+    case models.IngredientUnitGram, models.IngredientUnitMilliliter, models.IngredientUnitUnit:
+    default:
+        return errors.New("invalid ingredient unit")
+    }
+
+    // Validar que el stock no sea negativo
+    if ingredient.Stock.IsNegative() {
+        return errors.New("stock cannot be negative")
+    }
+    return nil
+}
+
+func (s *ingredientService) CreateIngredient(ingredient models.Ingredient, employeeID int) (models.Ingredient, error) {
+    if err := validateIngredient(ingredient); err != nil {
+        return models.Ingredient{}, err
+    }
+
+    // Validar que el nombre sea único
+    _, err := s.ingredientRepo.FindByName(ingredient.IngredientName)
+    if err == nil {
+        return models.Ingredient{}, errors.New("ingredient name already exists")
+    }
+    if !strings.Contains(err.Error(), "ingredient not found") {
+        return models.Ingredient{}, errors.Wrap(err, "failed to check ingredient name uniqueness")
+    }
+
+    createdIngredient, err := s.ingredientRepo.Create(ingredient, employeeID)
+    if err != nil {
+        return models.Ingredient{}, errors.Wrap(err, "failed to create ingredient")
+    }
+    return createdIngredient, nil
+}
+
+func (s *ingredientService) GetIngredient(ingredientID int) (models.Ingredient, error) {
+    ingredient, err := s.ingredientRepo.FindByID(ingredientID)
+    if err != nil {
+        return models.Ingredient{}, errors.Wrap(err, "failed to get ingredient")
+    }
+    return ingredient, nil
+}
+
+func (s *ingredientService) ListIngredients() ([]models.Ingredient, error) {
+    ingredients, err := s.ingredientRepo.FindAll()
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to list ingredients")
+    }
+    return ingredients, nil
+}
+
+func (s *ingredientService) UpdateIngredient(ingredient models.Ingredient, employeeID int) (models.Ingredient, error) {
+    if err := validateIngredient(ingredient); err != nil {
+        return models.Ingredient{}, err
+    }
+
+    currentIngredient, err := s.ingredientRepo.FindByID(ingredient.ID)
+    if err != nil {
+        return models.Ingredient{}, errors.Wrap(err, "failed to find ingredient")
+    }
+
+    // Validar unicidad del nombre si cambió
+    if ingredient.IngredientName != currentIngredient.IngredientName {
+        _, err := s.ingredientRepo.FindByName(ingredient.IngredientName)
+        if err == nil {
+            return models.Ingredient{}, errors.New("ingredient name already exists")
+        }
+        if !strings.Contains(err.Error(), "ingredient not found") {
+            return models.Ingredient{}, errors.Wrap(err, "failed to check ingredient name uniqueness")
+        }
+    }
+
+    updatedIngredient, err := s.ingredientRepo.Update(ingredient, employeeID)
+    if err != nil {
+        return models.Ingredient{}, errors.Wrap(err, "failed to update ingredient")
+    }
+    return updatedIngredient, nil
+}
+
+func (s *ingredientService) DeleteIngredient(ingredientID int) error {
+    if err := s.ingredientRepo.Delete(ingredientID); err != nil {
+        return errors.Wrap(err, "failed to delete ingredient")
+    }
+    return nil
+}
+
+func (s *ingredientService) GetRecipe(menuItemID int) ([]models.RecipeItem, error) {
+    // Validar que el ítem exista
+    if _, err := s.menuItemRepo.FindByID(menuItemID); err != nil {
+        return nil, errors.Wrap(err, "failed to find item")
+    }
+
+    items, err := s.recipeRepo.FindByMenuItemID(menuItemID)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to get recipe")
+    }
+    return items, nil
+}
+
+// SetRecipe reemplaza la receta del ítem; la cantidad de cada ingrediente se expresa en su unidad
+// y corresponde a una unidad vendida del ítem
+func (s *ingredientService) SetRecipe(menuItemID int, items []models.RecipeItem) ([]models.RecipeItem, error) {
+    // Validar que el ítem exista
+    if _, err := s.menuItemRepo.FindByID(menuItemID); err != nil {
+        return nil, errors.Wrap(err, "failed to find item")
+    }
+
+    seen := make(map[int]bool, len(items))
+    for _, item := range items {
+        // Validar que la cantidad sea mayor a 0
+        if item.Quantity.LessThanOrEqual(decimal.Zero) {
+            return nil, errors.New("recipe quantity must be greater than 0")
+        }
+
+        // Validar que el ingrediente no se repita
+        if seen[item.IngredientID] {
+            return nil, errors.New("ingredient repeated in recipe")
+        }
+        seen[item.IngredientID] = true
+
+        // Validar que el ingrediente exista
+        if _, err := s.ingredientRepo.FindByID(item.IngredientID); err != nil {
+            return nil, errors.Wrap(err, "failed to find ingredient")
+        }
+    }
+
+    if err := s.recipeRepo.Replace(menuItemID, items); err != nil {
+        return nil, errors.Wrap(err, "failed to save recipe")
+    }
+    return s.GetRecipe(menuItemID)
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+    "strings"
+
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/internal/repositories"
+
+    "github.com/pkg/errors"
+    "github.com/shopspring/decimal"
+)
+
+type InventoryCountService interface {
+    OpenCount(notes string, employeeID int) (models.InventoryCount, error)
+    GetCount(inventoryCountID int) (models.InventoryCount, error)
+    ListCounts() ([]models.InventoryCount, error)
+    RecordEntry(entry models.InventoryCountEntry) (models.InventoryCountEntry, error)
+    GetVariances(inventoryCountID int) ([]models.InventoryCountVariance, error)
+    ApproveCount(inventoryCountID, employeeID int) (models.InventoryCount, error)
+    CancelCount(inventoryCountID int) (models.InventoryCount, error)
+    GetShrinkageReport(inventoryCountID int) (models.InventoryShrinkageReport, error)
+}
+
+type inventoryCountService struct {
+    inventoryCountRepo repositories.InventoryCountRepository
+    menuItemRepo       repositories.MenuItemRepository
+}
+
+func NewInventoryCountService(inventoryCountRepo repositories.InventoryCountRepository, menuItemRepo repositories.MenuItemRepository) InventoryCountService {
+    return &inventoryCountService{
+        inventoryCountRepo: inventoryCountRepo,
+        menuItemRepo:       menuItemRepo,
+    }
+}
+
+func (s *inventoryCountService) OpenCount(notes string, employeeID int) (models.InventoryCount, error) {
+    count, err := s.inventoryCountRepo.Open(strings.TrimSpace(notes), employeeID)
+    if err != nil {
+        return models.InventoryCount{}, errors.Wrap(err, "failed to open inventory count")
+    }
+    return count, nil
+}
+
+func (s *inventoryCountService) GetCount(inventoryCountID int) (models.InventoryCount, error) {
+    count, err := s.inventoryCountRepo.FindByID(inventoryCountID)
+    if err != nil {
+        return models.InventoryCount{}, errors.Wrap(err, "failed to get inventory count")
+    }
+    return count, nil
+}
+
+func (s *inventoryCountService) ListCounts() ([]models.InventoryCount, error) {
+    counts, err := s.inventoryCountRepo.FindAll()
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to list inventory counts")
+    }
+    return counts, nil
+}
+
+// RecordEntry registra la cantidad que contó el empleado de un ítem con stock propio
+func (s *inventoryCountService) RecordEntry(entry models.InventoryCountEntry) (models.InventoryCountEntry, error) {
+    // Validar que la cantidad contada no sea negativa
+    if entry.CountedQuantity < 0 {
+        return models.InventoryCountEntry{}, errors.New("counted quantity cannot be negative")
+    }
+
+    // Validar que el ítem exista y que su stock no se derive de una receta
+    item, err := s.menuItemRepo.FindByID(entry.MenuItemID)
+    if err != nil {
+        return models.InventoryCountEntry{}, errors.Wrap(err, "failed to find item")
+    }
+    if item.StockMode == models.MenuItemStockModeRecipe {
+        return models.InventoryCountEntry{}, errors.New("item stock is derived from its recipe ingredients")
+    }
+
+    savedEntry, err := s.inventoryCountRepo.SaveEntry(entry)
+    if err != nil {
+        return models.InventoryCountEntry{}, errors.Wrap(err, "failed to record inventory count entry")
+    }
+    return savedEntry, nil
+}
+
+// GetVariances compara lo contado hasta el momento con el stock actual del sistema
+func (s *inventoryCountService) GetVariances(inventoryCountID int) ([]models.InventoryCountVariance, error) {
+    count, err := s.inventoryCountRepo.FindByID(inventoryCountID)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to find inventory count")
+    }
+
+    // Los conteos cerrados muestran las diferencias que quedaron registradas al aprobarlos
+    var variances []models.InventoryCountVariance
+    if count.Status == models.InventoryCountStatusOpen {
+        variances, err = s.inventoryCountRepo.FindVariances(inventoryCountID)
+    } else {
+        variances, err = s.inventoryCountRepo.FindApprovedLines(inventoryCountID)
+    }
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to get inventory count variances")
+    }
+    return variances, nil
+}
+
+// ApproveCount publica las diferencias del conteo como correcciones de stock
+func (s *inventoryCountService) ApproveCount(inventoryCountID, employeeID int) (models.InventoryCount, error) {
+    count, err := s.inventoryCountRepo.Approve(inventoryCountID, employeeID)
+    if err != nil {
+        return models.InventoryCount{}, errors.Wrap(err, "failed to approve inventory count")
+    }
+    return count, nil
+}
+
+func (s *inventoryCountService) CancelCount(inventoryCountID int) (models.InventoryCount, error) {
+    count, err := s.inventoryCountRepo.Cancel(inventoryCountID)
+    if err != nil {
+        return models.InventoryCount{}, errors.Wrap(err, "failed to cancel inventory count")
+    }
+    return count, nil
+}
+
+// GetShrinkageReport resume los faltantes y sobrantes que quedaron registrados al aprobar el conteo
+func (s *inventoryCountService) GetShrinkageReport(inventoryCountID int) (models.InventoryShrinkageReport, error) {
+    count, err := s.inventoryCountRepo.FindByID(inventoryCountID)
+    if err != nil {
+        return models.InventoryShrinkageReport{}, errors.Wrap(err, "failed to find inventory count")
+    }
+    if count.Status != models.InventoryCountStatusApproved {
+        return models.InventoryShrinkageReport{}, errors.New("inventory count is not approved")
+    }
+
+    lines, err := s.inventoryCountRepo.FindApprovedLines(inventoryCountID)
+    if err != nil {
+        return models.InventoryShrinkageReport{}, errors.Wrap(err, "failed to get inventory count lines")
+    }
+
+    report := models.InventoryShrinkageReport{
+        InventoryCountID: count.ID,
+        ApprovedBy:       count.ApprovedBy,
+        ApprovedAt:       count.ApprovedAt,
+        Lines:            lines,
+        ShrinkageValue:   decimal.Zero,
+    }
+    for _, line := range lines {
+        if line.Variance > 0 {
+            report.SurplusUnits += line.Variance
+            continue
+        }
+        report.ShrinkageUnits -= line.Variance
+        if line.VarianceValue != nil {
+            report.ShrinkageValue = report.ShrinkageValue.Sub(*line.VarianceValue)
+        }
+    }
+    return report, nil
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+    "encoding/csv"
+    "encoding/json"
+    "io"
+    "strconv"
+    "strings"
+
+    "gastrobar-backend/internal/models"
+
+    "github.com/pkg/errors"
+    "github.com/shopspring/decimal"
+)
+
+// Formatos aceptados para importar y exportar el menú
+const (
+    MenuFormatCSV  = "csv"
+    MenuFormatJSON = "json"
+)
+
+// menuCSVHeader define el orden de las columnas del CSV exportado
+var menuCSVHeader = []string{"item_name", "category", "price", "stock", "description"}
+
+// menuImportRow es una fila del archivo importado; ParseError se llena cuando la fila no se pudo leer
+type menuImportRow struct {
+    Item       models.MenuItem
+    ParseError string
+}
+
+// ImportMenuItems valida todas las filas con las mismas reglas que CreateMenuItem y, si no hay
+// errores y no es una simulación, crea o actualiza los ítems por nombre en una sola transacción
+func (s *menuItemService) ImportMenuItems(format string, data io.Reader, dryRun bool, employeeID int) (models.MenuItemImportResult, error) {
+    var rows []menuImportRow
+    var err error
+    switch format {
+    case MenuFormatCSV:
+        rows, err = parseMenuCSV(data)
+    case MenuFormatJSON:
+        rows, err = parseMenuJSON(data)
+    default:
+        return models.MenuItemImportResult{}, errors.New("unsupported import format")
+    }
+    if err != nil {
+        return models.MenuItemImportResult{}, err
+    }
+    if len(rows) == 0 {
+        return models.MenuItemImportResult{}, errors.New("import file has no rows")
+    }
+
+    // Obtener los ítems existentes para saber cuáles se crean y cuáles se actualizan
+    existingItems, err := s.menuItemRepo.FindAll()
+    if err != nil {
+        return models.MenuItemImportResult{}, errors.Wrap(err, "failed to list items")
+    }
+    existing := make(map[string]bool, len(existingItems))
+    for _, item := range existingItems {
+        existing[item.ItemName] = true
+    }
+
+    result := models.MenuItemImportResult{
+        DryRun: dryRun,
+        Total:  len(rows),
+        Errors: []models.MenuItemImportRowError{},
+    }
+    seen := make(map[string]int)
+    items := make([]models.MenuItem, 0, len(rows))
+    for i, row := range rows {
+        rowNumber := i + 1
+        item := row.Item
+        item.ItemName = strings.TrimSpace(item.ItemName)
+
+        rowErr := row.ParseError
+        if rowErr == "" {
+            if err := validateMenuItem(item); err != nil {
+                rowErr = err.Error()
+            } else if firstRow, ok := seen[item.ItemName]; ok {
+                rowErr = "item name duplicated in row " + strconv.Itoa(firstRow)
+            }
+        }
+        if rowErr != "" {
+            result.Errors = append(result.Errors, models.MenuItemImportRowError{
+                Row:      rowNumber,
+                ItemName: item.ItemName,
+                Error:    rowErr,
+            })
+            continue
+        }
+
+        seen[item.ItemName] = rowNumber
+        if existing[item.ItemName] {
+            result.Updated++
+        } else {
+            result.Created++
+        }
+        items = append(items, item)
+    }
+
+    // Todo o nada: si alguna fila es inválida no se aplica ningún cambio
+    if len(result.Errors) > 0 {
+        return result, errors.New("import has invalid rows")
+    }
+    if dryRun {
+        return result, nil
+    }
+
+    if err := s.menuItemRepo.UpsertBatch(items, employeeID); err != nil {
+        return models.MenuItemImportResult{}, errors.Wrap(err, "failed to import items")
+    }
+    return result, nil
+}
+
+// ExportMenuItems escribe todos los ítems del menú en el formato indicado, con las mismas
+// columnas que acepta ImportMenuItems
+func (s *menuItemService) ExportMenuItems(format string, w io.Writer) error {
+    if format != MenuFormatCSV && format != MenuFormatJSON {
+        return errors.New("unsupported export format")
+    }
+
+    items, err := s.menuItemRepo.FindAll()
+    if err != nil {
+        return errors.Wrap(err, "failed to list items")
+    }
+
+    if format == MenuFormatJSON {
+        type exportedItem struct {
+            ItemName    string          `json:"item_name"`
+            Category    string          `json:"category"`
+            Price       decimal.Decimal `json:"price"`
+            Stock       int             `json:"stock"`
+            Description string          `json:"description"`
+        }
+        exported := make([]exportedItem, len(items))
+        for i, item := range items {
+            exported[i] = exportedItem{item.ItemName, item.Category, item.Price, item.Stock, item.Description}
+        }
+        return json.NewEncoder(w).Encode(exported)
+    }
+
+    writer := csv.NewWriter(w)
+    if err := writer.Write(menuCSVHeader); err != nil {
+        return errors.Wrap(err, "failed to write csv header")
+    }
+    for _, item := range items {
+        record := []string{item.ItemName, item.Category, item.Price.StringFixed(2), strconv.Itoa(item.Stock), item.Description}
+        if err := writer.Write(record); err != nil {
+            return errors.Wrap(err, "failed to write csv row")
+        }
+    }
+    writer.Flush()
+    return writer.Error()
+}
+
+// parseMenuCSV lee un CSV con encabezado; las columnas se identifican por nombre
+// (se acepta "name" como alias de "item_name")
+func parseMenuCSV(data io.Reader) ([]menuImportRow, error) {
+    reader := csv.NewReader(data)
+    reader.TrimLeadingSpace = true
+    reader.FieldsPerRecord = -1
+
+    header, err := reader.Read()
+    if err == io.EOF {
+        return nil, errors.New("import file has no rows")
+    }
+    if err != nil {
+        return nil, errors.New("invalid csv file")
+    }
+
+    columns := make(map[string]int)
+    for i, name := range header {
+        name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+        if name == "name" {
+            name = "item_name"
+        }
+        columns[name] = i
+    }
+    for _, required := range []string{"item_name", "price"} {
+        if _, ok := columns[required]; !ok {
+            return nil, errors.New("csv header must include item_name and price columns")
+        }
+    }
+
+    var rows []menuImportRow
+    for {
+        record, err := reader.Read()
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            return nil, errors.New("invalid csv file")
+        }
+
+        field := func(name string) string {
+            if i, ok := columns[name]; ok && i < len(record) {
+                return strings.TrimSpace(record[i])
+            }
+            return ""
+        }
+
+        row := menuImportRow{Item: models.MenuItem{
+            ItemName:    field("item_name"),
+            Category:    field("category"),
+            Description: field("description"),
+        }}
+        if price, err := decimal.NewFromString(field("price")); err != nil {
+            row.ParseError = "invalid price"
+        } else {
+            row.Item.Price = price
+        }
+        if stock := field("stock"); stock != "" && row.ParseError == "" {
+            if row.Item.Stock, err = strconv.Atoi(stock); err != nil {
+                row.ParseError = "invalid stock"
+            }
+        }
+        rows = append(rows, row)
+    }
+    return rows, nil
+}
+
+// parseMenuJSON lee un arreglo JSON de ítems; cada elemento se decodifica por separado para
+// poder reportar el error de la fila en lugar de rechazar todo el archivo
+func parseMenuJSON(data io.Reader) ([]menuImportRow, error) {
+    var raw []json.RawMessage
+    if err := json.NewDecoder(data).Decode(&raw); err != nil {
+        return nil, errors.New("invalid json file")
+    }
+
+    rows := make([]menuImportRow, len(raw))
+    for i, element := range raw {
+        var entry struct {
+            ItemName    string          `json:"item_name"`
+            Name        string          `json:"name"`
+            Category    string          `json:"category"`
+            Price       decimal.Decimal `json:"price"`
+            Stock       int             `json:"stock"`
+            Description string          `json:"description"`
+        }
+        if err := json.Unmarshal(element, &entry); err != nil {
+            rows[i].ParseError = "invalid row: " + err.Error()
+            continue
+        }
+        if entry.ItemName == "" {
+            entry.ItemName = entry.Name
+        }
+        rows[i].Item = models.MenuItem{
+            ItemName:    entry.ItemName,
+            Category:    entry.Category,
+            Price:       entry.Price,
+            Stock:       entry.Stock,
+            Description: entry.Description,
+        }
+    }
+    return rows, nil
+}
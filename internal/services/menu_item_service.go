@@ -1,36 +1,50 @@
 package services
 
 import (
+	"io"
+	"log"
+	"strings"
+	"time"
+
 	"gastrobar-backend/internal/models"
 	"gastrobar-backend/internal/repositories"
-	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/shopspring/decimal"
 )
 
 type MenuItemService interface {
-    CreateMenuItem(item models.MenuItem) (models.MenuItem, error)
+    CreateMenuItem(item models.MenuItem, employeeID int) (models.MenuItem, error)
     GetMenuItem(itemID int) (models.MenuItem, error)
     ListMenuItems() ([]models.MenuItem, error)
-    UpdateMenuItem(item models.MenuItem) (models.MenuItem, error)
+    UpdateMenuItem(item models.MenuItem, employeeID int) (models.MenuItem, error)
     DeleteMenuItem(itemID int) error
+    GetPriceHistory(itemID int) ([]models.MenuItemPriceHistory, error)
+    SchedulePriceChange(change models.ScheduledPriceChange) (models.ScheduledPriceChange, error)
+    ListScheduledPriceChanges(itemID int) ([]models.ScheduledPriceChange, error)
+    CancelScheduledPriceChange(itemID int, changeID int) error
+    ApplyDueScheduledPriceChanges() (int, error)
+    ImportMenuItems(format string, data io.Reader, dryRun bool, employeeID int) (models.MenuItemImportResult, error)
+    ExportMenuItems(format string, w io.Writer) error
+    ListLowStockItems() ([]models.MenuItem, error)
 }
 
 type menuItemService struct {
-    menuItemRepo repositories.MenuItemRepository
+    menuItemRepo      repositories.MenuItemRepository
+    menuItemPriceRepo repositories.MenuItemPriceRepository
 }
 
-func NewMenuItemService(menuItemRepo repositories.MenuItemRepository) MenuItemService {
+func NewMenuItemService(menuItemRepo repositories.MenuItemRepository, menuItemPriceRepo repositories.MenuItemPriceRepository) MenuItemService {
     return &menuItemService{
-        menuItemRepo: menuItemRepo,
+        menuItemRepo:      menuItemRepo,
+        menuItemPriceRepo: menuItemPriceRepo,
     }
 }
 
-func (s *menuItemService) CreateMenuItem(item models.MenuItem) (models.MenuItem, error) {
-    // Validar que el nombre no esté vacío
-    if item.ItemName == "" {
-        return models.MenuItem{}, errors.New("item name cannot be empty")
+func (s *menuItemService) CreateMenuItem(item models.MenuItem, employeeID int) (models.MenuItem, error) {
+    // Validar los campos del ítem
+    if err := validateMenuItem(item); err != nil {
+        return models.MenuItem{}, err
     }
 
     // Validar que el nombre sea único
@@ -42,27 +56,51 @@ func (s *menuItemService) CreateMenuItem(item models.MenuItem) (models.MenuItem,
         return models.MenuItem{}, errors.Wrap(err, "failed to check item name uniqueness")
     }
 
+    // Por defecto el ítem maneja su propio stock
+    if item.StockMode == "" {
+        item.StockMode = models.MenuItemStockModeItem
+    }
+
+    // Crear el ítem en el repositorio
+    createdItem, err := s.menuItemRepo.Create(item, employeeID)
+    if err != nil {
+        return models.MenuItem{}, errors.Wrap(err, "failed to create item")
+    }
+    return createdItem, nil
+}
+
+// validateMenuItem aplica las reglas de validación de los campos de un ítem del menú
+func validateMenuItem(item models.MenuItem) error {
+    // Validar que el nombre no esté vacío
+    if item.ItemName == "" {
+        return errors.New("item name cannot be empty")
+    }
+
     // Validar que la descripción no esté vacía
     if item.Description == "" {
-        return models.MenuItem{}, errors.New("description cannot be empty")
+        return errors.New("description cannot be empty")
     }
 
     // Validar que el precio sea mayor a 0
     if item.Price.LessThanOrEqual(decimal.Zero) {
-        return models.MenuItem{}, errors.New("price must be greater than 0")
+        return errors.New("price must be greater than 0")
     }
 
     // Validar que el stock no sea negativo
     if item.Stock < 0 {
-        return models.MenuItem{}, errors.New("stock cannot be negative")
+        return errors.New("stock cannot be negative")
     }
 
-    // Crear el ítem en el repositorio
-    createdItem, err := s.menuItemRepo.Create(item)
-    if err != nil {
-        return models.MenuItem{}, errors.Wrap(err, "failed to create item")
+    // Validar que el umbral de reposición no sea negativo
+    if item.ReorderThreshold < 0 {
+        return errors.New("reorder threshold cannot be negative")
     }
-    return createdItem, nil
+
+    // Validar el modo de inventario (vacío conserva el actual o usa el predeterminado)
+    if item.StockMode != "" && item.StockMode != models.MenuItemStockModeItem && item.StockMode != models.MenuItemStockModeRecipe {
+        return errors.New("invalid stock mode")
+    }
+    return nil
 }
 
 func (s *menuItemService) GetMenuItem(itemID int) (models.MenuItem, error) {
@@ -81,10 +119,19 @@ func (s *menuItemService) ListMenuItems() ([]models.MenuItem, error) {
     return items, nil
 }
 
-func (s *menuItemService) UpdateMenuItem(item models.MenuItem) (models.MenuItem, error) {
-    // Validar que el nombre no esté vacío
-    if item.ItemName == "" {
-        return models.MenuItem{}, errors.New("item name cannot be empty")
+// ListLowStockItems lista los ítems cuya disponibilidad está por debajo de su umbral de reposición
+func (s *menuItemService) ListLowStockItems() ([]models.MenuItem, error) {
+    items, err := s.menuItemRepo.FindBelowReorderThreshold()
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to list low stock items")
+    }
+    return items, nil
+}
+
+func (s *menuItemService) UpdateMenuItem(item models.MenuItem, employeeID int) (models.MenuItem, error) {
+    // Validar los campos del ítem
+    if err := validateMenuItem(item); err != nil {
+        return models.MenuItem{}, err
     }
 
     // Obtener el ítem actual para verificar si el nombre cambió
@@ -104,26 +151,17 @@ func (s *menuItemService) UpdateMenuItem(item models.MenuItem) (models.MenuItem,
         }
     }
 
-    // Validar que la descripción no esté vacía
-    if item.Description == "" {
-        return models.MenuItem{}, errors.New("description cannot be empty")
-    }
-
-    // Validar que el precio sea mayor a 0
-    if item.Price.LessThanOrEqual(decimal.Zero) {
-        return models.MenuItem{}, errors.New("price must be greater than 0")
+    // Conservar el modo de inventario si no se envía
+    if item.StockMode == "" {
+        item.StockMode = currentItem.StockMode
     }
 
-    // Validar que el stock no sea negativo
-    if item.Stock < 0 {
-        return models.MenuItem{}, errors.New("stock cannot be negative")
-    }
-
-    // Actualizar el ítem en el repositorio
-    updatedItem, err := s.menuItemRepo.Update(item)
+    // Actualizar el ítem en el repositorio; el cambio de precio queda en el historial en la misma transacción
+    updatedItem, err := s.menuItemRepo.Update(item, employeeID)
     if err != nil {
         return models.MenuItem{}, errors.Wrap(err, "failed to update item")
     }
+
     return updatedItem, nil
 }
 
@@ -133,4 +171,106 @@ func (s *menuItemService) DeleteMenuItem(itemID int) error {
         return errors.Wrap(err, "failed to delete item")
     }
     return nil
-}
\ No newline at end of file
+}
+
+func (s *menuItemService) GetPriceHistory(itemID int) ([]models.MenuItemPriceHistory, error) {
+    // Validar que el ítem exista
+    if _, err := s.menuItemRepo.FindByID(itemID); err != nil {
+        return nil, errors.Wrap(err, "failed to find item")
+    }
+
+    history, err := s.menuItemPriceRepo.FindHistoryByMenuItemID(itemID)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to get price history")
+    }
+    return history, nil
+}
+
+func (s *menuItemService) SchedulePriceChange(change models.ScheduledPriceChange) (models.ScheduledPriceChange, error) {
+    // Validar que el ítem exista
+    if _, err := s.menuItemRepo.FindByID(change.MenuItemID); err != nil {
+        return models.ScheduledPriceChange{}, errors.Wrap(err, "failed to find item")
+    }
+
+    // Validar que el precio sea mayor a 0
+    if change.NewPrice.LessThanOrEqual(decimal.Zero) {
+        return models.ScheduledPriceChange{}, errors.New("price must be greater than 0")
+    }
+
+    // Validar que la fecha efectiva sea futura
+    if !change.EffectiveAt.After(time.Now()) {
+        return models.ScheduledPriceChange{}, errors.New("effective date must be in the future")
+    }
+
+    change.Status = models.ScheduledPriceStatusPending
+
+    createdChange, err := s.menuItemPriceRepo.CreateScheduled(change)
+    if err != nil {
+        return models.ScheduledPriceChange{}, errors.Wrap(err, "failed to schedule price change")
+    }
+    return createdChange, nil
+}
+
+func (s *menuItemService) ListScheduledPriceChanges(itemID int) ([]models.ScheduledPriceChange, error) {
+    // Validar que el ítem exista
+    if _, err := s.menuItemRepo.FindByID(itemID); err != nil {
+        return nil, errors.Wrap(err, "failed to find item")
+    }
+
+    changes, err := s.menuItemPriceRepo.FindScheduledByMenuItemID(itemID)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to list scheduled price changes")
+    }
+    return changes, nil
+}
+
+func (s *menuItemService) CancelScheduledPriceChange(itemID int, changeID int) error {
+    change, err := s.menuItemPriceRepo.FindScheduledByID(changeID)
+    if err != nil {
+        return errors.Wrap(err, "failed to find scheduled price change")
+    }
+
+    // Validar que el cambio pertenezca al ítem indicado
+    if change.MenuItemID != itemID {
+        return errors.New("scheduled price change not found")
+    }
+
+    // Solo se pueden cancelar cambios pendientes
+    if change.Status != models.ScheduledPriceStatusPending {
+        return errors.New("only pending price changes can be cancelled")
+    }
+
+    err = s.menuItemPriceRepo.UpdateScheduledStatus(changeID, models.ScheduledPriceStatusCancelled)
+    if err != nil {
+        // Se aplicó o canceló entre la lectura y la actualización
+        if strings.Contains(err.Error(), "scheduled price change is not pending") {
+            return errors.New("only pending price changes can be cancelled")
+        }
+        return errors.Wrap(err, "failed to cancel scheduled price change")
+    }
+    return nil
+}
+
+// ApplyDueScheduledPriceChanges aplica los cambios de precio cuya fecha efectiva ya pasó
+// y devuelve cuántos se aplicaron. Un cambio que falla se registra en el log y no impide aplicar
+// los siguientes; sigue pendiente y se reintenta en la próxima ejecución
+func (s *menuItemService) ApplyDueScheduledPriceChanges() (int, error) {
+    changes, err := s.menuItemPriceRepo.FindDueScheduled(time.Now())
+    if err != nil {
+        return 0, errors.Wrap(err, "failed to find due price changes")
+    }
+
+    applied := 0
+    for _, change := range changes {
+        if err := s.menuItemPriceRepo.ApplyScheduled(change); err != nil {
+            // Se canceló o lo aplicó otra ejecución después de buscarlo
+            if strings.Contains(err.Error(), "scheduled price change is not pending") {
+                continue
+            }
+            log.Printf("Error applying scheduled price change %d: %v", change.ID, err)
+            continue
+        }
+        applied++
+    }
+    return applied, nil
+}
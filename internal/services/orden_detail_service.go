@@ -1,8 +1,11 @@
 package services
 
 import (
+	"fmt"
 	"gastrobar-backend/internal/models"
 	"gastrobar-backend/internal/repositories"
+	"gastrobar-backend/pkg/notifier"
+	"log"
 	"time"
 
 	"github.com/pkg/errors"
@@ -15,6 +18,8 @@ type OrderDetailService interface {
 	GetOrderDetailsByOrderID(orderID int) ([]models.OrderDetail, error)
 	UpdateOrderDetail(orderDetail models.OrderDetail) (models.OrderDetail, error)
 	DeleteOrderDetail(id int) error
+	ListPendingConfirmation() ([]models.OrderDetail, error)
+	ConfirmOrderDetail(id int) (models.OrderDetail, error)
 }
 
 type orderDetailService struct {
@@ -22,6 +27,7 @@ type orderDetailService struct {
 	customerOrderRepo repositories.CustomerOrderRepository
 	menuItemRepo      repositories.MenuItemRepository
 	tableRepo         repositories.TableRepository
+	alertNotifier     notifier.Notifier
 }
 
 func NewOrderDetailService(
@@ -29,21 +35,26 @@ func NewOrderDetailService(
 	customerOrderRepo repositories.CustomerOrderRepository,
 	menuItemRepo repositories.MenuItemRepository,
 	tableRepo repositories.TableRepository,
+	alertNotifier notifier.Notifier,
 ) OrderDetailService {
 	return &orderDetailService{
 		orderDetailRepo:   orderDetailRepo,
 		customerOrderRepo: customerOrderRepo,
 		menuItemRepo:      menuItemRepo,
 		tableRepo:         tableRepo,
+		alertNotifier:     alertNotifier,
 	}
 }
 
 func (s *orderDetailService) CreateOrderDetail(orderDetail models.OrderDetail, tableID int) (models.OrderDetail, error) {
-	// Validar que la mesa exista
-	_, err := s.tableRepo.FindByID(tableID)
+	// Validar que la mesa exista y esté activa
+	table, err := s.tableRepo.FindByID(tableID)
 	if err != nil {
 		return models.OrderDetail{}, errors.Wrap(err, "failed to find table")
 	}
+	if !table.Active {
+		return models.OrderDetail{}, errors.New("table is inactive")
+	}
 
 	var customerOrder models.CustomerOrder
 	var menu_item models.MenuItem
@@ -62,11 +73,12 @@ func (s *orderDetailService) CreateOrderDetail(orderDetail models.OrderDetail, t
 			// La última orden está completada, podemos proceder a crear una nueva
 		}
 
-		// Crear un nuevo customer_order
+		// Crear un nuevo customer_order; quien carga el primer pedido es quien abre la mesa
 		newOrder := models.CustomerOrder{
 			TableID:     tableID,
 			TotalAmount: decimal.NewFromFloat(0.0),
 			Status:      "pending",
+			OpenedBy:    orderDetail.CreatedBy,
 			CreatedAt:   time.Now(),
 		}
 		customerOrder, err = s.customerOrderRepo.Create(newOrder)
@@ -93,16 +105,24 @@ func (s *orderDetailService) CreateOrderDetail(orderDetail models.OrderDetail, t
 		return models.OrderDetail{}, errors.Wrap(err, "failed to find menu item")
 	}
 
-	// Validar el stock
-	if menuItem.Stock < orderDetail.Quantity {
+	// Validar el stock (propio o derivado de los ingredientes de la receta)
+	if menuItem.AvailableQuantity < orderDetail.Quantity {
 		return models.OrderDetail{}, errors.Errorf("insufficient stock for item %s (ID: %d). Available: %d, Requested: %d",
-			menuItem.ItemName, menuItem.ID, menuItem.Stock, orderDetail.Quantity)
+			menuItem.ItemName, menuItem.ID, menuItem.AvailableQuantity, orderDetail.Quantity)
 	}
 
 	// Asignar el order_id y created_at
 	orderDetail.OrderID = customerOrder.ID
 	orderDetail.CreatedAt = time.Now()
 
+	// Por defecto las líneas las registra el personal y quedan confirmadas
+	if orderDetail.Source == "" {
+		orderDetail.Source = models.OrderDetailSourceStaff
+	}
+	if orderDetail.Status == "" {
+		orderDetail.Status = models.OrderDetailStatusConfirmed
+	}
+
 	// Crear el order_detail
 	createdDetail, err := s.orderDetailRepo.Create(orderDetail)
 	if err != nil {
@@ -112,6 +132,11 @@ func (s *orderDetailService) CreateOrderDetail(orderDetail models.OrderDetail, t
 	menu_item, err = s.menuItemRepo.FindByID(orderDetail.MenuItemID)
 	createdDetail.MenuItem = menu_item
 
+	// Emitir la alerta si esta línea dejó el ítem por debajo de su umbral de reposición
+	if err == nil {
+		s.notifyLowStock(menuItem, menu_item, createdDetail.ID)
+	}
+
 	return createdDetail, nil
 }
 
@@ -163,16 +188,26 @@ func (s *orderDetailService) UpdateOrderDetail(orderDetail models.OrderDetail) (
 		return models.OrderDetail{}, errors.New("cannot update order detail: customer order is already completed")
 	}
 
+	// Obtener la línea actual: el stock ya descontado por ella se devuelve al modificarla
+	currentDetail, err := s.orderDetailRepo.FindByID(orderDetail.ID)
+	if err != nil {
+		return models.OrderDetail{}, errors.Wrap(err, "failed to find order detail")
+	}
+
 	// Validar el menu_item y el stock
 	menuItem, err := s.menuItemRepo.FindByID(orderDetail.MenuItemID)
 	if err != nil {
 		return models.OrderDetail{}, errors.Wrap(err, "failed to find menu item")
 	}
 
-	// Validar el stock
-	if menuItem.Stock < orderDetail.Quantity {
+	// Validar el stock (propio o derivado de los ingredientes de la receta) solo por la cantidad adicional
+	requested := orderDetail.Quantity
+	if currentDetail.MenuItemID == orderDetail.MenuItemID {
+		requested = orderDetail.Quantity - currentDetail.Quantity
+	}
+	if requested > 0 && menuItem.AvailableQuantity < requested {
 		return models.OrderDetail{}, errors.Errorf("insufficient stock for item %s (ID: %d). Available: %d, Requested: %d",
-			menuItem.ItemName, menuItem.ID, menuItem.Stock, orderDetail.Quantity)
+			menuItem.ItemName, menuItem.ID, menuItem.AvailableQuantity, requested)
 	}
 
 	// Actualizar el order_detail
@@ -181,6 +216,10 @@ func (s *orderDetailService) UpdateOrderDetail(orderDetail models.OrderDetail) (
 		return models.OrderDetail{}, errors.Wrap(err, "failed to update order detail")
 	}
 
+	if updatedItem, err := s.menuItemRepo.FindByID(orderDetail.MenuItemID); err == nil {
+		s.notifyLowStock(menuItem, updatedItem, updatedDetail.ID)
+	}
+
 	return updatedDetail, nil
 }
 
@@ -212,3 +251,69 @@ func (s *orderDetailService) DeleteOrderDetail(id int) error {
 
 	return nil
 }
+
+// ListPendingConfirmation lista las líneas hechas por clientes que esperan confirmación de un mesero
+func (s *orderDetailService) ListPendingConfirmation() ([]models.OrderDetail, error) {
+	orderDetails, err := s.orderDetailRepo.FindByStatus(models.OrderDetailStatusPendingConfirmation)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list order details pending confirmation")
+	}
+	return orderDetails, nil
+}
+
+// ConfirmOrderDetail confirma una línea hecha por un cliente para que pase a cocina
+func (s *orderDetailService) ConfirmOrderDetail(id int) (models.OrderDetail, error) {
+	if id <= 0 {
+		return models.OrderDetail{}, errors.New("invalid order detail ID")
+	}
+
+	orderDetail, err := s.orderDetailRepo.FindByID(id)
+	if err != nil {
+		return models.OrderDetail{}, errors.Wrap(err, "failed to find order detail")
+	}
+	if orderDetail.Status != models.OrderDetailStatusPendingConfirmation {
+		return models.OrderDetail{}, errors.New("order detail is not pending confirmation")
+	}
+
+	// Validar que la customer_order no esté completada
+	customerOrder, err := s.customerOrderRepo.FindByID(orderDetail.OrderID)
+	if err != nil {
+		return models.OrderDetail{}, errors.Wrap(err, "failed to find customer order")
+	}
+	if customerOrder.Status == "completed" {
+		return models.OrderDetail{}, errors.New("cannot confirm order detail: customer order is already completed")
+	}
+
+	confirmedDetail, err := s.orderDetailRepo.UpdateStatus(id, models.OrderDetailStatusConfirmed)
+	if err != nil {
+		return models.OrderDetail{}, errors.Wrap(err, "failed to confirm order detail")
+	}
+	return confirmedDetail, nil
+}
+
+// notifyLowStock emite una alerta cuando la disponibilidad del ítem cruza su umbral de reposición.
+// La entrega es asíncrona para no demorar el pedido si el destino (p. ej. un webhook) es lento
+func (s *orderDetailService) notifyLowStock(before, after models.MenuItem, orderDetailID int) {
+	threshold := after.ReorderThreshold
+	if threshold <= 0 || before.AvailableQuantity < threshold || after.AvailableQuantity >= threshold {
+		return
+	}
+
+	event := notifier.Event{
+		Type:    "low_stock",
+		Message: fmt.Sprintf("%s is below its reorder threshold (%d available, threshold %d)", after.ItemName, after.AvailableQuantity, threshold),
+		Data: models.LowStockAlert{
+			MenuItemID:        after.ID,
+			ItemName:          after.ItemName,
+			AvailableQuantity: after.AvailableQuantity,
+			ReorderThreshold:  threshold,
+			OrderDetailID:     orderDetailID,
+		},
+		OccurredAt: time.Now(),
+	}
+	go func() {
+		if err := s.alertNotifier.Notify(event); err != nil {
+			log.Printf("Error sending low stock alert for item %d: %v", after.ID, err)
+		}
+	}()
+}
@@ -0,0 +1,479 @@
+package services
+
+import (
+    "encoding/csv"
+    "encoding/json"
+    "io"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/internal/repositories"
+
+    "github.com/pkg/errors"
+    "github.com/shopspring/decimal"
+)
+
+type PayrollService interface {
+    ListRates() ([]models.PayRate, error)
+    SetRate(employeeID int, hourlyRate decimal.Decimal, updatedBy int) (models.PayRate, error)
+    GetSettings() (models.PayrollSettings, error)
+    UpdateSettings(settings models.PayrollSettings, updatedBy int) (models.PayrollSettings, error)
+    ListHolidays(from, to *time.Time) ([]models.Holiday, error)
+    AddHoliday(holiday models.Holiday) (models.Holiday, error)
+    DeleteHoliday(date string) error
+    ListTips(from, to *time.Time) ([]models.TipPoolEntry, error)
+    AddTip(entry models.TipPoolEntry, createdBy int) (models.TipPoolEntry, error)
+    DeleteTip(entryID int) error
+    CalculatePayroll(from, to *time.Time) (models.PayrollReport, error)
+    ExportPayroll(report models.PayrollReport, format string, w io.Writer) error
+}
+
+type payrollService struct {
+    payrollRepo   repositories.PayrollRepository
+    timeClockRepo repositories.TimeClockRepository
+}
+
+func NewPayrollService(payrollRepo repositories.PayrollRepository, timeClockRepo repositories.TimeClockRepository) PayrollService {
+    return &payrollService{
+        payrollRepo:   payrollRepo,
+        timeClockRepo: timeClockRepo,
+    }
+}
+
+var payrollCSVHeader = []string{
+    "employee_id", "employee_name", "hourly_rate", "worked_hours", "regular_hours", "overtime_hours", "night_hours", "holiday_hours",
+    "base_pay", "overtime_pay", "night_pay", "holiday_pay", "gross_pay", "tip_share", "total_pay", "missing_rate", "open_entries",
+}
+
+var minutesPerHour = decimal.NewFromInt(60)
+
+func (s *payrollService) ListRates() ([]models.PayRate, error) {
+    rates, err := s.payrollRepo.FindRates()
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to list pay rates")
+    }
+    return rates, nil
+}
+
+func (s *payrollService) SetRate(employeeID int, hourlyRate decimal.Decimal, updatedBy int) (models.PayRate, error) {
+    if hourlyRate.IsNegative() {
+        return models.PayRate{}, errors.New("hourly rate cannot be negative")
+    }
+
+    rate, err := s.payrollRepo.SetRate(employeeID, hourlyRate.Round(2), updatedBy)
+    if err != nil {
+        return models.PayRate{}, errors.Wrap(err, "failed to set pay rate")
+    }
+    return rate, nil
+}
+
+func (s *payrollService) GetSettings() (models.PayrollSettings, error) {
+    settings, err := s.payrollRepo.FindSettings()
+    if err != nil {
+        return models.PayrollSettings{}, errors.Wrap(err, "failed to get payroll settings")
+    }
+    return settings, nil
+}
+
+// parseClockMinutes convierte una hora HH:MM en los minutos desde la medianoche
+func parseClockMinutes(value string) (int, error) {
+    t, err := time.Parse("15:04", value)
+    if err != nil {
+        return 0, errors.New("invalid time, use HH:MM")
+    }
+    return t.Hour()*60 + t.Minute(), nil
+}
+
+func (s *payrollService) UpdateSettings(settings models.PayrollSettings, updatedBy int) (models.PayrollSettings, error) {
+    // Validar el umbral de horas extra y los recargos
+    if !settings.DailyOvertimeHours.IsPositive() || settings.DailyOvertimeHours.GreaterThan(decimal.NewFromInt(24)) {
+        return models.PayrollSettings{}, errors.New("daily overtime hours must be between 0 and 24")
+    }
+    if settings.OvertimeSurcharge.IsNegative() || settings.NightSurcharge.IsNegative() || settings.HolidaySurcharge.IsNegative() {
+        return models.PayrollSettings{}, errors.New("surcharges cannot be negative")
+    }
+
+    // Validar la franja nocturna
+    nightStart, err := parseClockMinutes(settings.NightStart)
+    if err != nil {
+        return models.PayrollSettings{}, errors.New("invalid night start, use HH:MM")
+    }
+    nightEnd, err := parseClockMinutes(settings.NightEnd)
+    if err != nil {
+        return models.PayrollSettings{}, errors.New("invalid night end, use HH:MM")
+    }
+    if nightStart == nightEnd {
+        return models.PayrollSettings{}, errors.New("night start and end must differ")
+    }
+
+    settings.UpdatedBy = &updatedBy
+    updatedSettings, err := s.payrollRepo.UpdateSettings(settings)
+    if err != nil {
+        return models.PayrollSettings{}, errors.Wrap(err, "failed to update payroll settings")
+    }
+    return updatedSettings, nil
+}
+
+func (s *payrollService) ListHolidays(from, to *time.Time) ([]models.Holiday, error) {
+    if from != nil && to != nil && !from.Before(*to) {
+        return nil, errors.New("from must be before to")
+    }
+
+    holidays, err := s.payrollRepo.FindHolidays(from, to)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to list holidays")
+    }
+    return holidays, nil
+}
+
+func (s *payrollService) AddHoliday(holiday models.Holiday) (models.Holiday, error) {
+    holiday.Description = strings.TrimSpace(holiday.Description)
+    if _, err := time.Parse("2006-01-02", holiday.Date); err != nil {
+        return models.Holiday{}, errors.New("invalid holiday date, use YYYY-MM-DD")
+    }
+    if holiday.Description == "" {
+        return models.Holiday{}, errors.New("holiday description cannot be empty")
+    }
+
+    createdHoliday, err := s.payrollRepo.CreateHoliday(holiday)
+    if err != nil {
+        return models.Holiday{}, errors.Wrap(err, "failed to add holiday")
+    }
+    return createdHoliday, nil
+}
+
+func (s *payrollService) DeleteHoliday(date string) error {
+    if _, err := time.Parse("2006-01-02", date); err != nil {
+        return errors.New("invalid holiday date, use YYYY-MM-DD")
+    }
+    if err := s.payrollRepo.DeleteHoliday(date); err != nil {
+        return errors.Wrap(err, "failed to delete holiday")
+    }
+    return nil
+}
+
+func (s *payrollService) ListTips(from, to *time.Time) ([]models.TipPoolEntry, error) {
+    if from != nil && to != nil && !from.Before(*to) {
+        return nil, errors.New("from must be before to")
+    }
+
+    entries, err := s.payrollRepo.FindTipEntries(from, to)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to list tips")
+    }
+    return entries, nil
+}
+
+// AddTip registra propinas recaudadas; si no se indica la fecha se toma la de hoy
+func (s *payrollService) AddTip(entry models.TipPoolEntry, createdBy int) (models.TipPoolEntry, error) {
+    entry.Notes = strings.TrimSpace(entry.Notes)
+    if entry.CollectedOn == "" {
+        entry.CollectedOn = time.Now().Format("2006-01-02")
+    }
+    if _, err := time.Parse("2006-01-02", entry.CollectedOn); err != nil {
+        return models.TipPoolEntry{}, errors.New("invalid collection date, use YYYY-MM-DD")
+    }
+    if !entry.Amount.IsPositive() {
+        return models.TipPoolEntry{}, errors.New("amount must be greater than 0")
+    }
+    entry.Amount = entry.Amount.Round(2)
+    entry.CreatedBy = createdBy
+
+    createdEntry, err := s.payrollRepo.CreateTipEntry(entry)
+    if err != nil {
+        return models.TipPoolEntry{}, errors.Wrap(err, "failed to add tip")
+    }
+    return createdEntry, nil
+}
+
+func (s *payrollService) DeleteTip(entryID int) error {
+    if err := s.payrollRepo.DeleteTipEntry(entryID); err != nil {
+        return errors.Wrap(err, "failed to delete tip")
+    }
+    return nil
+}
+
+// fortnight devuelve la quincena que contiene a t: del 1 al 15 o del 16 a fin de mes
+func fortnight(t time.Time) (time.Time, time.Time) {
+    t = t.In(time.Local)
+    if t.Day() <= 15 {
+        start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.Local)
+        return start, start.AddDate(0, 0, 15)
+    }
+    start := time.Date(t.Year(), t.Month(), 16, 0, 0, 0, 0, time.Local)
+    return start, time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.Local)
+}
+
+// payrollMinutes acumula los minutos trabajados de un empleado clasificados para la liquidación
+type payrollMinutes struct {
+    worked, overtime, night, holiday int
+}
+
+// payrollClassifier clasifica cada minuto trabajado según los parámetros de la nómina
+type payrollClassifier struct {
+    settings        models.PayrollSettings
+    nightStart      int
+    nightEnd        int
+    overtimeMinutes int
+    holidays        map[string]bool
+}
+
+func (c payrollClassifier) isNight(t time.Time) bool {
+    minute := t.Hour()*60 + t.Minute()
+    if c.nightStart < c.nightEnd {
+        return minute >= c.nightStart && minute < c.nightEnd
+    }
+    // La franja cruza la medianoche (por ejemplo de 21:00 a 06:00)
+    return minute >= c.nightStart || minute < c.nightEnd
+}
+
+func (c payrollClassifier) isHoliday(t time.Time) bool {
+    return c.holidays[t.Format("2006-01-02")] || (c.settings.SundayIsHoliday && t.Weekday() == time.Sunday)
+}
+
+// addEntry clasifica minuto a minuto una jornada cerrada sin sus pausas. Las horas extra se cuentan
+// por día de entrada: los minutos que superan el umbral diario en las jornadas que empiezan ese día.
+// dayMinutes lleva lo ya trabajado por día y las jornadas deben llegar en orden cronológico
+func (c payrollClassifier) addEntry(totals *payrollMinutes, dayMinutes map[string]int, entry models.TimeEntry) {
+    day := entry.ClockInAt.In(time.Local).Format("2006-01-02")
+    end := entry.End(entry.ClockInAt)
+    for t := entry.ClockInAt; !t.Add(time.Minute).After(end); t = t.Add(time.Minute) {
+        onBreak := false
+        for _, b := range entry.Breaks {
+            if !t.Before(b.StartedAt) && (b.EndedAt == nil || t.Before(*b.EndedAt)) {
+                onBreak = true
+                break
+            }
+        }
+        if onBreak {
+            continue
+        }
+
+        local := t.In(time.Local)
+        totals.worked++
+        dayMinutes[day]++
+        if dayMinutes[day] > c.overtimeMinutes {
+            totals.overtime++
+        }
+        if c.isNight(local) {
+            totals.night++
+        }
+        if c.isHoliday(local) {
+            totals.holiday++
+        }
+    }
+}
+
+// minutesToHours convierte minutos en horas con dos decimales
+func minutesToHours(minutes int) decimal.Decimal {
+    return decimal.NewFromInt(int64(minutes)).Div(minutesPerHour).Round(2)
+}
+
+// minutesPay calcula lo que se paga por una cantidad de minutos a la tarifa indicada con el factor dado
+func minutesPay(minutes int, rate, factor decimal.Decimal) decimal.Decimal {
+    return rate.Mul(decimal.NewFromInt(int64(minutes))).Div(minutesPerHour).Mul(factor).Round(2)
+}
+
+// CalculatePayroll liquida las jornadas cerradas que empiezan en el periodo (por defecto la
+// quincena actual): horas regulares, extra, nocturnas y festivas a la tarifa de cada empleado, más
+// su parte del fondo de propinas del periodo repartido según las horas trabajadas
+func (s *payrollService) CalculatePayroll(from, to *time.Time) (models.PayrollReport, error) {
+    reference := time.Now()
+    if from != nil {
+        reference = *from
+    }
+    start, end := fortnight(reference)
+    if from != nil {
+        start = *from
+    }
+    if to != nil {
+        end = *to
+    }
+    if !start.Before(end) {
+        return models.PayrollReport{}, errors.New("from must be before to")
+    }
+
+    settings, err := s.payrollRepo.FindSettings()
+    if err != nil {
+        return models.PayrollReport{}, errors.Wrap(err, "failed to get payroll settings")
+    }
+    rates, err := s.payrollRepo.FindRates()
+    if err != nil {
+        return models.PayrollReport{}, errors.Wrap(err, "failed to get pay rates")
+    }
+    holidays, err := s.payrollRepo.FindHolidays(&start, &end)
+    if err != nil {
+        return models.PayrollReport{}, errors.Wrap(err, "failed to get holidays")
+    }
+    tips, err := s.payrollRepo.FindTipEntries(&start, &end)
+    if err != nil {
+        return models.PayrollReport{}, errors.Wrap(err, "failed to get tips")
+    }
+    entries, err := s.timeClockRepo.FindEntries(0, start, end)
+    if err != nil {
+        return models.PayrollReport{}, errors.Wrap(err, "failed to get time entries")
+    }
+
+    classifier := payrollClassifier{
+        settings:        settings,
+        overtimeMinutes: int(settings.DailyOvertimeHours.Mul(minutesPerHour).IntPart()),
+        holidays:        make(map[string]bool),
+    }
+    if classifier.nightStart, err = parseClockMinutes(settings.NightStart); err != nil {
+        return models.PayrollReport{}, errors.Wrap(err, "invalid night start in payroll settings")
+    }
+    if classifier.nightEnd, err = parseClockMinutes(settings.NightEnd); err != nil {
+        return models.PayrollReport{}, errors.Wrap(err, "invalid night end in payroll settings")
+    }
+    for _, holiday := range holidays {
+        classifier.holidays[holiday.Date] = true
+    }
+
+    // Clasificar los minutos de cada empleado; las jornadas que empezaron antes del periodo se
+    // liquidaron en el anterior y las abiertas quedan pendientes
+    minutes := make(map[int]*payrollMinutes)
+    dayMinutes := make(map[int]map[string]int)
+    openEntries := make(map[int]int)
+    for _, entry := range entries {
+        if entry.ClockInAt.Before(start) {
+            continue
+        }
+        if entry.ClockOutAt == nil {
+            openEntries[entry.EmployeeID]++
+            continue
+        }
+        if minutes[entry.EmployeeID] == nil {
+            minutes[entry.EmployeeID] = &payrollMinutes{}
+            dayMinutes[entry.EmployeeID] = make(map[string]int)
+        }
+        classifier.addEntry(minutes[entry.EmployeeID], dayMinutes[entry.EmployeeID], entry)
+    }
+
+    report := models.PayrollReport{
+        From:              start,
+        To:                end,
+        Settings:          settings,
+        Lines:             []models.PayrollLine{},
+        TipPool:           decimal.Zero,
+        WorkedHours:       decimal.Zero,
+        GrossPay:          decimal.Zero,
+        TipShare:          decimal.Zero,
+        UndistributedTips: decimal.Zero,
+        TotalPay:          decimal.Zero,
+    }
+    for _, tip := range tips {
+        report.TipPool = report.TipPool.Add(tip.Amount)
+    }
+
+    totalMinutes := 0
+    var lineMinutes []int
+    for _, rate := range rates {
+        worked := minutes[rate.EmployeeID]
+        if worked == nil && openEntries[rate.EmployeeID] == 0 {
+            continue
+        }
+        if worked == nil {
+            worked = &payrollMinutes{}
+        }
+
+        line := models.PayrollLine{
+            EmployeeID:    rate.EmployeeID,
+            EmployeeName:  rate.EmployeeName,
+            HourlyRate:    rate.HourlyRate,
+            MissingRate:   rate.HourlyRate == nil,
+            WorkedHours:   minutesToHours(worked.worked),
+            RegularHours:  minutesToHours(worked.worked - worked.overtime),
+            OvertimeHours: minutesToHours(worked.overtime),
+            NightHours:    minutesToHours(worked.night),
+            HolidayHours:  minutesToHours(worked.holiday),
+            BasePay:       decimal.Zero,
+            OvertimePay:   decimal.Zero,
+            NightPay:      decimal.Zero,
+            HolidayPay:    decimal.Zero,
+            OpenEntries:   openEntries[rate.EmployeeID],
+        }
+        if rate.HourlyRate != nil {
+            line.BasePay = minutesPay(worked.worked, *rate.HourlyRate, decimal.NewFromInt(1))
+            line.OvertimePay = minutesPay(worked.overtime, *rate.HourlyRate, settings.OvertimeSurcharge)
+            line.NightPay = minutesPay(worked.night, *rate.HourlyRate, settings.NightSurcharge)
+            line.HolidayPay = minutesPay(worked.holiday, *rate.HourlyRate, settings.HolidaySurcharge)
+        } else {
+            report.MissingRates++
+        }
+        line.GrossPay = line.BasePay.Add(line.OvertimePay).Add(line.NightPay).Add(line.HolidayPay)
+
+        report.Lines = append(report.Lines, line)
+        lineMinutes = append(lineMinutes, worked.worked)
+        totalMinutes += worked.worked
+        report.OpenEntries += line.OpenEntries
+    }
+
+    // Repartir el fondo de propinas según los minutos trabajados; el residuo del redondeo se le
+    // suma a quien más trabajó para que el reparto cuadre con el fondo. Si nadie trabajó en el
+    // periodo, el fondo queda sin repartir y se informa para que el dueño decida qué hacer con él
+    if totalMinutes == 0 {
+        report.UndistributedTips = report.TipPool
+    } else {
+        distributed := decimal.Zero
+        largest := 0
+        for i := range report.Lines {
+            share := report.TipPool.Mul(decimal.NewFromInt(int64(lineMinutes[i]))).Div(decimal.NewFromInt(int64(totalMinutes))).Round(2)
+            report.Lines[i].TipShare = share
+            distributed = distributed.Add(share)
+            if lineMinutes[i] > lineMinutes[largest] {
+                largest = i
+            }
+        }
+        report.Lines[largest].TipShare = report.Lines[largest].TipShare.Add(report.TipPool.Sub(distributed))
+    }
+
+    for i := range report.Lines {
+        line := &report.Lines[i]
+        line.TotalPay = line.GrossPay.Add(line.TipShare)
+        report.WorkedHours = report.WorkedHours.Add(line.WorkedHours)
+        report.GrossPay = report.GrossPay.Add(line.GrossPay)
+        report.TipShare = report.TipShare.Add(line.TipShare)
+        report.TotalPay = report.TotalPay.Add(line.TotalPay)
+    }
+    sort.SliceStable(report.Lines, func(i, j int) bool { return report.Lines[i].EmployeeName < report.Lines[j].EmployeeName })
+    return report, nil
+}
+
+// ExportPayroll escribe la liquidación en el formato indicado: en CSV una fila por empleado con los
+// importes con dos decimales, en JSON el reporte completo
+func (s *payrollService) ExportPayroll(report models.PayrollReport, format string, w io.Writer) error {
+    if format != MenuFormatCSV && format != MenuFormatJSON {
+        return errors.New("unsupported export format")
+    }
+
+    if format == MenuFormatJSON {
+        return json.NewEncoder(w).Encode(report)
+    }
+
+    writer := csv.NewWriter(w)
+    if err := writer.Write(payrollCSVHeader); err != nil {
+        return errors.Wrap(err, "failed to write csv header")
+    }
+    for _, line := range report.Lines {
+        hourlyRate := ""
+        if line.HourlyRate != nil {
+            hourlyRate = line.HourlyRate.StringFixed(2)
+        }
+        record := []string{
+            strconv.Itoa(line.EmployeeID), line.EmployeeName, hourlyRate,
+            line.WorkedHours.StringFixed(2), line.RegularHours.StringFixed(2), line.OvertimeHours.StringFixed(2),
+            line.NightHours.StringFixed(2), line.HolidayHours.StringFixed(2),
+            line.BasePay.StringFixed(2), line.OvertimePay.StringFixed(2), line.NightPay.StringFixed(2), line.HolidayPay.StringFixed(2),
+            line.GrossPay.StringFixed(2), line.TipShare.StringFixed(2), line.TotalPay.StringFixed(2),
+            strconv.FormatBool(line.MissingRate), strconv.Itoa(line.OpenEntries),
+        }
+        if err := writer.Write(record); err != nil {
+            return errors.Wrap(err, "failed to write csv row")
+        }
+    }
+    writer.Flush()
+    return writer.Error()
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+    "sync"
+    "time"
+
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/internal/repositories"
+
+    "github.com/pkg/errors"
+)
+
+// PermissionCacheTTL es cuánto se reutilizan en memoria los permisos de los roles antes de volver a
+// leerlos; los cambios hechos en esta instancia se aplican al instante y los de otras, al vencer
+const PermissionCacheTTL = time.Minute
+
+type PermissionService interface {
+    ListPermissions() ([]models.PermissionInfo, error)
+    ListRolePermissions() ([]models.RolePermissions, error)
+    GetRolePermissions(role models.EmployeeRole) (models.RolePermissions, error)
+    SetRolePermissions(role models.EmployeeRole, request models.RolePermissionsRequest, employeeID int) (models.RolePermissions, error)
+    HasPermission(role models.EmployeeRole, permission models.Permission) (bool, error)
+    SeedDefaultRolePermissions() (bool, error)
+}
+
+type permissionService struct {
+    permissionRepo repositories.PermissionRepository
+
+    // Caché de los permisos por rol que consulta el middleware en cada solicitud
+    mu       sync.RWMutex
+    granted  map[models.EmployeeRole]map[models.Permission]bool
+    loadedAt time.Time
+    version  int // Se incrementa al invalidar, para descartar cargas que empezaron antes del cambio
+}
+
+func NewPermissionService(permissionRepo repositories.PermissionRepository) PermissionService {
+    return &permissionService{
+        permissionRepo: permissionRepo,
+    }
+}
+
+func (s *permissionService) ListPermissions() ([]models.PermissionInfo, error) {
+    permissions, err := s.permissionRepo.FindAll()
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to list permissions")
+    }
+    return permissions, nil
+}
+
+func (s *permissionService) ListRolePermissions() ([]models.RolePermissions, error) {
+    rolePermissions, err := s.permissionRepo.FindRolePermissions()
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to list role permissions")
+    }
+    return rolePermissions, nil
+}
+
+func (s *permissionService) GetRolePermissions(role models.EmployeeRole) (models.RolePermissions, error) {
+    if !validRole(role) {
+        return models.RolePermissions{}, errors.New("invalid role")
+    }
+    rolePermissions, err := s.permissionRepo.FindByRole(role)
+    if err != nil {
+        return models.RolePermissions{}, errors.Wrap(err, "failed to get role permissions")
+    }
+    return rolePermissions, nil
+}
+
+// SetRolePermissions reemplaza los permisos de un rol. El dueño no puede quitarse permissions.manage,
+// porque nadie podría volver a editar los permisos
+func (s *permissionService) SetRolePermissions(role models.EmployeeRole, request models.RolePermissionsRequest, employeeID int) (models.RolePermissions, error) {
+    if !validRole(role) {
+        return models.RolePermissions{}, errors.New("invalid role")
+    }
+
+    // Validar que los permisos existan en el catálogo, sin repetir
+    catalog, err := s.permissionRepo.FindAll()
+    if err != nil {
+        return models.RolePermissions{}, errors.Wrap(err, "failed to list permissions")
+    }
+    known := make(map[models.Permission]bool)
+    for _, permission := range catalog {
+        known[permission.Code] = true
+    }
+    seen := make(map[models.Permission]bool)
+    permissions := []models.Permission{}
+    for _, permission := range request.Permissions {
+        if !known[permission] {
+            return models.RolePermissions{}, errors.Errorf("unknown permission: %s", permission)
+        }
+        if !seen[permission] {
+            seen[permission] = true
+            permissions = append(permissions, permission)
+        }
+    }
+    if role == models.EmployeeRoleOwner && !seen[models.PermissionPermissionsManage] {
+        return models.RolePermissions{}, errors.New("owner role must keep permissions.manage")
+    }
+
+    rolePermissions, err := s.permissionRepo.SetRolePermissions(role, permissions, employeeID)
+    if err != nil {
+        return models.RolePermissions{}, errors.Wrap(err, "failed to set role permissions")
+    }
+
+    // Invalidar la caché para que el cambio se aplique en la próxima solicitud
+    s.mu.Lock()
+    s.granted = nil
+    s.version++
+    s.mu.Unlock()
+    return rolePermissions, nil
+}
+
+// SeedDefaultRolePermissions concede models.DefaultRolePermissions si ningún rol tiene permisos
+// (base recién creada o migrada); se ejecuta al iniciar la aplicación
+func (s *permissionService) SeedDefaultRolePermissions() (bool, error) {
+    seeded, err := s.permissionRepo.SeedRolePermissions(models.DefaultRolePermissions())
+    if err != nil {
+        return false, errors.Wrap(err, "failed to seed default role permissions")
+    }
+    if seeded {
+        s.mu.Lock()
+        s.granted = nil
+        s.version++
+        s.mu.Unlock()
+    }
+    return seeded, nil
+}
+
+// HasPermission indica si el rol tiene el permiso, usando la caché mientras no haya vencido
+func (s *permissionService) HasPermission(role models.EmployeeRole, permission models.Permission) (bool, error) {
+    s.mu.RLock()
+    if s.granted != nil && time.Since(s.loadedAt) < PermissionCacheTTL {
+        allowed := s.granted[role][permission]
+        s.mu.RUnlock()
+        return allowed, nil
+    }
+    version := s.version
+    s.mu.RUnlock()
+
+    rolePermissions, err := s.permissionRepo.FindRolePermissions()
+    if err != nil {
+        return false, errors.Wrap(err, "failed to load role permissions")
+    }
+    granted := make(map[models.EmployeeRole]map[models.Permission]bool)
+    for _, rp := range rolePermissions {
+        granted[rp.Role] = make(map[models.Permission]bool)
+        for _, code := range rp.Permissions {
+            granted[rp.Role][code] = true
+        }
+    }
+
+    s.mu.Lock()
+    if s.version == version {
+        s.granted = granted
+        s.loadedAt = time.Now()
+    }
+    s.mu.Unlock()
+    return granted[role][permission], nil
+}
@@ -0,0 +1,398 @@
+package services
+
+import (
+    "bytes"
+    "fmt"
+    "image"
+    "image/jpeg"
+    "image/png"
+    "log"
+    "net/http"
+    "regexp"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/internal/repositories"
+    "gastrobar-backend/pkg/imaging"
+    "gastrobar-backend/pkg/storage"
+
+    "github.com/pkg/errors"
+)
+
+// DefaultMenuLocale es el idioma en el que se registran los ítems en menu_items
+const DefaultMenuLocale = "es"
+
+// MaxMenuImageSize es el tamaño máximo permitido para una foto del menú (5 MB)
+const MaxMenuImageSize = 5 << 20
+
+// MaxMenuImagePixels es la cantidad máxima de píxeles (ancho × alto) de una foto del menú (40 MP); una
+// imagen comprimida pequeña puede ocupar mucha memoria al decodificarse
+const MaxMenuImagePixels = 40_000_000
+
+// menuThumbnailSize es el lado mayor, en píxeles, de las miniaturas generadas
+const menuThumbnailSize = 320
+
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})?$`)
+
+// PublicMenuService define las operaciones de la carta digital pública (fotos, etiquetas e idiomas)
+type PublicMenuService interface {
+    GetPublicMenu(acceptLanguage string) (models.PublicMenu, error)
+    UploadMenuItemImage(itemID int, data []byte) (models.MenuItemImage, error)
+    DeleteMenuItemImage(itemID int) error
+    SetMenuItemTags(itemID int, tags []models.MenuItemTag) ([]models.MenuItemTag, error)
+    ListMenuItemTranslations(itemID int) ([]models.MenuItemTranslation, error)
+    SaveMenuItemTranslation(translation models.MenuItemTranslation) (models.MenuItemTranslation, error)
+    DeleteMenuItemTranslation(itemID int, locale string) error
+}
+
+type publicMenuService struct {
+    menuItemRepo    repositories.MenuItemRepository
+    imageRepo       repositories.MenuItemImageRepository
+    tagRepo         repositories.MenuItemTagRepository
+    translationRepo repositories.MenuItemTranslationRepository
+    storage         storage.Storage
+}
+
+// NewPublicMenuService crea una nueva instancia del servicio de la carta digital
+func NewPublicMenuService(
+    menuItemRepo repositories.MenuItemRepository,
+    imageRepo repositories.MenuItemImageRepository,
+    tagRepo repositories.MenuItemTagRepository,
+    translationRepo repositories.MenuItemTranslationRepository,
+    fileStorage storage.Storage,
+) PublicMenuService {
+    return &publicMenuService{
+        menuItemRepo:    menuItemRepo,
+        imageRepo:       imageRepo,
+        tagRepo:         tagRepo,
+        translationRepo: translationRepo,
+        storage:         fileStorage,
+    }
+}
+
+// GetPublicMenu arma la carta agrupada por categoría en el idioma que mejor coincida con Accept-Language
+func (s *publicMenuService) GetPublicMenu(acceptLanguage string) (models.PublicMenu, error) {
+    items, err := s.menuItemRepo.FindAll()
+    if err != nil {
+        return models.PublicMenu{}, errors.Wrap(err, "failed to list items")
+    }
+
+    availableLocales, err := s.translationRepo.FindLocales()
+    if err != nil {
+        return models.PublicMenu{}, errors.Wrap(err, "failed to list locales")
+    }
+    locale := negotiateLocale(acceptLanguage, availableLocales)
+
+    // Cargar las traducciones del idioma elegido
+    translations := make(map[int]models.MenuItemTranslation)
+    if locale != DefaultMenuLocale {
+        localeTranslations, err := s.translationRepo.FindByLocale(locale)
+        if err != nil {
+            return models.PublicMenu{}, errors.Wrap(err, "failed to list translations")
+        }
+        for _, translation := range localeTranslations {
+            translations[translation.MenuItemID] = translation
+        }
+    }
+
+    images, err := s.imageRepo.FindAll()
+    if err != nil {
+        return models.PublicMenu{}, errors.Wrap(err, "failed to list images")
+    }
+    imagesByItem := make(map[int]models.MenuItemImage)
+    for _, img := range images {
+        imagesByItem[img.MenuItemID] = img
+    }
+
+    tagsByItem, err := s.tagRepo.FindAll()
+    if err != nil {
+        return models.PublicMenu{}, errors.Wrap(err, "failed to list tags")
+    }
+
+    categories := make(map[string][]models.PublicMenuItem)
+    for _, item := range items {
+        publicItem := models.PublicMenuItem{
+            ID:          item.ID,
+            ItemName:    item.ItemName,
+            Description: item.Description,
+            Price:       item.Price,
+            Available:   item.AvailableQuantity > 0,
+            DietaryTags: []models.MenuItemTag{},
+            Allergens:   []models.MenuItemTag{},
+        }
+
+        // Aplicar la traducción si existe, si no se deja el texto original
+        if translation, ok := translations[item.ID]; ok {
+            if translation.ItemName != "" {
+                publicItem.ItemName = translation.ItemName
+            }
+            if translation.Description != "" {
+                publicItem.Description = translation.Description
+            }
+        }
+
+        if img, ok := imagesByItem[item.ID]; ok {
+            publicItem.ImageURL = s.storage.URL(img.ImagePath)
+            publicItem.ThumbnailURL = s.storage.URL(img.ThumbnailPath)
+        }
+
+        for _, tag := range tagsByItem[item.ID] {
+            if models.DietaryTags[tag] {
+                publicItem.DietaryTags = append(publicItem.DietaryTags, tag)
+            } else if models.AllergenTags[tag] {
+                publicItem.Allergens = append(publicItem.Allergens, tag)
+            }
+        }
+
+        category := item.Category
+        if category == "" {
+            category = "Otros"
+        }
+        categories[category] = append(categories[category], publicItem)
+    }
+
+    menu := models.PublicMenu{Locale: locale, Categories: []models.PublicMenuCategory{}}
+    for category, categoryItems := range categories {
+        sort.Slice(categoryItems, func(i, j int) bool { return categoryItems[i].ItemName < categoryItems[j].ItemName })
+        menu.Categories = append(menu.Categories, models.PublicMenuCategory{Category: category, Items: categoryItems})
+    }
+    sort.Slice(menu.Categories, func(i, j int) bool { return menu.Categories[i].Category < menu.Categories[j].Category })
+
+    return menu, nil
+}
+
+// UploadMenuItemImage guarda la foto de un ítem y genera su miniatura
+func (s *publicMenuService) UploadMenuItemImage(itemID int, data []byte) (models.MenuItemImage, error) {
+    // Validar que el ítem exista
+    if _, err := s.menuItemRepo.FindByID(itemID); err != nil {
+        return models.MenuItemImage{}, errors.Wrap(err, "failed to find item")
+    }
+
+    if len(data) == 0 {
+        return models.MenuItemImage{}, errors.New("image file is required")
+    }
+    if len(data) > MaxMenuImageSize {
+        return models.MenuItemImage{}, errors.New("image exceeds maximum size")
+    }
+
+    // Validar el formato de la imagen (solo JPEG y PNG)
+    contentType := http.DetectContentType(data)
+    var ext string
+    switch contentType {
+    case "image/jpeg":
+        ext = ".jpg"
+    case "image/png":
+        ext = ".png"
+    default:
+        return models.MenuItemImage{}, errors.New("unsupported image format")
+    }
+
+    // Validar las dimensiones leyendo solo la cabecera, antes de decodificar la imagen completa
+    config, _, err := image.DecodeConfig(bytes.NewReader(data))
+    if err != nil {
+        return models.MenuItemImage{}, errors.New("unsupported image format")
+    }
+    if config.Width <= 0 || config.Height <= 0 || int64(config.Width)*int64(config.Height) > MaxMenuImagePixels {
+        return models.MenuItemImage{}, errors.New("image dimensions exceed maximum")
+    }
+
+    src, _, err := image.Decode(bytes.NewReader(data))
+    if err != nil {
+        return models.MenuItemImage{}, errors.New("unsupported image format")
+    }
+
+    // Generar la miniatura conservando el formato de origen
+    var thumbnail bytes.Buffer
+    thumb := imaging.Thumbnail(src, menuThumbnailSize)
+    if ext == ".png" {
+        err = png.Encode(&thumbnail, thumb)
+    } else {
+        err = jpeg.Encode(&thumbnail, thumb, &jpeg.Options{Quality: 80})
+    }
+    if err != nil {
+        return models.MenuItemImage{}, errors.Wrap(err, "failed to encode thumbnail")
+    }
+
+    // Guardar los archivos con un nombre único para evitar cachés obsoletas
+    baseName := fmt.Sprintf("menu-items/%d/%s", itemID, strconv.FormatInt(time.Now().UnixNano(), 36))
+    imagePath := baseName + ext
+    thumbnailPath := baseName + "-thumb" + ext
+    if err := s.storage.Save(imagePath, data); err != nil {
+        return models.MenuItemImage{}, errors.Wrap(err, "failed to store image")
+    }
+    if err := s.storage.Save(thumbnailPath, thumbnail.Bytes()); err != nil {
+        s.storage.Delete(imagePath)
+        return models.MenuItemImage{}, errors.Wrap(err, "failed to store thumbnail")
+    }
+
+    // Recordar la foto anterior para eliminarla después de reemplazarla
+    previous, previousErr := s.imageRepo.FindByMenuItemID(itemID)
+
+    savedImage, err := s.imageRepo.Upsert(models.MenuItemImage{
+        MenuItemID:    itemID,
+        ImagePath:     imagePath,
+        ThumbnailPath: thumbnailPath,
+    })
+    if err != nil {
+        s.storage.Delete(imagePath)
+        s.storage.Delete(thumbnailPath)
+        return models.MenuItemImage{}, errors.Wrap(err, "failed to save image")
+    }
+
+    if previousErr == nil {
+        s.deleteImageFiles(previous)
+    }
+
+    savedImage.ImageURL = s.storage.URL(savedImage.ImagePath)
+    savedImage.ThumbnailURL = s.storage.URL(savedImage.ThumbnailPath)
+    return savedImage, nil
+}
+
+// DeleteMenuItemImage elimina la foto de un ítem y sus archivos
+func (s *publicMenuService) DeleteMenuItemImage(itemID int) error {
+    img, err := s.imageRepo.FindByMenuItemID(itemID)
+    if err != nil {
+        return errors.Wrap(err, "failed to find image")
+    }
+
+    if err := s.imageRepo.Delete(itemID); err != nil {
+        return errors.Wrap(err, "failed to delete image")
+    }
+
+    s.deleteImageFiles(img)
+    return nil
+}
+
+// SetMenuItemTags reemplaza las etiquetas de alérgenos y dietas de un ítem
+func (s *publicMenuService) SetMenuItemTags(itemID int, tags []models.MenuItemTag) ([]models.MenuItemTag, error) {
+    // Validar que el ítem exista
+    if _, err := s.menuItemRepo.FindByID(itemID); err != nil {
+        return nil, errors.Wrap(err, "failed to find item")
+    }
+
+    // Validar que todas las etiquetas sean conocidas
+    for _, tag := range tags {
+        if !models.DietaryTags[tag] && !models.AllergenTags[tag] {
+            return nil, errors.Errorf("invalid tag: %s", tag)
+        }
+    }
+
+    if err := s.tagRepo.Replace(itemID, tags); err != nil {
+        return nil, errors.Wrap(err, "failed to save tags")
+    }
+
+    savedTags, err := s.tagRepo.FindByMenuItemID(itemID)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to get tags")
+    }
+    return savedTags, nil
+}
+
+func (s *publicMenuService) ListMenuItemTranslations(itemID int) ([]models.MenuItemTranslation, error) {
+    // Validar que el ítem exista
+    if _, err := s.menuItemRepo.FindByID(itemID); err != nil {
+        return nil, errors.Wrap(err, "failed to find item")
+    }
+
+    translations, err := s.translationRepo.FindByMenuItemID(itemID)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to list translations")
+    }
+    return translations, nil
+}
+
+func (s *publicMenuService) SaveMenuItemTranslation(translation models.MenuItemTranslation) (models.MenuItemTranslation, error) {
+    // Validar que el ítem exista
+    if _, err := s.menuItemRepo.FindByID(translation.MenuItemID); err != nil {
+        return models.MenuItemTranslation{}, errors.Wrap(err, "failed to find item")
+    }
+
+    translation.Locale = strings.ToLower(strings.TrimSpace(translation.Locale))
+    if !localePattern.MatchString(translation.Locale) {
+        return models.MenuItemTranslation{}, errors.New("invalid locale")
+    }
+    if translation.Locale == DefaultMenuLocale {
+        return models.MenuItemTranslation{}, errors.New("default locale is edited on the menu item itself")
+    }
+
+    // Validar que el nombre traducido no esté vacío
+    if translation.ItemName == "" {
+        return models.MenuItemTranslation{}, errors.New("item name cannot be empty")
+    }
+
+    savedTranslation, err := s.translationRepo.Upsert(translation)
+    if err != nil {
+        return models.MenuItemTranslation{}, errors.Wrap(err, "failed to save translation")
+    }
+    return savedTranslation, nil
+}
+
+func (s *publicMenuService) DeleteMenuItemTranslation(itemID int, locale string) error {
+    err := s.translationRepo.Delete(itemID, strings.ToLower(locale))
+    if err != nil {
+        return errors.Wrap(err, "failed to delete translation")
+    }
+    return nil
+}
+
+// deleteImageFiles elimina los archivos de una foto; los errores solo se registran
+func (s *publicMenuService) deleteImageFiles(img models.MenuItemImage) {
+    if err := s.storage.Delete(img.ImagePath); err != nil {
+        log.Printf("Error deleting image file %s: %v", img.ImagePath, err)
+    }
+    if err := s.storage.Delete(img.ThumbnailPath); err != nil {
+        log.Printf("Error deleting thumbnail file %s: %v", img.ThumbnailPath, err)
+    }
+}
+
+// negotiateLocale elige el idioma según el header Accept-Language (p. ej. "en-US,en;q=0.9,es;q=0.5")
+// entre los idiomas con traducciones disponibles, usando DefaultMenuLocale si ninguno coincide
+func negotiateLocale(acceptLanguage string, availableLocales []string) string {
+    available := map[string]bool{DefaultMenuLocale: true}
+    for _, locale := range availableLocales {
+        available[locale] = true
+    }
+
+    type weightedLocale struct {
+        tag     string
+        quality float64
+    }
+    var candidates []weightedLocale
+    for _, part := range strings.Split(acceptLanguage, ",") {
+        fields := strings.Split(strings.TrimSpace(part), ";")
+        tag := strings.ToLower(strings.TrimSpace(fields[0]))
+        if tag == "" {
+            continue
+        }
+        quality := 1.0
+        for _, param := range fields[1:] {
+            param = strings.TrimSpace(param)
+            if strings.HasPrefix(param, "q=") {
+                if q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+                    quality = q
+                }
+            }
+        }
+        if quality > 0 {
+            candidates = append(candidates, weightedLocale{tag: tag, quality: quality})
+        }
+    }
+    sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].quality > candidates[j].quality })
+
+    for _, candidate := range candidates {
+        if candidate.tag == "*" {
+            return DefaultMenuLocale
+        }
+        if available[candidate.tag] {
+            return candidate.tag
+        }
+        // Probar con el idioma base (en-US -> en)
+        if base, _, found := strings.Cut(candidate.tag, "-"); found && available[base] {
+            return base
+        }
+    }
+    return DefaultMenuLocale
+}
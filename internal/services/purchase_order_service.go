@@ -0,0 +1,194 @@
+package services
+
+import (
+    "strings"
+
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/internal/repositories"
+
+    "github.com/pkg/errors"
+)
+
+type PurchaseOrderService interface {
+    CreatePurchaseOrder(order models.PurchaseOrder, employeeID int) (models.PurchaseOrder, error)
+    GetPurchaseOrder(purchaseOrderID int) (models.PurchaseOrder, error)
+    ListPurchaseOrders(status string) ([]models.PurchaseOrder, error)
+    UpdatePurchaseOrder(order models.PurchaseOrder) (models.PurchaseOrder, error)
+    SendPurchaseOrder(purchaseOrderID int) (models.PurchaseOrder, error)
+    DeletePurchaseOrder(purchaseOrderID int) error
+    ReceivePurchaseOrder(purchaseOrderID int, receipt models.PurchaseOrderReceipt, employeeID int) (models.PurchaseOrder, error)
+}
+
+type purchaseOrderService struct {
+    purchaseOrderRepo repositories.PurchaseOrderRepository
+    supplierRepo      repositories.SupplierRepository
+    menuItemRepo      repositories.MenuItemRepository
+    ingredientRepo    repositories.IngredientRepository
+}
+
+func NewPurchaseOrderService(purchaseOrderRepo repositories.PurchaseOrderRepository, supplierRepo repositories.SupplierRepository, menuItemRepo repositories.MenuItemRepository, ingredientRepo repositories.IngredientRepository) PurchaseOrderService {
+    return &purchaseOrderService{
+        purchaseOrderRepo: purchaseOrderRepo,
+        supplierRepo:      supplierRepo,
+        menuItemRepo:      menuItemRepo,
+        ingredientRepo:    ingredientRepo,
+    }
+}
+
+// validatePurchaseOrder valida el proveedor y las líneas de una orden de compra
+func (s *purchaseOrderService) validatePurchaseOrder(order models.PurchaseOrder) error {
+    // Validar que el proveedor exista
+    if _, err := s.supplierRepo.FindByID(order.SupplierID); err != nil {
+        return errors.Wrap(err, "failed to find supplier")
+    }
+
+    // Validar que la orden tenga al menos una línea
+    if len(order.Lines) == 0 {
+        return errors.New("purchase order must have at least one line")
+    }
+
+    for _, line := range order.Lines {
+        if !line.Quantity.IsPositive() {
+            return errors.New("quantity must be greater than 0")
+        }
+        if line.ExpectedUnitCost.IsNegative() {
+            return errors.New("unit cost cannot be negative")
+        }
+
+        // Validar que la línea sea de un ítem o de un ingrediente, pero no de ambos
+        if (line.MenuItemID == nil) == (line.IngredientID == nil) {
+            return errors.New("line must reference either a menu item or an ingredient")
+        }
+        if line.MenuItemID != nil {
+            item, err := s.menuItemRepo.FindByID(*line.MenuItemID)
+            if err != nil {
+                return errors.Wrap(err, "failed to find item")
+            }
+            // Los ítems preparados no se compran: se compran sus ingredientes
+            if item.StockMode == models.MenuItemStockModeRecipe {
+                return errors.New("item stock is derived from its recipe ingredients")
+            }
+            if !line.Quantity.Equal(line.Quantity.Truncate(0)) {
+                return errors.New("item quantity must be a whole number")
+            }
+        } else {
+            if _, err := s.ingredientRepo.FindByID(*line.IngredientID); err != nil {
+                return errors.Wrap(err, "failed to find ingredient")
+            }
+        }
+    }
+    return nil
+}
+
+func (s *purchaseOrderService) CreatePurchaseOrder(order models.PurchaseOrder, employeeID int) (models.PurchaseOrder, error) {
+    if err := s.validatePurchaseOrder(order); err != nil {
+        return models.PurchaseOrder{}, err
+    }
+    order.Notes = strings.TrimSpace(order.Notes)
+    order.CreatedBy = employeeID
+
+    createdOrder, err := s.purchaseOrderRepo.Create(order)
+    if err != nil {
+        return models.PurchaseOrder{}, errors.Wrap(err, "failed to create purchase order")
+    }
+    return createdOrder, nil
+}
+
+func (s *purchaseOrderService) GetPurchaseOrder(purchaseOrderID int) (models.PurchaseOrder, error) {
+    order, err := s.purchaseOrderRepo.FindByID(purchaseOrderID)
+    if err != nil {
+        return models.PurchaseOrder{}, errors.Wrap(err, "failed to get purchase order")
+    }
+    return order, nil
+}
+
+func (s *purchaseOrderService) ListPurchaseOrders(status string) ([]models.PurchaseOrder, error) {
+    // Validar el filtro de estado si se indicó
+    switch models.PurchaseOrderStatus(status) {
+    case "", models.PurchaseOrderStatusDraft, models.PurchaseOrderStatusSent, models.PurchaseOrderStatusPartiallyReceived, models.PurchaseOrderStatusReceived:
+    default:
+        return nil, errors.New("invalid purchase order status")
+    }
+
+    orders, err := s.purchaseOrderRepo.FindAll(status)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to list purchase orders")
+    }
+    return orders, nil
+}
+
+// UpdatePurchaseOrder reemplaza el contenido de una orden; solo se permite mientras está en borrador
+func (s *purchaseOrderService) UpdatePurchaseOrder(order models.PurchaseOrder) (models.PurchaseOrder, error) {
+    if err := s.validatePurchaseOrder(order); err != nil {
+        return models.PurchaseOrder{}, err
+    }
+    order.Notes = strings.TrimSpace(order.Notes)
+
+    updatedOrder, err := s.purchaseOrderRepo.UpdateDraft(order)
+    if err != nil {
+        return models.PurchaseOrder{}, errors.Wrap(err, "failed to update purchase order")
+    }
+    return updatedOrder, nil
+}
+
+func (s *purchaseOrderService) SendPurchaseOrder(purchaseOrderID int) (models.PurchaseOrder, error) {
+    sentOrder, err := s.purchaseOrderRepo.MarkSent(purchaseOrderID)
+    if err != nil {
+        return models.PurchaseOrder{}, errors.Wrap(err, "failed to send purchase order")
+    }
+    return sentOrder, nil
+}
+
+func (s *purchaseOrderService) DeletePurchaseOrder(purchaseOrderID int) error {
+    if err := s.purchaseOrderRepo.Delete(purchaseOrderID); err != nil {
+        return errors.Wrap(err, "failed to delete purchase order")
+    }
+    return nil
+}
+
+// ReceivePurchaseOrder registra la mercancía recibida con su costo unitario real; el stock se
+// incrementa mediante movimientos de compra en el libro de inventario
+func (s *purchaseOrderService) ReceivePurchaseOrder(purchaseOrderID int, receipt models.PurchaseOrderReceipt, employeeID int) (models.PurchaseOrder, error) {
+    // Validar que se reciba al menos una línea
+    if len(receipt.Lines) == 0 {
+        return models.PurchaseOrder{}, errors.New("receipt must have at least one line")
+    }
+
+    order, err := s.purchaseOrderRepo.FindByID(purchaseOrderID)
+    if err != nil {
+        return models.PurchaseOrder{}, errors.Wrap(err, "failed to find purchase order")
+    }
+    orderLines := make(map[int]models.PurchaseOrderLine, len(order.Lines))
+    for _, line := range order.Lines {
+        orderLines[line.ID] = line
+    }
+
+    seen := make(map[int]bool, len(receipt.Lines))
+    for _, received := range receipt.Lines {
+        line, ok := orderLines[received.LineID]
+        if !ok {
+            return models.PurchaseOrder{}, errors.New("purchase order line not found")
+        }
+        // Cada línea se recibe una sola vez por operación
+        if seen[received.LineID] {
+            return models.PurchaseOrder{}, errors.New("purchase order line duplicated in receipt")
+        }
+        seen[received.LineID] = true
+
+        if !received.Quantity.IsPositive() {
+            return models.PurchaseOrder{}, errors.New("quantity must be greater than 0")
+        }
+        if received.UnitCost.IsNegative() {
+            return models.PurchaseOrder{}, errors.New("unit cost cannot be negative")
+        }
+        if line.MenuItemID != nil && !received.Quantity.Equal(received.Quantity.Truncate(0)) {
+            return models.PurchaseOrder{}, errors.New("item quantity must be a whole number")
+        }
+    }
+
+    receivedOrder, err := s.purchaseOrderRepo.Receive(purchaseOrderID, receipt, employeeID)
+    if err != nil {
+        return models.PurchaseOrder{}, errors.Wrap(err, "failed to receive purchase order")
+    }
+    return receivedOrder, nil
+}
@@ -0,0 +1,267 @@
+package services
+
+import (
+    "sort"
+    "strings"
+    "time"
+
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/internal/repositories"
+
+    "github.com/pkg/errors"
+)
+
+// DefaultReservationDuration es la duración en minutos de una reserva cuando no se indica
+const DefaultReservationDuration = 90
+
+// ReservationSeatingWindow es cuánto antes de la hora reservada se puede sentar a los clientes; es
+// el mismo margen en el que la mesa aparece como reservada en el plano
+const ReservationSeatingWindow = time.Hour
+
+type ReservationService interface {
+    CreateReservation(reservation models.Reservation, employeeID int) (models.Reservation, error)
+    GetReservation(reservationID int) (models.Reservation, error)
+    ListReservations(from, to *time.Time, status string) ([]models.Reservation, error)
+    UpdateReservation(reservation models.Reservation) (models.Reservation, error)
+    SearchAvailability(reservedAt time.Time, partySize, durationMinutes int) (models.ReservationAvailability, error)
+    SeatReservation(reservationID, employeeID int) (models.Reservation, error)
+    MarkNoShow(reservationID int) (models.Reservation, error)
+    CancelReservation(reservationID int) (models.Reservation, error)
+}
+
+type reservationService struct {
+    reservationRepo repositories.ReservationRepository
+    tableRepo       repositories.TableRepository
+}
+
+func NewReservationService(reservationRepo repositories.ReservationRepository, tableRepo repositories.TableRepository) ReservationService {
+    return &reservationService{
+        reservationRepo: reservationRepo,
+        tableRepo:       tableRepo,
+    }
+}
+
+// validateReservation normaliza y valida los datos de una reserva y que sus mesas alcancen para el grupo
+func (s *reservationService) validateReservation(reservation *models.Reservation) error {
+    reservation.GuestName = strings.TrimSpace(reservation.GuestName)
+    reservation.GuestPhone = strings.TrimSpace(reservation.GuestPhone)
+    reservation.Notes = strings.TrimSpace(reservation.Notes)
+
+    if reservation.GuestName == "" {
+        return errors.New("guest name cannot be empty")
+    }
+    if reservation.GuestPhone == "" {
+        return errors.New("guest phone cannot be empty")
+    }
+    if reservation.PartySize <= 0 {
+        return errors.New("party size must be greater than 0")
+    }
+
+    // Validar la fecha y la duración
+    if reservation.ReservedAt.IsZero() {
+        return errors.New("reservation time is required")
+    }
+    if !reservation.ReservedAt.After(time.Now()) {
+        return errors.New("reservation time must be in the future")
+    }
+    if reservation.DurationMinutes == 0 {
+        reservation.DurationMinutes = DefaultReservationDuration
+    }
+    if reservation.DurationMinutes < 0 {
+        return errors.New("duration must be greater than 0")
+    }
+
+    // Validar las mesas: sin repetir y con capacidad suficiente para el grupo
+    if len(reservation.TableIDs) == 0 {
+        return errors.New("reservation must have at least one table")
+    }
+    seen := make(map[int]bool)
+    capacity := 0
+    for _, tableID := range reservation.TableIDs {
+        if seen[tableID] {
+            return errors.New("table duplicated in reservation")
+        }
+        seen[tableID] = true
+
+        table, err := s.tableRepo.FindByID(tableID)
+        if err != nil {
+            return errors.Wrap(err, "failed to find table")
+        }
+        if !table.Active {
+            return errors.New("table is inactive")
+        }
+        capacity += table.Capacity
+    }
+    if capacity < reservation.PartySize {
+        return errors.New("tables capacity is lower than party size")
+    }
+    return nil
+}
+
+func (s *reservationService) CreateReservation(reservation models.Reservation, employeeID int) (models.Reservation, error) {
+    if err := s.validateReservation(&reservation); err != nil {
+        return models.Reservation{}, err
+    }
+    reservation.CreatedBy = employeeID
+
+    createdReservation, err := s.reservationRepo.Create(reservation)
+    if err != nil {
+        return models.Reservation{}, errors.Wrap(err, "failed to create reservation")
+    }
+    return createdReservation, nil
+}
+
+func (s *reservationService) GetReservation(reservationID int) (models.Reservation, error) {
+    reservation, err := s.reservationRepo.FindByID(reservationID)
+    if err != nil {
+        return models.Reservation{}, errors.Wrap(err, "failed to get reservation")
+    }
+    return reservation, nil
+}
+
+func (s *reservationService) ListReservations(from, to *time.Time, status string) ([]models.Reservation, error) {
+    // Validar el rango de fechas
+    if from != nil && to != nil && !from.Before(*to) {
+        return nil, errors.New("from must be before to")
+    }
+
+    // Validar el filtro de estado si se indicó
+    switch models.ReservationStatus(status) {
+    case "", models.ReservationStatusBooked, models.ReservationStatusSeated, models.ReservationStatusNoShow, models.ReservationStatusCancelled:
+    default:
+        return nil, errors.New("invalid reservation status")
+    }
+
+    reservations, err := s.reservationRepo.FindAll(from, to, status)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to list reservations")
+    }
+    return reservations, nil
+}
+
+// UpdateReservation reemplaza los datos de una reserva; solo se permite mientras está reservada
+func (s *reservationService) UpdateReservation(reservation models.Reservation) (models.Reservation, error) {
+    if err := s.validateReservation(&reservation); err != nil {
+        return models.Reservation{}, err
+    }
+
+    updatedReservation, err := s.reservationRepo.Update(reservation)
+    if err != nil {
+        return models.Reservation{}, errors.Wrap(err, "failed to update reservation")
+    }
+    return updatedReservation, nil
+}
+
+// SearchAvailability busca las mesas libres en el horario pedido y sugiere cuáles asignar al grupo
+func (s *reservationService) SearchAvailability(reservedAt time.Time, partySize, durationMinutes int) (models.ReservationAvailability, error) {
+    if partySize <= 0 {
+        return models.ReservationAvailability{}, errors.New("party size must be greater than 0")
+    }
+    if durationMinutes == 0 {
+        durationMinutes = DefaultReservationDuration
+    }
+    if durationMinutes < 0 {
+        return models.ReservationAvailability{}, errors.New("duration must be greater than 0")
+    }
+
+    end := reservedAt.Add(time.Duration(durationMinutes) * time.Minute)
+    tables, err := s.reservationRepo.FindAvailableTables(reservedAt, end, 0)
+    if err != nil {
+        return models.ReservationAvailability{}, errors.Wrap(err, "failed to search availability")
+    }
+
+    suggested := suggestTables(tables, partySize)
+    return models.ReservationAvailability{
+        ReservedAt:        reservedAt,
+        DurationMinutes:   durationMinutes,
+        PartySize:         partySize,
+        Available:         len(suggested) > 0,
+        AvailableTables:   tables,
+        SuggestedTableIDs: suggested,
+    }, nil
+}
+
+// suggestTables elige las mesas para un grupo: la mesa libre más pequeña en la que quepa o, si no
+// hay ninguna, la combinación con menos mesas, prefiriendo juntar mesas de una misma zona.
+// tables debe venir ordenada de menor a mayor capacidad
+func suggestTables(tables []models.Table, partySize int) []int {
+    for _, table := range tables {
+        if table.Capacity >= partySize {
+            return []int{table.ID}
+        }
+    }
+
+    // combine junta mesas de mayor a menor capacidad hasta que quepa el grupo
+    combine := func(candidates []models.Table) []int {
+        sorted := append([]models.Table(nil), candidates...)
+        sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Capacity > sorted[j].Capacity })
+        var ids []int
+        capacity := 0
+        for _, table := range sorted {
+            ids = append(ids, table.ID)
+            capacity += table.Capacity
+            if capacity >= partySize {
+                sort.Ints(ids)
+                return ids
+            }
+        }
+        return nil
+    }
+
+    byZone := make(map[models.TableZone][]models.Table)
+    for _, table := range tables {
+        byZone[table.Zone] = append(byZone[table.Zone], table)
+    }
+    var best []int
+    for _, zone := range []models.TableZone{models.TableZoneHall, models.TableZoneTerrace, models.TableZoneBar} {
+        if ids := combine(byZone[zone]); ids != nil && (best == nil || len(ids) < len(best)) {
+            best = ids
+        }
+    }
+    if best != nil {
+        return best
+    }
+    return combine(tables)
+}
+
+// SeatReservation sienta a los clientes y abre el pedido pendiente en su mesa
+func (s *reservationService) SeatReservation(reservationID, employeeID int) (models.Reservation, error) {
+    reservation, err := s.reservationRepo.FindByID(reservationID)
+    if err != nil {
+        return models.Reservation{}, errors.Wrap(err, "failed to find reservation")
+    }
+    if time.Now().Before(reservation.ReservedAt.Add(-ReservationSeatingWindow)) {
+        return models.Reservation{}, errors.New("reservation is too early to be seated")
+    }
+
+    seatedReservation, err := s.reservationRepo.Seat(reservationID, employeeID)
+    if err != nil {
+        return models.Reservation{}, errors.Wrap(err, "failed to seat reservation")
+    }
+    return seatedReservation, nil
+}
+
+// MarkNoShow registra que los clientes no llegaron; solo se permite desde la hora reservada
+func (s *reservationService) MarkNoShow(reservationID int) (models.Reservation, error) {
+    reservation, err := s.reservationRepo.FindByID(reservationID)
+    if err != nil {
+        return models.Reservation{}, errors.Wrap(err, "failed to find reservation")
+    }
+    if time.Now().Before(reservation.ReservedAt) {
+        return models.Reservation{}, errors.New("reservation time has not arrived yet")
+    }
+
+    closedReservation, err := s.reservationRepo.Close(reservationID, models.ReservationStatusNoShow)
+    if err != nil {
+        return models.Reservation{}, errors.Wrap(err, "failed to mark reservation as no-show")
+    }
+    return closedReservation, nil
+}
+
+func (s *reservationService) CancelReservation(reservationID int) (models.Reservation, error) {
+    cancelledReservation, err := s.reservationRepo.Close(reservationID, models.ReservationStatusCancelled)
+    if err != nil {
+        return models.Reservation{}, errors.Wrap(err, "failed to cancel reservation")
+    }
+    return cancelledReservation, nil
+}
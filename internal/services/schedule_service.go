@@ -0,0 +1,450 @@
+package services
+
+import (
+    "sort"
+    "strings"
+    "time"
+
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/internal/repositories"
+
+    "github.com/pkg/errors"
+)
+
+// MinRestBetweenShifts es el descanso mínimo entre jornadas: entre el fin de un turno y el siguiente
+// turno que empieza otro día. Los turnos partidos de un mismo día no cuentan
+const MinRestBetweenShifts = 12 * time.Hour
+
+// MyShiftsDefaultRange es el periodo que muestra /my-shifts cuando no se indica un rango
+const MyShiftsDefaultRange = 14 * 24 * time.Hour
+
+type ScheduleService interface {
+    CreateTemplate(template models.ShiftTemplate) (models.ShiftTemplate, error)
+    ListTemplates() ([]models.ShiftTemplate, error)
+    UpdateTemplate(template models.ShiftTemplate) (models.ShiftTemplate, error)
+    DeleteTemplate(templateID int) error
+    CreateSchedule(weekStart string, employeeID int) (models.Schedule, error)
+    GetSchedule(scheduleID int) (models.Schedule, error)
+    ListSchedules() ([]models.Schedule, error)
+    DeleteSchedule(scheduleID int) error
+    AddShift(shift models.Shift) (models.Shift, error)
+    UpdateShift(shift models.Shift) (models.Shift, error)
+    DeleteShift(shiftID int) error
+    GetConflicts(scheduleID int) ([]models.ShiftConflict, error)
+    PublishSchedule(scheduleID, employeeID int) (models.Schedule, error)
+    UnpublishSchedule(scheduleID int) (models.Schedule, error)
+    CopySchedule(scheduleID int, weekStart string, employeeID int) (models.Schedule, error)
+    GetMyShifts(employeeID int, from, to *time.Time) ([]models.Shift, error)
+}
+
+type scheduleService struct {
+    scheduleRepo      repositories.ScheduleRepository
+    shiftTemplateRepo repositories.ShiftTemplateRepository
+}
+
+func NewScheduleService(scheduleRepo repositories.ScheduleRepository, shiftTemplateRepo repositories.ShiftTemplateRepository) ScheduleService {
+    return &scheduleService{
+        scheduleRepo:      scheduleRepo,
+        shiftTemplateRepo: shiftTemplateRepo,
+    }
+}
+
+// validShiftRole indica si el rol es uno de los roles de turno permitidos
+func validShiftRole(role models.ShiftRole) bool {
+    switch role {
+    case models.ShiftRoleWaiter, models.ShiftRoleKitchen, models.ShiftRoleBar, models.ShiftRoleCashier, models.ShiftRoleSupervisor:
+        return true
+    }
+    return false
+}
+
+// parseWeekStart convierte una fecha YYYY-MM-DD en la medianoche local de ese día, que debe ser lunes
+func parseWeekStart(value string) (time.Time, error) {
+    weekStart, err := time.ParseInLocation("2006-01-02", value, time.Local)
+    if err != nil {
+        return time.Time{}, errors.New("invalid week start, use YYYY-MM-DD")
+    }
+    if weekStart.Weekday() != time.Monday {
+        return time.Time{}, errors.New("week start must be a monday")
+    }
+    return weekStart, nil
+}
+
+// validateTemplate normaliza y valida los datos de una plantilla de turno
+func (s *scheduleService) validateTemplate(template *models.ShiftTemplate) error {
+    template.TemplateName = strings.TrimSpace(template.TemplateName)
+    if template.TemplateName == "" {
+        return errors.New("template name cannot be empty")
+    }
+    if !validShiftRole(template.Role) {
+        return errors.New("invalid shift role")
+    }
+    if _, err := time.Parse("15:04", template.StartTime); err != nil {
+        return errors.New("invalid start time, use HH:MM")
+    }
+    if _, err := time.Parse("15:04", template.EndTime); err != nil {
+        return errors.New("invalid end time, use HH:MM")
+    }
+    if template.StartTime == template.EndTime {
+        return errors.New("start and end time cannot be equal")
+    }
+    return nil
+}
+
+func (s *scheduleService) CreateTemplate(template models.ShiftTemplate) (models.ShiftTemplate, error) {
+    if err := s.validateTemplate(&template); err != nil {
+        return models.ShiftTemplate{}, err
+    }
+
+    // Validar que el nombre sea único
+    _, err := s.shiftTemplateRepo.FindByName(template.TemplateName)
+    if err == nil {
+        return models.ShiftTemplate{}, errors.New("template name already exists")
+    }
+    if !strings.Contains(err.Error(), "shift template not found") {
+        return models.ShiftTemplate{}, errors.Wrap(err, "failed to check template name uniqueness")
+    }
+
+    createdTemplate, err := s.shiftTemplateRepo.Create(template)
+    if err != nil {
+        return models.ShiftTemplate{}, errors.Wrap(err, "failed to create shift template")
+    }
+    return createdTemplate, nil
+}
+
+func (s *scheduleService) ListTemplates() ([]models.ShiftTemplate, error) {
+    templates, err := s.shiftTemplateRepo.FindAll()
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to list shift templates")
+    }
+    return templates, nil
+}
+
+// UpdateTemplate modifica una plantilla; los turnos ya creados con ella no cambian
+func (s *scheduleService) UpdateTemplate(template models.ShiftTemplate) (models.ShiftTemplate, error) {
+    if err := s.validateTemplate(&template); err != nil {
+        return models.ShiftTemplate{}, err
+    }
+
+    // Validar que el nombre sea único (excepto para la misma plantilla)
+    existing, err := s.shiftTemplateRepo.FindByName(template.TemplateName)
+    if err == nil && existing.ID != template.ID {
+        return models.ShiftTemplate{}, errors.New("template name already exists")
+    }
+    if err != nil && !strings.Contains(err.Error(), "shift template not found") {
+        return models.ShiftTemplate{}, errors.Wrap(err, "failed to check template name uniqueness")
+    }
+
+    updatedTemplate, err := s.shiftTemplateRepo.Update(template)
+    if err != nil {
+        return models.ShiftTemplate{}, errors.Wrap(err, "failed to update shift template")
+    }
+    return updatedTemplate, nil
+}
+
+func (s *scheduleService) DeleteTemplate(templateID int) error {
+    if err := s.shiftTemplateRepo.Delete(templateID); err != nil {
+        return errors.Wrap(err, "failed to delete shift template")
+    }
+    return nil
+}
+
+func (s *scheduleService) CreateSchedule(weekStart string, employeeID int) (models.Schedule, error) {
+    if _, err := parseWeekStart(weekStart); err != nil {
+        return models.Schedule{}, err
+    }
+    if err := s.checkWeekIsFree(weekStart); err != nil {
+        return models.Schedule{}, err
+    }
+
+    createdSchedule, err := s.scheduleRepo.Create(models.Schedule{WeekStart: weekStart, CreatedBy: employeeID})
+    if err != nil {
+        return models.Schedule{}, errors.Wrap(err, "failed to create schedule")
+    }
+    return createdSchedule, nil
+}
+
+// checkWeekIsFree valida que todavía no exista un horario para la semana
+func (s *scheduleService) checkWeekIsFree(weekStart string) error {
+    _, err := s.scheduleRepo.FindByWeek(weekStart)
+    if err == nil {
+        return errors.New("schedule already exists for that week")
+    }
+    if !strings.Contains(err.Error(), "schedule not found") {
+        return errors.Wrap(err, "failed to check schedule week")
+    }
+    return nil
+}
+
+// GetSchedule devuelve el horario con todos sus turnos
+func (s *scheduleService) GetSchedule(scheduleID int) (models.Schedule, error) {
+    schedule, err := s.scheduleRepo.FindByID(scheduleID)
+    if err != nil {
+        return models.Schedule{}, errors.Wrap(err, "failed to get schedule")
+    }
+    schedule.Shifts, err = s.scheduleRepo.FindShifts(scheduleID)
+    if err != nil {
+        return models.Schedule{}, errors.Wrap(err, "failed to get shifts")
+    }
+    return schedule, nil
+}
+
+func (s *scheduleService) ListSchedules() ([]models.Schedule, error) {
+    schedules, err := s.scheduleRepo.FindAll()
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to list schedules")
+    }
+    return schedules, nil
+}
+
+func (s *scheduleService) DeleteSchedule(scheduleID int) error {
+    if err := s.scheduleRepo.Delete(scheduleID); err != nil {
+        return errors.Wrap(err, "failed to delete schedule")
+    }
+    return nil
+}
+
+// resolveShift completa un turno creado desde una plantilla (horario y rol a partir del día indicado)
+// y valida que quede dentro de la semana del horario
+func (s *scheduleService) resolveShift(shift *models.Shift, schedule models.Schedule) error {
+    weekStart, err := parseWeekStart(schedule.WeekStart)
+    if err != nil {
+        return err
+    }
+    shift.Notes = strings.TrimSpace(shift.Notes)
+
+    if shift.ShiftTemplateID != nil {
+        template, err := s.shiftTemplateRepo.FindByID(*shift.ShiftTemplateID)
+        if err != nil {
+            return errors.Wrap(err, "failed to find shift template")
+        }
+        day, err := time.ParseInLocation("2006-01-02", shift.Date, time.Local)
+        if err != nil {
+            return errors.New("shift date is required with a template, use YYYY-MM-DD")
+        }
+        start, _ := time.Parse("15:04", template.StartTime)
+        end, _ := time.Parse("15:04", template.EndTime)
+        shift.StartsAt = time.Date(day.Year(), day.Month(), day.Day(), start.Hour(), start.Minute(), 0, 0, time.Local)
+        shift.EndsAt = time.Date(day.Year(), day.Month(), day.Day(), end.Hour(), end.Minute(), 0, 0, time.Local)
+        if !shift.EndsAt.After(shift.StartsAt) {
+            shift.EndsAt = shift.EndsAt.AddDate(0, 0, 1)
+        }
+        if shift.Role == "" {
+            shift.Role = template.Role
+        }
+    }
+
+    if shift.EmployeeID <= 0 {
+        return errors.New("employee is required")
+    }
+    if !validShiftRole(shift.Role) {
+        return errors.New("invalid shift role")
+    }
+    if shift.StartsAt.IsZero() || shift.EndsAt.IsZero() {
+        return errors.New("shift start and end are required")
+    }
+    if !shift.EndsAt.After(shift.StartsAt) {
+        return errors.New("shift end must be after start")
+    }
+    if shift.StartsAt.Before(weekStart) || !shift.StartsAt.Before(weekStart.AddDate(0, 0, 7)) {
+        return errors.New("shift must start within the schedule week")
+    }
+    return nil
+}
+
+// AddShift agrega un turno a un horario en borrador, con horario explícito o desde una plantilla
+func (s *scheduleService) AddShift(shift models.Shift) (models.Shift, error) {
+    schedule, err := s.scheduleRepo.FindByID(shift.ScheduleID)
+    if err != nil {
+        return models.Shift{}, errors.Wrap(err, "failed to find schedule")
+    }
+    if err := s.resolveShift(&shift, schedule); err != nil {
+        return models.Shift{}, err
+    }
+
+    createdShift, err := s.scheduleRepo.CreateShift(shift)
+    if err != nil {
+        return models.Shift{}, errors.Wrap(err, "failed to add shift")
+    }
+    return createdShift, nil
+}
+
+func (s *scheduleService) UpdateShift(shift models.Shift) (models.Shift, error) {
+    existing, err := s.scheduleRepo.FindShiftByID(shift.ID)
+    if err != nil {
+        return models.Shift{}, errors.Wrap(err, "failed to find shift")
+    }
+    schedule, err := s.scheduleRepo.FindByID(existing.ScheduleID)
+    if err != nil {
+        return models.Shift{}, errors.Wrap(err, "failed to find schedule")
+    }
+    shift.ScheduleID = existing.ScheduleID
+    if err := s.resolveShift(&shift, schedule); err != nil {
+        return models.Shift{}, err
+    }
+
+    updatedShift, err := s.scheduleRepo.UpdateShift(shift)
+    if err != nil {
+        return models.Shift{}, errors.Wrap(err, "failed to update shift")
+    }
+    return updatedShift, nil
+}
+
+func (s *scheduleService) DeleteShift(shiftID int) error {
+    if err := s.scheduleRepo.DeleteShift(shiftID); err != nil {
+        return errors.Wrap(err, "failed to delete shift")
+    }
+    return nil
+}
+
+// GetConflicts revisa los turnos del horario contra todos los turnos de sus empleados (incluidos los
+// de las semanas vecinas) y devuelve los cruces y los descansos entre jornadas menores al mínimo
+func (s *scheduleService) GetConflicts(scheduleID int) ([]models.ShiftConflict, error) {
+    schedule, err := s.scheduleRepo.FindByID(scheduleID)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to find schedule")
+    }
+    weekStart, err := parseWeekStart(schedule.WeekStart)
+    if err != nil {
+        return nil, err
+    }
+
+    // Se amplía el rango para alcanzar los turnos vecinos que puedan chocar con los de la semana
+    from := weekStart.Add(-MinRestBetweenShifts).AddDate(0, 0, -1)
+    to := weekStart.AddDate(0, 0, 8).Add(MinRestBetweenShifts)
+    shifts, err := s.scheduleRepo.FindShiftsInRange(from, to, 0, false)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to get shifts")
+    }
+    return detectShiftConflicts(shifts, scheduleID), nil
+}
+
+// detectShiftConflicts compara los turnos de cada empleado (ordenados por inicio) y devuelve los
+// conflictos en los que participa al menos un turno del horario indicado
+func detectShiftConflicts(shifts []models.Shift, scheduleID int) []models.ShiftConflict {
+    byEmployee := make(map[int][]models.Shift)
+    for _, shift := range shifts {
+        byEmployee[shift.EmployeeID] = append(byEmployee[shift.EmployeeID], shift)
+    }
+    employeeIDs := make([]int, 0, len(byEmployee))
+    for employeeID := range byEmployee {
+        employeeIDs = append(employeeIDs, employeeID)
+    }
+    sort.Ints(employeeIDs)
+
+    conflicts := []models.ShiftConflict{}
+    for _, employeeID := range employeeIDs {
+        list := byEmployee[employeeID]
+        sort.SliceStable(list, func(i, j int) bool { return list[i].StartsAt.Before(list[j].StartsAt) })
+
+        for i := range list {
+            for j := i + 1; j < len(list); j++ {
+                a, b := list[i], list[j]
+                if a.ScheduleID != scheduleID && b.ScheduleID != scheduleID {
+                    continue
+                }
+                if b.StartsAt.Before(a.EndsAt) {
+                    conflicts = append(conflicts, models.ShiftConflict{
+                        Type:               models.ShiftConflictOverlap,
+                        EmployeeID:         employeeID,
+                        ShiftID:            a.ID,
+                        ConflictingShiftID: b.ID,
+                    })
+                    continue
+                }
+                // El descanso solo se mide contra el turno siguiente
+                if j != i+1 {
+                    continue
+                }
+                rest := b.StartsAt.Sub(a.EndsAt)
+                if rest < MinRestBetweenShifts && a.StartsAt.In(time.Local).Format("2006-01-02") != b.StartsAt.In(time.Local).Format("2006-01-02") {
+                    conflicts = append(conflicts, models.ShiftConflict{
+                        Type:               models.ShiftConflictMinRest,
+                        EmployeeID:         employeeID,
+                        ShiftID:            a.ID,
+                        ConflictingShiftID: b.ID,
+                        RestMinutes:        int(rest.Minutes()),
+                    })
+                }
+            }
+        }
+    }
+    return conflicts
+}
+
+// PublishSchedule publica el horario para que los empleados vean sus turnos; no se permite si tiene conflictos
+func (s *scheduleService) PublishSchedule(scheduleID, employeeID int) (models.Schedule, error) {
+    conflicts, err := s.GetConflicts(scheduleID)
+    if err != nil {
+        return models.Schedule{}, err
+    }
+    if len(conflicts) > 0 {
+        return models.Schedule{}, errors.New("schedule has conflicts")
+    }
+
+    publishedSchedule, err := s.scheduleRepo.Publish(scheduleID, employeeID)
+    if err != nil {
+        return models.Schedule{}, errors.Wrap(err, "failed to publish schedule")
+    }
+    return publishedSchedule, nil
+}
+
+func (s *scheduleService) UnpublishSchedule(scheduleID int) (models.Schedule, error) {
+    schedule, err := s.scheduleRepo.Unpublish(scheduleID)
+    if err != nil {
+        return models.Schedule{}, errors.Wrap(err, "failed to unpublish schedule")
+    }
+    return schedule, nil
+}
+
+// CopySchedule copia los turnos de un horario a otra semana (por defecto la siguiente) como borrador
+func (s *scheduleService) CopySchedule(scheduleID int, weekStart string, employeeID int) (models.Schedule, error) {
+    source, err := s.scheduleRepo.FindByID(scheduleID)
+    if err != nil {
+        return models.Schedule{}, errors.Wrap(err, "failed to find schedule")
+    }
+    if weekStart == "" {
+        sourceWeek, err := parseWeekStart(source.WeekStart)
+        if err != nil {
+            return models.Schedule{}, err
+        }
+        weekStart = sourceWeek.AddDate(0, 0, 7).Format("2006-01-02")
+    }
+    if _, err := parseWeekStart(weekStart); err != nil {
+        return models.Schedule{}, err
+    }
+    if weekStart == source.WeekStart {
+        return models.Schedule{}, errors.New("cannot copy a schedule onto its own week")
+    }
+    if err := s.checkWeekIsFree(weekStart); err != nil {
+        return models.Schedule{}, err
+    }
+
+    copiedSchedule, err := s.scheduleRepo.Copy(scheduleID, weekStart, employeeID)
+    if err != nil {
+        return models.Schedule{}, errors.Wrap(err, "failed to copy schedule")
+    }
+    return s.GetSchedule(copiedSchedule.ID)
+}
+
+// GetMyShifts devuelve los turnos publicados del empleado; por defecto desde hoy y por dos semanas
+func (s *scheduleService) GetMyShifts(employeeID int, from, to *time.Time) ([]models.Shift, error) {
+    now := time.Now()
+    start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+    if from != nil {
+        start = *from
+    }
+    end := start.Add(MyShiftsDefaultRange)
+    if to != nil {
+        end = *to
+    }
+    if !start.Before(end) {
+        return nil, errors.New("from must be before to")
+    }
+
+    shifts, err := s.scheduleRepo.FindShiftsInRange(start, end, employeeID, true)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to get shifts")
+    }
+    return shifts, nil
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+    "strings"
+
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/internal/repositories"
+
+    "github.com/pkg/errors"
+)
+
+type StockMovementService interface {
+    RecordMovement(movement models.StockMovement) (models.StockMovement, error)
+    ListMenuItemMovements(menuItemID int) ([]models.StockMovement, error)
+    ListIngredientMovements(ingredientID int) ([]models.StockMovement, error)
+    Reconcile() ([]models.StockReconciliationEntry, error)
+}
+
+type stockMovementService struct {
+    stockMovementRepo repositories.StockMovementRepository
+    menuItemRepo      repositories.MenuItemRepository
+    ingredientRepo    repositories.IngredientRepository
+}
+
+func NewStockMovementService(stockMovementRepo repositories.StockMovementRepository, menuItemRepo repositories.MenuItemRepository, ingredientRepo repositories.IngredientRepository) StockMovementService {
+    return &stockMovementService{
+        stockMovementRepo: stockMovementRepo,
+        menuItemRepo:      menuItemRepo,
+        ingredientRepo:    ingredientRepo,
+    }
+}
+
+// RecordMovement registra manualmente una merma o un ajuste. Para las mermas se indica la cantidad
+// perdida (positiva) y se registra como salida; los ajustes llevan el signo del cambio.
+// Ventas, devoluciones, compras y correcciones de conteo se generan solo desde sus propios flujos
+func (s *stockMovementService) RecordMovement(movement models.StockMovement) (models.StockMovement, error) {
+    // Validar el tipo de movimiento
+    switch movement.MovementType {
+    case models.StockMovementTypeWaste:
+        if !movement.Quantity.IsPositive() {
+            return models.StockMovement{}, errors.New("waste quantity must be greater than 0")
+        }
+        movement.Quantity = movement.Quantity.Neg()
+    case models.StockMovementTypeAdjustment:
+        if movement.Quantity.IsZero() {
+            return models.StockMovement{}, errors.New("adjustment quantity cannot be zero")
+        }
+    default:
+        return models.StockMovement{}, errors.New("movement type not allowed")
+    }
+
+    // Validar que se indique el motivo
+    movement.Reason = strings.TrimSpace(movement.Reason)
+    if movement.Reason == "" {
+        return models.StockMovement{}, errors.New("reason cannot be empty")
+    }
+
+    // Validar que el movimiento sea de un ítem o de un ingrediente, pero no de ambos
+    if (movement.MenuItemID == nil) == (movement.IngredientID == nil) {
+        return models.StockMovement{}, errors.New("movement must reference either a menu item or an ingredient")
+    }
+    if movement.MenuItemID != nil {
+        item, err := s.menuItemRepo.FindByID(*movement.MenuItemID)
+        if err != nil {
+            return models.StockMovement{}, errors.Wrap(err, "failed to find item")
+        }
+        if item.StockMode == models.MenuItemStockModeRecipe {
+            return models.StockMovement{}, errors.New("item stock is derived from its recipe ingredients")
+        }
+        if !movement.Quantity.Equal(movement.Quantity.Truncate(0)) {
+            return models.StockMovement{}, errors.New("item quantity must be a whole number")
+        }
+    } else {
+        if _, err := s.ingredientRepo.FindByID(*movement.IngredientID); err != nil {
+            return models.StockMovement{}, errors.Wrap(err, "failed to find ingredient")
+        }
+    }
+    movement.OrderDetailID = nil
+
+    createdMovement, err := s.stockMovementRepo.Create(movement)
+    if err != nil {
+        return models.StockMovement{}, errors.Wrap(err, "failed to record stock movement")
+    }
+    return createdMovement, nil
+}
+
+func (s *stockMovementService) ListMenuItemMovements(menuItemID int) ([]models.StockMovement, error) {
+    // Validar que el ítem exista
+    if _, err := s.menuItemRepo.FindByID(menuItemID); err != nil {
+        return nil, errors.Wrap(err, "failed to find item")
+    }
+
+    movements, err := s.stockMovementRepo.FindByMenuItemID(menuItemID)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to list stock movements")
+    }
+    return movements, nil
+}
+
+func (s *stockMovementService) ListIngredientMovements(ingredientID int) ([]models.StockMovement, error) {
+    // Validar que el ingrediente exista
+    if _, err := s.ingredientRepo.FindByID(ingredientID); err != nil {
+        return nil, errors.Wrap(err, "failed to find ingredient")
+    }
+
+    movements, err := s.stockMovementRepo.FindByIngredientID(ingredientID)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to list stock movements")
+    }
+    return movements, nil
+}
+
+// Reconcile lista los ítems e ingredientes cuyo stock no coincide con la suma del libro
+func (s *stockMovementService) Reconcile() ([]models.StockReconciliationEntry, error) {
+    entries, err := s.stockMovementRepo.Reconcile()
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to reconcile stock")
+    }
+    return entries, nil
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+    "net/mail"
+    "regexp"
+    "strconv"
+    "strings"
+
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/internal/repositories"
+
+    "github.com/pkg/errors"
+)
+
+type SupplierService interface {
+    CreateSupplier(supplier models.Supplier) (models.Supplier, error)
+    GetSupplier(supplierID int) (models.Supplier, error)
+    ListSuppliers() ([]models.Supplier, error)
+    UpdateSupplier(supplier models.Supplier) (models.Supplier, error)
+    DeleteSupplier(supplierID int) error
+}
+
+type supplierService struct {
+    supplierRepo repositories.SupplierRepository
+}
+
+func NewSupplierService(supplierRepo repositories.SupplierRepository) SupplierService {
+    return &supplierService{
+        supplierRepo: supplierRepo,
+    }
+}
+
+// nitPattern acepta el NIT sin puntos ni espacios, con o sin el dígito de verificación (ej. 900123456-7)
+var nitPattern = regexp.MustCompile(`^(\d{6,15})(-(\d))?$`)
+
+// nitWeights son los pesos primos que usa la DIAN para calcular el dígito de verificación,
+// aplicados de derecha a izquierda
+var nitWeights = []int{3, 7, 13, 17, 19, 23, 29, 37, 41, 43, 47, 53, 59, 67, 71}
+
+// nitCheckDigit calcula el dígito de verificación de un NIT (módulo 11)
+func nitCheckDigit(nit string) int {
+    sum := 0
+    for i := 0; i < len(nit); i++ {
+        digit := int(nit[len(nit)-1-i] - '0')
+        sum += digit * nitWeights[i]
+    }
+    remainder := sum % 11
+    if remainder >= 2 {
+        return 11 - remainder
+    }
+    return remainder
+}
+
+// normalizeNIT quita puntos y espacios del NIT y valida el dígito de verificación si se incluyó
+func normalizeNIT(nit string) (string, error) {
+    nit = strings.NewReplacer(".", "", " ", "").Replace(nit)
+    matches := nitPattern.FindStringSubmatch(nit)
+    if matches == nil {
+        return "", errors.New("invalid nit")
+    }
+    if matches[3] != "" {
+        checkDigit, _ := strconv.Atoi(matches[3])
+        if nitCheckDigit(matches[1]) != checkDigit {
+            return "", errors.New("invalid nit check digit")
+        }
+    }
+    return nit, nil
+}
+
+// validateSupplier aplica las reglas de validación de los campos de un proveedor y normaliza el NIT
+func validateSupplier(supplier *models.Supplier) error {
+    // Validar que el nombre no esté vacío
+    supplier.SupplierName = strings.TrimSpace(supplier.SupplierName)
+    if supplier.SupplierName == "" {
+        return errors.New("supplier name cannot be empty")
+    }
+
+    // Validar el NIT
+    nit, err := normalizeNIT(supplier.NIT)
+    if err != nil {
+        return err
+    }
+    supplier.NIT = nit
+
+    // Validar el correo de contacto si se indicó
+    supplier.Email = strings.TrimSpace(supplier.Email)
+    if supplier.Email != "" {
+        if _, err := mail.ParseAddress(supplier.Email); err != nil {
+            return errors.New("invalid email")
+        }
+    }
+    return nil
+}
+
+func (s *supplierService) CreateSupplier(supplier models.Supplier) (models.Supplier, error) {
+    if err := validateSupplier(&supplier); err != nil {
+        return models.Supplier{}, err
+    }
+
+    // Validar que el NIT sea único
+    _, err := s.supplierRepo.FindByNIT(supplier.NIT)
+    if err == nil {
+        return models.Supplier{}, errors.New("nit already exists")
+    }
+    if !strings.Contains(err.Error(), "supplier not found") {
+        return models.Supplier{}, errors.Wrap(err, "failed to check nit uniqueness")
+    }
+
+    createdSupplier, err := s.supplierRepo.Create(supplier)
+    if err != nil {
+        return models.Supplier{}, errors.Wrap(err, "failed to create supplier")
+    }
+    return createdSupplier, nil
+}
+
+func (s *supplierService) GetSupplier(supplierID int) (models.Supplier, error) {
+    supplier, err := s.supplierRepo.FindByID(supplierID)
+    if err != nil {
+        return models.Supplier{}, errors.Wrap(err, "failed to get supplier")
+    }
+    return supplier, nil
+}
+
+func (s *supplierService) ListSuppliers() ([]models.Supplier, error) {
+    suppliers, err := s.supplierRepo.FindAll()
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to list suppliers")
+    }
+    return suppliers, nil
+}
+
+func (s *supplierService) UpdateSupplier(supplier models.Supplier) (models.Supplier, error) {
+    if err := validateSupplier(&supplier); err != nil {
+        return models.Supplier{}, err
+    }
+
+    currentSupplier, err := s.supplierRepo.FindByID(supplier.ID)
+    if err != nil {
+        return models.Supplier{}, errors.Wrap(err, "failed to find supplier")
+    }
+
+    // Validar unicidad del NIT si cambió
+    if supplier.NIT != currentSupplier.NIT {
+        _, err := s.supplierRepo.FindByNIT(supplier.NIT)
+        if err == nil {
+            return models.Supplier{}, errors.New("nit already exists")
+        }
+        if !strings.Contains(err.Error(), "supplier not found") {
+            return models.Supplier{}, errors.Wrap(err, "failed to check nit uniqueness")
+        }
+    }
+
+    updatedSupplier, err := s.supplierRepo.Update(supplier)
+    if err != nil {
+        return models.Supplier{}, errors.Wrap(err, "failed to update supplier")
+    }
+    return updatedSupplier, nil
+}
+
+func (s *supplierService) DeleteSupplier(supplierID int) error {
+    if err := s.supplierRepo.Delete(supplierID); err != nil {
+        return errors.Wrap(err, "failed to delete supplier")
+    }
+    return nil
+}
@@ -8,29 +8,90 @@ import (
 	"github.com/pkg/errors"
 )
 
-const MaxTables = 4
+// DefaultTableCapacity es la capacidad de una mesa creada sin indicarla (el DEFAULT de tables.capacity)
+const DefaultTableCapacity = 2
 
 type TableService interface {
     CreateTable(table models.Table) (models.Table, error)
     GetTable(tableID int) (models.Table, error)
-    ListTables() ([]models.Table, error)
+    ListTables(includeStatus bool) ([]models.Table, error)
     UpdateTable(table models.Table) (models.Table, error)
+    ActivateTable(tableID int) (models.Table, error)
+    DeactivateTable(tableID int) (models.Table, error)
+    DeleteTable(tableID int) error
 }
 
 type tableService struct {
-    tableRepo repositories.TableRepository
+    tableRepo         repositories.TableRepository
+    businessRepo      repositories.BusinessRepository
+    customerOrderRepo repositories.CustomerOrderRepository
 }
 
-func NewTableService(tableRepo repositories.TableRepository) TableService {
+func NewTableService(tableRepo repositories.TableRepository, businessRepo repositories.BusinessRepository, customerOrderRepo repositories.CustomerOrderRepository) TableService {
     return &tableService{
-        tableRepo: tableRepo,
+        tableRepo:         tableRepo,
+        businessRepo:      businessRepo,
+        customerOrderRepo: customerOrderRepo,
     }
 }
 
-func (s *tableService) CreateTable(table models.Table) (models.Table, error) {
+// checkTableLimit valida que se pueda tener una mesa activa más según el límite configurado en el negocio
+func (s *tableService) checkTableLimit() error {
+    business, err := s.businessRepo.Find()
+    if err != nil {
+        return errors.Wrap(err, "failed to get business")
+    }
+    if business.MaxTables == nil {
+        return nil
+    }
+
+    count, err := s.tableRepo.CountActive()
+    if err != nil {
+        return errors.Wrap(err, "failed to count tables")
+    }
+    if count >= *business.MaxTables {
+        return errors.New("maximum number of tables reached")
+    }
+    return nil
+}
+
+// validateTable aplica las reglas de validación de los campos de una mesa
+func validateTable(table models.Table) error {
     // Validar que el nombre no esté vacío
     if table.TableName == "" {
-        return models.Table{}, errors.New("table name cannot be empty")
+        return errors.New("table name cannot be empty")
+    }
+
+    // Validar la capacidad
+    if table.Capacity <= 0 {
+        return errors.New("capacity must be greater than 0")
+    }
+
+    // Validar la zona
+    switch table.Zone {
+    case models.TableZoneTerrace, models.TableZoneBar, models.TableZoneHall:
+    default:
+        return errors.New("invalid table zone")
+    }
+
+    // Validar que la posición en el plano no sea negativa
+    if table.PosX < 0 || table.PosY < 0 {
+        return errors.New("table position cannot be negative")
+    }
+    return nil
+}
+
+func (s *tableService) CreateTable(table models.Table) (models.Table, error) {
+    // Aplicar los valores por defecto de la capacidad y la zona si no se envían
+    if table.Capacity == 0 {
+        table.Capacity = DefaultTableCapacity
+    }
+    if table.Zone == "" {
+        table.Zone = models.TableZoneHall
+    }
+
+    if err := validateTable(table); err != nil {
+        return models.Table{}, err
     }
 
     // Validar que el nombre sea único
@@ -42,15 +103,10 @@ func (s *tableService) CreateTable(table models.Table) (models.Table, error) {
         return models.Table{}, errors.Wrap(err, "failed to check table name uniqueness")
     }
 
-    // Validar el límite de mesas
-    count, err := s.tableRepo.Count()
-    if err != nil {
-        return models.Table{}, errors.Wrap(err, "failed to count tables")
+    // Validar el límite de mesas del negocio
+    if err := s.checkTableLimit(); err != nil {
+        return models.Table{}, err
     }
-    if count >= MaxTables {
-        return models.Table{}, errors.New("maximum number of tables reached")
-    }
-
 
     // Crear la mesa en el repositorio
     createdTable, err := s.tableRepo.Create(table)
@@ -69,8 +125,15 @@ func (s *tableService) GetTable(tableID int) (models.Table, error) {
     return table, nil
 }
 
-func (s *tableService) ListTables() ([]models.Table, error) {
-    tables, err := s.tableRepo.FindAll()
+// ListTables lista las mesas; con includeStatus se agrega el estado en vivo de cada una para el plano
+func (s *tableService) ListTables(includeStatus bool) ([]models.Table, error) {
+    var tables []models.Table
+    var err error
+    if includeStatus {
+        tables, err = s.tableRepo.FindAllWithStatus()
+    } else {
+        tables, err = s.tableRepo.FindAll()
+    }
     if err != nil {
         return nil, errors.Wrap(err, "failed to list tables")
     }
@@ -78,17 +141,24 @@ func (s *tableService) ListTables() ([]models.Table, error) {
 }
 
 func (s *tableService) UpdateTable(table models.Table) (models.Table, error) {
-    // Validar que el nombre no esté vacío
-    if table.TableName == "" {
-        return models.Table{}, errors.New("table name cannot be empty")
-    }
-
     // Obtener la mesa actual para verificar si el nombre cambió
     currentTable, err := s.tableRepo.FindByID(table.ID)
     if err != nil {
         return models.Table{}, errors.Wrap(err, "failed to find table")
     }
 
+    // Conservar la capacidad y la zona si no se envían
+    if table.Capacity == 0 {
+        table.Capacity = currentTable.Capacity
+    }
+    if table.Zone == "" {
+        table.Zone = currentTable.Zone
+    }
+
+    if err := validateTable(table); err != nil {
+        return models.Table{}, err
+    }
+
     // Validar unicidad del nombre si cambió
     if table.TableName != currentTable.TableName {
         _, err := s.tableRepo.FindByName(table.TableName)
@@ -106,4 +176,58 @@ func (s *tableService) UpdateTable(table models.Table) (models.Table, error) {
         return models.Table{}, errors.Wrap(err, "failed to update table")
     }
     return updatedTable, nil
-}
\ No newline at end of file
+}
+// ActivateTable vuelve a habilitar una mesa, respetando el límite de mesas activas
+func (s *tableService) ActivateTable(tableID int) (models.Table, error) {
+    table, err := s.tableRepo.FindByID(tableID)
+    if err != nil {
+        return models.Table{}, errors.Wrap(err, "failed to find table")
+    }
+    if table.Active {
+        return table, nil
+    }
+
+    if err := s.checkTableLimit(); err != nil {
+        return models.Table{}, err
+    }
+
+    activatedTable, err := s.tableRepo.SetActive(tableID, true)
+    if err != nil {
+        return models.Table{}, errors.Wrap(err, "failed to activate table")
+    }
+    return activatedTable, nil
+}
+
+// DeactivateTable retira una mesa del servicio; no se permite mientras tenga un pedido pendiente
+func (s *tableService) DeactivateTable(tableID int) (models.Table, error) {
+    table, err := s.tableRepo.FindByID(tableID)
+    if err != nil {
+        return models.Table{}, errors.Wrap(err, "failed to find table")
+    }
+    if !table.Active {
+        return table, nil
+    }
+
+    // Validar que la mesa no tenga un pedido pendiente
+    pendingOrder, err := s.customerOrderRepo.FindPendingByTableID(tableID)
+    if err == nil && pendingOrder.ID != 0 {
+        return models.Table{}, errors.New("table has a pending order")
+    }
+    if err != nil && !strings.Contains(err.Error(), "no pending customer order found") {
+        return models.Table{}, errors.Wrap(err, "failed to check pending order")
+    }
+
+    deactivatedTable, err := s.tableRepo.SetActive(tableID, false)
+    if err != nil {
+        return models.Table{}, errors.Wrap(err, "failed to deactivate table")
+    }
+    return deactivatedTable, nil
+}
+
+// DeleteTable elimina una mesa que nunca tuvo pedidos ni reservas; las demás solo pueden desactivarse
+func (s *tableService) DeleteTable(tableID int) error {
+    if err := s.tableRepo.Delete(tableID); err != nil {
+        return errors.Wrap(err, "failed to delete table")
+    }
+    return nil
+}
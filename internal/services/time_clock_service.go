@@ -0,0 +1,441 @@
+package services
+
+import (
+    "sort"
+    "strings"
+    "time"
+
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/internal/repositories"
+
+    "github.com/pkg/errors"
+)
+
+// MaxOpenEntryDuration es cuánto puede durar una jornada sin turno asignado antes de considerar que
+// el empleado olvidó marcar la salida
+const MaxOpenEntryDuration = 16 * time.Hour
+
+// MissedPunchGrace es el margen sobre el horario del turno antes de dar una marcación por faltante:
+// la entrada desde el inicio del turno y la salida desde su fin
+const MissedPunchGrace = 2 * time.Hour
+
+// MissedPunchDefaultRange es el periodo hacia atrás que revisa la detección de marcaciones faltantes
+const MissedPunchDefaultRange = 7 * 24 * time.Hour
+
+type TimeClockService interface {
+    GetStatus(employeeID int) (models.TimeClockStatus, error)
+    ClockIn(employeeID int) (models.TimeEntry, error)
+    ClockOut(employeeID int) (models.TimeEntry, error)
+    StartBreak(employeeID int) (models.TimeEntry, error)
+    EndBreak(employeeID int) (models.TimeEntry, error)
+    CreateEntry(entry models.TimeEntry, adjustedBy int) (models.TimeEntry, error)
+    AdjustEntry(entry models.TimeEntry, adjustedBy int) (models.TimeEntry, error)
+    ListMissedPunches(from, to *time.Time) ([]models.MissedPunch, error)
+    GetTimesheet(employeeID int, from, to *time.Time) (models.Timesheet, error)
+}
+
+type timeClockService struct {
+    timeClockRepo repositories.TimeClockRepository
+    scheduleRepo  repositories.ScheduleRepository
+    employeeRepo  repositories.EmployeeRepository
+}
+
+func NewTimeClockService(
+    timeClockRepo repositories.TimeClockRepository,
+    scheduleRepo repositories.ScheduleRepository,
+    employeeRepo repositories.EmployeeRepository,
+) TimeClockService {
+    return &timeClockService{
+        timeClockRepo: timeClockRepo,
+        scheduleRepo:  scheduleRepo,
+        employeeRepo:  employeeRepo,
+    }
+}
+
+// withDurations calcula el tiempo trabajado y de pausas de la jornada
+func withDurations(entry models.TimeEntry, now time.Time) models.TimeEntry {
+    entry.WorkedMinutes = int(entry.WorkedDuration(now).Minutes())
+    entry.BreakMinutes = int(entry.BreakDuration(now).Minutes())
+    return entry
+}
+
+// GetStatus indica si el empleado está fuera, trabajando o en pausa, con su jornada abierta
+func (s *timeClockService) GetStatus(employeeID int) (models.TimeClockStatus, error) {
+    status := models.TimeClockStatus{EmployeeID: employeeID, State: models.TimeClockStateClockedOut}
+
+    entry, err := s.timeClockRepo.FindOpenEntry(employeeID)
+    if err != nil {
+        if strings.Contains(err.Error(), "no open time entry") {
+            return status, nil
+        }
+        return models.TimeClockStatus{}, errors.Wrap(err, "failed to get open time entry")
+    }
+
+    entry = withDurations(entry, time.Now())
+    status.Entry = &entry
+    status.State = models.TimeClockStateWorking
+    for _, b := range entry.Breaks {
+        if b.EndedAt == nil {
+            status.State = models.TimeClockStateOnBreak
+        }
+    }
+    return status, nil
+}
+
+func (s *timeClockService) ClockIn(employeeID int) (models.TimeEntry, error) {
+    entry, err := s.timeClockRepo.ClockIn(employeeID)
+    if err != nil {
+        return models.TimeEntry{}, errors.Wrap(err, "failed to clock in")
+    }
+    return withDurations(entry, time.Now()), nil
+}
+
+func (s *timeClockService) ClockOut(employeeID int) (models.TimeEntry, error) {
+    entry, err := s.timeClockRepo.ClockOut(employeeID)
+    if err != nil {
+        return models.TimeEntry{}, errors.Wrap(err, "failed to clock out")
+    }
+    return withDurations(entry, time.Now()), nil
+}
+
+func (s *timeClockService) StartBreak(employeeID int) (models.TimeEntry, error) {
+    entry, err := s.timeClockRepo.StartBreak(employeeID)
+    if err != nil {
+        return models.TimeEntry{}, errors.Wrap(err, "failed to start break")
+    }
+    return withDurations(entry, time.Now()), nil
+}
+
+func (s *timeClockService) EndBreak(employeeID int) (models.TimeEntry, error) {
+    entry, err := s.timeClockRepo.EndBreak(employeeID)
+    if err != nil {
+        return models.TimeEntry{}, errors.Wrap(err, "failed to end break")
+    }
+    return withDurations(entry, time.Now()), nil
+}
+
+// validateEntryTimes valida la entrada y la salida de una jornada registrada o corregida a mano
+func validateEntryTimes(entry models.TimeEntry) error {
+    if entry.ClockInAt.IsZero() {
+        return errors.New("clock in is required")
+    }
+    if entry.ClockOutAt == nil {
+        return errors.New("clock out is required")
+    }
+    if !entry.ClockOutAt.After(entry.ClockInAt) {
+        return errors.New("clock out must be after clock in")
+    }
+    if entry.ClockOutAt.After(time.Now()) {
+        return errors.New("clock out cannot be in the future")
+    }
+    return nil
+}
+
+// CreateEntry registra a mano una jornada completa, por ejemplo para un turno sin marcación de entrada
+func (s *timeClockService) CreateEntry(entry models.TimeEntry, adjustedBy int) (models.TimeEntry, error) {
+    if entry.EmployeeID <= 0 {
+        return models.TimeEntry{}, errors.New("employee is required")
+    }
+    if err := validateEntryTimes(entry); err != nil {
+        return models.TimeEntry{}, err
+    }
+    entry.AdjustedBy = &adjustedBy
+
+    createdEntry, err := s.timeClockRepo.CreateEntry(entry)
+    if err != nil {
+        return models.TimeEntry{}, errors.Wrap(err, "failed to create time entry")
+    }
+    return withDurations(createdEntry, time.Now()), nil
+}
+
+// AdjustEntry corrige la entrada y la salida de una jornada, por ejemplo una salida no marcada
+func (s *timeClockService) AdjustEntry(entry models.TimeEntry, adjustedBy int) (models.TimeEntry, error) {
+    if err := validateEntryTimes(entry); err != nil {
+        return models.TimeEntry{}, err
+    }
+    entry.AdjustedBy = &adjustedBy
+
+    adjustedEntry, err := s.timeClockRepo.AdjustEntry(entry)
+    if err != nil {
+        return models.TimeEntry{}, errors.Wrap(err, "failed to adjust time entry")
+    }
+    return withDurations(adjustedEntry, time.Now()), nil
+}
+
+// matchEntriesToShifts asigna cada jornada al turno del mismo empleado con el que más se cruza.
+// Devuelve las jornadas de cada turno (por índice) y las que no corresponden a ningún turno
+func matchEntriesToShifts(shifts []models.Shift, entries []models.TimeEntry, now time.Time) (map[int][]models.TimeEntry, []models.TimeEntry) {
+    matched := make(map[int][]models.TimeEntry)
+    var unmatched []models.TimeEntry
+    for _, entry := range entries {
+        best := -1
+        var bestOverlap time.Duration
+        for i, shift := range shifts {
+            if shift.EmployeeID != entry.EmployeeID {
+                continue
+            }
+            start, end := shift.StartsAt, shift.EndsAt
+            if entry.ClockInAt.After(start) {
+                start = entry.ClockInAt
+            }
+            if entry.End(now).Before(end) {
+                end = entry.End(now)
+            }
+            if overlap := end.Sub(start); overlap > bestOverlap {
+                best, bestOverlap = i, overlap
+            }
+        }
+        if best == -1 {
+            unmatched = append(unmatched, entry)
+            continue
+        }
+        matched[best] = append(matched[best], entry)
+    }
+    return matched, unmatched
+}
+
+// missedClockOut indica si una jornada abierta ya debería haberse cerrado: pasado el margen desde el
+// fin de su turno o, si no tiene turno, pasada la duración máxima de una jornada
+func missedClockOut(entry models.TimeEntry, shift *models.Shift, now time.Time) (time.Time, bool) {
+    if entry.ClockOutAt != nil {
+        return time.Time{}, false
+    }
+    if shift != nil {
+        return shift.EndsAt, now.After(shift.EndsAt.Add(MissedPunchGrace))
+    }
+    expected := entry.ClockInAt.Add(MaxOpenEntryDuration)
+    return expected, now.After(expected)
+}
+
+// ListMissedPunches detecta los turnos publicados sin entrada y las jornadas abiertas que ya deberían
+// haberse cerrado; por defecto revisa los últimos siete días
+func (s *timeClockService) ListMissedPunches(from, to *time.Time) ([]models.MissedPunch, error) {
+    now := time.Now()
+    end := now
+    if to != nil {
+        end = *to
+    }
+    start := end.Add(-MissedPunchDefaultRange)
+    if from != nil {
+        start = *from
+    }
+    if !start.Before(end) {
+        return nil, errors.New("from must be before to")
+    }
+
+    shifts, err := s.scheduleRepo.FindShiftsInRange(start, end, 0, true)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to get shifts")
+    }
+    entries, err := s.timeClockRepo.FindEntries(0, start.Add(-MaxOpenEntryDuration), end)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to get time entries")
+    }
+    // Las jornadas abiertas se revisan aunque hayan empezado antes del periodo
+    openEntries, err := s.timeClockRepo.FindOpenEntries()
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to get open time entries")
+    }
+    seen := make(map[int]bool)
+    for _, entry := range entries {
+        seen[entry.ID] = true
+    }
+    for _, entry := range openEntries {
+        if !seen[entry.ID] {
+            entries = append(entries, entry)
+        }
+    }
+
+    matched, unmatched := matchEntriesToShifts(shifts, entries, now)
+    names := make(map[int]string)
+    for _, shift := range shifts {
+        names[shift.EmployeeID] = shift.EmployeeName
+    }
+
+    missed := []models.MissedPunch{}
+    for i := range shifts {
+        shift := shifts[i]
+        shiftID := shift.ID
+        if len(matched[i]) == 0 {
+            if shift.StartsAt.Before(start) || !now.After(shift.StartsAt.Add(MissedPunchGrace)) {
+                continue
+            }
+            missed = append(missed, models.MissedPunch{
+                Type:       models.MissedPunchClockIn,
+                EmployeeID: shift.EmployeeID,
+                ShiftID:    &shiftID,
+                ExpectedAt: shift.StartsAt,
+            })
+            continue
+        }
+        for _, entry := range matched[i] {
+            if expected, ok := missedClockOut(entry, &shift, now); ok {
+                entryID := entry.ID
+                missed = append(missed, models.MissedPunch{
+                    Type:        models.MissedPunchClockOut,
+                    EmployeeID:  entry.EmployeeID,
+                    TimeEntryID: &entryID,
+                    ShiftID:     &shiftID,
+                    ExpectedAt:  expected,
+                })
+            }
+        }
+    }
+    for _, entry := range unmatched {
+        if expected, ok := missedClockOut(entry, nil, now); ok {
+            entryID := entry.ID
+            missed = append(missed, models.MissedPunch{
+                Type:        models.MissedPunchClockOut,
+                EmployeeID:  entry.EmployeeID,
+                TimeEntryID: &entryID,
+                ExpectedAt:  expected,
+            })
+        }
+    }
+
+    // Completar los nombres de los empleados que no aparecen en los turnos
+    for i := range missed {
+        name, ok := names[missed[i].EmployeeID]
+        if !ok {
+            employee, err := s.employeeRepo.FindByID(missed[i].EmployeeID)
+            if err != nil {
+                return nil, errors.Wrap(err, "failed to find employee")
+            }
+            name = employee.EmployeeName
+            names[missed[i].EmployeeID] = name
+        }
+        missed[i].EmployeeName = name
+    }
+    sort.SliceStable(missed, func(i, j int) bool { return missed[i].ExpectedAt.Before(missed[j].ExpectedAt) })
+    return missed, nil
+}
+
+// GetTimesheet compara los turnos publicados del empleado con sus jornadas en el periodo (por
+// defecto la semana actual) y totaliza el tiempo programado, trabajado, llegadas tarde y ausencias
+func (s *timeClockService) GetTimesheet(employeeID int, from, to *time.Time) (models.Timesheet, error) {
+    employee, err := s.employeeRepo.FindByID(employeeID)
+    if err != nil {
+        return models.Timesheet{}, errors.Wrap(err, "failed to find employee")
+    }
+
+    now := time.Now()
+    today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+    start := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+    if from != nil {
+        start = *from
+    }
+    end := start.AddDate(0, 0, 7)
+    if to != nil {
+        end = *to
+    }
+    if !start.Before(end) {
+        return models.Timesheet{}, errors.New("from must be before to")
+    }
+
+    shifts, err := s.scheduleRepo.FindShiftsInRange(start, end, employeeID, true)
+    if err != nil {
+        return models.Timesheet{}, errors.Wrap(err, "failed to get shifts")
+    }
+    entries, err := s.timeClockRepo.FindEntries(employeeID, start, end)
+    if err != nil {
+        return models.Timesheet{}, errors.Wrap(err, "failed to get time entries")
+    }
+    for i := range entries {
+        entries[i] = withDurations(entries[i], now)
+    }
+    matched, unmatched := matchEntriesToShifts(shifts, entries, now)
+
+    timesheet := models.Timesheet{
+        EmployeeID:   employeeID,
+        EmployeeName: employee.EmployeeName,
+        From:         start,
+        To:           end,
+        Lines:        []models.TimesheetLine{},
+    }
+    for i := range shifts {
+        shift := shifts[i]
+        line := models.TimesheetLine{
+            Date:             shift.StartsAt.In(time.Local).Format("2006-01-02"),
+            Shift:            &shift,
+            Entries:          matched[i],
+            ScheduledMinutes: int(shift.EndsAt.Sub(shift.StartsAt).Minutes()),
+        }
+        if line.Entries == nil {
+            line.Entries = []models.TimeEntry{}
+        }
+        for _, entry := range line.Entries {
+            line.WorkedMinutes += entry.WorkedMinutes
+            line.BreakMinutes += entry.BreakMinutes
+        }
+
+        switch {
+        case len(line.Entries) == 0 && now.Before(shift.StartsAt.Add(MissedPunchGrace)):
+            line.Status = models.TimesheetLineUpcoming
+        case len(line.Entries) == 0:
+            line.Status = models.TimesheetLineAbsent
+        default:
+            line.Status = models.TimesheetLineOK
+            first, last := line.Entries[0], line.Entries[len(line.Entries)-1]
+            if late := first.ClockInAt.Sub(shift.StartsAt); late > 0 {
+                line.LateMinutes = int(late.Minutes())
+            }
+            if last.ClockOutAt == nil {
+                line.Status = models.TimesheetLineInProgress
+                if _, ok := missedClockOut(last, &shift, now); ok {
+                    line.Status = models.TimesheetLineMissedPunch
+                }
+            } else if early := shift.EndsAt.Sub(*last.ClockOutAt); early > 0 {
+                line.EarlyLeaveMinutes = int(early.Minutes())
+            }
+        }
+        // Los turnos que todavía no empiezan no cuentan como diferencia
+        if line.Status != models.TimesheetLineUpcoming {
+            line.DifferenceMinutes = line.WorkedMinutes - line.ScheduledMinutes
+        }
+        timesheet.Lines = append(timesheet.Lines, line)
+    }
+    for _, entry := range unmatched {
+        line := models.TimesheetLine{
+            Date:              entry.ClockInAt.In(time.Local).Format("2006-01-02"),
+            Entries:           []models.TimeEntry{entry},
+            Status:            models.TimesheetLineUnscheduled,
+            WorkedMinutes:     entry.WorkedMinutes,
+            BreakMinutes:      entry.BreakMinutes,
+            DifferenceMinutes: entry.WorkedMinutes,
+        }
+        if _, ok := missedClockOut(entry, nil, now); ok {
+            line.Status = models.TimesheetLineMissedPunch
+        }
+        timesheet.Lines = append(timesheet.Lines, line)
+    }
+
+    // Ordenar las líneas por la hora de inicio del turno o de la primera jornada
+    lineStart := func(line models.TimesheetLine) time.Time {
+        if line.Shift != nil {
+            return line.Shift.StartsAt
+        }
+        return line.Entries[0].ClockInAt
+    }
+    sort.SliceStable(timesheet.Lines, func(i, j int) bool {
+        return lineStart(timesheet.Lines[i]).Before(lineStart(timesheet.Lines[j]))
+    })
+
+    for _, line := range timesheet.Lines {
+        if line.Status != models.TimesheetLineUpcoming {
+            timesheet.ScheduledMinutes += line.ScheduledMinutes
+        }
+        timesheet.WorkedMinutes += line.WorkedMinutes
+        timesheet.BreakMinutes += line.BreakMinutes
+        timesheet.DifferenceMinutes += line.DifferenceMinutes
+        if line.LateMinutes > 0 {
+            timesheet.LateCount++
+        }
+        if line.Status == models.TimesheetLineAbsent {
+            timesheet.AbsentCount++
+        }
+        if line.Status == models.TimesheetLineMissedPunch {
+            timesheet.MissedPunchCount++
+        }
+    }
+    return timesheet, nil
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+    "time"
+
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/internal/repositories"
+
+    "github.com/pkg/errors"
+)
+
+type WaiterAssignmentService interface {
+    CreateAssignment(assignment models.WaiterAssignment, createdBy int) (models.WaiterAssignment, error)
+    ListAssignments(from, to *time.Time, employeeID int) ([]models.WaiterAssignment, error)
+    DeleteAssignment(assignmentID int) error
+    GetMyTables(employeeID int) (models.MyTables, error)
+}
+
+type waiterAssignmentService struct {
+    waiterAssignmentRepo repositories.WaiterAssignmentRepository
+    employeeRepo         repositories.EmployeeRepository
+}
+
+func NewWaiterAssignmentService(waiterAssignmentRepo repositories.WaiterAssignmentRepository, employeeRepo repositories.EmployeeRepository) WaiterAssignmentService {
+    return &waiterAssignmentService{
+        waiterAssignmentRepo: waiterAssignmentRepo,
+        employeeRepo:         employeeRepo,
+    }
+}
+
+// CreateAssignment asigna una sección (un grupo de mesas) a un mesero durante un turno
+func (s *waiterAssignmentService) CreateAssignment(assignment models.WaiterAssignment, createdBy int) (models.WaiterAssignment, error) {
+    // Validar que el empleado existe y sigue activo
+    employee, err := s.employeeRepo.FindByID(assignment.EmployeeID)
+    if err != nil {
+        return models.WaiterAssignment{}, errors.Wrap(err, "failed to find employee")
+    }
+    if !employee.Active {
+        return models.WaiterAssignment{}, errors.New("employee is inactive")
+    }
+
+    // Validar el horario del turno
+    if assignment.StartsAt.IsZero() || assignment.EndsAt.IsZero() {
+        return models.WaiterAssignment{}, errors.New("assignment start and end are required")
+    }
+    if !assignment.EndsAt.After(assignment.StartsAt) {
+        return models.WaiterAssignment{}, errors.New("assignment end must be after start")
+    }
+
+    // Validar las mesas: al menos una y sin repetir
+    if len(assignment.TableIDs) == 0 {
+        return models.WaiterAssignment{}, errors.New("assignment must have at least one table")
+    }
+    seen := make(map[int]bool)
+    for _, tableID := range assignment.TableIDs {
+        if seen[tableID] {
+            return models.WaiterAssignment{}, errors.New("table duplicated in assignment")
+        }
+        seen[tableID] = true
+    }
+    assignment.CreatedBy = createdBy
+
+    createdAssignment, err := s.waiterAssignmentRepo.Create(assignment)
+    if err != nil {
+        return models.WaiterAssignment{}, errors.Wrap(err, "failed to create waiter assignment")
+    }
+    return createdAssignment, nil
+}
+
+func (s *waiterAssignmentService) ListAssignments(from, to *time.Time, employeeID int) ([]models.WaiterAssignment, error) {
+    // Validar el rango de fechas
+    if from != nil && to != nil && !from.Before(*to) {
+        return nil, errors.New("from must be before to")
+    }
+
+    assignments, err := s.waiterAssignmentRepo.FindAll(from, to, employeeID)
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to list waiter assignments")
+    }
+    return assignments, nil
+}
+
+func (s *waiterAssignmentService) DeleteAssignment(assignmentID int) error {
+    if err := s.waiterAssignmentRepo.Delete(assignmentID); err != nil {
+        return errors.Wrap(err, "failed to delete waiter assignment")
+    }
+    return nil
+}
+
+// GetMyTables devuelve la sección vigente del empleado con el estado de sus mesas y los pedidos
+// abiertos a su cargo (los que atiende o los que están en sus mesas)
+func (s *waiterAssignmentService) GetMyTables(employeeID int) (models.MyTables, error) {
+    // Con from = to = now, FindAll devuelve las asignaciones vigentes en este momento
+    now := time.Now()
+    current, err := s.waiterAssignmentRepo.FindAll(&now, &now, employeeID)
+    if err != nil {
+        return models.MyTables{}, errors.Wrap(err, "failed to get current assignments")
+    }
+
+    tables, err := s.waiterAssignmentRepo.FindTablesForEmployee(employeeID, now)
+    if err != nil {
+        return models.MyTables{}, errors.Wrap(err, "failed to get assigned tables")
+    }
+    orders, err := s.waiterAssignmentRepo.FindOpenOrdersForEmployee(employeeID, now)
+    if err != nil {
+        return models.MyTables{}, errors.Wrap(err, "failed to get open orders")
+    }
+
+    return models.MyTables{
+        EmployeeID:  employeeID,
+        Assignments: current,
+        Tables:      tables,
+        OpenOrders:  orders,
+    }, nil
+}
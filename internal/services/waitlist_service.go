@@ -0,0 +1,199 @@
+package services
+
+import (
+    "math"
+    "strings"
+    "time"
+
+    "gastrobar-backend/internal/models"
+    "gastrobar-backend/internal/repositories"
+
+    "github.com/pkg/errors"
+)
+
+// DefaultSeatingMinutes es el tiempo de mesa que se asume mientras no haya pedidos completados
+const DefaultSeatingMinutes = 60
+
+// SeatingAverageWindow es el periodo de pedidos completados con el que se promedia el tiempo de mesa
+const SeatingAverageWindow = 30 * 24 * time.Hour
+
+type WaitlistService interface {
+    AddParty(entry models.WaitlistEntry, employeeID int) (models.WaitlistEntry, error)
+    GetEntry(entryID int) (models.WaitlistEntry, error)
+    ListWaiting() ([]models.WaitlistEntry, error)
+    EstimateWait(partySize int) (models.WaitEstimate, error)
+    SeatNext(tableID, employeeID int) (models.WaitlistEntry, error)
+    MarkLeft(entryID int) (models.WaitlistEntry, error)
+}
+
+type waitlistService struct {
+    waitlistRepo repositories.WaitlistRepository
+}
+
+func NewWaitlistService(waitlistRepo repositories.WaitlistRepository) WaitlistService {
+    return &waitlistService{
+        waitlistRepo: waitlistRepo,
+    }
+}
+
+// AddParty anota un grupo en la lista de espera; si no se indica la espera informada se usa la estimada
+func (s *waitlistService) AddParty(entry models.WaitlistEntry, employeeID int) (models.WaitlistEntry, error) {
+    entry.GuestName = strings.TrimSpace(entry.GuestName)
+    entry.GuestPhone = strings.TrimSpace(entry.GuestPhone)
+    if entry.GuestName == "" {
+        return models.WaitlistEntry{}, errors.New("guest name cannot be empty")
+    }
+    if entry.PartySize <= 0 {
+        return models.WaitlistEntry{}, errors.New("party size must be greater than 0")
+    }
+    if entry.QuotedWaitMinutes != nil && *entry.QuotedWaitMinutes < 0 {
+        return models.WaitlistEntry{}, errors.New("quoted wait cannot be negative")
+    }
+
+    estimate, err := s.EstimateWait(entry.PartySize)
+    if err != nil {
+        return models.WaitlistEntry{}, err
+    }
+    if entry.QuotedWaitMinutes == nil {
+        entry.QuotedWaitMinutes = &estimate.EstimatedWaitMinutes
+    }
+    entry.CreatedBy = employeeID
+
+    createdEntry, err := s.waitlistRepo.Create(entry)
+    if err != nil {
+        return models.WaitlistEntry{}, errors.Wrap(err, "failed to add party to waitlist")
+    }
+    createdEntry.Position = estimate.PartiesAhead + 1
+    createdEntry.EstimatedWaitMinutes = &estimate.EstimatedWaitMinutes
+    return createdEntry, nil
+}
+
+func (s *waitlistService) GetEntry(entryID int) (models.WaitlistEntry, error) {
+    entry, err := s.waitlistRepo.FindByID(entryID)
+    if err != nil {
+        return models.WaitlistEntry{}, errors.Wrap(err, "failed to get waitlist entry")
+    }
+    return entry, nil
+}
+
+// ListWaiting devuelve los grupos en espera con su posición y su espera estimada actual
+func (s *waitlistService) ListWaiting() ([]models.WaitlistEntry, error) {
+    entries, err := s.waitlistRepo.FindWaiting()
+    if err != nil {
+        return nil, errors.Wrap(err, "failed to list waitlist")
+    }
+    occupancy, average, err := s.loadSeatingState()
+    if err != nil {
+        return nil, err
+    }
+
+    now := time.Now()
+    for i := range entries {
+        entries[i].Position = i + 1
+        if minutes, ok := estimateWaitMinutes(occupancy, entries[:i], entries[i].PartySize, average, now); ok {
+            entries[i].EstimatedWaitMinutes = &minutes
+        }
+    }
+    return entries, nil
+}
+
+// EstimateWait estima la espera de un grupo que llega ahora, detrás de los que ya están esperando
+func (s *waitlistService) EstimateWait(partySize int) (models.WaitEstimate, error) {
+    if partySize <= 0 {
+        return models.WaitEstimate{}, errors.New("party size must be greater than 0")
+    }
+
+    waiting, err := s.waitlistRepo.FindWaiting()
+    if err != nil {
+        return models.WaitEstimate{}, errors.Wrap(err, "failed to list waitlist")
+    }
+    occupancy, average, err := s.loadSeatingState()
+    if err != nil {
+        return models.WaitEstimate{}, err
+    }
+
+    minutes, ok := estimateWaitMinutes(occupancy, waiting, partySize, average, time.Now())
+    if !ok {
+        return models.WaitEstimate{}, errors.New("party size exceeds the largest table")
+    }
+    return models.WaitEstimate{
+        PartySize:             partySize,
+        EstimatedWaitMinutes:  minutes,
+        AverageSeatingMinutes: int(math.Round(average.Minutes())),
+        PartiesAhead:          len(waiting),
+    }, nil
+}
+
+// loadSeatingState obtiene la ocupación actual de las mesas y el tiempo promedio de mesa
+func (s *waitlistService) loadSeatingState() ([]models.TableOccupancy, time.Duration, error) {
+    occupancy, err := s.waitlistRepo.FindTableOccupancy()
+    if err != nil {
+        return nil, 0, errors.Wrap(err, "failed to get table occupancy")
+    }
+
+    averageMinutes, samples, err := s.waitlistRepo.FindAverageSeatingMinutes(time.Now().Add(-SeatingAverageWindow))
+    if err != nil {
+        return nil, 0, errors.Wrap(err, "failed to get average seating duration")
+    }
+    if samples == 0 {
+        averageMinutes = DefaultSeatingMinutes
+    }
+    return occupancy, time.Duration(averageMinutes * float64(time.Minute)), nil
+}
+
+// estimateWaitMinutes simula la lista de espera: cada mesa ocupada se libera al cumplir el tiempo
+// promedio de mesa (o al terminar su reserva) y cada grupo que va adelante toma la primera mesa
+// en la que quepa, ocupándola otro tiempo promedio. La espera es lo que tarda en liberarse la primera
+// mesa en la que quepa el grupo. Devuelve false si el grupo no cabe en ninguna mesa
+func estimateWaitMinutes(occupancy []models.TableOccupancy, ahead []models.WaitlistEntry, partySize int, average time.Duration, now time.Time) (int, bool) {
+    freeAt := make([]time.Time, len(occupancy))
+    for i, table := range occupancy {
+        freeAt[i] = now
+        if table.OccupiedSince != nil && table.OccupiedSince.Add(average).After(freeAt[i]) {
+            freeAt[i] = table.OccupiedSince.Add(average)
+        }
+        if table.ReservedUntil != nil && table.ReservedUntil.After(freeAt[i]) {
+            freeAt[i] = *table.ReservedUntil
+        }
+    }
+
+    // firstFree devuelve la mesa que se libera primero entre las que tienen capacidad suficiente
+    firstFree := func(size int) int {
+        best := -1
+        for i, table := range occupancy {
+            if table.Capacity >= size && (best == -1 || freeAt[i].Before(freeAt[best])) {
+                best = i
+            }
+        }
+        return best
+    }
+
+    for _, party := range ahead {
+        if i := firstFree(party.PartySize); i != -1 {
+            freeAt[i] = freeAt[i].Add(average)
+        }
+    }
+
+    i := firstFree(partySize)
+    if i == -1 {
+        return 0, false
+    }
+    return int(math.Ceil(freeAt[i].Sub(now).Minutes())), true
+}
+
+// SeatNext sienta en la mesa liberada al primer grupo en espera que quepa en ella
+func (s *waitlistService) SeatNext(tableID, employeeID int) (models.WaitlistEntry, error) {
+    entry, err := s.waitlistRepo.SeatNext(tableID, employeeID)
+    if err != nil {
+        return models.WaitlistEntry{}, errors.Wrap(err, "failed to seat next party")
+    }
+    return entry, nil
+}
+
+func (s *waitlistService) MarkLeft(entryID int) (models.WaitlistEntry, error) {
+    entry, err := s.waitlistRepo.Leave(entryID)
+    if err != nil {
+        return models.WaitlistEntry{}, errors.Wrap(err, "failed to remove party from waitlist")
+    }
+    return entry, nil
+}
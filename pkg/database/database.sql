@@ -12,6 +12,8 @@ CREATE TABLE business (
     phone_number     VARCHAR(20),
     email            VARCHAR(255),
     corporate_reason VARCHAR(20)  NOT NULL,
+    guest_orders_require_confirmation BOOLEAN NOT NULL DEFAULT FALSE,
+    max_tables       INTEGER      CHECK (max_tables > 0), -- Límite de mesas activas; NULL = sin límite
     created_at       TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     updated_at       TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
@@ -31,63 +33,433 @@ CREATE TYPE employee_role AS ENUM ('dueño', 'administrador', 'empleado');
 
 -- Crear la tabla para los empleados (employees, con role como ENUM)
 CREATE TABLE employees (
-    id            SERIAL PRIMARY KEY,
-    employee_name VARCHAR(100)  NOT NULL,
-    email         VARCHAR(255),
-    phone_number  VARCHAR(20),
-    role          employee_role NOT NULL,
-    username      VARCHAR(200)  NOT NULL,
-    password      VARCHAR(200)  NOT NULL,
-    created_at    TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+    id             SERIAL PRIMARY KEY,
+    employee_name  VARCHAR(100)  NOT NULL,
+    email          VARCHAR(255),
+    phone_number   VARCHAR(20),
+    role           employee_role NOT NULL,
+    username       VARCHAR(200)  NOT NULL,
+    password       VARCHAR(200)  NOT NULL,
+    token_version  INTEGER       NOT NULL DEFAULT 0, -- Se incrementa al cambiar la contraseña o el rol para invalidar las sesiones emitidas
+    active         BOOLEAN       NOT NULL DEFAULT TRUE, -- Los empleados dados de baja no pueden iniciar sesión; su historial se conserva
+    deactivated_at TIMESTAMP WITH TIME ZONE,
+    deactivated_by INTEGER       REFERENCES employees(id),
+    created_at     TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
 
 -- Crear la tabla para las mesas (tables)
 CREATE TABLE tables (
     id         SERIAL PRIMARY KEY,
     table_name VARCHAR(50),
+    capacity   INTEGER     NOT NULL DEFAULT 2 CHECK (capacity > 0),
+    zone       VARCHAR(20) NOT NULL DEFAULT 'salón' CHECK (zone IN ('terraza', 'barra', 'salón')),
+    pos_x      INTEGER     NOT NULL DEFAULT 0, -- Posición en el plano del local (unidades de la grilla de la UI)
+    pos_y      INTEGER     NOT NULL DEFAULT 0,
+    qr_version INTEGER     NOT NULL DEFAULT 1,
+    active     BOOLEAN     NOT NULL DEFAULT TRUE, -- Las mesas inactivas no aceptan pedidos ni cuentan para el límite
     created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
 
 -- Crear la tabla menu_items para agregar el campo description
 CREATE TABLE menu_items (
-    id          SERIAL PRIMARY KEY,
-    item_name   VARCHAR(100)   NOT NULL,
-    category    VARCHAR(50),
-    price       NUMERIC(10, 2) NOT NULL,
-    stock       INTEGER        NOT NULL DEFAULT 0,
-    description TEXT,
-    created_at  TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+    id                SERIAL PRIMARY KEY,
+    item_name         VARCHAR(100)   NOT NULL,
+    category          VARCHAR(50),
+    price             NUMERIC(10, 2) NOT NULL,
+    stock             INTEGER        NOT NULL DEFAULT 0,
+    stock_mode        VARCHAR(10)    NOT NULL DEFAULT 'item' CHECK (stock_mode IN ('item', 'recipe')),
+    reorder_threshold INTEGER        NOT NULL DEFAULT 0 CHECK (reorder_threshold >= 0), -- 0 = sin alerta de stock bajo
+    description       TEXT,
+    created_at        TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
 
--- Crear la tabla customer_orders para asociar pedidos con mesas
-CREATE TABLE customer_orders (
+-- Crear la tabla de ingredientes (inventario a nivel de insumo)
+CREATE TABLE ingredients (
+    id              SERIAL PRIMARY KEY,
+    ingredient_name VARCHAR(100)   NOT NULL UNIQUE,
+    unit            VARCHAR(10)    NOT NULL CHECK (unit IN ('g', 'ml', 'unit')),
+    stock           NUMERIC(12, 3) NOT NULL DEFAULT 0 CHECK (stock >= 0),
+    created_at      TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Crear la tabla de recetas (cantidad de cada ingrediente por unidad del ítem)
+CREATE TABLE recipe_items (
+    menu_item_id  INTEGER        NOT NULL REFERENCES menu_items(id) ON DELETE CASCADE,
+    ingredient_id INTEGER        NOT NULL REFERENCES ingredients(id) ON DELETE RESTRICT,
+    quantity      NUMERIC(12, 3) NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (menu_item_id, ingredient_id)
+);
+
+-- Crear la tabla para el historial de precios de los ítems del menú
+CREATE TABLE menu_item_price_history (
+    id                        SERIAL PRIMARY KEY,
+    menu_item_id              INTEGER        NOT NULL REFERENCES menu_items(id) ON DELETE CASCADE,
+    old_price                 NUMERIC(10, 2) NOT NULL,
+    new_price                 NUMERIC(10, 2) NOT NULL,
+    changed_by                INTEGER        NOT NULL REFERENCES employees(id),
+    scheduled_price_change_id INTEGER,
+    changed_at                TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Crear la tabla para los cambios de precio programados
+CREATE TABLE menu_item_scheduled_prices (
     id           SERIAL PRIMARY KEY,
-    table_id     INTEGER        NOT NULL REFERENCES tables(id) ON DELETE CASCADE,
-    total_amount NUMERIC(10, 2) NOT NULL DEFAULT 0.0,
-    status       VARCHAR(20)    NOT NULL CHECK (status IN ('pending', 'completed')),
+    menu_item_id INTEGER        NOT NULL REFERENCES menu_items(id) ON DELETE CASCADE,
+    new_price    NUMERIC(10, 2) NOT NULL CHECK (new_price > 0),
+    effective_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    status       VARCHAR(20)    NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'applied', 'cancelled')),
+    created_by   INTEGER        NOT NULL REFERENCES employees(id),
+    applied_at   TIMESTAMP WITH TIME ZONE,
     created_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
 
+-- Crear la tabla para las fotos de los ítems del menú (una por ítem)
+CREATE TABLE menu_item_images (
+    id             SERIAL PRIMARY KEY,
+    menu_item_id   INTEGER      NOT NULL UNIQUE REFERENCES menu_items(id) ON DELETE CASCADE,
+    image_path     VARCHAR(255) NOT NULL,
+    thumbnail_path VARCHAR(255) NOT NULL,
+    created_at     TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Crear la tabla para las etiquetas de alérgenos y dietas de los ítems del menú
+CREATE TABLE menu_item_tags (
+    menu_item_id INTEGER     NOT NULL REFERENCES menu_items(id) ON DELETE CASCADE,
+    tag          VARCHAR(30) NOT NULL,
+    PRIMARY KEY (menu_item_id, tag)
+);
+
+-- Crear la tabla para las traducciones de los ítems del menú (el idioma base es 'es')
+CREATE TABLE menu_item_translations (
+    id           SERIAL PRIMARY KEY,
+    menu_item_id INTEGER      NOT NULL REFERENCES menu_items(id) ON DELETE CASCADE,
+    locale       VARCHAR(15)  NOT NULL,
+    item_name    VARCHAR(100) NOT NULL,
+    description  TEXT,
+    updated_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (menu_item_id, locale)
+);
+
+-- Crear la tabla customer_orders para asociar pedidos con mesas
+CREATE TABLE customer_orders (
+    id                SERIAL PRIMARY KEY,
+    table_id          INTEGER        NOT NULL REFERENCES tables(id) ON DELETE CASCADE,
+    total_amount      NUMERIC(10, 2) NOT NULL DEFAULT 0.0,
+    status            VARCHAR(20)    NOT NULL CHECK (status IN ('pending', 'completed')),
+    bill_requested_at TIMESTAMP WITH TIME ZONE, -- Se pidió la cuenta: la mesa queda esperando el pago
+    completed_at      TIMESTAMP WITH TIME ZONE, -- Cierre de la cuenta; con created_at da el tiempo de mesa
+    opened_by         INTEGER        REFERENCES employees(id), -- NULL si el pedido lo abrió un cliente por QR
+    served_by         INTEGER        REFERENCES employees(id), -- Mesero a cargo de la mesa
+    closed_by         INTEGER        REFERENCES employees(id),
+    created_at        TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
 -- Crear la tabla order_details (eliminamos price_at_time)
 CREATE TABLE order_details (
     id           SERIAL PRIMARY KEY,
     order_id     INTEGER        NOT NULL REFERENCES customer_orders(id) ON DELETE CASCADE,
     menu_item_id INTEGER        NOT NULL REFERENCES menu_items(id),
     quantity     INTEGER        NOT NULL CHECK (quantity > 0),
+    status       VARCHAR(20)    NOT NULL DEFAULT 'confirmed' CHECK (status IN ('pending_confirmation', 'confirmed')),
+    source       VARCHAR(10)    NOT NULL DEFAULT 'staff' CHECK (source IN ('staff', 'guest')),
+    created_by   INTEGER        REFERENCES employees(id), -- NULL para las líneas de clientes por QR
     created_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
 
+-- Crear la tabla de asignaciones de meseros: durante un turno cada mesero atiende una sección
+-- (un grupo de mesas)
+CREATE TABLE waiter_assignments (
+    id          SERIAL PRIMARY KEY,
+    employee_id INTEGER NOT NULL REFERENCES employees(id),
+    starts_at   TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at     TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_by  INTEGER NOT NULL REFERENCES employees(id),
+    created_at  TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_at > starts_at)
+);
+
+-- Crear la tabla de mesas de cada sección asignada
+CREATE TABLE waiter_assignment_tables (
+    assignment_id INTEGER NOT NULL REFERENCES waiter_assignments(id) ON DELETE CASCADE,
+    table_id      INTEGER NOT NULL REFERENCES tables(id) ON DELETE CASCADE,
+    PRIMARY KEY (assignment_id, table_id)
+);
+
+-- Crear la tabla de reservas (tomadas por teléfono o WhatsApp); ocupan sus mesas desde reserved_at
+-- durante duration_minutes. Al sentar a los clientes se abre un pedido pendiente en la mesa
+CREATE TABLE reservations (
+    id                SERIAL PRIMARY KEY,
+    guest_name        VARCHAR(100) NOT NULL,
+    guest_phone       VARCHAR(20)  NOT NULL,
+    party_size        INTEGER      NOT NULL CHECK (party_size > 0),
+    reserved_at       TIMESTAMP WITH TIME ZONE NOT NULL,
+    duration_minutes  INTEGER      NOT NULL DEFAULT 90 CHECK (duration_minutes > 0),
+    status            VARCHAR(20)  NOT NULL DEFAULT 'booked' CHECK (status IN ('booked', 'seated', 'no_show', 'cancelled')),
+    notes             TEXT,
+    customer_order_id INTEGER      REFERENCES customer_orders(id) ON DELETE SET NULL, -- Pedido abierto al sentar
+    created_by        INTEGER      NOT NULL REFERENCES employees(id),
+    seated_at         TIMESTAMP WITH TIME ZONE,
+    created_at        TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Crear la tabla de mesas asignadas a cada reserva (un grupo grande puede ocupar varias mesas)
+CREATE TABLE reservation_tables (
+    reservation_id INTEGER NOT NULL REFERENCES reservations(id) ON DELETE CASCADE,
+    table_id       INTEGER NOT NULL REFERENCES tables(id),
+    PRIMARY KEY (reservation_id, table_id)
+);
+
+-- Crear la tabla de la lista de espera para clientes sin reserva; al sentar al grupo se le asigna
+-- una mesa y se abre su pedido pendiente
+CREATE TABLE waitlist_entries (
+    id                  SERIAL PRIMARY KEY,
+    guest_name          VARCHAR(100) NOT NULL,
+    guest_phone         VARCHAR(20),
+    party_size          INTEGER      NOT NULL CHECK (party_size > 0),
+    quoted_wait_minutes INTEGER      NOT NULL CHECK (quoted_wait_minutes >= 0), -- Espera informada al cliente
+    status              VARCHAR(20)  NOT NULL DEFAULT 'waiting' CHECK (status IN ('waiting', 'seated', 'left')),
+    table_id            INTEGER      REFERENCES tables(id) ON DELETE SET NULL,
+    customer_order_id   INTEGER      REFERENCES customer_orders(id) ON DELETE SET NULL,
+    created_by          INTEGER      NOT NULL REFERENCES employees(id),
+    seated_at           TIMESTAMP WITH TIME ZONE,
+    left_at             TIMESTAMP WITH TIME ZONE,
+    created_at          TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Crear la tabla de proveedores
+CREATE TABLE suppliers (
+    id            SERIAL PRIMARY KEY,
+    supplier_name VARCHAR(100) NOT NULL,
+    nit           VARCHAR(20)  NOT NULL UNIQUE,
+    contact_name  VARCHAR(100),
+    phone_number  VARCHAR(20),
+    email         VARCHAR(100),
+    created_at    TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Crear la tabla de órdenes de compra
+CREATE TABLE purchase_orders (
+    id          SERIAL PRIMARY KEY,
+    supplier_id INTEGER     NOT NULL REFERENCES suppliers(id),
+    status      VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'sent', 'partially_received', 'received')),
+    notes       TEXT,
+    created_by  INTEGER     NOT NULL REFERENCES employees(id),
+    sent_at     TIMESTAMP WITH TIME ZONE,
+    received_at TIMESTAMP WITH TIME ZONE,
+    created_at  TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Crear la tabla de líneas de las órdenes de compra (un ítem embotellado o un ingrediente por línea)
+CREATE TABLE purchase_order_lines (
+    id                 SERIAL PRIMARY KEY,
+    purchase_order_id  INTEGER        NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    menu_item_id       INTEGER        REFERENCES menu_items(id),
+    ingredient_id      INTEGER        REFERENCES ingredients(id),
+    quantity           NUMERIC(12, 3) NOT NULL CHECK (quantity > 0),
+    expected_unit_cost NUMERIC(12, 4) NOT NULL CHECK (expected_unit_cost >= 0),
+    received_quantity  NUMERIC(12, 3) NOT NULL DEFAULT 0 CHECK (received_quantity >= 0),
+    actual_unit_cost   NUMERIC(12, 4) CHECK (actual_unit_cost >= 0), -- Costo real de la última recepción
+    CHECK ((menu_item_id IS NULL) <> (ingredient_id IS NULL))
+);
+
+-- Crear la tabla de sesiones de conteo físico de inventario
+CREATE TABLE inventory_counts (
+    id          SERIAL PRIMARY KEY,
+    status      VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'approved', 'cancelled')),
+    notes       TEXT,
+    opened_by   INTEGER     NOT NULL REFERENCES employees(id),
+    opened_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    approved_by INTEGER     REFERENCES employees(id),
+    approved_at TIMESTAMP WITH TIME ZONE,
+    closed_at   TIMESTAMP WITH TIME ZONE -- Fecha de aprobación o cancelación
+);
+
+-- Crear la tabla de cantidades contadas; cada empleado registra lo que contó de un ítem (por ejemplo
+-- la barra y la bodega) y el total contado es la suma de sus registros
+CREATE TABLE inventory_count_entries (
+    id                 SERIAL PRIMARY KEY,
+    inventory_count_id INTEGER NOT NULL REFERENCES inventory_counts(id) ON DELETE CASCADE,
+    menu_item_id       INTEGER NOT NULL REFERENCES menu_items(id),
+    employee_id        INTEGER NOT NULL REFERENCES employees(id),
+    counted_quantity   INTEGER NOT NULL CHECK (counted_quantity >= 0),
+    updated_at         TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (inventory_count_id, menu_item_id, employee_id)
+);
+
+-- Crear la tabla con el resultado de cada conteo aprobado (se conserva como reporte de faltantes)
+CREATE TABLE inventory_count_lines (
+    inventory_count_id INTEGER NOT NULL REFERENCES inventory_counts(id),
+    menu_item_id       INTEGER NOT NULL REFERENCES menu_items(id),
+    system_quantity    INTEGER NOT NULL,
+    counted_quantity   INTEGER NOT NULL,
+    variance           INTEGER NOT NULL, -- Contado - sistema: negativo es faltante
+    unit_cost          NUMERIC(12, 4), -- Último costo de compra conocido al aprobar
+    PRIMARY KEY (inventory_count_id, menu_item_id)
+);
+
+-- Crear la tabla del libro de movimientos de inventario (solo inserciones; el stock se deriva de ella)
+-- quantity es el cambio con signo: negativo para salidas (venta, merma) y positivo para entradas
+CREATE TABLE stock_movements (
+    id                     SERIAL PRIMARY KEY,
+    menu_item_id           INTEGER        REFERENCES menu_items(id),
+    ingredient_id          INTEGER        REFERENCES ingredients(id),
+    movement_type          VARCHAR(20)    NOT NULL CHECK (movement_type IN ('sale', 'return', 'purchase', 'waste', 'adjustment', 'count_correction')),
+    quantity               NUMERIC(12, 3) NOT NULL CHECK (quantity <> 0),
+    employee_id            INTEGER        REFERENCES employees(id),
+    reason                 TEXT,
+    order_detail_id        INTEGER, -- Sin FK: la línea puede eliminarse pero el movimiento se conserva
+    purchase_order_line_id INTEGER        REFERENCES purchase_order_lines(id),
+    unit_cost              NUMERIC(12, 4), -- Costo unitario real (solo compras)
+    inventory_count_id     INTEGER        REFERENCES inventory_counts(id),
+    created_at             TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((menu_item_id IS NULL) <> (ingredient_id IS NULL))
+);
+
 -- Crear la tabla para las tareas de los empleados (employee_tasks)
 CREATE TABLE employee_tasks (
     id               SERIAL PRIMARY KEY,
     employee_id      INTEGER NOT NULL REFERENCES employees(id),
     task_description TEXT    NOT NULL,
-    status           VARCHAR(20) DEFAULT 'pending',
+    status           VARCHAR(20) NOT NULL DEFAULT 'pendiente' CHECK (status IN ('pendiente', 'en_progreso', 'completada', 'cancelada')),
     assigned_at      TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
     completed_at     TIMESTAMP WITH TIME ZONE,
     created_at       TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
 );
 
+-- Crear la tabla de plantillas de turno (por ejemplo "Almuerzo salón" de 11:00 a 16:00). Si end_time
+-- es menor o igual que start_time el turno termina al día siguiente
+CREATE TABLE shift_templates (
+    id            SERIAL PRIMARY KEY,
+    template_name VARCHAR(100) NOT NULL UNIQUE,
+    role          VARCHAR(20)  NOT NULL CHECK (role IN ('mesero', 'cocina', 'barra', 'caja', 'encargado')),
+    start_time    TIME         NOT NULL,
+    end_time      TIME         NOT NULL,
+    created_at    TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Crear la tabla de horarios semanales; los empleados solo ven los turnos de horarios publicados
+CREATE TABLE schedules (
+    id           SERIAL PRIMARY KEY,
+    week_start   DATE        NOT NULL UNIQUE CHECK (EXTRACT(ISODOW FROM week_start) = 1), -- Lunes de la semana
+    status       VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'published')),
+    published_at TIMESTAMP WITH TIME ZONE,
+    published_by INTEGER     REFERENCES employees(id),
+    created_by   INTEGER     NOT NULL REFERENCES employees(id),
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Crear la tabla de turnos de cada horario: un empleado cumple un rol en un intervalo
+CREATE TABLE shifts (
+    id                SERIAL PRIMARY KEY,
+    schedule_id       INTEGER     NOT NULL REFERENCES schedules(id) ON DELETE CASCADE,
+    employee_id       INTEGER     NOT NULL REFERENCES employees(id),
+    shift_template_id INTEGER     REFERENCES shift_templates(id) ON DELETE SET NULL,
+    role              VARCHAR(20) NOT NULL CHECK (role IN ('mesero', 'cocina', 'barra', 'caja', 'encargado')),
+    starts_at         TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at           TIMESTAMP WITH TIME ZONE NOT NULL,
+    notes             TEXT,
+    created_at        TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_at > starts_at)
+);
+
+-- Crear la tabla de marcaciones de asistencia: cada fila es una jornada desde la entrada hasta la
+-- salida (NULL mientras el empleado sigue trabajando). adjusted_by indica una corrección manual
+CREATE TABLE time_entries (
+    id           SERIAL PRIMARY KEY,
+    employee_id  INTEGER NOT NULL REFERENCES employees(id),
+    clock_in_at  TIMESTAMP WITH TIME ZONE NOT NULL,
+    clock_out_at TIMESTAMP WITH TIME ZONE,
+    adjusted_by  INTEGER REFERENCES employees(id),
+    adjusted_at  TIMESTAMP WITH TIME ZONE,
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (clock_out_at IS NULL OR clock_out_at > clock_in_at)
+);
+
+-- Crear la tabla de pausas de cada jornada (ended_at es NULL mientras la pausa sigue en curso)
+CREATE TABLE time_entry_breaks (
+    id            SERIAL PRIMARY KEY,
+    time_entry_id INTEGER NOT NULL REFERENCES time_entries(id) ON DELETE CASCADE,
+    started_at    TIMESTAMP WITH TIME ZONE NOT NULL,
+    ended_at      TIMESTAMP WITH TIME ZONE,
+    CHECK (ended_at IS NULL OR ended_at >= started_at)
+);
+
+-- Crear la tabla de tarifas por hora de cada empleado (base para la liquidación de la nómina)
+CREATE TABLE employee_pay_rates (
+    employee_id INTEGER       PRIMARY KEY REFERENCES employees(id) ON DELETE CASCADE,
+    hourly_rate NUMERIC(10,2) NOT NULL CHECK (hourly_rate >= 0),
+    updated_by  INTEGER       REFERENCES employees(id),
+    updated_at  TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Crear la tabla de parámetros de la nómina (una sola fila). Los recargos son fracciones de la
+-- tarifa que se suman a la hora base: 0.35 es un 35% adicional
+CREATE TABLE payroll_settings (
+    id                   INTEGER      PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    daily_overtime_hours NUMERIC(4,2) NOT NULL DEFAULT 8 CHECK (daily_overtime_hours > 0), -- Horas diarias antes de las extras
+    overtime_surcharge   NUMERIC(4,2) NOT NULL DEFAULT 0.25 CHECK (overtime_surcharge >= 0),
+    night_start          TIME         NOT NULL DEFAULT '21:00',
+    night_end            TIME         NOT NULL DEFAULT '06:00',
+    night_surcharge      NUMERIC(4,2) NOT NULL DEFAULT 0.35 CHECK (night_surcharge >= 0),
+    holiday_surcharge    NUMERIC(4,2) NOT NULL DEFAULT 0.75 CHECK (holiday_surcharge >= 0),
+    sunday_is_holiday    BOOLEAN      NOT NULL DEFAULT TRUE, -- Los domingos llevan el recargo de festivo
+    updated_by           INTEGER      REFERENCES employees(id),
+    updated_at           TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Crear la tabla de días festivos (llevan el recargo de festivo)
+CREATE TABLE holidays (
+    holiday_date DATE         PRIMARY KEY,
+    description  VARCHAR(100) NOT NULL,
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Crear la tabla de propinas recaudadas; el total del periodo se reparte según las horas trabajadas
+CREATE TABLE tip_pool_entries (
+    id           SERIAL PRIMARY KEY,
+    collected_on DATE          NOT NULL,
+    amount       NUMERIC(10,2) NOT NULL CHECK (amount > 0),
+    notes        TEXT,
+    created_by   INTEGER       NOT NULL REFERENCES employees(id),
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Crear la tabla de auditoría de las acciones sobre empleados (altas, ediciones, contraseñas, bajas y traspasos)
+CREATE TABLE employee_audit_logs (
+    id          SERIAL PRIMARY KEY,
+    employee_id INTEGER     NOT NULL REFERENCES employees(id),
+    actor_id    INTEGER     NOT NULL REFERENCES employees(id),
+    action      VARCHAR(30) NOT NULL,
+    old_data    JSONB, -- Fila del empleado antes de la acción, sin la contraseña; NULL al crear
+    new_data    JSONB       NOT NULL,
+    created_at  TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Crear la tabla del catálogo de permisos que declaran las rutas del personal
+CREATE TABLE permissions (
+    code        VARCHAR(50) PRIMARY KEY, -- p. ej. 'orders.void', 'menu.edit'
+    description TEXT        NOT NULL
+);
+
+-- Crear la tabla de permisos concedidos a cada rol (la edita el dueño)
+CREATE TABLE role_permissions (
+    role            employee_role NOT NULL,
+    permission_code VARCHAR(50)   NOT NULL REFERENCES permissions(code) ON DELETE CASCADE,
+    granted_by      INTEGER       REFERENCES employees(id),
+    granted_at      TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (role, permission_code)
+);
+
+-- Crear la tabla del historial de cambios de estado de las tareas
+CREATE TABLE employee_task_status_history (
+    id          SERIAL PRIMARY KEY,
+    task_id     INTEGER     NOT NULL REFERENCES employee_tasks(id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL,
+    to_status   VARCHAR(20) NOT NULL,
+    changed_by  INTEGER     NOT NULL REFERENCES employees(id),
+    changed_at  TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
 -- =====================================================================
 -- FUNCIONES Y TRIGGERS (ORDENADOS POR TABLA AFECTADA)
 -- =====================================================================
@@ -133,30 +505,156 @@ CREATE TRIGGER business_info_delete_trigger
 
 -- --------------------- Triggers para menu_items (a través de order_details) -------------------------
 
--- Crear la función para actualizar el stock automáticamente
-CREATE OR REPLACE FUNCTION update_menu_item_stock()
-RETURNS TRIGGER AS $$
+-- Crear la función que calcula cuántas unidades de un ítem se pueden vender: el stock propio
+-- para productos embotellados o, para ítems con receta, el mínimo que permiten sus ingredientes
+CREATE OR REPLACE FUNCTION menu_item_available_quantity(p_menu_item_id INTEGER)
+RETURNS INTEGER AS $$
 DECLARE
-    item_name     VARCHAR(100);
-    current_stock INTEGER;
+    item_stock_mode VARCHAR(10);
+    item_stock      INTEGER;
+    available       INTEGER;
 BEGIN
-    -- Obtener el nombre y el stock actual del ítem
-    SELECT menu_items.item_name, stock INTO item_name, current_stock
+    SELECT stock_mode, stock INTO item_stock_mode, item_stock
     FROM menu_items
-    WHERE id = NEW.menu_item_id;
+    WHERE id = p_menu_item_id;
 
-    -- Reducir el stock del ítem en menu_items basado en la quantity de order_details
-    UPDATE menu_items
-    SET stock = stock - NEW.quantity
-    WHERE id = NEW.menu_item_id
-      AND stock >= NEW.quantity;
+    IF item_stock_mode IS NULL THEN
+        RETURN 0;
+    END IF;
 
-    -- Verificar si la actualización fue exitosa (stock suficiente)
-    IF NOT FOUND THEN
-        RAISE EXCEPTION 'Insufficient stock for item % (ID: %). Available: %, Requested: %', item_name, NEW.menu_item_id, current_stock, NEW.quantity;
+    IF item_stock_mode = 'item' THEN
+        RETURN item_stock;
     END IF;
 
-    RETURN NEW;
+    SELECT MIN(FLOOR(i.stock / r.quantity))::INTEGER INTO available
+    FROM recipe_items r
+    JOIN ingredients i ON i.id = r.ingredient_id
+    WHERE r.menu_item_id = p_menu_item_id;
+
+    -- Un ítem con receta vacía no está disponible
+    RETURN COALESCE(available, 0);
+END;
+$$ LANGUAGE plpgsql STABLE;
+
+-- Crear la función que calcula el costo unitario promedio ponderado de las compras recibidas de un
+-- ítem o de un ingrediente (NULL si nunca se ha comprado con costo)
+CREATE OR REPLACE FUNCTION weighted_purchase_cost(p_menu_item_id INTEGER, p_ingredient_id INTEGER)
+RETURNS NUMERIC AS $$
+    SELECT SUM(quantity * unit_cost) / NULLIF(SUM(quantity), 0)
+    FROM stock_movements
+    WHERE movement_type = 'purchase'
+      AND unit_cost IS NOT NULL
+      AND (menu_item_id = p_menu_item_id OR ingredient_id = p_ingredient_id);
+$$ LANGUAGE sql STABLE;
+
+-- Crear la función que calcula el costo unitario de un ítem: el costo de compra para productos
+-- embotellados o, para ítems con receta, la suma del costo de cada ingrediente por su cantidad.
+-- Devuelve NULL si algún componente no tiene costo conocido
+CREATE OR REPLACE FUNCTION menu_item_unit_cost(p_menu_item_id INTEGER)
+RETURNS NUMERIC AS $$
+DECLARE
+    item_stock_mode VARCHAR(10);
+    missing_costs   INTEGER;
+    recipe_cost     NUMERIC;
+BEGIN
+    SELECT stock_mode INTO item_stock_mode
+    FROM menu_items
+    WHERE id = p_menu_item_id;
+
+    IF item_stock_mode IS NULL THEN
+        RETURN NULL;
+    END IF;
+
+    IF item_stock_mode = 'item' THEN
+        RETURN ROUND(weighted_purchase_cost(p_menu_item_id, NULL), 2);
+    END IF;
+
+    SELECT COUNT(*) FILTER (WHERE c.cost IS NULL), SUM(r.quantity * c.cost)
+    INTO missing_costs, recipe_cost
+    FROM recipe_items r
+    CROSS JOIN LATERAL (SELECT weighted_purchase_cost(NULL, r.ingredient_id) AS cost) c
+    WHERE r.menu_item_id = p_menu_item_id;
+
+    IF missing_costs > 0 THEN
+        RETURN NULL;
+    END IF;
+    RETURN ROUND(recipe_cost, 2);
+END;
+$$ LANGUAGE plpgsql STABLE;
+
+-- Crear la función que registra en el libro los movimientos de una línea de pedido: para ítems con
+-- receta se registra un movimiento por ingrediente, para el resto uno sobre el propio ítem.
+-- p_quantity es el cambio en unidades vendidas (positivo para venta, negativo para devolución)
+CREATE OR REPLACE FUNCTION record_order_detail_movements(p_order_detail_id INTEGER, p_menu_item_id INTEGER, p_quantity INTEGER, p_movement_type VARCHAR)
+RETURNS VOID AS $$
+DECLARE
+    item_name       VARCHAR(100);
+    current_stock   INTEGER;
+    item_stock_mode VARCHAR(10);
+    recipe          RECORD;
+BEGIN
+    -- Obtener el nombre, el stock actual y el modo de inventario del ítem
+    SELECT menu_items.item_name, stock, stock_mode INTO item_name, current_stock, item_stock_mode
+    FROM menu_items
+    WHERE id = p_menu_item_id
+    FOR UPDATE;
+
+    -- Ítems con receta: un movimiento por ingrediente según la cantidad de la receta
+    IF item_stock_mode = 'recipe' THEN
+        IF p_quantity > 0 AND NOT EXISTS (SELECT 1 FROM recipe_items WHERE menu_item_id = p_menu_item_id) THEN
+            RAISE EXCEPTION 'Insufficient stock for item % (ID: %). Available: 0, Requested: %', item_name, p_menu_item_id, p_quantity;
+        END IF;
+
+        FOR recipe IN
+            SELECT r.ingredient_id, r.quantity, i.ingredient_name, i.stock
+            FROM recipe_items r
+            JOIN ingredients i ON i.id = r.ingredient_id
+            WHERE r.menu_item_id = p_menu_item_id
+        LOOP
+            IF p_quantity > 0 AND recipe.stock < recipe.quantity * p_quantity THEN
+                RAISE EXCEPTION 'Insufficient stock for item % (ID: %). Ingredient % available: %, required: %', item_name, p_menu_item_id, recipe.ingredient_name, recipe.stock, recipe.quantity * p_quantity;
+            END IF;
+
+            INSERT INTO stock_movements (ingredient_id, movement_type, quantity, order_detail_id)
+            VALUES (recipe.ingredient_id, p_movement_type, -(recipe.quantity * p_quantity), p_order_detail_id);
+        END LOOP;
+
+        RETURN;
+    END IF;
+
+    -- Verificar que haya stock suficiente para la venta
+    IF p_quantity > 0 AND current_stock < p_quantity THEN
+        RAISE EXCEPTION 'Insufficient stock for item % (ID: %). Available: %, Requested: %', item_name, p_menu_item_id, current_stock, p_quantity;
+    END IF;
+
+    INSERT INTO stock_movements (menu_item_id, movement_type, quantity, order_detail_id)
+    VALUES (p_menu_item_id, p_movement_type, -p_quantity, p_order_detail_id);
+END;
+$$ LANGUAGE plpgsql;
+
+-- Crear la función para actualizar el stock automáticamente a través del libro de movimientos
+CREATE OR REPLACE FUNCTION update_menu_item_stock()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        PERFORM record_order_detail_movements(NEW.id, NEW.menu_item_id, NEW.quantity, 'sale');
+        RETURN NEW;
+    ELSIF TG_OP = 'UPDATE' THEN
+        -- Si cambia el ítem se devuelve todo lo anterior y se vende lo nuevo; si solo cambia la cantidad se registra la diferencia
+        IF NEW.menu_item_id <> OLD.menu_item_id THEN
+            PERFORM record_order_detail_movements(OLD.id, OLD.menu_item_id, -OLD.quantity, 'return');
+            PERFORM record_order_detail_movements(NEW.id, NEW.menu_item_id, NEW.quantity, 'sale');
+        ELSIF NEW.quantity > OLD.quantity THEN
+            PERFORM record_order_detail_movements(NEW.id, NEW.menu_item_id, NEW.quantity - OLD.quantity, 'sale');
+        ELSIF NEW.quantity < OLD.quantity THEN
+            PERFORM record_order_detail_movements(NEW.id, NEW.menu_item_id, NEW.quantity - OLD.quantity, 'return');
+        END IF;
+        RETURN NEW;
+    ELSIF TG_OP = 'DELETE' THEN
+        PERFORM record_order_detail_movements(OLD.id, OLD.menu_item_id, -OLD.quantity, 'return');
+        RETURN OLD;
+    END IF;
+    RETURN NULL;
 END;
 $$ LANGUAGE plpgsql;
 
@@ -166,6 +664,62 @@ CREATE TRIGGER update_stock_after_order
     FOR EACH ROW
     EXECUTE FUNCTION update_menu_item_stock();
 
+-- Crear el trigger para devolver o descontar la diferencia al modificar una línea
+CREATE TRIGGER update_stock_after_order_update
+    AFTER UPDATE OF menu_item_id, quantity ON order_details
+    FOR EACH ROW
+    EXECUTE FUNCTION update_menu_item_stock();
+
+-- Crear el trigger para devolver el stock al eliminar una línea
+CREATE TRIGGER update_stock_after_order_delete
+    AFTER DELETE ON order_details
+    FOR EACH ROW
+    EXECUTE FUNCTION update_menu_item_stock();
+
+-- --------------------- Triggers para stock_movements -------------------------
+
+-- Crear la función que aplica cada movimiento al stock del ítem o del ingrediente
+CREATE OR REPLACE FUNCTION apply_stock_movement()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.menu_item_id IS NOT NULL THEN
+        UPDATE menu_items
+        SET stock = stock + NEW.quantity::INTEGER
+        WHERE id = NEW.menu_item_id
+          AND stock + NEW.quantity::INTEGER >= 0;
+    ELSE
+        UPDATE ingredients
+        SET stock = stock + NEW.quantity
+        WHERE id = NEW.ingredient_id
+          AND stock + NEW.quantity >= 0;
+    END IF;
+
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'Resulting stock cannot be negative (movement type: %, quantity: %)', NEW.movement_type, NEW.quantity;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER apply_stock_movement_after_insert
+    AFTER INSERT ON stock_movements
+    FOR EACH ROW
+    EXECUTE FUNCTION apply_stock_movement();
+
+-- Crear la función que impide modificar o eliminar movimientos (el libro es de solo inserción)
+CREATE OR REPLACE FUNCTION prevent_stock_movement_changes()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'stock_movements is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER prevent_stock_movement_update_delete
+    BEFORE UPDATE OR DELETE ON stock_movements
+    FOR EACH ROW
+    EXECUTE FUNCTION prevent_stock_movement_changes();
+
 -- --------------------- Triggers para customer_orders -------------------------
 
 -- Crear la función para actualizar total_amount en customer_orders
@@ -223,6 +777,33 @@ BEGIN
 END;
 $$ LANGUAGE plpgsql;
 
+-- Crear la función que asigna el mesero de un pedido nuevo: el que tiene la mesa asignada en el
+-- turno en curso o, si nadie la tiene, el empleado que abrió el pedido
+CREATE OR REPLACE FUNCTION assign_customer_order_server()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.served_by IS NULL THEN
+        SELECT wa.employee_id INTO NEW.served_by
+        FROM waiter_assignments wa
+        JOIN waiter_assignment_tables wat ON wat.assignment_id = wa.id
+        WHERE wat.table_id = NEW.table_id
+          AND wa.starts_at <= CURRENT_TIMESTAMP
+          AND wa.ends_at > CURRENT_TIMESTAMP
+        ORDER BY wa.starts_at DESC
+        LIMIT 1;
+
+        NEW.served_by := COALESCE(NEW.served_by, NEW.opened_by);
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Trigger para antes de insertar un customer_order
+CREATE TRIGGER assign_server_before_insert
+    BEFORE INSERT ON customer_orders
+    FOR EACH ROW
+    EXECUTE FUNCTION assign_customer_order_server();
+
 -- Trigger para después de insertar un order_detail
 CREATE TRIGGER update_total_amount_after_insert
     AFTER INSERT ON order_details
@@ -246,10 +827,42 @@ CREATE TRIGGER update_total_amount_after_delete
 -- =====================================================================
 
 CREATE INDEX idx_customer_orders_table_id ON customer_orders(table_id);
+CREATE INDEX idx_customer_orders_pending_table_id ON customer_orders(table_id) WHERE status = 'pending';
 CREATE INDEX idx_order_details_order_id ON order_details(order_id);
 CREATE INDEX idx_order_details_menu_item_id ON order_details(menu_item_id);
+CREATE INDEX idx_order_details_status ON order_details(status);
 CREATE INDEX idx_employee_tasks_employee_id ON employee_tasks(employee_id);
 CREATE INDEX idx_employee_tasks_status ON employee_tasks(status);
+CREATE INDEX idx_menu_item_price_history_menu_item_id ON menu_item_price_history(menu_item_id);
+CREATE INDEX idx_menu_item_scheduled_prices_due ON menu_item_scheduled_prices(status, effective_at);
+CREATE INDEX idx_menu_item_translations_locale ON menu_item_translations(locale);
+CREATE INDEX idx_recipe_items_ingredient_id ON recipe_items(ingredient_id);
+CREATE INDEX idx_stock_movements_menu_item_id ON stock_movements(menu_item_id, created_at);
+CREATE INDEX idx_stock_movements_ingredient_id ON stock_movements(ingredient_id, created_at);
+CREATE INDEX idx_stock_movements_order_detail_id ON stock_movements(order_detail_id);
+CREATE INDEX idx_reservations_reserved_at ON reservations(reserved_at) WHERE status IN ('booked', 'seated');
+CREATE INDEX idx_reservation_tables_table_id ON reservation_tables(table_id);
+CREATE INDEX idx_customer_orders_served_by ON customer_orders(served_by) WHERE status = 'pending';
+CREATE INDEX idx_waiter_assignments_employee_id ON waiter_assignments(employee_id, starts_at);
+CREATE INDEX idx_waiter_assignment_tables_table_id ON waiter_assignment_tables(table_id);
+CREATE INDEX idx_customer_orders_completed_at ON customer_orders(completed_at) WHERE status = 'completed';
+CREATE INDEX idx_waitlist_entries_waiting ON waitlist_entries(created_at) WHERE status = 'waiting';
+CREATE INDEX idx_shifts_schedule_id ON shifts(schedule_id);
+CREATE INDEX idx_shifts_employee_id ON shifts(employee_id, starts_at);
+CREATE INDEX idx_time_entries_employee_id ON time_entries(employee_id, clock_in_at);
+CREATE UNIQUE INDEX idx_time_entries_single_open ON time_entries(employee_id) WHERE clock_out_at IS NULL;
+CREATE INDEX idx_time_entry_breaks_time_entry_id ON time_entry_breaks(time_entry_id);
+CREATE INDEX idx_tip_pool_entries_collected_on ON tip_pool_entries(collected_on);
+CREATE UNIQUE INDEX idx_employees_username_lower ON employees(lower(username));
+CREATE UNIQUE INDEX idx_employees_email_lower ON employees(lower(email)) WHERE email IS NOT NULL;
+CREATE INDEX idx_employee_audit_logs_employee_id ON employee_audit_logs(employee_id, created_at);
+CREATE INDEX idx_role_permissions_permission_code ON role_permissions(permission_code);
+CREATE INDEX idx_employee_task_status_history_task_id ON employee_task_status_history(task_id, changed_at);
+CREATE INDEX idx_purchase_orders_supplier_id ON purchase_orders(supplier_id);
+CREATE INDEX idx_purchase_orders_status ON purchase_orders(status);
+CREATE INDEX idx_purchase_order_lines_purchase_order_id ON purchase_order_lines(purchase_order_id);
+CREATE UNIQUE INDEX idx_inventory_counts_single_open ON inventory_counts(status) WHERE status = 'open';
+CREATE INDEX idx_inventory_count_entries_inventory_count_id ON inventory_count_entries(inventory_count_id, menu_item_id);
 
 -- =====================================================================
 -- DATOS INICIALES PARA PRUEBAS
@@ -266,28 +879,178 @@ VALUES ('Juan Pérez', 'juan@gastrobar.com', '+1234567891', 'dueño', 'juanperez
        ('Carlos López', 'carlos@gastrobar.com', '+1234567893', 'empleado', 'carloslopez', '$2a$10$Ef6Y5TC3WGfxvLrAvnKQIuq1e7gp/JteObA.mBEfw3Mi68I4ldBNG');
 
 -- Datos para las mesas
-INSERT INTO tables (table_name)
-VALUES ('Mesa 1 - Para 4'),
-       ('Mesa 2 - Para 2'),
-       ('Mesa 3 - Para 6');
+INSERT INTO tables (table_name, capacity, zone, pos_x, pos_y)
+VALUES ('Mesa 1', 4, 'salón', 2, 2),
+       ('Mesa 2', 2, 'barra', 8, 1),
+       ('Mesa 3', 6, 'terraza', 2, 8);
 
 -- Datos para el menú (actualizados con description)
-INSERT INTO menu_items (item_name, category, price, stock, description)
-VALUES ('Cerveza artesanal', 'Bebidas', 5.50, 100, 'Cerveza artesanal de cebada y lúpulo'),
-       ('Ensalada César', 'Entradas', 8.00, 50, 'Ensalada con lechuga, pollo y aderezo César'),
-       ('Hamburguesa clásica', 'Platos fuertes', 12.00, 30, 'Hamburguesa con carne y vegetales frescos');
+-- (el stock inicial se carga como movimientos de ajuste para que el libro cuadre con el stock)
+INSERT INTO menu_items (item_name, category, price, stock, stock_mode, reorder_threshold, description)
+VALUES ('Cerveza artesanal', 'Bebidas', 5.50, 0, 'item', 24, 'Cerveza artesanal de cebada y lúpulo'),
+       ('Ensalada César', 'Entradas', 8.00, 0, 'item', 10, 'Ensalada con lechuga, pollo y aderezo César'),
+       ('Hamburguesa clásica', 'Platos fuertes', 12.00, 0, 'recipe', 5, 'Hamburguesa con carne y vegetales frescos');
+
+-- Datos para los ingredientes y la receta de la hamburguesa
+INSERT INTO ingredients (ingredient_name, unit, stock)
+VALUES ('Pan de hamburguesa', 'unit', 0),
+       ('Carne de res', 'g', 0),
+       ('Queso cheddar', 'g', 0);
+
+INSERT INTO stock_movements (menu_item_id, ingredient_id, movement_type, quantity, employee_id, reason)
+VALUES (1, NULL, 'adjustment', 100, 1, 'Saldo inicial'),
+       (2, NULL, 'adjustment', 50, 1, 'Saldo inicial'),
+       (NULL, 1, 'adjustment', 40, 1, 'Saldo inicial'),
+       (NULL, 2, 'adjustment', 6000, 1, 'Saldo inicial'),
+       (NULL, 3, 'adjustment', 1500, 1, 'Saldo inicial');
+
+INSERT INTO recipe_items (menu_item_id, ingredient_id, quantity)
+VALUES (3, 1, 1),
+       (3, 2, 150),
+       (3, 3, 30);
+
+-- Datos para los proveedores
+INSERT INTO suppliers (supplier_name, nit, contact_name, phone_number, email)
+VALUES ('Distribuidora Andina S.A.S.', '900123456-8', 'Laura Gómez', '3001234567', 'pedidos@distandina.co');
+
+-- Datos para una orden de compra ya recibida (da costo de compra a los ítems e ingredientes)
+INSERT INTO purchase_orders (supplier_id, status, notes, created_by, sent_at, received_at)
+VALUES (1, 'received', 'Pedido inicial', 1, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+
+INSERT INTO purchase_order_lines (purchase_order_id, menu_item_id, ingredient_id, quantity, expected_unit_cost, received_quantity, actual_unit_cost)
+VALUES (1, 1, NULL, 24, 2.0000, 24, 2.1000),
+       (1, 2, NULL, 10, 3.5000, 10, 3.5000),
+       (1, NULL, 1, 20, 0.4000, 20, 0.4000),
+       (1, NULL, 2, 3000, 0.0120, 3000, 0.0120),
+       (1, NULL, 3, 1000, 0.0200, 1000, 0.0200);
+
+INSERT INTO stock_movements (menu_item_id, ingredient_id, movement_type, quantity, employee_id, reason, purchase_order_line_id, unit_cost)
+VALUES (1, NULL, 'purchase', 24, 1, 'Recepción de la orden de compra #1', 1, 2.1000),
+       (2, NULL, 'purchase', 10, 1, 'Recepción de la orden de compra #1', 2, 3.5000),
+       (NULL, 1, 'purchase', 20, 1, 'Recepción de la orden de compra #1', 3, 0.4000),
+       (NULL, 2, 'purchase', 3000, 1, 'Recepción de la orden de compra #1', 4, 0.0120),
+       (NULL, 3, 'purchase', 1000, 1, 'Recepción de la orden de compra #1', 5, 0.0200);
+
+-- Datos para las etiquetas y traducciones de la carta digital
+INSERT INTO menu_item_tags (menu_item_id, tag)
+VALUES (1, 'gluten'),
+       (1, 'vegano'),
+       (2, 'lacteos'),
+       (2, 'huevo'),
+       (3, 'gluten');
+
+INSERT INTO menu_item_translations (menu_item_id, locale, item_name, description)
+VALUES (1, 'en', 'Craft beer', 'Craft beer brewed with barley and hops'),
+       (2, 'en', 'Caesar salad', 'Lettuce, chicken and Caesar dressing'),
+       (3, 'en', 'Classic burger', 'Beef burger with fresh vegetables');
 
 -- Datos para las tareas de los empleados
 INSERT INTO employee_tasks (employee_id, task_description, status)
-VALUES (1, 'Limpiar Mesa 1', 'pending'),
-       (2, 'Atender Mesa 2', 'pending'),
-       (3, 'Preparar pedido de Hamburguesa', 'pending');
+VALUES (1, 'Limpiar Mesa 1', 'pendiente'),
+       (2, 'Atender Mesa 2', 'pendiente'),
+       (3, 'Preparar pedido de Hamburguesa', 'pendiente');
+
+-- Datos para las secciones de los meseros (Carlos atiende las mesas 1 y 2 durante el turno de hoy)
+INSERT INTO waiter_assignments (employee_id, starts_at, ends_at, created_by)
+VALUES (3, date_trunc('day', CURRENT_TIMESTAMP) + INTERVAL '12 hours', date_trunc('day', CURRENT_TIMESTAMP) + INTERVAL '23 hours', 2);
+
+INSERT INTO waiter_assignment_tables (assignment_id, table_id)
+VALUES (1, 1),
+       (1, 2);
 
 -- Datos para customer_orders
-INSERT INTO customer_orders (table_id, total_amount, status)
-VALUES (1, 0.0, 'pending');
+INSERT INTO customer_orders (table_id, total_amount, status, opened_by, served_by)
+VALUES (1, 0.0, 'pending', 3, 3);
 
 -- Datos para order_details (eliminamos price_at_time)
-INSERT INTO order_details (order_id, menu_item_id, quantity)
-VALUES (1, 1, 2), -- 2 cervezas artesanales
-       (1, 2, 1); -- 1 ensalada César
\ No newline at end of file
+INSERT INTO order_details (order_id, menu_item_id, quantity, created_by)
+VALUES (1, 1, 2, 3), -- 2 cervezas artesanales
+       (1, 2, 1, 3); -- 1 ensalada César
+
+-- Datos para las reservas
+INSERT INTO reservations (guest_name, guest_phone, party_size, reserved_at, duration_minutes, created_by)
+VALUES ('Laura Gómez', '+573001234567', 5, date_trunc('day', CURRENT_TIMESTAMP) + INTERVAL '1 day 20 hours', 120, 2);
+
+INSERT INTO reservation_tables (reservation_id, table_id)
+VALUES (1, 3);
+
+-- Datos para las plantillas de turno y el horario de la semana actual
+INSERT INTO shift_templates (template_name, role, start_time, end_time)
+VALUES ('Almuerzo salón', 'mesero', '11:00', '16:00'),
+       ('Noche salón', 'mesero', '18:00', '23:30'),
+       ('Noche barra', 'barra', '18:00', '02:00'),
+       ('Cocina día', 'cocina', '10:00', '17:00');
+
+INSERT INTO schedules (week_start, status, published_at, published_by, created_by)
+VALUES (date_trunc('week', CURRENT_DATE)::DATE, 'published', CURRENT_TIMESTAMP, 2, 2);
+
+INSERT INTO shifts (schedule_id, employee_id, shift_template_id, role, starts_at, ends_at)
+VALUES (1, 3, 2, 'mesero', date_trunc('week', CURRENT_DATE) + INTERVAL '4 days 18 hours', date_trunc('week', CURRENT_DATE) + INTERVAL '4 days 23 hours 30 minutes'),
+       (1, 3, 2, 'mesero', date_trunc('week', CURRENT_DATE) + INTERVAL '5 days 18 hours', date_trunc('week', CURRENT_DATE) + INTERVAL '5 days 23 hours 30 minutes');
+
+-- Datos para las marcaciones (Carlos trabajó el turno de ayer con una pausa de 30 minutos)
+INSERT INTO time_entries (employee_id, clock_in_at, clock_out_at)
+VALUES (3, date_trunc('day', CURRENT_TIMESTAMP) - INTERVAL '1 day' + INTERVAL '17 hours 55 minutes', date_trunc('day', CURRENT_TIMESTAMP) - INTERVAL '1 day' + INTERVAL '23 hours 40 minutes');
+
+INSERT INTO time_entry_breaks (time_entry_id, started_at, ended_at)
+VALUES (1, date_trunc('day', CURRENT_TIMESTAMP) - INTERVAL '1 day' + INTERVAL '20 hours 30 minutes', date_trunc('day', CURRENT_TIMESTAMP) - INTERVAL '1 day' + INTERVAL '21 hours');
+
+-- Datos para la nómina: parámetros por defecto, tarifas, festivos y propinas de ayer
+INSERT INTO payroll_settings (id)
+VALUES (1);
+
+INSERT INTO employee_pay_rates (employee_id, hourly_rate, updated_by)
+VALUES (2, 9500.00, 1),
+       (3, 7000.00, 1);
+
+INSERT INTO holidays (holiday_date, description)
+VALUES ('2026-01-01', 'Año Nuevo'),
+       ('2026-05-01', 'Día del Trabajo'),
+       ('2026-07-20', 'Día de la Independencia'),
+       ('2026-08-07', 'Batalla de Boyacá'),
+       ('2026-12-08', 'Inmaculada Concepción'),
+       ('2026-12-25', 'Navidad');
+
+INSERT INTO tip_pool_entries (collected_on, amount, notes, created_by)
+VALUES (CURRENT_DATE - 1, 85000.00, 'Propinas del turno de la noche', 2);
+
+-- Catálogo de permisos
+INSERT INTO permissions (code, description)
+VALUES ('business.view',                'Ver los datos del negocio'),
+       ('business.edit',                'Editar los datos del negocio'),
+       ('employees.view',               'Ver los empleados'),
+       ('employees.manage',             'Crear, editar, dar de baja y reactivar empleados de rango inferior'),
+       ('employees.audit',              'Ver el historial de acciones sobre los empleados'),
+       ('employees.transfer_ownership', 'Traspasar la propiedad del negocio (solo el dueño)'),
+       ('tasks.view',                   'Ver todas las tareas'),
+       ('tasks.manage',                 'Crear, editar y eliminar tareas'),
+       ('tables.view',                  'Ver las mesas'),
+       ('tables.update',                'Editar las mesas'),
+       ('tables.manage',                'Crear, eliminar, activar y desactivar mesas y gestionar sus códigos QR'),
+       ('reservations.manage',          'Tomar y gestionar reservas'),
+       ('waitlist.manage',              'Gestionar la lista de espera'),
+       ('waiter_assignments.manage',    'Asignar secciones de mesas a los meseros'),
+       ('schedules.manage',             'Gestionar plantillas de turno, horarios y turnos'),
+       ('timeclock.manage',             'Revisar y corregir marcaciones y hojas de asistencia'),
+       ('payroll.view',                 'Ver la liquidación de la nómina, tarifas y parámetros'),
+       ('payroll.manage',               'Gestionar festivos y propinas'),
+       ('payroll.configure',            'Fijar tarifas por hora y parámetros de la nómina'),
+       ('menu.view',                    'Ver los ítems del menú con su historial de precios, traducciones y recetas'),
+       ('menu.edit',                    'Crear, editar y eliminar ítems del menú, sus precios, fotos, etiquetas, traducciones y recetas'),
+       ('menu.export',                  'Exportar el menú'),
+       ('stock.view_alerts',            'Ver los ítems con stock bajo'),
+       ('inventory.view',               'Ver insumos, movimientos de inventario y conciliación'),
+       ('inventory.edit',               'Crear, editar y eliminar insumos'),
+       ('inventory.adjust',             'Registrar mermas y ajustes de inventario'),
+       ('suppliers.manage',             'Gestionar proveedores'),
+       ('purchasing.manage',            'Gestionar órdenes de compra y recepciones'),
+       ('inventory_counts.record',      'Consultar conteos físicos y registrar lo contado'),
+       ('inventory_counts.manage',      'Abrir, revisar, aprobar y cancelar conteos físicos'),
+       ('reports.view',                 'Ver los reportes de costo y margen'),
+       ('orders.view',                  'Ver pedidos y sus líneas'),
+       ('orders.edit',                  'Agregar, editar y confirmar líneas de pedido'),
+       ('orders.void',                  'Anular líneas de pedido'),
+       ('orders.close',                 'Pedir y cerrar la cuenta de un pedido'),
+       ('permissions.manage',           'Editar los permisos de cada rol');
+
+-- Los permisos de cada rol los siembra la aplicación al iniciar (models.DefaultRolePermissions)
@@ -0,0 +1,21 @@
+-- GASTROBAR --
+
+-- =====================================================================
+-- MIGRACIÓN 000: COLUMNAS DE SESIÓN Y BAJA DE LOS EMPLEADOS
+-- =====================================================================
+-- Agrega a employees las columnas que usan el inicio de sesión, la validación de sesiones y la baja de
+-- empleados en bases creadas antes de que existieran. Debe ejecutarse antes que las demás migraciones
+
+BEGIN;
+
+-- Versión de las sesiones: se incrementa al cambiar la contraseña o el rol para invalidar las sesiones emitidas
+ALTER TABLE employees
+    ADD COLUMN IF NOT EXISTS token_version INTEGER NOT NULL DEFAULT 0;
+
+-- Baja de empleados: los dados de baja no pueden iniciar sesión; su historial se conserva
+ALTER TABLE employees
+    ADD COLUMN IF NOT EXISTS active         BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN IF NOT EXISTS deactivated_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS deactivated_by INTEGER REFERENCES employees(id);
+
+COMMIT;
@@ -0,0 +1,44 @@
+-- GASTROBAR --
+
+-- =====================================================================
+-- MIGRACIÓN 001: USUARIO Y EMAIL ÚNICOS EN EMPLEADOS
+-- =====================================================================
+-- Crea los índices únicos sin distinguir mayúsculas sobre employees.username y employees.email
+-- en bases creadas antes de que existieran. Si ya hay duplicados la migración se aborta y lista
+-- los empleados afectados, para corregirlos a mano antes de volver a ejecutarla
+
+BEGIN;
+
+-- Normalizar los espacios sobrantes y los emails vacíos, que no cuentan como duplicados
+UPDATE employees SET username = trim(username) WHERE username <> trim(username);
+UPDATE employees SET email = NULLIF(trim(email), '') WHERE email IS DISTINCT FROM NULLIF(trim(email), '');
+
+-- Detectar los duplicados existentes
+DO $$
+DECLARE
+    duplicates TEXT;
+BEGIN
+    SELECT string_agg(format('%s "%s" (empleados %s)', field, value, ids), E'\n')
+    INTO duplicates
+    FROM (
+        SELECT 'username' AS field, lower(username) AS value, string_agg(id::TEXT, ', ' ORDER BY id) AS ids
+        FROM employees
+        GROUP BY lower(username)
+        HAVING COUNT(*) > 1
+        UNION ALL
+        SELECT 'email', lower(email), string_agg(id::TEXT, ', ' ORDER BY id)
+        FROM employees
+        WHERE email IS NOT NULL
+        GROUP BY lower(email)
+        HAVING COUNT(*) > 1
+    ) d;
+
+    IF duplicates IS NOT NULL THEN
+        RAISE EXCEPTION E'Hay empleados con usuario o email duplicado:\n%', duplicates;
+    END IF;
+END $$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_employees_username_lower ON employees(lower(username));
+CREATE UNIQUE INDEX IF NOT EXISTS idx_employees_email_lower ON employees(lower(email)) WHERE email IS NOT NULL;
+
+COMMIT;
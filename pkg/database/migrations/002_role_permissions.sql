@@ -0,0 +1,71 @@
+-- GASTROBAR --
+
+-- =====================================================================
+-- MIGRACIÓN 002: PERMISOS POR ROL
+-- =====================================================================
+-- Crea el catálogo de permisos y los permisos de cada rol en bases creadas antes de que las rutas
+-- declararan permisos. Los permisos por defecto de cada rol no se conceden aquí: los siembra la
+-- aplicación al iniciar, y conservan el acceso que tenía cada rol salvo que el dueño recibe todos
+-- (antes no podía ver ítems del menú por ID ni consultar pedidos). Se puede volver a ejecutar
+
+BEGIN;
+
+-- Crear la tabla del catálogo de permisos que declaran las rutas del personal
+CREATE TABLE IF NOT EXISTS permissions (
+    code        VARCHAR(50) PRIMARY KEY, -- p. ej. 'orders.void', 'menu.edit'
+    description TEXT        NOT NULL
+);
+
+-- Crear la tabla de permisos concedidos a cada rol (la edita el dueño)
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role            employee_role NOT NULL,
+    permission_code VARCHAR(50)   NOT NULL REFERENCES permissions(code) ON DELETE CASCADE,
+    granted_by      INTEGER       REFERENCES employees(id),
+    granted_at      TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (role, permission_code)
+);
+
+CREATE INDEX IF NOT EXISTS idx_role_permissions_permission_code ON role_permissions(permission_code);
+
+-- Catálogo de permisos
+INSERT INTO permissions (code, description)
+VALUES ('business.view',             'Ver los datos del negocio'),
+       ('business.edit',             'Editar los datos del negocio'),
+       ('employees.view',            'Ver los empleados'),
+       ('employees.manage',          'Crear, editar, dar de baja y reactivar empleados'),
+       ('tasks.view',                'Ver todas las tareas'),
+       ('tasks.manage',              'Crear, editar y eliminar tareas'),
+       ('tables.view',               'Ver las mesas'),
+       ('tables.update',             'Editar las mesas'),
+       ('tables.manage',             'Crear, eliminar, activar y desactivar mesas y gestionar sus códigos QR'),
+       ('reservations.manage',       'Tomar y gestionar reservas'),
+       ('waitlist.manage',           'Gestionar la lista de espera'),
+       ('waiter_assignments.manage', 'Asignar secciones de mesas a los meseros'),
+       ('schedules.manage',          'Gestionar plantillas de turno, horarios y turnos'),
+       ('timeclock.manage',          'Revisar y corregir marcaciones y hojas de asistencia'),
+       ('payroll.view',              'Ver la liquidación de la nómina, tarifas y parámetros'),
+       ('payroll.manage',            'Gestionar festivos y propinas'),
+       ('payroll.configure',         'Fijar tarifas por hora y parámetros de la nómina'),
+       ('menu.view',                 'Ver los ítems del menú con su historial de precios, traducciones y recetas'),
+       ('menu.edit',                 'Crear, editar y eliminar ítems del menú, sus precios, fotos, etiquetas, traducciones y recetas'),
+       ('menu.export',               'Exportar el menú'),
+       ('stock.view_alerts',         'Ver los ítems con stock bajo'),
+       ('inventory.view',            'Ver insumos, movimientos de inventario y conciliación'),
+       ('inventory.edit',            'Crear, editar y eliminar insumos'),
+       ('inventory.adjust',          'Registrar mermas y ajustes de inventario'),
+       ('suppliers.manage',          'Gestionar proveedores'),
+       ('purchasing.manage',         'Gestionar órdenes de compra y recepciones'),
+       ('inventory_counts.record',   'Consultar conteos físicos y registrar lo contado'),
+       ('inventory_counts.manage',   'Abrir, revisar, aprobar y cancelar conteos físicos'),
+       ('reports.view',              'Ver los reportes de costo y margen'),
+       ('orders.view',               'Ver pedidos y sus líneas'),
+       ('orders.edit',               'Agregar, editar y confirmar líneas de pedido'),
+       ('orders.void',               'Anular líneas de pedido'),
+       ('orders.close',              'Pedir y cerrar la cuenta de un pedido'),
+       ('permissions.manage',        'Editar los permisos de cada rol')
+ON CONFLICT (code) DO NOTHING;
+
+-- Los permisos de cada rol los siembra la aplicación al iniciar si role_permissions está vacía
+-- (models.DefaultRolePermissions)
+
+COMMIT;
@@ -0,0 +1,43 @@
+-- GASTROBAR --
+
+-- =====================================================================
+-- MIGRACIÓN 003: AUDITORÍA DE EMPLEADOS Y TRASPASO DE PROPIEDAD
+-- =====================================================================
+-- Crea el registro de auditoría de las acciones sobre empleados y los permisos para consultarlo y
+-- para traspasar la propiedad del negocio. Requiere la migración 002
+
+BEGIN;
+
+-- Crear la tabla de auditoría de las acciones sobre empleados (altas, ediciones, contraseñas, bajas y traspasos)
+CREATE TABLE IF NOT EXISTS employee_audit_logs (
+    id          SERIAL PRIMARY KEY,
+    employee_id INTEGER     NOT NULL REFERENCES employees(id),
+    actor_id    INTEGER     NOT NULL REFERENCES employees(id),
+    action      VARCHAR(30) NOT NULL,
+    old_data    JSONB, -- Fila del empleado antes de la acción, sin la contraseña; NULL al crear
+    new_data    JSONB       NOT NULL,
+    created_at  TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_employee_audit_logs_employee_id ON employee_audit_logs(employee_id, created_at);
+
+INSERT INTO permissions (code, description)
+VALUES ('employees.audit',              'Ver el historial de acciones sobre los empleados'),
+       ('employees.transfer_ownership', 'Traspasar la propiedad del negocio (solo el dueño)')
+ON CONFLICT (code) DO NOTHING;
+
+UPDATE permissions
+SET description = 'Crear, editar, dar de baja y reactivar empleados de rango inferior'
+WHERE code = 'employees.manage';
+
+-- Solo se amplían permisos ya sembrados; si role_permissions está vacía, la aplicación siembra
+-- al iniciar los permisos por defecto, que ya incluyen estos
+INSERT INTO role_permissions (role, permission_code)
+SELECT grant_role::employee_role, grant_code
+FROM (VALUES ('dueño', 'employees.audit'),
+             ('dueño', 'employees.transfer_ownership'),
+             ('administrador', 'employees.audit')) AS grants(grant_role, grant_code)
+WHERE EXISTS (SELECT 1 FROM role_permissions)
+ON CONFLICT DO NOTHING;
+
+COMMIT;
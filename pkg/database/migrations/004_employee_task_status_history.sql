@@ -0,0 +1,30 @@
+-- =====================================================================
+-- MIGRACIÓN 004: ESTADOS E HISTORIAL DE LAS TAREAS
+-- =====================================================================
+-- Corrige las tareas creadas con el estado 'pending' (el valor por defecto anterior, que la aplicación
+-- no reconoce), restringe los estados válidos y crea el historial de cambios de estado
+
+BEGIN;
+
+UPDATE employee_tasks
+SET status = 'pendiente'
+WHERE status = 'pending' OR status IS NULL;
+
+ALTER TABLE employee_tasks
+    ALTER COLUMN status SET DEFAULT 'pendiente',
+    ALTER COLUMN status SET NOT NULL,
+    ADD CONSTRAINT employee_tasks_status_check CHECK (status IN ('pendiente', 'en_progreso', 'completada', 'cancelada'));
+
+-- Crear la tabla del historial de cambios de estado de las tareas
+CREATE TABLE IF NOT EXISTS employee_task_status_history (
+    id          SERIAL PRIMARY KEY,
+    task_id     INTEGER     NOT NULL REFERENCES employee_tasks(id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL,
+    to_status   VARCHAR(20) NOT NULL,
+    changed_by  INTEGER     NOT NULL REFERENCES employees(id),
+    changed_at  TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_employee_task_status_history_task_id ON employee_task_status_history(task_id, changed_at);
+
+COMMIT;
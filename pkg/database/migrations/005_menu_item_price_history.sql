@@ -0,0 +1,37 @@
+-- GASTROBAR --
+
+-- =====================================================================
+-- MIGRACIÓN 005: HISTORIAL DE PRECIOS Y CAMBIOS DE PRECIO PROGRAMADOS
+-- =====================================================================
+-- Crea el historial de precios de los ítems del menú y los cambios de precio programados en bases
+-- creadas antes de que existieran
+
+BEGIN;
+
+-- Crear la tabla para el historial de precios de los ítems del menú
+CREATE TABLE IF NOT EXISTS menu_item_price_history (
+    id                        SERIAL PRIMARY KEY,
+    menu_item_id              INTEGER        NOT NULL REFERENCES menu_items(id) ON DELETE CASCADE,
+    old_price                 NUMERIC(10, 2) NOT NULL,
+    new_price                 NUMERIC(10, 2) NOT NULL,
+    changed_by                INTEGER        NOT NULL REFERENCES employees(id),
+    scheduled_price_change_id INTEGER,
+    changed_at                TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Crear la tabla para los cambios de precio programados
+CREATE TABLE IF NOT EXISTS menu_item_scheduled_prices (
+    id           SERIAL PRIMARY KEY,
+    menu_item_id INTEGER        NOT NULL REFERENCES menu_items(id) ON DELETE CASCADE,
+    new_price    NUMERIC(10, 2) NOT NULL CHECK (new_price > 0),
+    effective_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    status       VARCHAR(20)    NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'applied', 'cancelled')),
+    created_by   INTEGER        NOT NULL REFERENCES employees(id),
+    applied_at   TIMESTAMP WITH TIME ZONE,
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_menu_item_price_history_menu_item_id ON menu_item_price_history(menu_item_id);
+CREATE INDEX IF NOT EXISTS idx_menu_item_scheduled_prices_due ON menu_item_scheduled_prices(status, effective_at);
+
+COMMIT;
@@ -0,0 +1,40 @@
+-- GASTROBAR --
+
+-- =====================================================================
+-- MIGRACIÓN 006: CARTA DIGITAL
+-- =====================================================================
+-- Crea las fotos, las etiquetas de alérgenos y dietas y las traducciones de los ítems del menú que
+-- usa la carta digital pública
+
+BEGIN;
+
+-- Crear la tabla para las fotos de los ítems del menú (una por ítem)
+CREATE TABLE IF NOT EXISTS menu_item_images (
+    id             SERIAL PRIMARY KEY,
+    menu_item_id   INTEGER      NOT NULL UNIQUE REFERENCES menu_items(id) ON DELETE CASCADE,
+    image_path     VARCHAR(255) NOT NULL,
+    thumbnail_path VARCHAR(255) NOT NULL,
+    created_at     TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Crear la tabla para las etiquetas de alérgenos y dietas de los ítems del menú
+CREATE TABLE IF NOT EXISTS menu_item_tags (
+    menu_item_id INTEGER     NOT NULL REFERENCES menu_items(id) ON DELETE CASCADE,
+    tag          VARCHAR(30) NOT NULL,
+    PRIMARY KEY (menu_item_id, tag)
+);
+
+-- Crear la tabla para las traducciones de los ítems del menú (el idioma base es 'es')
+CREATE TABLE IF NOT EXISTS menu_item_translations (
+    id           SERIAL PRIMARY KEY,
+    menu_item_id INTEGER      NOT NULL REFERENCES menu_items(id) ON DELETE CASCADE,
+    locale       VARCHAR(15)  NOT NULL,
+    item_name    VARCHAR(100) NOT NULL,
+    description  TEXT,
+    updated_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (menu_item_id, locale)
+);
+
+CREATE INDEX IF NOT EXISTS idx_menu_item_translations_locale ON menu_item_translations(locale);
+
+COMMIT;
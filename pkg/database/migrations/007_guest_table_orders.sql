@@ -0,0 +1,24 @@
+-- GASTROBAR --
+
+-- =====================================================================
+-- MIGRACIÓN 007: PEDIDOS DE CLIENTES POR QR
+-- =====================================================================
+-- Agrega la confirmación opcional de los pedidos de clientes, la versión del QR de cada mesa (al
+-- regenerarlo se invalidan los anteriores) y el estado y origen de las líneas de pedido. Las líneas
+-- existentes quedan confirmadas y con origen 'staff'
+
+BEGIN;
+
+ALTER TABLE business
+    ADD COLUMN IF NOT EXISTS guest_orders_require_confirmation BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE tables
+    ADD COLUMN IF NOT EXISTS qr_version INTEGER NOT NULL DEFAULT 1;
+
+ALTER TABLE order_details
+    ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'confirmed' CHECK (status IN ('pending_confirmation', 'confirmed')),
+    ADD COLUMN IF NOT EXISTS source VARCHAR(10) NOT NULL DEFAULT 'staff' CHECK (source IN ('staff', 'guest'));
+
+CREATE INDEX IF NOT EXISTS idx_order_details_status ON order_details(status);
+
+COMMIT;
@@ -0,0 +1,119 @@
+-- GASTROBAR --
+
+-- =====================================================================
+-- MIGRACIÓN 008: INGREDIENTES Y RECETAS
+-- =====================================================================
+-- Agrega el inventario a nivel de ingrediente y las recetas de los ítems del menú. Los ítems existentes
+-- quedan con stock_mode 'item' (descuentan su propio stock); la venta de un ítem con receta descuenta
+-- sus ingredientes
+
+BEGIN;
+
+ALTER TABLE menu_items
+    ADD COLUMN IF NOT EXISTS stock_mode VARCHAR(10) NOT NULL DEFAULT 'item' CHECK (stock_mode IN ('item', 'recipe'));
+
+-- Crear la tabla de ingredientes (inventario a nivel de insumo)
+CREATE TABLE IF NOT EXISTS ingredients (
+    id              SERIAL PRIMARY KEY,
+    ingredient_name VARCHAR(100)   NOT NULL UNIQUE,
+    unit            VARCHAR(10)    NOT NULL CHECK (unit IN ('g', 'ml', 'unit')),
+    stock           NUMERIC(12, 3) NOT NULL DEFAULT 0 CHECK (stock >= 0),
+    created_at      TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Crear la tabla de recetas (cantidad de cada ingrediente por unidad del ítem)
+CREATE TABLE IF NOT EXISTS recipe_items (
+    menu_item_id  INTEGER        NOT NULL REFERENCES menu_items(id) ON DELETE CASCADE,
+    ingredient_id INTEGER        NOT NULL REFERENCES ingredients(id) ON DELETE RESTRICT,
+    quantity      NUMERIC(12, 3) NOT NULL CHECK (quantity > 0),
+    PRIMARY KEY (menu_item_id, ingredient_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_recipe_items_ingredient_id ON recipe_items(ingredient_id);
+
+-- Crear la función que calcula cuántas unidades de un ítem se pueden vender: el stock propio
+-- para productos embotellados o, para ítems con receta, el mínimo que permiten sus ingredientes
+CREATE OR REPLACE FUNCTION menu_item_available_quantity(p_menu_item_id INTEGER)
+RETURNS INTEGER AS $$
+DECLARE
+    item_stock_mode VARCHAR(10);
+    item_stock      INTEGER;
+    available       INTEGER;
+BEGIN
+    SELECT stock_mode, stock INTO item_stock_mode, item_stock
+    FROM menu_items
+    WHERE id = p_menu_item_id;
+
+    IF item_stock_mode IS NULL THEN
+        RETURN 0;
+    END IF;
+
+    IF item_stock_mode = 'item' THEN
+        RETURN item_stock;
+    END IF;
+
+    SELECT MIN(FLOOR(i.stock / r.quantity))::INTEGER INTO available
+    FROM recipe_items r
+    JOIN ingredients i ON i.id = r.ingredient_id
+    WHERE r.menu_item_id = p_menu_item_id;
+
+    -- Un ítem con receta vacía no está disponible
+    RETURN COALESCE(available, 0);
+END;
+$$ LANGUAGE plpgsql STABLE;
+
+-- Crear la función para actualizar el stock automáticamente
+CREATE OR REPLACE FUNCTION update_menu_item_stock()
+RETURNS TRIGGER AS $$
+DECLARE
+    item_name       VARCHAR(100);
+    current_stock   INTEGER;
+    item_stock_mode VARCHAR(10);
+    recipe          RECORD;
+BEGIN
+    -- Obtener el nombre, el stock actual y el modo de inventario del ítem
+    SELECT menu_items.item_name, stock, stock_mode INTO item_name, current_stock, item_stock_mode
+    FROM menu_items
+    WHERE id = NEW.menu_item_id;
+
+    -- Ítems con receta: descontar cada ingrediente según la cantidad de la receta
+    IF item_stock_mode = 'recipe' THEN
+        IF NOT EXISTS (SELECT 1 FROM recipe_items WHERE menu_item_id = NEW.menu_item_id) THEN
+            RAISE EXCEPTION 'Insufficient stock for item % (ID: %). Available: 0, Requested: %', item_name, NEW.menu_item_id, NEW.quantity;
+        END IF;
+
+        FOR recipe IN
+            SELECT r.ingredient_id, r.quantity, i.ingredient_name, i.stock
+            FROM recipe_items r
+            JOIN ingredients i ON i.id = r.ingredient_id
+            WHERE r.menu_item_id = NEW.menu_item_id
+        LOOP
+            UPDATE ingredients
+            SET stock = stock - recipe.quantity * NEW.quantity
+            WHERE id = recipe.ingredient_id
+              AND stock >= recipe.quantity * NEW.quantity;
+
+            IF NOT FOUND THEN
+                RAISE EXCEPTION 'Insufficient stock for item % (ID: %). Ingredient % available: %, required: %', item_name, NEW.menu_item_id, recipe.ingredient_name, recipe.stock, recipe.quantity * NEW.quantity;
+            END IF;
+        END LOOP;
+
+        RETURN NEW;
+    END IF;
+
+    -- Reducir el stock del ítem en menu_items basado en la quantity de order_details
+    UPDATE menu_items
+    SET stock = stock - NEW.quantity
+    WHERE id = NEW.menu_item_id
+      AND stock >= NEW.quantity;
+
+    -- Verificar si la actualización fue exitosa (stock suficiente)
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'Insufficient stock for item % (ID: %). Available: %, Requested: %', item_name, NEW.menu_item_id, current_stock, NEW.quantity;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMIT;
@@ -0,0 +1,183 @@
+-- GASTROBAR --
+
+-- =====================================================================
+-- MIGRACIÓN 009: LIBRO DE MOVIMIENTOS DE INVENTARIO
+-- =====================================================================
+-- Crea el libro de movimientos de inventario (solo inserciones) y hace que las ventas, devoluciones y
+-- ediciones de líneas de pedido pasen por él. El stock actual de cada ítem e ingrediente se registra
+-- como saldo inicial antes de crear el trigger que aplica los movimientos, para que el libro cuadre
+-- con el stock. Se puede volver a ejecutar
+
+BEGIN;
+
+-- Crear la tabla del libro de movimientos de inventario (solo inserciones; el stock se deriva de ella)
+-- quantity es el cambio con signo: negativo para salidas (venta, merma) y positivo para entradas
+CREATE TABLE IF NOT EXISTS stock_movements (
+    id              SERIAL PRIMARY KEY,
+    menu_item_id    INTEGER        REFERENCES menu_items(id),
+    ingredient_id   INTEGER        REFERENCES ingredients(id),
+    movement_type   VARCHAR(20)    NOT NULL CHECK (movement_type IN ('sale', 'return', 'purchase', 'waste', 'adjustment', 'count_correction')),
+    quantity        NUMERIC(12, 3) NOT NULL CHECK (quantity <> 0),
+    employee_id     INTEGER        REFERENCES employees(id),
+    reason          TEXT,
+    order_detail_id INTEGER, -- Sin FK: la línea puede eliminarse pero el movimiento se conserva
+    created_at      TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((menu_item_id IS NULL) <> (ingredient_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_stock_movements_menu_item_id ON stock_movements(menu_item_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_stock_movements_ingredient_id ON stock_movements(ingredient_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_stock_movements_order_detail_id ON stock_movements(order_detail_id);
+
+-- Registrar el stock actual como saldo inicial; solo la primera vez, antes de que exista el trigger
+-- que aplica los movimientos
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_trigger WHERE tgname = 'apply_stock_movement_after_insert') THEN
+        INSERT INTO stock_movements (menu_item_id, movement_type, quantity, reason)
+        SELECT id, 'adjustment', stock, 'Saldo inicial'
+        FROM menu_items
+        WHERE stock <> 0;
+
+        INSERT INTO stock_movements (ingredient_id, movement_type, quantity, reason)
+        SELECT id, 'adjustment', stock, 'Saldo inicial'
+        FROM ingredients
+        WHERE stock <> 0;
+    END IF;
+END $$;
+
+-- Crear la función que registra en el libro los movimientos de una línea de pedido: para ítems con
+-- receta se registra un movimiento por ingrediente, para el resto uno sobre el propio ítem.
+-- p_quantity es el cambio en unidades vendidas (positivo para venta, negativo para devolución)
+CREATE OR REPLACE FUNCTION record_order_detail_movements(p_order_detail_id INTEGER, p_menu_item_id INTEGER, p_quantity INTEGER, p_movement_type VARCHAR)
+RETURNS VOID AS $$
+DECLARE
+    item_name       VARCHAR(100);
+    current_stock   INTEGER;
+    item_stock_mode VARCHAR(10);
+    recipe          RECORD;
+BEGIN
+    -- Obtener el nombre, el stock actual y el modo de inventario del ítem
+    SELECT menu_items.item_name, stock, stock_mode INTO item_name, current_stock, item_stock_mode
+    FROM menu_items
+    WHERE id = p_menu_item_id
+    FOR UPDATE;
+
+    -- Ítems con receta: un movimiento por ingrediente según la cantidad de la receta
+    IF item_stock_mode = 'recipe' THEN
+        IF p_quantity > 0 AND NOT EXISTS (SELECT 1 FROM recipe_items WHERE menu_item_id = p_menu_item_id) THEN
+            RAISE EXCEPTION 'Insufficient stock for item % (ID: %). Available: 0, Requested: %', item_name, p_menu_item_id, p_quantity;
+        END IF;
+
+        FOR recipe IN
+            SELECT r.ingredient_id, r.quantity, i.ingredient_name, i.stock
+            FROM recipe_items r
+            JOIN ingredients i ON i.id = r.ingredient_id
+            WHERE r.menu_item_id = p_menu_item_id
+        LOOP
+            IF p_quantity > 0 AND recipe.stock < recipe.quantity * p_quantity THEN
+                RAISE EXCEPTION 'Insufficient stock for item % (ID: %). Ingredient % available: %, required: %', item_name, p_menu_item_id, recipe.ingredient_name, recipe.stock, recipe.quantity * p_quantity;
+            END IF;
+
+            INSERT INTO stock_movements (ingredient_id, movement_type, quantity, order_detail_id)
+            VALUES (recipe.ingredient_id, p_movement_type, -(recipe.quantity * p_quantity), p_order_detail_id);
+        END LOOP;
+
+        RETURN;
+    END IF;
+
+    -- Verificar que haya stock suficiente para la venta
+    IF p_quantity > 0 AND current_stock < p_quantity THEN
+        RAISE EXCEPTION 'Insufficient stock for item % (ID: %). Available: %, Requested: %', item_name, p_menu_item_id, current_stock, p_quantity;
+    END IF;
+
+    INSERT INTO stock_movements (menu_item_id, movement_type, quantity, order_detail_id)
+    VALUES (p_menu_item_id, p_movement_type, -p_quantity, p_order_detail_id);
+END;
+$$ LANGUAGE plpgsql;
+
+-- Crear la función para actualizar el stock automáticamente a través del libro de movimientos
+CREATE OR REPLACE FUNCTION update_menu_item_stock()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        PERFORM record_order_detail_movements(NEW.id, NEW.menu_item_id, NEW.quantity, 'sale');
+        RETURN NEW;
+    ELSIF TG_OP = 'UPDATE' THEN
+        -- Si cambia el ítem se devuelve todo lo anterior y se vende lo nuevo; si solo cambia la cantidad se registra la diferencia
+        IF NEW.menu_item_id <> OLD.menu_item_id THEN
+            PERFORM record_order_detail_movements(OLD.id, OLD.menu_item_id, -OLD.quantity, 'return');
+            PERFORM record_order_detail_movements(NEW.id, NEW.menu_item_id, NEW.quantity, 'sale');
+        ELSIF NEW.quantity > OLD.quantity THEN
+            PERFORM record_order_detail_movements(NEW.id, NEW.menu_item_id, NEW.quantity - OLD.quantity, 'sale');
+        ELSIF NEW.quantity < OLD.quantity THEN
+            PERFORM record_order_detail_movements(NEW.id, NEW.menu_item_id, NEW.quantity - OLD.quantity, 'return');
+        END IF;
+        RETURN NEW;
+    ELSIF TG_OP = 'DELETE' THEN
+        PERFORM record_order_detail_movements(OLD.id, OLD.menu_item_id, -OLD.quantity, 'return');
+        RETURN OLD;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Crear el trigger para devolver o descontar la diferencia al modificar una línea
+DROP TRIGGER IF EXISTS update_stock_after_order_update ON order_details;
+CREATE TRIGGER update_stock_after_order_update
+    AFTER UPDATE OF menu_item_id, quantity ON order_details
+    FOR EACH ROW
+    EXECUTE FUNCTION update_menu_item_stock();
+
+-- Crear el trigger para devolver el stock al eliminar una línea
+DROP TRIGGER IF EXISTS update_stock_after_order_delete ON order_details;
+CREATE TRIGGER update_stock_after_order_delete
+    AFTER DELETE ON order_details
+    FOR EACH ROW
+    EXECUTE FUNCTION update_menu_item_stock();
+
+-- Crear la función que aplica cada movimiento al stock del ítem o del ingrediente
+CREATE OR REPLACE FUNCTION apply_stock_movement()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.menu_item_id IS NOT NULL THEN
+        UPDATE menu_items
+        SET stock = stock + NEW.quantity::INTEGER
+        WHERE id = NEW.menu_item_id
+          AND stock + NEW.quantity::INTEGER >= 0;
+    ELSE
+        UPDATE ingredients
+        SET stock = stock + NEW.quantity
+        WHERE id = NEW.ingredient_id
+          AND stock + NEW.quantity >= 0;
+    END IF;
+
+    IF NOT FOUND THEN
+        RAISE EXCEPTION 'Resulting stock cannot be negative (movement type: %, quantity: %)', NEW.movement_type, NEW.quantity;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS apply_stock_movement_after_insert ON stock_movements;
+CREATE TRIGGER apply_stock_movement_after_insert
+    AFTER INSERT ON stock_movements
+    FOR EACH ROW
+    EXECUTE FUNCTION apply_stock_movement();
+
+-- Crear la función que impide modificar o eliminar movimientos (el libro es de solo inserción)
+CREATE OR REPLACE FUNCTION prevent_stock_movement_changes()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'stock_movements is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS prevent_stock_movement_update_delete ON stock_movements;
+CREATE TRIGGER prevent_stock_movement_update_delete
+    BEFORE UPDATE OR DELETE ON stock_movements
+    FOR EACH ROW
+    EXECUTE FUNCTION prevent_stock_movement_changes();
+
+COMMIT;
@@ -0,0 +1,14 @@
+-- GASTROBAR --
+
+-- =====================================================================
+-- MIGRACIÓN 010: UMBRALES DE REPOSICIÓN
+-- =====================================================================
+-- Agrega a los ítems del menú el umbral por debajo del cual se alerta de stock bajo. Los ítems
+-- existentes quedan en 0 (sin alerta)
+
+BEGIN;
+
+ALTER TABLE menu_items
+    ADD COLUMN IF NOT EXISTS reorder_threshold INTEGER NOT NULL DEFAULT 0 CHECK (reorder_threshold >= 0); -- 0 = sin alerta de stock bajo
+
+COMMIT;
@@ -0,0 +1,55 @@
+-- GASTROBAR --
+
+-- =====================================================================
+-- MIGRACIÓN 011: PROVEEDORES Y ÓRDENES DE COMPRA
+-- =====================================================================
+-- Crea los proveedores y las órdenes de compra, y agrega al libro de movimientos la línea de compra y
+-- el costo unitario de cada recepción. Requiere la migración 009
+
+BEGIN;
+
+-- Crear la tabla de proveedores
+CREATE TABLE IF NOT EXISTS suppliers (
+    id            SERIAL PRIMARY KEY,
+    supplier_name VARCHAR(100) NOT NULL,
+    nit           VARCHAR(20)  NOT NULL UNIQUE,
+    contact_name  VARCHAR(100),
+    phone_number  VARCHAR(20),
+    email         VARCHAR(100),
+    created_at    TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Crear la tabla de órdenes de compra
+CREATE TABLE IF NOT EXISTS purchase_orders (
+    id          SERIAL PRIMARY KEY,
+    supplier_id INTEGER     NOT NULL REFERENCES suppliers(id),
+    status      VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'sent', 'partially_received', 'received')),
+    notes       TEXT,
+    created_by  INTEGER     NOT NULL REFERENCES employees(id),
+    sent_at     TIMESTAMP WITH TIME ZONE,
+    received_at TIMESTAMP WITH TIME ZONE,
+    created_at  TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Crear la tabla de líneas de las órdenes de compra (un ítem embotellado o un ingrediente por línea)
+CREATE TABLE IF NOT EXISTS purchase_order_lines (
+    id                 SERIAL PRIMARY KEY,
+    purchase_order_id  INTEGER        NOT NULL REFERENCES purchase_orders(id) ON DELETE CASCADE,
+    menu_item_id       INTEGER        REFERENCES menu_items(id),
+    ingredient_id      INTEGER        REFERENCES ingredients(id),
+    quantity           NUMERIC(12, 3) NOT NULL CHECK (quantity > 0),
+    expected_unit_cost NUMERIC(12, 2) NOT NULL CHECK (expected_unit_cost >= 0),
+    received_quantity  NUMERIC(12, 3) NOT NULL DEFAULT 0 CHECK (received_quantity >= 0),
+    actual_unit_cost   NUMERIC(12, 2) CHECK (actual_unit_cost >= 0), -- Costo real de la última recepción
+    CHECK ((menu_item_id IS NULL) <> (ingredient_id IS NULL))
+);
+
+ALTER TABLE stock_movements
+    ADD COLUMN IF NOT EXISTS purchase_order_line_id INTEGER REFERENCES purchase_order_lines(id),
+    ADD COLUMN IF NOT EXISTS unit_cost              NUMERIC(12, 2); -- Costo unitario real (solo compras)
+
+CREATE INDEX IF NOT EXISTS idx_purchase_orders_supplier_id ON purchase_orders(supplier_id);
+CREATE INDEX IF NOT EXISTS idx_purchase_orders_status ON purchase_orders(status);
+CREATE INDEX IF NOT EXISTS idx_purchase_order_lines_purchase_order_id ON purchase_order_lines(purchase_order_id);
+
+COMMIT;
@@ -0,0 +1,53 @@
+-- GASTROBAR --
+
+-- =====================================================================
+-- MIGRACIÓN 012: CONTEOS FÍSICOS DE INVENTARIO
+-- =====================================================================
+-- Crea las sesiones de conteo físico, las cantidades contadas por cada empleado y el resultado de los
+-- conteos aprobados, y enlaza las correcciones del libro de movimientos con su conteo. Requiere la
+-- migración 009
+
+BEGIN;
+
+-- Crear la tabla de sesiones de conteo físico de inventario
+CREATE TABLE IF NOT EXISTS inventory_counts (
+    id          SERIAL PRIMARY KEY,
+    status      VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'approved', 'cancelled')),
+    notes       TEXT,
+    opened_by   INTEGER     NOT NULL REFERENCES employees(id),
+    opened_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    approved_by INTEGER     REFERENCES employees(id),
+    approved_at TIMESTAMP WITH TIME ZONE,
+    closed_at   TIMESTAMP WITH TIME ZONE -- Fecha de aprobación o cancelación
+);
+
+-- Crear la tabla de cantidades contadas; cada empleado registra lo que contó de un ítem (por ejemplo
+-- la barra y la bodega) y el total contado es la suma de sus registros
+CREATE TABLE IF NOT EXISTS inventory_count_entries (
+    id                 SERIAL PRIMARY KEY,
+    inventory_count_id INTEGER NOT NULL REFERENCES inventory_counts(id) ON DELETE CASCADE,
+    menu_item_id       INTEGER NOT NULL REFERENCES menu_items(id),
+    employee_id        INTEGER NOT NULL REFERENCES employees(id),
+    counted_quantity   INTEGER NOT NULL CHECK (counted_quantity >= 0),
+    updated_at         TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (inventory_count_id, menu_item_id, employee_id)
+);
+
+-- Crear la tabla con el resultado de cada conteo aprobado (se conserva como reporte de faltantes)
+CREATE TABLE IF NOT EXISTS inventory_count_lines (
+    inventory_count_id INTEGER NOT NULL REFERENCES inventory_counts(id),
+    menu_item_id       INTEGER NOT NULL REFERENCES menu_items(id),
+    system_quantity    INTEGER NOT NULL,
+    counted_quantity   INTEGER NOT NULL,
+    variance           INTEGER NOT NULL, -- Contado - sistema: negativo es faltante
+    unit_cost          NUMERIC(12, 2), -- Último costo de compra conocido al aprobar
+    PRIMARY KEY (inventory_count_id, menu_item_id)
+);
+
+ALTER TABLE stock_movements
+    ADD COLUMN IF NOT EXISTS inventory_count_id INTEGER REFERENCES inventory_counts(id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_inventory_counts_single_open ON inventory_counts(status) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_inventory_count_entries_inventory_count_id ON inventory_count_entries(inventory_count_id, menu_item_id);
+
+COMMIT;
@@ -0,0 +1,68 @@
+-- GASTROBAR --
+
+-- =====================================================================
+-- MIGRACIÓN 013: COSTO UNITARIO Y MARGEN DE LOS ÍTEMS
+-- =====================================================================
+-- Amplía a cuatro decimales los costos unitarios de compra (los ingredientes por gramo o mililitro
+-- cuestan fracciones de centavo) y crea las funciones que calculan el costo unitario de cada ítem.
+-- Requiere las migraciones 011 y 012
+
+BEGIN;
+
+ALTER TABLE purchase_order_lines
+    ALTER COLUMN expected_unit_cost TYPE NUMERIC(12, 4),
+    ALTER COLUMN actual_unit_cost   TYPE NUMERIC(12, 4);
+
+ALTER TABLE inventory_count_lines
+    ALTER COLUMN unit_cost TYPE NUMERIC(12, 4);
+
+ALTER TABLE stock_movements
+    ALTER COLUMN unit_cost TYPE NUMERIC(12, 4);
+
+-- Crear la función que calcula el costo unitario promedio ponderado de las compras recibidas de un
+-- ítem o de un ingrediente (NULL si nunca se ha comprado con costo)
+CREATE OR REPLACE FUNCTION weighted_purchase_cost(p_menu_item_id INTEGER, p_ingredient_id INTEGER)
+RETURNS NUMERIC AS $$
+    SELECT SUM(quantity * unit_cost) / NULLIF(SUM(quantity), 0)
+    FROM stock_movements
+    WHERE movement_type = 'purchase'
+      AND unit_cost IS NOT NULL
+      AND (menu_item_id = p_menu_item_id OR ingredient_id = p_ingredient_id);
+$$ LANGUAGE sql STABLE;
+
+-- Crear la función que calcula el costo unitario de un ítem: el costo de compra para productos
+-- embotellados o, para ítems con receta, la suma del costo de cada ingrediente por su cantidad.
+-- Devuelve NULL si algún componente no tiene costo conocido
+CREATE OR REPLACE FUNCTION menu_item_unit_cost(p_menu_item_id INTEGER)
+RETURNS NUMERIC AS $$
+DECLARE
+    item_stock_mode VARCHAR(10);
+    missing_costs   INTEGER;
+    recipe_cost     NUMERIC;
+BEGIN
+    SELECT stock_mode INTO item_stock_mode
+    FROM menu_items
+    WHERE id = p_menu_item_id;
+
+    IF item_stock_mode IS NULL THEN
+        RETURN NULL;
+    END IF;
+
+    IF item_stock_mode = 'item' THEN
+        RETURN ROUND(weighted_purchase_cost(p_menu_item_id, NULL), 2);
+    END IF;
+
+    SELECT COUNT(*) FILTER (WHERE c.cost IS NULL), SUM(r.quantity * c.cost)
+    INTO missing_costs, recipe_cost
+    FROM recipe_items r
+    CROSS JOIN LATERAL (SELECT weighted_purchase_cost(NULL, r.ingredient_id) AS cost) c
+    WHERE r.menu_item_id = p_menu_item_id;
+
+    IF missing_costs > 0 THEN
+        RETURN NULL;
+    END IF;
+    RETURN ROUND(recipe_cost, 2);
+END;
+$$ LANGUAGE plpgsql STABLE;
+
+COMMIT;
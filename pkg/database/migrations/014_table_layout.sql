@@ -0,0 +1,23 @@
+-- GASTROBAR --
+
+-- =====================================================================
+-- MIGRACIÓN 014: CAPACIDAD, ZONA Y POSICIÓN DE LAS MESAS
+-- =====================================================================
+-- Agrega a las mesas la capacidad, la zona y la posición en el plano del local, y a los pedidos la
+-- fecha en que se pidió la cuenta (estado en vivo de cada mesa). Las mesas existentes quedan para 2
+-- personas en el salón
+
+BEGIN;
+
+ALTER TABLE tables
+    ADD COLUMN IF NOT EXISTS capacity INTEGER     NOT NULL DEFAULT 2 CHECK (capacity > 0),
+    ADD COLUMN IF NOT EXISTS zone     VARCHAR(20) NOT NULL DEFAULT 'salón' CHECK (zone IN ('terraza', 'barra', 'salón')),
+    ADD COLUMN IF NOT EXISTS pos_x    INTEGER     NOT NULL DEFAULT 0, -- Posición en el plano del local (unidades de la grilla de la UI)
+    ADD COLUMN IF NOT EXISTS pos_y    INTEGER     NOT NULL DEFAULT 0;
+
+ALTER TABLE customer_orders
+    ADD COLUMN IF NOT EXISTS bill_requested_at TIMESTAMP WITH TIME ZONE; -- Se pidió la cuenta: la mesa queda esperando el pago
+
+CREATE INDEX IF NOT EXISTS idx_customer_orders_pending_table_id ON customer_orders(table_id) WHERE status = 'pending';
+
+COMMIT;
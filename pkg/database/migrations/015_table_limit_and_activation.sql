@@ -0,0 +1,17 @@
+-- GASTROBAR --
+
+-- =====================================================================
+-- MIGRACIÓN 015: LÍMITE DE MESAS Y MESAS INACTIVAS
+-- =====================================================================
+-- Reemplaza el límite fijo de 4 mesas por un ajuste del negocio (NULL = sin límite, como queda en las
+-- bases existentes) y permite desactivar mesas. Las mesas existentes quedan activas
+
+BEGIN;
+
+ALTER TABLE business
+    ADD COLUMN IF NOT EXISTS max_tables INTEGER CHECK (max_tables > 0); -- Límite de mesas activas; NULL = sin límite
+
+ALTER TABLE tables
+    ADD COLUMN IF NOT EXISTS active BOOLEAN NOT NULL DEFAULT TRUE; -- Las mesas inactivas no aceptan pedidos ni cuentan para el límite
+
+COMMIT;
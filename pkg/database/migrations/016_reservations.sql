@@ -0,0 +1,37 @@
+-- GASTROBAR --
+
+-- =====================================================================
+-- MIGRACIÓN 016: RESERVAS
+-- =====================================================================
+-- Crea las reservas y las mesas asignadas a cada una
+
+BEGIN;
+
+-- Crear la tabla de reservas (tomadas por teléfono o WhatsApp); ocupan sus mesas desde reserved_at
+-- durante duration_minutes. Al sentar a los clientes se abre un pedido pendiente en la mesa
+CREATE TABLE IF NOT EXISTS reservations (
+    id                SERIAL PRIMARY KEY,
+    guest_name        VARCHAR(100) NOT NULL,
+    guest_phone       VARCHAR(20)  NOT NULL,
+    party_size        INTEGER      NOT NULL CHECK (party_size > 0),
+    reserved_at       TIMESTAMP WITH TIME ZONE NOT NULL,
+    duration_minutes  INTEGER      NOT NULL DEFAULT 90 CHECK (duration_minutes > 0),
+    status            VARCHAR(20)  NOT NULL DEFAULT 'booked' CHECK (status IN ('booked', 'seated', 'no_show', 'cancelled')),
+    notes             TEXT,
+    customer_order_id INTEGER      REFERENCES customer_orders(id) ON DELETE SET NULL, -- Pedido abierto al sentar
+    created_by        INTEGER      NOT NULL REFERENCES employees(id),
+    seated_at         TIMESTAMP WITH TIME ZONE,
+    created_at        TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Crear la tabla de mesas asignadas a cada reserva (un grupo grande puede ocupar varias mesas)
+CREATE TABLE IF NOT EXISTS reservation_tables (
+    reservation_id INTEGER NOT NULL REFERENCES reservations(id) ON DELETE CASCADE,
+    table_id       INTEGER NOT NULL REFERENCES tables(id),
+    PRIMARY KEY (reservation_id, table_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reservations_reserved_at ON reservations(reserved_at) WHERE status IN ('booked', 'seated');
+CREATE INDEX IF NOT EXISTS idx_reservation_tables_table_id ON reservation_tables(table_id);
+
+COMMIT;
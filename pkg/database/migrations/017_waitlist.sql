@@ -0,0 +1,35 @@
+-- GASTROBAR --
+
+-- =====================================================================
+-- MIGRACIÓN 017: LISTA DE ESPERA
+-- =====================================================================
+-- Crea la lista de espera para clientes sin reserva y agrega a los pedidos la fecha de cierre de la
+-- cuenta, con la que se estima la espera a partir del tiempo promedio de mesa. Los pedidos ya
+-- completados quedan sin fecha de cierre y no cuentan para el promedio
+
+BEGIN;
+
+ALTER TABLE customer_orders
+    ADD COLUMN IF NOT EXISTS completed_at TIMESTAMP WITH TIME ZONE; -- Cierre de la cuenta; con created_at da el tiempo de mesa
+
+-- Crear la tabla de la lista de espera para clientes sin reserva; al sentar al grupo se le asigna
+-- una mesa y se abre su pedido pendiente
+CREATE TABLE IF NOT EXISTS waitlist_entries (
+    id                  SERIAL PRIMARY KEY,
+    guest_name          VARCHAR(100) NOT NULL,
+    guest_phone         VARCHAR(20),
+    party_size          INTEGER      NOT NULL CHECK (party_size > 0),
+    quoted_wait_minutes INTEGER      NOT NULL CHECK (quoted_wait_minutes >= 0), -- Espera informada al cliente
+    status              VARCHAR(20)  NOT NULL DEFAULT 'waiting' CHECK (status IN ('waiting', 'seated', 'left')),
+    table_id            INTEGER      REFERENCES tables(id) ON DELETE SET NULL,
+    customer_order_id   INTEGER      REFERENCES customer_orders(id) ON DELETE SET NULL,
+    created_by          INTEGER      NOT NULL REFERENCES employees(id),
+    seated_at           TIMESTAMP WITH TIME ZONE,
+    left_at             TIMESTAMP WITH TIME ZONE,
+    created_at          TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_orders_completed_at ON customer_orders(completed_at) WHERE status = 'completed';
+CREATE INDEX IF NOT EXISTS idx_waitlist_entries_waiting ON waitlist_entries(created_at) WHERE status = 'waiting';
+
+COMMIT;
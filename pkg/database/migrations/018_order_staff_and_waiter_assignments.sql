@@ -0,0 +1,71 @@
+-- GASTROBAR --
+
+-- =====================================================================
+-- MIGRACIÓN 018: PERSONAL DE LOS PEDIDOS Y SECCIONES DE LOS MESEROS
+-- =====================================================================
+-- Agrega a los pedidos quién los abrió, atendió y cerró y a las líneas quién las creó, y crea las
+-- secciones que atiende cada mesero por turno. Los pedidos y líneas existentes quedan sin personal
+-- (NULL), igual que los de clientes por QR
+
+BEGIN;
+
+ALTER TABLE customer_orders
+    ADD COLUMN IF NOT EXISTS opened_by INTEGER REFERENCES employees(id), -- NULL si el pedido lo abrió un cliente por QR
+    ADD COLUMN IF NOT EXISTS served_by INTEGER REFERENCES employees(id), -- Mesero a cargo de la mesa
+    ADD COLUMN IF NOT EXISTS closed_by INTEGER REFERENCES employees(id);
+
+ALTER TABLE order_details
+    ADD COLUMN IF NOT EXISTS created_by INTEGER REFERENCES employees(id); -- NULL para las líneas de clientes por QR
+
+-- Crear la tabla de asignaciones de meseros: durante un turno cada mesero atiende una sección
+-- (un grupo de mesas)
+CREATE TABLE IF NOT EXISTS waiter_assignments (
+    id          SERIAL PRIMARY KEY,
+    employee_id INTEGER NOT NULL REFERENCES employees(id),
+    starts_at   TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at     TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_by  INTEGER NOT NULL REFERENCES employees(id),
+    created_at  TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_at > starts_at)
+);
+
+-- Crear la tabla de mesas de cada sección asignada
+CREATE TABLE IF NOT EXISTS waiter_assignment_tables (
+    assignment_id INTEGER NOT NULL REFERENCES waiter_assignments(id) ON DELETE CASCADE,
+    table_id      INTEGER NOT NULL REFERENCES tables(id) ON DELETE CASCADE,
+    PRIMARY KEY (assignment_id, table_id)
+);
+
+-- Crear la función que asigna el mesero de un pedido nuevo: el que tiene la mesa asignada en el
+-- turno en curso o, si nadie la tiene, el empleado que abrió el pedido
+CREATE OR REPLACE FUNCTION assign_customer_order_server()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.served_by IS NULL THEN
+        SELECT wa.employee_id INTO NEW.served_by
+        FROM waiter_assignments wa
+        JOIN waiter_assignment_tables wat ON wat.assignment_id = wa.id
+        WHERE wat.table_id = NEW.table_id
+          AND wa.starts_at <= CURRENT_TIMESTAMP
+          AND wa.ends_at > CURRENT_TIMESTAMP
+        ORDER BY wa.starts_at DESC
+        LIMIT 1;
+
+        NEW.served_by := COALESCE(NEW.served_by, NEW.opened_by);
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Trigger para antes de insertar un customer_order
+DROP TRIGGER IF EXISTS assign_server_before_insert ON customer_orders;
+CREATE TRIGGER assign_server_before_insert
+    BEFORE INSERT ON customer_orders
+    FOR EACH ROW
+    EXECUTE FUNCTION assign_customer_order_server();
+
+CREATE INDEX IF NOT EXISTS idx_customer_orders_served_by ON customer_orders(served_by) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_waiter_assignments_employee_id ON waiter_assignments(employee_id, starts_at);
+CREATE INDEX IF NOT EXISTS idx_waiter_assignment_tables_table_id ON waiter_assignment_tables(table_id);
+
+COMMIT;
@@ -0,0 +1,49 @@
+-- GASTROBAR --
+
+-- =====================================================================
+-- MIGRACIÓN 019: PLANTILLAS DE TURNO Y HORARIOS SEMANALES
+-- =====================================================================
+-- Crea las plantillas de turno, los horarios semanales y sus turnos
+
+BEGIN;
+
+-- Crear la tabla de plantillas de turno (por ejemplo "Almuerzo salón" de 11:00 a 16:00). Si end_time
+-- es menor o igual que start_time el turno termina al día siguiente
+CREATE TABLE IF NOT EXISTS shift_templates (
+    id            SERIAL PRIMARY KEY,
+    template_name VARCHAR(100) NOT NULL UNIQUE,
+    role          VARCHAR(20)  NOT NULL CHECK (role IN ('mesero', 'cocina', 'barra', 'caja', 'encargado')),
+    start_time    TIME         NOT NULL,
+    end_time      TIME         NOT NULL,
+    created_at    TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Crear la tabla de horarios semanales; los empleados solo ven los turnos de horarios publicados
+CREATE TABLE IF NOT EXISTS schedules (
+    id           SERIAL PRIMARY KEY,
+    week_start   DATE        NOT NULL UNIQUE CHECK (EXTRACT(ISODOW FROM week_start) = 1), -- Lunes de la semana
+    status       VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'published')),
+    published_at TIMESTAMP WITH TIME ZONE,
+    published_by INTEGER     REFERENCES employees(id),
+    created_by   INTEGER     NOT NULL REFERENCES employees(id),
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Crear la tabla de turnos de cada horario: un empleado cumple un rol en un intervalo
+CREATE TABLE IF NOT EXISTS shifts (
+    id                SERIAL PRIMARY KEY,
+    schedule_id       INTEGER     NOT NULL REFERENCES schedules(id) ON DELETE CASCADE,
+    employee_id       INTEGER     NOT NULL REFERENCES employees(id),
+    shift_template_id INTEGER     REFERENCES shift_templates(id) ON DELETE SET NULL,
+    role              VARCHAR(20) NOT NULL CHECK (role IN ('mesero', 'cocina', 'barra', 'caja', 'encargado')),
+    starts_at         TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at           TIMESTAMP WITH TIME ZONE NOT NULL,
+    notes             TEXT,
+    created_at        TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_shifts_schedule_id ON shifts(schedule_id);
+CREATE INDEX IF NOT EXISTS idx_shifts_employee_id ON shifts(employee_id, starts_at);
+
+COMMIT;
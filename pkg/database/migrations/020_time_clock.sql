@@ -0,0 +1,36 @@
+-- GASTROBAR --
+
+-- =====================================================================
+-- MIGRACIÓN 020: MARCACIONES DE ASISTENCIA
+-- =====================================================================
+-- Crea las marcaciones de entrada y salida de los empleados y sus pausas
+
+BEGIN;
+
+-- Crear la tabla de marcaciones de asistencia: cada fila es una jornada desde la entrada hasta la
+-- salida (NULL mientras el empleado sigue trabajando). adjusted_by indica una corrección manual
+CREATE TABLE IF NOT EXISTS time_entries (
+    id           SERIAL PRIMARY KEY,
+    employee_id  INTEGER NOT NULL REFERENCES employees(id),
+    clock_in_at  TIMESTAMP WITH TIME ZONE NOT NULL,
+    clock_out_at TIMESTAMP WITH TIME ZONE,
+    adjusted_by  INTEGER REFERENCES employees(id),
+    adjusted_at  TIMESTAMP WITH TIME ZONE,
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (clock_out_at IS NULL OR clock_out_at > clock_in_at)
+);
+
+-- Crear la tabla de pausas de cada jornada (ended_at es NULL mientras la pausa sigue en curso)
+CREATE TABLE IF NOT EXISTS time_entry_breaks (
+    id            SERIAL PRIMARY KEY,
+    time_entry_id INTEGER NOT NULL REFERENCES time_entries(id) ON DELETE CASCADE,
+    started_at    TIMESTAMP WITH TIME ZONE NOT NULL,
+    ended_at      TIMESTAMP WITH TIME ZONE,
+    CHECK (ended_at IS NULL OR ended_at >= started_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_time_entries_employee_id ON time_entries(employee_id, clock_in_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_time_entries_single_open ON time_entries(employee_id) WHERE clock_out_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_time_entry_breaks_time_entry_id ON time_entry_breaks(time_entry_id);
+
+COMMIT;
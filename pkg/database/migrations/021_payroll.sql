@@ -0,0 +1,58 @@
+-- GASTROBAR --
+
+-- =====================================================================
+-- MIGRACIÓN 021: NÓMINA
+-- =====================================================================
+-- Crea las tarifas por hora, los parámetros de la nómina con sus valores por defecto, los días
+-- festivos y las propinas recaudadas. Requiere la migración 020
+
+BEGIN;
+
+-- Crear la tabla de tarifas por hora de cada empleado (base para la liquidación de la nómina)
+CREATE TABLE IF NOT EXISTS employee_pay_rates (
+    employee_id INTEGER       PRIMARY KEY REFERENCES employees(id) ON DELETE CASCADE,
+    hourly_rate NUMERIC(10,2) NOT NULL CHECK (hourly_rate >= 0),
+    updated_by  INTEGER       REFERENCES employees(id),
+    updated_at  TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Crear la tabla de parámetros de la nómina (una sola fila). Los recargos son fracciones de la
+-- tarifa que se suman a la hora base: 0.35 es un 35% adicional
+CREATE TABLE IF NOT EXISTS payroll_settings (
+    id                   INTEGER      PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    daily_overtime_hours NUMERIC(4,2) NOT NULL DEFAULT 8 CHECK (daily_overtime_hours > 0), -- Horas diarias antes de las extras
+    overtime_surcharge   NUMERIC(4,2) NOT NULL DEFAULT 0.25 CHECK (overtime_surcharge >= 0),
+    night_start          TIME         NOT NULL DEFAULT '21:00',
+    night_end            TIME         NOT NULL DEFAULT '06:00',
+    night_surcharge      NUMERIC(4,2) NOT NULL DEFAULT 0.35 CHECK (night_surcharge >= 0),
+    holiday_surcharge    NUMERIC(4,2) NOT NULL DEFAULT 0.75 CHECK (holiday_surcharge >= 0),
+    sunday_is_holiday    BOOLEAN      NOT NULL DEFAULT TRUE, -- Los domingos llevan el recargo de festivo
+    updated_by           INTEGER      REFERENCES employees(id),
+    updated_at           TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Crear la tabla de días festivos (llevan el recargo de festivo)
+CREATE TABLE IF NOT EXISTS holidays (
+    holiday_date DATE         PRIMARY KEY,
+    description  VARCHAR(100) NOT NULL,
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Crear la tabla de propinas recaudadas; el total del periodo se reparte según las horas trabajadas
+CREATE TABLE IF NOT EXISTS tip_pool_entries (
+    id           SERIAL PRIMARY KEY,
+    collected_on DATE          NOT NULL,
+    amount       NUMERIC(10,2) NOT NULL CHECK (amount > 0),
+    notes        TEXT,
+    created_by   INTEGER       NOT NULL REFERENCES employees(id),
+    created_at   TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_tip_pool_entries_collected_on ON tip_pool_entries(collected_on);
+
+-- Parámetros por defecto de la nómina (la liquidación requiere la fila)
+INSERT INTO payroll_settings (id)
+VALUES (1)
+ON CONFLICT (id) DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,65 @@
+package imaging
+
+import (
+    "image"
+    "image/color"
+)
+
+// Thumbnail reduce la imagen para que su lado mayor no supere maxSize, promediando
+// los píxeles de origen de cada píxel destino. Si la imagen ya es pequeña se devuelve tal cual.
+func Thumbnail(src image.Image, maxSize int) image.Image {
+    bounds := src.Bounds()
+    srcW, srcH := bounds.Dx(), bounds.Dy()
+    if maxSize <= 0 || (srcW <= maxSize && srcH <= maxSize) {
+        return src
+    }
+
+    // Calcular las dimensiones destino conservando la proporción
+    dstW, dstH := maxSize, maxSize
+    if srcW > srcH {
+        dstH = srcH * maxSize / srcW
+    } else {
+        dstW = srcW * maxSize / srcH
+    }
+    if dstW < 1 {
+        dstW = 1
+    }
+    if dstH < 1 {
+        dstH = 1
+    }
+
+    dst := image.NewRGBA64(image.Rect(0, 0, dstW, dstH))
+    for y := 0; y < dstH; y++ {
+        y0 := bounds.Min.Y + y*srcH/dstH
+        y1 := bounds.Min.Y + (y+1)*srcH/dstH
+        if y1 <= y0 {
+            y1 = y0 + 1
+        }
+        for x := 0; x < dstW; x++ {
+            x0 := bounds.Min.X + x*srcW/dstW
+            x1 := bounds.Min.X + (x+1)*srcW/dstW
+            if x1 <= x0 {
+                x1 = x0 + 1
+            }
+
+            var r, g, b, a, n uint64
+            for sy := y0; sy < y1; sy++ {
+                for sx := x0; sx < x1; sx++ {
+                    cr, cg, cb, ca := src.At(sx, sy).RGBA()
+                    r += uint64(cr)
+                    g += uint64(cg)
+                    b += uint64(cb)
+                    a += uint64(ca)
+                    n++
+                }
+            }
+            dst.SetRGBA64(x, y, color.RGBA64{
+                R: uint16(r / n),
+                G: uint16(g / n),
+                B: uint16(b / n),
+                A: uint16(a / n),
+            })
+        }
+    }
+    return dst
+}
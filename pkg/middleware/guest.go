@@ -0,0 +1,68 @@
+package middleware
+
+import (
+    "context"
+    "net/http"
+    "strings"
+
+    "gastrobar-backend/config"
+
+    "github.com/golang-jwt/jwt/v5"
+)
+
+// GuestMiddleware valida el token de sesión de cliente emitido al escanear el QR de una mesa
+// y agrega table_id y qr_version al contexto. Los tokens de empleados no son aceptados.
+func GuestMiddleware() func(http.Handler) http.Handler {
+    return func(next http.Handler) http.Handler {
+        return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+            // Obtener el token del header Authorization
+            authHeader := r.Header.Get("Authorization")
+            if authHeader == "" {
+                http.Error(w, "Authorization header required", http.StatusUnauthorized)
+                return
+            }
+
+            parts := strings.Split(authHeader, " ")
+            if len(parts) != 2 || parts[0] != "Bearer" {
+                http.Error(w, "Invalid Authorization header format", http.StatusUnauthorized)
+                return
+            }
+
+            // Parsear y validar el token
+            token, err := jwt.Parse(parts[1], func(token *jwt.Token) (interface{}, error) {
+                if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+                    return nil, http.ErrAbortHandler
+                }
+                return []byte(config.GetJWTSecret()), nil
+            })
+            if err != nil || !token.Valid {
+                http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
+                return
+            }
+
+            claims, ok := token.Claims.(jwt.MapClaims)
+            if !ok {
+                http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+                return
+            }
+
+            // Solo se aceptan sesiones de cliente
+            if scope, _ := claims["scope"].(string); scope != "guest" {
+                http.Error(w, "Guest session required", http.StatusForbidden)
+                return
+            }
+
+            tableID, ok := claims["table_id"].(float64)
+            if !ok {
+                http.Error(w, "Table not found in token", http.StatusUnauthorized)
+                return
+            }
+            qrVersion, _ := claims["qr_version"].(float64)
+
+            ctx := context.WithValue(r.Context(), "table_id", int(tableID))
+            ctx = context.WithValue(ctx, "qr_version", int(qrVersion))
+
+            next.ServeHTTP(w, r.WithContext(ctx))
+        })
+    }
+}
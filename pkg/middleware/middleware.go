@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"log"
 	"net/http"
 	"strings"
 
@@ -11,50 +12,20 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-// AuthMiddleware valida el token JWT y verifica los roles permitidos
-func AuthMiddleware(allowedRoles []models.EmployeeRole) func(http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Obtener el token del header Authorization
-			authHeader := r.Header.Get("Authorization")
-			if authHeader == "" {
-				http.Error(w, "Authorization header required", http.StatusUnauthorized)
-				return
-			}
-
-			parts := strings.Split(authHeader, " ")
-			if len(parts) != 2 || parts[0] != "Bearer" {
-				http.Error(w, "Invalid Authorization header format", http.StatusUnauthorized)
-				return
-			}
-			tokenString := parts[1]
+// SessionValidator verifica contra la base de datos que la sesión de un token siga vigente; recibe el
+// employee_id y la versión de sesión (claim "tv") del token
+type SessionValidator func(employeeID, tokenVersion int) error
 
-			// Obtener jwtSecret desde la configuración
-			jwtSecret := config.GetJWTSecret()
+// PermissionResolver indica si un rol tiene un permiso según los permisos por rol configurados
+type PermissionResolver func(role models.EmployeeRole, permission models.Permission) (bool, error)
 
-			// Parsear y validar el token
-			token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-					return nil, http.ErrAbortHandler
-				}
-				return []byte(jwtSecret), nil
-			})
-			if err != nil || !token.Valid {
-				http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
-				return
-			}
-
-			// Obtener los claims del token
-			claims, ok := token.Claims.(jwt.MapClaims)
-			if !ok {
-				http.Error(w, "Invalid token claims", http.StatusUnauthorized)
-				return
-			}
-
-			// Verificar el rol del usuario
-			role, ok := claims["role"].(string)
+// AuthMiddleware valida el token JWT, verifica los roles permitidos y, si se indica un validador,
+// que la sesión no haya sido revocada
+func AuthMiddleware(allowedRoles []models.EmployeeRole, validateSession SessionValidator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, role, ok := authenticate(w, r, validateSession)
 			if !ok {
-				http.Error(w, "Role not found in token", http.StatusUnauthorized)
 				return
 			}
 
@@ -73,13 +44,101 @@ func AuthMiddleware(allowedRoles []models.EmployeeRole) func(http.Handler) http.
 				}
 			}
 
-			// Agregar el employee_id y el role al contexto para que las rutas puedan usarlos
-			employeeID, _ := claims["employee_id"].(float64)
-			ctx := context.WithValue(r.Context(), "employee_id", int(employeeID))
-			ctx = context.WithValue(ctx, "role", role)
-
 			// Continuar con el siguiente handler
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
+
+// PermissionMiddleware valida el token JWT y la sesión como AuthMiddleware y exige que el rol del
+// usuario tenga el permiso indicado
+func PermissionMiddleware(permission models.Permission, validateSession SessionValidator, hasPermission PermissionResolver) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, role, ok := authenticate(w, r, validateSession)
+			if !ok {
+				return
+			}
+
+			allowed, err := hasPermission(models.EmployeeRole(role), permission)
+			if err != nil {
+				log.Printf("Error resolving permission %s: %v", permission, err)
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+			if !allowed {
+				http.Error(w, "Insufficient permissions", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// authenticate valida el token JWT del header Authorization y, si se indica un validador, que la
+// sesión siga vigente. Devuelve el contexto con employee_id y role, o false si ya respondió el error
+func authenticate(w http.ResponseWriter, r *http.Request, validateSession SessionValidator) (context.Context, string, bool) {
+	// Obtener el token del header Authorization
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		http.Error(w, "Authorization header required", http.StatusUnauthorized)
+		return nil, "", false
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		http.Error(w, "Invalid Authorization header format", http.StatusUnauthorized)
+		return nil, "", false
+	}
+	tokenString := parts[1]
+
+	// Obtener jwtSecret desde la configuración
+	jwtSecret := config.GetJWTSecret()
+
+	// Parsear y validar el token
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, http.ErrAbortHandler
+		}
+		return []byte(jwtSecret), nil
+	})
+	if err != nil || !token.Valid {
+		http.Error(w, "Invalid or expired token", http.StatusUnauthorized)
+		return nil, "", false
+	}
+
+	// Obtener los claims del token
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		http.Error(w, "Invalid token claims", http.StatusUnauthorized)
+		return nil, "", false
+	}
+
+	// Obtener el rol del usuario
+	role, ok := claims["role"].(string)
+	if !ok {
+		http.Error(w, "Role not found in token", http.StatusUnauthorized)
+		return nil, "", false
+	}
+
+	// Verificar que la sesión siga vigente (los tokens anteriores a la versión de sesión no llevan "tv")
+	employeeID, _ := claims["employee_id"].(float64)
+	if validateSession != nil {
+		tokenVersion, _ := claims["tv"].(float64)
+		if err := validateSession(int(employeeID), int(tokenVersion)); err != nil {
+			if strings.Contains(err.Error(), "session has been revoked") || strings.Contains(err.Error(), "employee not found") || strings.Contains(err.Error(), "employee is inactive") {
+				http.Error(w, "Session is no longer valid", http.StatusUnauthorized)
+				return nil, "", false
+			}
+			log.Printf("Error validating session: %v", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return nil, "", false
+		}
+	}
+
+	// Agregar el employee_id y el role al contexto para que las rutas puedan usarlos
+	ctx := context.WithValue(r.Context(), "employee_id", int(employeeID))
+	ctx = context.WithValue(ctx, "role", role)
+	return ctx, role, true
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+    "net"
+    "net/http"
+    "strconv"
+    "sync"
+    "time"
+)
+
+// rateWindow cuenta las solicitudes de un cliente dentro de la ventana actual
+type rateWindow struct {
+    start time.Time
+    count int
+}
+
+// RateLimitMiddleware limita cada IP a maxRequests solicitudes por ventana de tiempo
+func RateLimitMiddleware(maxRequests int, window time.Duration) func(http.Handler) http.Handler {
+    var mu sync.Mutex
+    clients := make(map[string]*rateWindow)
+    lastCleanup := time.Now()
+
+    return func(next http.Handler) http.Handler {
+        return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+            ip, _, err := net.SplitHostPort(r.RemoteAddr)
+            if err != nil {
+                ip = r.RemoteAddr
+            }
+
+            now := time.Now()
+            mu.Lock()
+
+            // Limpiar periódicamente las ventanas vencidas para no acumular memoria
+            if now.Sub(lastCleanup) > window {
+                for key, client := range clients {
+                    if now.Sub(client.start) > window {
+                        delete(clients, key)
+                    }
+                }
+                lastCleanup = now
+            }
+
+            client, ok := clients[ip]
+            if !ok || now.Sub(client.start) > window {
+                client = &rateWindow{start: now}
+                clients[ip] = client
+            }
+            client.count++
+            exceeded := client.count > maxRequests
+            retryAfter := window - now.Sub(client.start)
+            mu.Unlock()
+
+            if exceeded {
+                w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+                http.Error(w, "Too many requests", http.StatusTooManyRequests)
+                return
+            }
+
+            next.ServeHTTP(w, r)
+        })
+    }
+}
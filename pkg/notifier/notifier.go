@@ -0,0 +1,76 @@
+package notifier
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "log"
+    "net/http"
+    "time"
+)
+
+// Event representa una alerta emitida por la aplicación
+type Event struct {
+    Type       string      `json:"type"`
+    Message    string      `json:"message"`
+    Data       interface{} `json:"data"`
+    OccurredAt time.Time   `json:"occurred_at"`
+}
+
+// Notifier entrega eventos de alerta a un destino (log, webhook, ...)
+type Notifier interface {
+    Notify(event Event) error
+}
+
+// LogNotifier registra los eventos en el log de la aplicación
+type LogNotifier struct{}
+
+// NewLogNotifier crea un notificador que solo escribe en el log
+func NewLogNotifier() *LogNotifier {
+    return &LogNotifier{}
+}
+
+func (n *LogNotifier) Notify(event Event) error {
+    log.Printf("[alert] %s: %s", event.Type, event.Message)
+    return nil
+}
+
+// WebhookNotifier envía los eventos como JSON a una URL mediante POST
+type WebhookNotifier struct {
+    url    string
+    client *http.Client
+}
+
+// NewWebhookNotifier crea un notificador que publica los eventos en la URL indicada
+func NewWebhookNotifier(url string) *WebhookNotifier {
+    return &WebhookNotifier{
+        url:    url,
+        client: &http.Client{Timeout: 5 * time.Second},
+    }
+}
+
+func (n *WebhookNotifier) Notify(event Event) error {
+    body, err := json.Marshal(event)
+    if err != nil {
+        return fmt.Errorf("failed to encode event: %w", err)
+    }
+
+    resp, err := n.client.Post(n.url, "application/json", bytes.NewReader(body))
+    if err != nil {
+        return fmt.Errorf("failed to deliver event: %w", err)
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+        return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+    }
+    return nil
+}
+
+// New devuelve un notificador por webhook si se configuró una URL, o uno de log en caso contrario
+func New(webhookURL string) Notifier {
+    if webhookURL == "" {
+        return NewLogNotifier()
+    }
+    return NewWebhookNotifier(webhookURL)
+}
@@ -0,0 +1,106 @@
+package storage
+
+import (
+    "net/http"
+    "os"
+    "path"
+    "path/filepath"
+    "strings"
+
+    "github.com/pkg/errors"
+)
+
+// Storage define las operaciones para guardar archivos subidos (imágenes del menú, etc.)
+type Storage interface {
+    Save(name string, data []byte) error
+    Delete(name string) error
+    URL(name string) string
+}
+
+// LocalStorage guarda los archivos en un directorio del disco local
+type LocalStorage struct {
+    baseDir string
+    baseURL string
+}
+
+// NewLocalStorage crea un almacenamiento local en baseDir cuyos archivos se sirven bajo baseURL
+func NewLocalStorage(baseDir, baseURL string) *LocalStorage {
+    return &LocalStorage{
+        baseDir: baseDir,
+        baseURL: strings.TrimSuffix(baseURL, "/"),
+    }
+}
+
+// Save escribe el archivo en el disco, creando los directorios necesarios
+func (s *LocalStorage) Save(name string, data []byte) error {
+    fullPath, err := s.resolve(name)
+    if err != nil {
+        return err
+    }
+    if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+        return errors.Wrap(err, "failed to create storage directory")
+    }
+    if err := os.WriteFile(fullPath, data, 0o644); err != nil {
+        return errors.Wrap(err, "failed to write file")
+    }
+    return nil
+}
+
+// Delete elimina el archivo; no falla si el archivo ya no existe
+func (s *LocalStorage) Delete(name string) error {
+    fullPath, err := s.resolve(name)
+    if err != nil {
+        return err
+    }
+    if err := os.Remove(fullPath); err != nil && !os.IsNotExist(err) {
+        return errors.Wrap(err, "failed to delete file")
+    }
+    return nil
+}
+
+// URL devuelve la URL pública del archivo
+func (s *LocalStorage) URL(name string) string {
+    return s.baseURL + "/" + path.Clean(name)
+}
+
+// BaseURL devuelve el prefijo bajo el cual se sirven los archivos
+func (s *LocalStorage) BaseURL() string {
+    return s.baseURL
+}
+
+// Handler sirve los archivos guardados bajo BaseURL, sin listar el contenido de los directorios
+func (s *LocalStorage) Handler() http.Handler {
+    return http.StripPrefix(s.baseURL+"/", http.FileServer(fileOnlyFS{http.Dir(s.baseDir)}))
+}
+
+// fileOnlyFS es un http.FileSystem que no expone directorios: abrir uno devuelve os.ErrNotExist,
+// así http.FileServer responde 404 en lugar del listado
+type fileOnlyFS struct {
+    fs http.FileSystem
+}
+
+func (f fileOnlyFS) Open(name string) (http.File, error) {
+    file, err := f.fs.Open(name)
+    if err != nil {
+        return nil, err
+    }
+    info, err := file.Stat()
+    if err != nil {
+        file.Close()
+        return nil, err
+    }
+    if info.IsDir() {
+        file.Close()
+        return nil, os.ErrNotExist
+    }
+    return file, nil
+}
+
+// resolve convierte el nombre lógico en una ruta del disco, evitando salir de baseDir
+func (s *LocalStorage) resolve(name string) (string, error) {
+    cleaned := path.Clean("/" + name)
+    if cleaned == "/" {
+        return "", errors.New("invalid file name")
+    }
+    return filepath.Join(s.baseDir, filepath.FromSlash(cleaned)), nil
+}